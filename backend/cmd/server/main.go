@@ -11,11 +11,15 @@ import (
 	"time"
 	"tongly-backend/internal/config"
 	"tongly-backend/internal/database"
+	"tongly-backend/internal/entities"
 	interfaces "tongly-backend/internal/handlers"
+	"tongly-backend/internal/jobs"
 	"tongly-backend/internal/logger"
 	"tongly-backend/internal/repositories"
 	"tongly-backend/internal/router"
 	"tongly-backend/internal/usecases"
+	"tongly-backend/pkg/mailer"
+	"tongly-backend/pkg/storage"
 
 	"github.com/gin-gonic/gin"
 	_ "github.com/lib/pq"
@@ -73,26 +77,103 @@ func main() {
 	goalRepo := repositories.NewGoalRepository(db)
 	prefsRepo := repositories.NewUserPreferencesRepository(db)
 	gameRepo := repositories.NewGameRepository(db)
+	earningsRepo := repositories.NewEarningsRepository(db)
+	groupClassRepo := repositories.NewGroupClassRepository(db)
+	calendarRepo := repositories.NewCalendarRepository(db)
+	busyTimeRepo := repositories.NewBusyTimeRepository(db)
+	jobRepo := repositories.NewJobRepository(db)
+	notificationRepo := repositories.NewNotificationRepository(db)
+	notificationPrefsRepo := repositories.NewNotificationPreferenceRepository(db)
+	messageRepo := repositories.NewMessageRepository(db)
+	lessonNotesRepo := repositories.NewLessonNotesRepository(db)
+	uploadRepo := repositories.NewUploadRepository(db)
+	reviewRepo := repositories.NewReviewRepository(db)
+	favoriteRepo := repositories.NewFavoriteRepository(db)
+	credentialRepo := repositories.NewCredentialRepository(db)
+	analyticsRepo := repositories.NewAnalyticsRepository(db)
+
+	// Emails go to the configured SMTP server (a local catcher in development) or only to the log
+	var mail mailer.Mailer = mailer.NewLogMailer()
+	if cfg.SMTPHost != "" {
+		mail = mailer.NewSMTPMailer(mailer.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.MailFrom,
+		})
+	}
+
+	// Uploaded files go to an S3-compatible bucket or a local directory served through signed URLs
+	var store storage.Storage = storage.NewLocalStorage(cfg.StorageDir, cfg.APIURL+"/api/files", cfg.StorageSigningKey)
+	if cfg.StorageBackend == "s3" {
+		store = storage.NewS3Storage(storage.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PathStyle: cfg.S3PathStyle,
+		})
+	}
+
+	// Notifications are delivered in-app and by email according to each user's preferences
+	emailUseCase := usecases.NewEmailUseCase(notificationPrefsRepo, lessonRepo, jobRepo, mail, cfg.AppURL)
+	notificationUseCase := usecases.NewNotificationUseCase(notificationRepo, notificationPrefsRepo, emailUseCase)
 
 	// Initialize usecases
 	authUseCase := usecases.NewAuthUseCase(userRepo, studentRepo, tutorRepo)
 	studentUseCase := usecases.NewStudentUseCase(studentRepo, userRepo, lessonRepo)
 	tutorUseCase := usecases.NewTutorUseCase(tutorRepo, userRepo, studentRepo, lessonRepo)
-	lessonUseCase := usecases.NewLessonUseCase(lessonRepo, userRepo, tutorRepo, studentRepo, langRepo)
+	lessonUseCase := usecases.NewLessonUseCase(lessonRepo, userRepo, tutorRepo, studentRepo, langRepo, busyTimeRepo, notificationUseCase)
 	commonUseCase := usecases.NewCommonUseCase(langRepo, interestRepo, goalRepo)
 	userUseCase := usecases.NewUserUseCase(userRepo)
 	prefsUseCase := usecases.NewUserPreferencesUseCase(prefsRepo, langRepo, interestRepo, goalRepo)
-	gameUseCase := usecases.NewGameUseCase(gameRepo, langRepo)
+	gameUseCase := usecases.NewGameUseCase(gameRepo, langRepo, jobRepo, notificationUseCase)
+	earningsUseCase := usecases.NewEarningsUseCase(earningsRepo, userRepo, cfg.EarningsHoldDays)
+	groupClassUseCase := usecases.NewGroupClassUseCase(groupClassRepo, lessonUseCase)
+	calendarUseCase := usecases.NewCalendarUseCase(calendarRepo)
+	busyTimeUseCase := usecases.NewBusyTimeUseCase(busyTimeRepo)
+	reminderUseCase := usecases.NewReminderUseCase(lessonRepo, jobRepo, notificationUseCase)
+	jobUseCase := usecases.NewJobUseCase(jobRepo)
+	messageUseCase := usecases.NewMessageUseCase(messageRepo, userRepo, notificationUseCase)
+	lessonNotesUseCase := usecases.NewLessonNotesUseCase(lessonNotesRepo, lessonRepo, notificationUseCase)
+	uploadUseCase := usecases.NewUploadUseCase(uploadRepo, lessonRepo, jobRepo, store, cfg.APIURL, cfg.UploadQuotaMB<<20, time.Duration(cfg.SignedURLMinutes)*time.Minute)
+	reviewUseCase := usecases.NewReviewUseCase(reviewRepo, notificationUseCase)
+	credentialUseCase := usecases.NewCredentialUseCase(credentialRepo, uploadRepo, userRepo, uploadUseCase, notificationUseCase)
+	analyticsUseCase := usecases.NewAnalyticsUseCase(analyticsRepo, lessonRepo)
+	favoriteUseCase := usecases.NewFavoriteUseCase(favoriteRepo, tutorRepo, userRepo, studentRepo, notificationUseCase)
+	recommendationUseCase := usecases.NewRecommendationUseCase(studentRepo, tutorRepo, userRepo, langRepo, entities.RecommendationWeights{
+		Interests:    cfg.RecommendationInterestsWeight,
+		Goals:        cfg.RecommendationGoalsWeight,
+		Language:     cfg.RecommendationLanguageWeight,
+		Availability: cfg.RecommendationAvailabilityWeight,
+		Rating:       cfg.RecommendationRatingWeight,
+		Price:        cfg.RecommendationPriceWeight,
+	})
 
 	// Initialize handlers
 	authHandler := interfaces.NewAuthHandler(*authUseCase, tutorUseCase, studentUseCase)
-	studentHandler := interfaces.NewStudentHandler(studentUseCase)
-	tutorHandler := interfaces.NewTutorHandler(tutorUseCase)
-	lessonHandler := interfaces.NewLessonHandler(lessonUseCase)
+	studentHandler := interfaces.NewStudentHandler(studentUseCase, recommendationUseCase)
+	tutorHandler := interfaces.NewTutorHandler(tutorUseCase, analyticsUseCase)
+	lessonHandler := interfaces.NewLessonHandler(lessonUseCase, calendarUseCase)
 	commonHandler := interfaces.NewCommonHandler(commonUseCase)
 	userHandler := interfaces.NewUserHandler(userUseCase)
 	preferencesHandler := interfaces.NewUserPreferencesHandler(prefsUseCase)
 	gameHandler := interfaces.NewGameHandler(gameUseCase)
+	earningsHandler := interfaces.NewEarningsHandler(earningsUseCase)
+	groupClassHandler := interfaces.NewGroupClassHandler(groupClassUseCase, lessonUseCase)
+	calendarHandler := interfaces.NewCalendarHandler(calendarUseCase, cfg.APIURL)
+	busyTimeHandler := interfaces.NewBusyTimeHandler(busyTimeUseCase)
+	adminHandler := interfaces.NewAdminHandler(jobUseCase, messageUseCase, reviewUseCase, credentialUseCase)
+	notificationHandler := interfaces.NewNotificationHandler(notificationUseCase)
+	messageHandler := interfaces.NewMessageHandler(messageUseCase)
+	lessonNotesHandler := interfaces.NewLessonNotesHandler(lessonNotesUseCase)
+	uploadHandler := interfaces.NewUploadHandler(uploadUseCase)
+	reviewHandler := interfaces.NewReviewHandler(reviewUseCase)
+	favoriteHandler := interfaces.NewFavoriteHandler(favoriteUseCase)
+	credentialHandler := interfaces.NewCredentialHandler(credentialUseCase)
+	analyticsHandler := interfaces.NewAnalyticsHandler(analyticsUseCase)
 
 	// Create a new Gin router with recommended production settings
 	gin.SetMode(gin.ReleaseMode)
@@ -105,8 +186,78 @@ func main() {
 		userHandler,
 		preferencesHandler,
 		gameHandler,
+		earningsHandler,
+		groupClassHandler,
+		calendarHandler,
+		busyTimeHandler,
+		adminHandler,
+		notificationHandler,
+		messageHandler,
+		lessonNotesHandler,
+		uploadHandler,
+		reviewHandler,
+		favoriteHandler,
+		credentialHandler,
+		analyticsHandler,
 	)
 
+	// Start background workers
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	scheduler := jobs.NewScheduler(jobRepo)
+	scheduler.Register(entities.JobTypeLessonReminder, reminderUseCase.HandleLessonReminder)
+	scheduler.Register(entities.JobTypeStreakReminders, gameUseCase.HandleStreakReminders)
+	scheduler.Register(entities.JobTypeSendEmail, emailUseCase.HandleSendEmail)
+	scheduler.Register(entities.JobTypeWeeklySummaries, emailUseCase.HandleWeeklySummaries)
+	scheduler.Register(entities.JobTypeProcessAvatar, uploadUseCase.HandleProcessAvatar)
+	scheduler.Every(entities.JobTypeTutorAlerts, time.Hour, favoriteUseCase.HandleTutorAlerts)
+
+	scheduler.Every(entities.JobTypeCancelUnderfilledLessons, time.Minute, func(ctx context.Context, _ *entities.Job) error {
+		cancelled, err := lessonUseCase.CancelUnderfilledGroupLessons(ctx)
+		if cancelled > 0 {
+			logger.Info("Cancelled underfilled group lessons", "count", cancelled)
+		}
+		return err
+	})
+	scheduler.Every(entities.JobTypeSyncCalendars, 5*time.Minute, func(ctx context.Context, _ *entities.Job) error {
+		synced, err := busyTimeUseCase.SyncDueSources(ctx)
+		if synced > 0 {
+			logger.Info("Synced external calendars", "count", synced)
+		}
+		return err
+	})
+	scheduler.Every(entities.JobTypeScheduleReminders, time.Minute, func(ctx context.Context, _ *entities.Job) error {
+		scheduled, err := reminderUseCase.ScheduleReminders(ctx)
+		if scheduled > 0 {
+			logger.Info("Scheduled lesson reminders", "count", scheduled)
+		}
+		return err
+	})
+	scheduler.Every(entities.JobTypeScheduleStreakReminders, time.Hour, func(ctx context.Context, _ *entities.Job) error {
+		return gameUseCase.ScheduleStreakReminders(ctx)
+	})
+	scheduler.Every(entities.JobTypeScheduleWeeklySummaries, time.Hour, func(ctx context.Context, _ *entities.Job) error {
+		return emailUseCase.ScheduleWeeklySummaries(ctx)
+	})
+	scheduler.Every(entities.JobTypeRefreshRatingStats, time.Hour, func(ctx context.Context, _ *entities.Job) error {
+		return tutorUseCase.RefreshRatingStats(ctx)
+	})
+	scheduler.Every(entities.JobTypePruneJobs, 24*time.Hour, func(ctx context.Context, _ *entities.Job) error {
+		pruned, err := jobUseCase.PruneJobs(ctx)
+		if pruned > 0 {
+			logger.Info("Pruned succeeded jobs", "count", pruned)
+		}
+		return err
+	})
+	go scheduler.Run(workerCtx)
+
+	go func() {
+		if err := notificationUseCase.Listen(workerCtx, dbURL); err != nil {
+			logger.Error("Notification listener stopped", "error", err)
+		}
+	}()
+
 	// Start server with graceful shutdown
 	srv := &http.Server{
 		Addr:    ":" + cfg.ServerPort,
@@ -164,6 +315,8 @@ func main() {
 	<-quit
 
 	logger.Info("Shutting down server...")
+	stopWorkers()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -18,6 +18,47 @@ type Config struct {
 	JWTSecret  string
 	ServerPort string
 	UseSSL     bool
+
+	// EarningsHoldDays is the number of days earnings stay pending before they can be paid out
+	EarningsHoldDays int
+
+	// AppURL is the address of the frontend, used in links sent by email
+	AppURL string
+
+	// SMTP server for outgoing email. Emails are only logged when SMTPHost is empty.
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	MailFrom     string
+
+	// APIURL is the public address of this API, used in links to uploaded files and calendar feeds
+	APIURL string
+
+	// Uploaded files are kept in StorageDir, or in an S3-compatible bucket when StorageBackend is "s3"
+	StorageBackend    string
+	StorageDir        string
+	StorageSigningKey string
+	S3Endpoint        string
+	S3Region          string
+	S3Bucket          string
+	S3AccessKey       string
+	S3SecretKey       string
+	S3PathStyle       bool
+
+	// UploadQuotaMB is the total size of the files each user may upload
+	UploadQuotaMB int64
+	// SignedURLMinutes is how long download links to uploaded files stay valid
+	SignedURLMinutes int
+
+	// Weights of the factors tutor recommendations are scored by. A factor with weight 0 is
+	// left out.
+	RecommendationInterestsWeight    float64
+	RecommendationGoalsWeight        float64
+	RecommendationLanguageWeight     float64
+	RecommendationAvailabilityWeight float64
+	RecommendationRatingWeight       float64
+	RecommendationPriceWeight        float64
 }
 
 func LoadConfig() *Config {
@@ -28,6 +69,16 @@ func LoadConfig() *Config {
 
 	dbPort, _ := strconv.Atoi(getEnv("DB_PORT", "5432"))
 	useSSL := getEnv("USE_SSL", "false") == "true"
+	earningsHoldDays, _ := strconv.Atoi(getEnv("EARNINGS_HOLD_DAYS", "7"))
+	smtpPort, _ := strconv.Atoi(getEnv("SMTP_PORT", "1025"))
+	uploadQuotaMB, _ := strconv.ParseInt(getEnv("UPLOAD_QUOTA_MB", "1024"), 10, 64)
+	signedURLMinutes, _ := strconv.Atoi(getEnv("SIGNED_URL_MINUTES", "15"))
+	interestsWeight, _ := strconv.ParseFloat(getEnv("RECOMMENDATION_INTERESTS_WEIGHT", "1"), 64)
+	goalsWeight, _ := strconv.ParseFloat(getEnv("RECOMMENDATION_GOALS_WEIGHT", "1.5"), 64)
+	languageWeight, _ := strconv.ParseFloat(getEnv("RECOMMENDATION_LANGUAGE_WEIGHT", "3"), 64)
+	availabilityWeight, _ := strconv.ParseFloat(getEnv("RECOMMENDATION_AVAILABILITY_WEIGHT", "2"), 64)
+	ratingWeight, _ := strconv.ParseFloat(getEnv("RECOMMENDATION_RATING_WEIGHT", "1.5"), 64)
+	priceWeight, _ := strconv.ParseFloat(getEnv("RECOMMENDATION_PRICE_WEIGHT", "1"), 64)
 
 	return &Config{
 		DBHost:     getEnv("DB_HOST", "localhost"),
@@ -39,6 +90,38 @@ func LoadConfig() *Config {
 		JWTSecret:  getEnv("JWT_SECRET", "supersecretkey"),
 		ServerPort: getEnv("SERVER_PORT", "8080"),
 		UseSSL:     useSSL,
+
+		EarningsHoldDays: earningsHoldDays,
+
+		AppURL: getEnv("APP_URL", "https://localhost"),
+
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     smtpPort,
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		MailFrom:     getEnv("MAIL_FROM", "Tongly <no-reply@tongly.local>"),
+
+		APIURL: getEnv("API_URL", "https://localhost:8080"),
+
+		StorageBackend:    getEnv("STORAGE_BACKEND", "local"),
+		StorageDir:        getEnv("STORAGE_DIR", "./uploads"),
+		StorageSigningKey: getEnv("STORAGE_SIGNING_KEY", "storagesigningkey"),
+		S3Endpoint:        getEnv("S3_ENDPOINT", "https://s3.amazonaws.com"),
+		S3Region:          getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:          getEnv("S3_BUCKET", ""),
+		S3AccessKey:       getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:       getEnv("S3_SECRET_KEY", ""),
+		S3PathStyle:       getEnv("S3_PATH_STYLE", "false") == "true",
+
+		UploadQuotaMB:    uploadQuotaMB,
+		SignedURLMinutes: signedURLMinutes,
+
+		RecommendationInterestsWeight:    interestsWeight,
+		RecommendationGoalsWeight:        goalsWeight,
+		RecommendationLanguageWeight:     languageWeight,
+		RecommendationAvailabilityWeight: availabilityWeight,
+		RecommendationRatingWeight:       ratingWeight,
+		RecommendationPriceWeight:        priceWeight,
 	}
 }
 
@@ -0,0 +1,165 @@
+// Package emails renders the localized emails sent to users.
+//
+// Every email is a template file per locale under templates/<locale>/<name>.tmpl that defines
+// a "subject", a plain "text" body and an "html" body. Locales without a template fall back to
+// English.
+package emails
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+	"time"
+	"tongly-backend/internal/entities"
+	"tongly-backend/pkg/mailer"
+)
+
+// Template identifies an email
+type Template string
+
+const (
+	TemplateLessonBooked      Template = "lesson_booked"
+	TemplateLessonCancelled   Template = "lesson_cancelled"
+	TemplateLessonRescheduled Template = "lesson_rescheduled"
+	TemplateLessonReminder    Template = "lesson_reminder"
+	TemplateWeeklySummary     Template = "weekly_summary"
+)
+
+// LessonData is the data of the emails about a lesson
+type LessonData struct {
+	Name              string // Recipient
+	OtherName         string // Tutor for students, student for tutors; empty for group lessons seen by the tutor
+	Language          string
+	StartTime         string // Formatted with FormatTime
+	PreviousStartTime string // Time a rescheduled lesson was moved from, formatted with FormatTime
+	IsTutor           bool
+	Trial             bool
+	Group             bool
+	Soon              bool // The reminder is sent shortly before the lesson rather than the day before
+	LessonURL         string
+}
+
+// WeeklySummaryData is the data of the weekly summary email
+type WeeklySummaryData struct {
+	Name             string
+	LessonsCompleted int
+	UpcomingLessons  int
+	NextLesson       string // Formatted with FormatTime, empty if there is none
+	CurrentStreak    int
+	AppURL           string
+}
+
+//go:embed templates
+var templateFS embed.FS
+
+type templateSet struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// templates holds the parsed templates by "<locale>/<name>"
+var templates = mustParseTemplates()
+
+func mustParseTemplates() map[string]templateSet {
+	sets := make(map[string]templateSet)
+
+	err := fs.WalkDir(templateFS, "templates", func(file string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(file) != ".tmpl" {
+			return err
+		}
+
+		content, err := templateFS.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		locale := path.Base(path.Dir(file))
+		name := strings.TrimSuffix(path.Base(file), ".tmpl")
+
+		text, err := texttemplate.New(name).Parse(string(content))
+		if err != nil {
+			return err
+		}
+		html, err := htmltemplate.New(name).Parse(string(content))
+		if err != nil {
+			return err
+		}
+
+		sets[locale+"/"+name] = templateSet{text: text, html: html}
+		return nil
+	})
+	if err != nil {
+		panic(fmt.Sprintf("emails: failed to parse templates: %v", err))
+	}
+
+	return sets
+}
+
+// Render renders an email in the recipient's locale
+func Render(to, locale string, name Template, data interface{}) (*mailer.Message, error) {
+	set, ok := templates[locale+"/"+string(name)]
+	if !ok {
+		set, ok = templates[entities.DefaultLocale+"/"+string(name)]
+		if !ok {
+			return nil, fmt.Errorf("email template %q not found", name)
+		}
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := set.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, err
+	}
+	if err := set.text.ExecuteTemplate(&text, "text", data); err != nil {
+		return nil, err
+	}
+	if err := set.html.ExecuteTemplate(&html, "html", data); err != nil {
+		return nil, err
+	}
+
+	return &mailer.Message{
+		To:      to,
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+		HTML:    strings.TrimSpace(html.String()) + "\n",
+	}, nil
+}
+
+var (
+	weekdays = map[string][7]string{
+		"en": {"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		"ru": {"воскресенье", "понедельник", "вторник", "среда", "четверг", "пятница", "суббота"},
+		"es": {"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+	}
+	// Russian dates use the genitive form of the month
+	months = map[string][12]string{
+		"en": {"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		"ru": {"января", "февраля", "марта", "апреля", "мая", "июня", "июля", "августа", "сентября", "октября", "ноября", "декабря"},
+		"es": {"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+	}
+)
+
+// FormatTime formats a lesson time in UTC for an email in the given locale
+func FormatTime(locale string, t time.Time) string {
+	if _, ok := weekdays[locale]; !ok {
+		locale = entities.DefaultLocale
+	}
+
+	t = t.UTC()
+	weekday := weekdays[locale][t.Weekday()]
+	month := months[locale][t.Month()-1]
+	clock := t.Format("15:04")
+
+	switch locale {
+	case "ru":
+		return fmt.Sprintf("%s, %d %s %d, %s UTC", weekday, t.Day(), month, t.Year(), clock)
+	case "es":
+		return fmt.Sprintf("%s, %d de %s de %d, %s UTC", weekday, t.Day(), month, t.Year(), clock)
+	default:
+		return fmt.Sprintf("%s, %d %s %d at %s UTC", weekday, t.Day(), month, t.Year(), clock)
+	}
+}
@@ -0,0 +1,103 @@
+package entities
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrInvalidAnalyticsRange = errors.New("invalid analytics period")
+
+// Every period of the range is a row of the analytics, so the range is limited by period
+const (
+	MaxAnalyticsDays  = 366 // With daily periods
+	MaxAnalyticsYears = 5   // With weekly or monthly periods
+)
+
+// ValidateAnalyticsRange checks that analytics can be computed for [from, to) by period
+func ValidateAnalyticsRange(from, to time.Time, period EarningsPeriod) error {
+	if !from.Before(to) {
+		return ErrInvalidAnalyticsRange
+	}
+
+	var limit time.Time
+	switch period {
+	case EarningsPeriodDay:
+		limit = from.AddDate(0, 0, MaxAnalyticsDays)
+	case EarningsPeriodWeek, EarningsPeriodMonth:
+		limit = from.AddDate(MaxAnalyticsYears, 0, 0)
+	default:
+		return ErrInvalidAnalyticsRange
+	}
+	if to.After(limit) {
+		return ErrInvalidAnalyticsRange
+	}
+
+	return nil
+}
+
+// TutorEventType represents something students did with a tutor that tutor analytics count
+type TutorEventType string
+
+const (
+	TutorEventProfileView      TutorEventType = "profile_view"      // A student opened the tutor's profile
+	TutorEventSearchImpression TutorEventType = "search_impression" // The tutor was listed in search results
+)
+
+// TutorAnalyticsBucket represents a tutor's activity in one period. Cancellations and
+// bookings only count one-to-one lessons, since students leave group lessons by giving up
+// their seat rather than cancelling the lesson.
+type TutorAnalyticsBucket struct {
+	Key                string    `json:"key"`
+	Start              time.Time `json:"start"`
+	LessonsTaught      int       `json:"lessons_taught"`
+	HoursTaught        float64   `json:"hours_taught"`
+	ScheduledLessons   int       `json:"scheduled_lessons"` // One-to-one lessons due to start in the period, cancelled or not
+	CancelledByTutor   int       `json:"cancelled_by_tutor"`
+	CancelledByStudent int       `json:"cancelled_by_student"`
+	CancelledOther     int       `json:"cancelled_other"` // By an admin or automatically
+	Bookings           int       `json:"bookings"`        // One-to-one lessons booked in the period
+	ReviewsCount       int       `json:"reviews_count"`
+	AverageRating      *float64  `json:"average_rating,omitempty"` // Of the reviews left in the period
+	ProfileViews       int       `json:"profile_views"`
+	SearchImpressions  int       `json:"search_impressions"`
+}
+
+// CancellationStats represents how many of a tutor's scheduled lessons were cancelled, and by whom
+type CancellationStats struct {
+	Scheduled   int     `json:"scheduled"`
+	ByTutor     int     `json:"by_tutor"`
+	ByStudent   int     `json:"by_student"`
+	Other       int     `json:"other"`
+	Rate        float64 `json:"rate"`
+	TutorRate   float64 `json:"tutor_rate"`
+	StudentRate float64 `json:"student_rate"`
+	OtherRate   float64 `json:"other_rate"`
+}
+
+// TutorAnalyticsSummary represents a tutor's activity over a whole range
+type TutorAnalyticsSummary struct {
+	LessonsTaught      int               `json:"lessons_taught"`
+	HoursTaught        float64           `json:"hours_taught"`
+	Students           int               `json:"students"`        // Students taught in the range
+	RepeatStudents     int               `json:"repeat_students"` // Of those, the ones with more than one lesson with the tutor so far
+	RepeatStudentRatio float64           `json:"repeat_student_ratio"`
+	Cancellations      CancellationStats `json:"cancellations"`
+	ReviewsCount       int               `json:"reviews_count"`
+	AverageRating      *float64          `json:"average_rating,omitempty"`
+	ProfileViews       int               `json:"profile_views"`
+	SearchImpressions  int               `json:"search_impressions"`
+	Bookings           int               `json:"bookings"`
+	ViewRate           float64           `json:"view_rate"`          // Profile views per search impression
+	BookingConversion  float64           `json:"booking_conversion"` // Bookings per profile view
+}
+
+// TutorAnalytics represents how a tutor has been doing over a range, overall and period by
+// period. Periods are grouped like earnings.
+type TutorAnalytics struct {
+	From     time.Time              `json:"from"`
+	To       time.Time              `json:"to"`
+	Period   EarningsPeriod         `json:"period"`
+	Summary  TutorAnalyticsSummary  `json:"summary"`
+	ByPeriod []TutorAnalyticsBucket `json:"by_period"`
+	Trials   *TrialConversionStats  `json:"trials"` // All time
+}
@@ -0,0 +1,35 @@
+package entities
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidateAnalyticsRange(t *testing.T) {
+	from := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		to     time.Time
+		period EarningsPeriod
+		want   error
+	}{
+		{name: "a year by day", to: from.AddDate(0, 0, MaxAnalyticsDays), period: EarningsPeriodDay},
+		{name: "over a year by day", to: from.AddDate(0, 0, MaxAnalyticsDays+1), period: EarningsPeriodDay, want: ErrInvalidAnalyticsRange},
+		{name: "five years by week", to: from.AddDate(MaxAnalyticsYears, 0, 0), period: EarningsPeriodWeek},
+		{name: "five years by month", to: from.AddDate(MaxAnalyticsYears, 0, 0), period: EarningsPeriodMonth},
+		{name: "over five years by month", to: from.AddDate(MaxAnalyticsYears, 0, 1), period: EarningsPeriodMonth, want: ErrInvalidAnalyticsRange},
+		{name: "empty range", to: from, period: EarningsPeriodDay, want: ErrInvalidAnalyticsRange},
+		{name: "reversed range", to: from.AddDate(0, 0, -1), period: EarningsPeriodDay, want: ErrInvalidAnalyticsRange},
+		{name: "unknown period", to: from.AddDate(0, 1, 0), period: "hour", want: ErrInvalidAnalyticsRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateAnalyticsRange(from, tt.to, tt.period); !errors.Is(err, tt.want) {
+				t.Errorf("ValidateAnalyticsRange() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
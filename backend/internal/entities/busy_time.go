@@ -0,0 +1,100 @@
+package entities
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	ErrCalendarSourceNotFound = errors.New("calendar source not found")
+	ErrInvalidCalendarURL     = errors.New("calendar URL must be an http, https or webcal URL")
+	ErrCalendarTooLarge       = errors.New("calendar file is too large")
+	ErrCalendarUnavailable    = errors.New("calendar could not be fetched")
+	ErrTooManyCalendarSources = errors.New("too many calendar sources")
+)
+
+const (
+	// MaxCalendarSize is the maximum size of an imported .ics file
+	MaxCalendarSize = 5 << 20
+	// MaxCalendarSourcesPerTutor is the maximum number of external calendars of a tutor
+	MaxCalendarSourcesPerTutor = 10
+	// BusyTimeHorizon is how far ahead external calendars are expanded into busy times
+	BusyTimeHorizon = 180 * 24 * time.Hour
+	// CalendarSyncInterval is how often subscribed calendars are fetched again
+	CalendarSyncInterval = time.Hour
+)
+
+// CalendarSourceType represents how an external calendar was added
+type CalendarSourceType string
+
+const (
+	CalendarSourceURL    CalendarSourceType = "url"
+	CalendarSourceUpload CalendarSourceType = "upload"
+)
+
+// CalendarSource represents an external calendar whose events block a tutor's time
+type CalendarSource struct {
+	ID           int                `json:"id"`
+	TutorID      int                `json:"tutor_id"`
+	Name         string             `json:"name"`
+	Type         CalendarSourceType `json:"source_type"`
+	URL          *string            `json:"url,omitempty"`
+	LastSyncedAt *time.Time         `json:"last_synced_at,omitempty"`
+	LastError    *string            `json:"last_error,omitempty"`
+	BusyCount    int                `json:"busy_count"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+
+	// Content of uploaded calendars (not returned to clients)
+	Content *string `json:"-"`
+}
+
+// CalendarSourceRequest represents the request to subscribe to an external calendar
+type CalendarSourceRequest struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// Validate checks if the calendar source request is valid and normalizes webcal URLs to https
+func (r *CalendarSourceRequest) Validate() error {
+	name, err := NormalizeCalendarSourceName(r.Name)
+	if err != nil {
+		return err
+	}
+	r.Name = name
+
+	parsed, err := url.Parse(strings.TrimSpace(r.URL))
+	if err != nil || parsed.Host == "" {
+		return ErrInvalidCalendarURL
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "webcal", "webcals":
+		parsed.Scheme = "https"
+	case "http", "https":
+	default:
+		return ErrInvalidCalendarURL
+	}
+	r.URL = parsed.String()
+
+	return nil
+}
+
+// NormalizeCalendarSourceName trims the name of an external calendar, defaulting it if it is empty
+func NormalizeCalendarSourceName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "External calendar", nil
+	}
+	if len(name) > 100 {
+		return "", errors.New("name must be at most 100 characters")
+	}
+	return name, nil
+}
+
+// BusyTime represents an interval in which a tutor is busy according to an external calendar
+type BusyTime struct {
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
@@ -0,0 +1,59 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+)
+
+const (
+	// CalendarFeedPastWindow is how far back finished and cancelled lessons stay in the feed
+	CalendarFeedPastWindow = 30 * 24 * time.Hour
+	// CalendarFeedFutureWindow is how far ahead upcoming lessons are included in the feed
+	CalendarFeedFutureWindow = 365 * 24 * time.Hour
+)
+
+// CalendarFeed represents the secret subscription URL of a user's lesson calendar
+type CalendarFeed struct {
+	UserID    int       `json:"user_id"`
+	Token     string    `json:"token"`
+	URL       string    `json:"url"`
+	WebcalURL string    `json:"webcal_url"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CalendarEntry represents a lesson as it appears in a user's calendar
+type CalendarEntry struct {
+	LessonID    int
+	TutorID     int
+	Type        LessonType
+	StartTime   time.Time
+	EndTime     time.Time
+	Topic       string
+	Language    string
+	TutorName   string
+	StudentName string // Empty for group lessons
+	// Cancelled is set when the lesson was cancelled or, for group lessons, the user's seat was cancelled
+	Cancelled bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// CalendarUID returns the stable iCalendar UID of the lesson
+func (e *CalendarEntry) CalendarUID() string {
+	return fmt.Sprintf("lesson-%d@tongly", e.LessonID)
+}
+
+// CalendarSequence returns the iCalendar sequence number of the entry. It grows with every
+// update of the lesson so that calendar clients replace the previous version.
+func (e *CalendarEntry) CalendarSequence() int {
+	seconds := e.UpdatedAt.Sub(e.CreatedAt) / time.Second
+	if seconds < 0 {
+		return 0
+	}
+	return int(seconds)
+}
@@ -0,0 +1,118 @@
+package entities
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrCredentialNotFound      = errors.New("credential not found")
+	ErrInvalidCredentialKind   = errors.New("kind must be one of education, certificate")
+	ErrInvalidCredential       = errors.New("title and issuer are required and must be at most 200 characters")
+	ErrInvalidCredentialYear   = errors.New("year must be between 1950 and the current year")
+	ErrCredentialDocument      = errors.New("a proof document uploaded for a credential is required")
+	ErrTooManyCredentials      = errors.New("too many credentials")
+	ErrRejectionReasonRequired = errors.New("a reason is required to reject a credential")
+)
+
+const (
+	// MaxCredentialsPerTutor is the maximum number of credentials of a tutor
+	MaxCredentialsPerTutor = 20
+	// MinCredentialYear is the earliest year a credential can be from
+	MinCredentialYear = 1950
+
+	maxCredentialTextLength = 200
+)
+
+// CredentialKind represents what a tutor credential is
+type CredentialKind string
+
+const (
+	CredentialEducation   CredentialKind = "education"   // A degree from a school or university
+	CredentialCertificate CredentialKind = "certificate" // A language or teaching certificate, such as DELE or CELTA
+)
+
+// CredentialStatus represents where a credential is in the admin review
+type CredentialStatus string
+
+const (
+	CredentialPending  CredentialStatus = "pending"
+	CredentialVerified CredentialStatus = "verified"
+	CredentialRejected CredentialStatus = "rejected"
+)
+
+// TutorCredential represents a degree or certificate a tutor claims, with the document that
+// proves it. Document holds a signed link to the document for the tutor and admins only.
+type TutorCredential struct {
+	ID              int              `json:"id"`
+	TutorID         int              `json:"tutor_id"`
+	Kind            CredentialKind   `json:"kind"`
+	Title           string           `json:"title"`
+	Issuer          string           `json:"issuer"`
+	FieldOfStudy    string           `json:"field_of_study,omitempty"`
+	Year            *int             `json:"year,omitempty"`
+	DocumentID      int              `json:"document_id,omitempty"`
+	Document        *Upload          `json:"document,omitempty"`
+	Status          CredentialStatus `json:"status"`
+	RejectionReason *string          `json:"rejection_reason,omitempty"`
+	ReviewedAt      *time.Time       `json:"reviewed_at,omitempty"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+
+	// Related entities (not in the database)
+	Tutor *User `json:"tutor,omitempty"`
+}
+
+// CredentialRequest represents the request to add or change a credential. A change is
+// reviewed again.
+type CredentialRequest struct {
+	Kind         CredentialKind `json:"kind"`
+	Title        string         `json:"title"`
+	Issuer       string         `json:"issuer"`
+	FieldOfStudy string         `json:"field_of_study"`
+	Year         *int           `json:"year"`
+	DocumentID   int            `json:"document_id"`
+}
+
+// Validate checks the credential request and trims its text
+func (r *CredentialRequest) Validate() error {
+	switch r.Kind {
+	case CredentialEducation, CredentialCertificate:
+	default:
+		return ErrInvalidCredentialKind
+	}
+
+	r.Title = strings.TrimSpace(r.Title)
+	r.Issuer = strings.TrimSpace(r.Issuer)
+	r.FieldOfStudy = strings.TrimSpace(r.FieldOfStudy)
+	if r.Title == "" || r.Issuer == "" {
+		return ErrInvalidCredential
+	}
+	for _, text := range []string{r.Title, r.Issuer, r.FieldOfStudy} {
+		if len([]rune(text)) > maxCredentialTextLength {
+			return ErrInvalidCredential
+		}
+	}
+
+	if r.Year != nil && (*r.Year < MinCredentialYear || *r.Year > time.Now().Year()) {
+		return ErrInvalidCredentialYear
+	}
+	if r.DocumentID <= 0 {
+		return ErrCredentialDocument
+	}
+
+	return nil
+}
+
+// RejectCredentialRequest represents an admin's request to reject a credential
+type RejectCredentialRequest struct {
+	Reason string `json:"reason"`
+}
+
+// CredentialFilters represents filters for the credential review queue
+type CredentialFilters struct {
+	Status CredentialStatus `json:"status"`
+	Limit  int              `json:"limit"`
+	Offset int              `json:"offset"`
+}
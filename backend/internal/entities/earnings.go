@@ -0,0 +1,130 @@
+package entities
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// EarningsItemType represents the kind of an earnings line item
+type EarningsItemType string
+
+const (
+	EarningsItemLesson              EarningsItemType = "lesson"
+	EarningsItemLateCancellationFee EarningsItemType = "late_cancellation_fee"
+	EarningsItemNoShowFee           EarningsItemType = "no_show_fee"
+)
+
+// EarningsPeriod represents the granularity used to group earnings
+type EarningsPeriod string
+
+const (
+	EarningsPeriodDay   EarningsPeriod = "day"
+	EarningsPeriodWeek  EarningsPeriod = "week"
+	EarningsPeriodMonth EarningsPeriod = "month"
+)
+
+// PayoutStatus represents the status of a tutor payout
+type PayoutStatus string
+
+const (
+	PayoutStatusRequested PayoutStatus = "requested"
+	PayoutStatusPaid      PayoutStatus = "paid"
+	PayoutStatusRejected  PayoutStatus = "rejected"
+)
+
+var (
+	ErrInvalidPayoutAmount  = errors.New("payout amount must be positive")
+	ErrInsufficientBalance  = errors.New("payout amount exceeds available balance")
+	ErrInvalidEarningsRange = errors.New("invalid earnings period")
+)
+
+// EarningsLineItem represents a single amount earned by a tutor
+type EarningsLineItem struct {
+	LessonID     int              `json:"lesson_id"`
+	Type         EarningsItemType `json:"type"`
+	Amount       float64          `json:"amount"`
+	OccurredAt   time.Time        `json:"occurred_at"`
+	AvailableAt  time.Time        `json:"available_at"`
+	StudentID    int              `json:"student_id"`
+	StudentName  string           `json:"student_name"`
+	LanguageID   int              `json:"language_id"`
+	LanguageName string           `json:"language_name"`
+}
+
+// EarningsTotal represents the earnings aggregated under a single key (period, language or student)
+type EarningsTotal struct {
+	Key     string  `json:"key"`
+	Label   string  `json:"label"`
+	Amount  float64 `json:"amount"`
+	Lessons int     `json:"lessons"`
+	Fees    float64 `json:"fees"`
+}
+
+// TutorEarnings represents a tutor's earnings over a period of time
+type TutorEarnings struct {
+	From       time.Time          `json:"from"`
+	To         time.Time          `json:"to"`
+	Period     EarningsPeriod     `json:"period"`
+	Total      float64            `json:"total"`
+	ByPeriod   []EarningsTotal    `json:"by_period"`
+	ByLanguage []EarningsTotal    `json:"by_language"`
+	ByStudent  []EarningsTotal    `json:"by_student"`
+	LineItems  []EarningsLineItem `json:"line_items"`
+}
+
+// TutorPayout represents a payout of earnings to a tutor
+type TutorPayout struct {
+	ID          int          `json:"id"`
+	TutorID     int          `json:"tutor_id"`
+	Amount      float64      `json:"amount"`
+	Status      PayoutStatus `json:"status"`
+	RequestedAt time.Time    `json:"requested_at"`
+	ProcessedAt *time.Time   `json:"processed_at,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// TutorBalance represents a tutor's balances after the hold period is applied
+type TutorBalance struct {
+	Pending   float64 `json:"pending"`
+	Available float64 `json:"available"`
+	PaidOut   float64 `json:"paid_out"`
+	Earned    float64 `json:"earned"`
+}
+
+// PayoutLedgerEntry represents a single credit or debit in a tutor's payout ledger
+type PayoutLedgerEntry struct {
+	Type        string     `json:"type"`
+	Amount      float64    `json:"amount"`
+	OccurredAt  time.Time  `json:"occurred_at"`
+	AvailableAt *time.Time `json:"available_at,omitempty"`
+	LessonID    *int       `json:"lesson_id,omitempty"`
+	PayoutID    *int       `json:"payout_id,omitempty"`
+	Status      string     `json:"status"`
+}
+
+// PayoutLedger represents a tutor's balances together with the entries that produced them
+type PayoutLedger struct {
+	HoldDays int                 `json:"hold_days"`
+	Balance  TutorBalance        `json:"balance"`
+	Entries  []PayoutLedgerEntry `json:"entries"`
+}
+
+// PayoutRequest represents the data needed to request a payout
+type PayoutRequest struct {
+	Amount float64 `json:"amount"`
+}
+
+// Validate checks if the payout request is valid
+func (r *PayoutRequest) Validate() error {
+	if r.Amount <= 0 {
+		return ErrInvalidPayoutAmount
+	}
+	return nil
+}
+
+// RoundMoney rounds an amount to whole cents
+func RoundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
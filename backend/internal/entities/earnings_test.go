@@ -0,0 +1,45 @@
+package entities
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRoundMoney(t *testing.T) {
+	tests := []struct {
+		amount float64
+		want   float64
+	}{
+		{amount: 10, want: 10},
+		{amount: 12.345, want: 12.35},
+		{amount: 12.344, want: 12.34},
+		{amount: 0.005, want: 0.01},
+		{amount: 29.999999, want: 30},
+		{amount: -4.125, want: -4.13},
+	}
+
+	for _, tt := range tests {
+		if got := RoundMoney(tt.amount); got != tt.want {
+			t.Errorf("RoundMoney(%v) = %v, want %v", tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestPayoutRequestValidate(t *testing.T) {
+	tests := []struct {
+		amount float64
+		want   error
+	}{
+		{amount: 50},
+		{amount: 0.01},
+		{amount: 0, want: ErrInvalidPayoutAmount},
+		{amount: -10, want: ErrInvalidPayoutAmount},
+	}
+
+	for _, tt := range tests {
+		req := PayoutRequest{Amount: tt.amount}
+		if err := req.Validate(); !errors.Is(err, tt.want) {
+			t.Errorf("Validate() with amount %v = %v, want %v", tt.amount, err, tt.want)
+		}
+	}
+}
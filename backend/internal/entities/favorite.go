@@ -0,0 +1,74 @@
+package entities
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrSavedSearchNotFound  = errors.New("saved search not found")
+	ErrInvalidSavedSearch   = errors.New("saved search name is required and must be at most 100 characters")
+	ErrTooManySavedSearches = errors.New("too many saved searches")
+	ErrTooManyFavorites     = errors.New("too many favorite tutors")
+)
+
+const (
+	// MaxSavedSearchesPerStudent is the maximum number of searches a student can save
+	MaxSavedSearchesPerStudent = 20
+	// MaxFavoriteTutors is the maximum number of tutors a student can shortlist
+	MaxFavoriteTutors = 200
+	// MaxSavedSearchAlertTutors is the most new tutors a single saved search alert lists
+	MaxSavedSearchAlertTutors = 5
+)
+
+// FavoriteTutor represents a tutor on a student's shortlist
+type FavoriteTutor struct {
+	Tutor   TutorProfile `json:"tutor"`
+	AddedAt time.Time    `json:"added_at"`
+}
+
+// SavedSearch represents tutor search filters a student saved to run again. With Notify set
+// the student is alerted when new tutors match them.
+type SavedSearch struct {
+	ID        int                `json:"id"`
+	StudentID int                `json:"student_id"`
+	Name      string             `json:"name"`
+	Filters   TutorSearchFilters `json:"filters"`
+	Notify    bool               `json:"notify"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// SavedSearchRequest represents the request to save or change a search. Notify defaults to true.
+type SavedSearchRequest struct {
+	Name    string             `json:"name"`
+	Filters TutorSearchFilters `json:"filters"`
+	Notify  *bool              `json:"notify"`
+}
+
+// Validate checks the name of the saved search and drops the paging of its filters, which
+// is not part of what was searched for. The filters themselves are checked like any search.
+func (r *SavedSearchRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" || len([]rune(r.Name)) > 100 {
+		return ErrInvalidSavedSearch
+	}
+	r.Filters.Limit = 0
+	r.Filters.Offset = 0
+	return nil
+}
+
+// SavedSearchMatch represents the tutors newly matching a saved search
+type SavedSearchMatch struct {
+	Search   SavedSearch
+	TutorIDs []int
+}
+
+// FavoriteAvailability represents a shortlisted tutor who added or changed their availability
+// since the student was last told about it
+type FavoriteAvailability struct {
+	StudentID int
+	TutorID   int
+	CheckedAt time.Time
+}
@@ -57,3 +57,13 @@ type SaveGameResultRequest struct {
 	LanguageID int    `json:"language_id" validate:"required"`
 	Score      int    `json:"score" validate:"required,min=0,max=100"`
 }
+
+// StreakReminderHour is the hour (UTC) after which students who have not played yet today are
+// reminded that their streak is about to end
+const StreakReminderHour = 18
+
+// StreakAtRisk represents a student who played yesterday but not yet today
+type StreakAtRisk struct {
+	UserID        int `json:"user_id"`
+	CurrentStreak int `json:"current_streak"`
+}
@@ -0,0 +1,95 @@
+package entities
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrGroupClassNotFound = errors.New("group class not found")
+	ErrInvalidTimeWindow  = errors.New("the end of the time window must be after its start")
+	ErrInvalidGroupClass  = errors.New("invalid group class")
+	ErrUnknownProficiency = errors.New("unknown proficiency level")
+	ErrUnknownInterest    = errors.New("unknown interest")
+)
+
+// GroupClass represents a group lesson published in the public catalog
+type GroupClass struct {
+	LessonID           int        `json:"lesson_id"`
+	TutorID            int        `json:"tutor_id"`
+	LanguageID         int        `json:"language_id"`
+	Topic              string     `json:"topic"`
+	Description        string     `json:"description"`
+	MinProficiencyID   int        `json:"min_proficiency_id"`
+	MaxProficiencyID   int        `json:"max_proficiency_id"`
+	LevelRange         string     `json:"level_range"` // e.g. "A2–B1"
+	StartTime          time.Time  `json:"start_time"`
+	EndTime            time.Time  `json:"end_time"`
+	EnrollmentDeadline *time.Time `json:"enrollment_deadline,omitempty"`
+	Capacity           int        `json:"capacity"`
+	MinParticipants    int        `json:"min_participants"`
+	SeatsTaken         int        `json:"seats_taken"`
+	SeatsLeft          int        `json:"seats_left"`
+	Price              float64    `json:"price"`
+	CreatedAt          time.Time  `json:"created_at"`
+
+	// Related entities
+	Tutor          *User                `json:"tutor,omitempty"`
+	Language       *Language            `json:"language,omitempty"`
+	MinProficiency *LanguageProficiency `json:"min_proficiency,omitempty"`
+	MaxProficiency *LanguageProficiency `json:"max_proficiency,omitempty"`
+	Interests      []Interest           `json:"interests"`
+}
+
+// GroupClassRequest represents the data needed for a tutor to publish a group class
+type GroupClassRequest struct {
+	GroupLessonRequest
+	Topic            string `json:"topic"`
+	Description      string `json:"description"`
+	MinProficiencyID int    `json:"min_proficiency_id"`
+	MaxProficiencyID int    `json:"max_proficiency_id"`
+	InterestIDs      []int  `json:"interest_ids"`
+}
+
+// Validate checks if the group class request is valid
+func (r *GroupClassRequest) Validate() error {
+	if err := r.GroupLessonRequest.Validate(); err != nil {
+		return err
+	}
+
+	r.Topic = strings.TrimSpace(r.Topic)
+	if r.Topic == "" {
+		return errors.New("topic is required")
+	}
+	if len(r.Topic) > 200 {
+		return errors.New("topic must be at most 200 characters")
+	}
+
+	if r.MinProficiencyID <= 0 || r.MaxProficiencyID <= 0 {
+		return errors.New("level range is required")
+	}
+	if r.MinProficiencyID > r.MaxProficiencyID {
+		return errors.New("minimum level must not be above the maximum level")
+	}
+
+	return nil
+}
+
+// GroupClassFilters represents filters for browsing the group class catalog
+type GroupClassFilters struct {
+	Languages     []string   `json:"languages"`      // Filter by language names
+	ProficiencyID int        `json:"proficiency_id"` // Only classes whose level range includes this level
+	Interests     []int      `json:"interests"`      // Filter by interest IDs
+	From          *time.Time `json:"from,omitempty"` // Only classes starting at or after this time
+	To            *time.Time `json:"to,omitempty"`   // Only classes starting before this time
+	TutorID       int        `json:"tutor_id,omitempty"`
+}
+
+// FormatLevelRange formats a proficiency range such as "A2–B1", or a single level if both ends are equal
+func FormatLevelRange(minLevel, maxLevel string) string {
+	if minLevel == maxLevel {
+		return minLevel
+	}
+	return minLevel + "–" + maxLevel
+}
@@ -0,0 +1,143 @@
+package entities
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var (
+	ErrJobNotFound     = errors.New("job not found")
+	ErrJobNotRetryable = errors.New("only failed jobs can be retried")
+	ErrJobLeaseLost    = errors.New("job is no longer leased by this worker")
+)
+
+const (
+	// DefaultJobMaxAttempts is the number of attempts of a job unless the job type asks for another one
+	DefaultJobMaxAttempts = 5
+	// JobBaseBackoff is the delay before the first retry, doubled on every further attempt
+	JobBaseBackoff = 30 * time.Second
+	// JobMaxBackoff caps the delay between retries
+	JobMaxBackoff = time.Hour
+	// JobRetention is how long succeeded jobs are kept before they are pruned
+	JobRetention = 7 * 24 * time.Hour
+)
+
+// JobType identifies the handler of a job
+type JobType string
+
+const (
+	JobTypeLessonReminder  JobType = "lesson_reminder"
+	JobTypeStreakReminders JobType = "streak_reminders"
+	JobTypeSendEmail       JobType = "send_email"
+	JobTypeWeeklySummaries JobType = "weekly_summaries"
+	JobTypeProcessAvatar   JobType = "process_avatar"
+	JobTypeTutorAlerts     JobType = "tutor_alerts"
+
+	// Recurring jobs, enqueued by every replica's scheduler once per interval
+	JobTypeCancelUnderfilledLessons JobType = "cancel_underfilled_lessons"
+	JobTypeSyncCalendars            JobType = "sync_calendars"
+	JobTypeScheduleReminders        JobType = "schedule_lesson_reminders"
+	JobTypeScheduleStreakReminders  JobType = "schedule_streak_reminders"
+	JobTypeScheduleWeeklySummaries  JobType = "schedule_weekly_summaries"
+	JobTypeRefreshRatingStats       JobType = "refresh_rating_stats"
+	JobTypePruneJobs                JobType = "prune_jobs"
+)
+
+// JobStatus represents the state of a job
+type JobStatus string
+
+const (
+	JobStatusPending   JobStatus = "pending"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSucceeded JobStatus = "succeeded"
+	JobStatusFailed    JobStatus = "failed" // All attempts used up
+)
+
+// Job represents a unit of background work stored in the jobs table
+type Job struct {
+	ID          int64           `json:"id"`
+	Type        JobType         `json:"job_type"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      JobStatus       `json:"status"`
+	DedupKey    *string         `json:"dedup_key,omitempty"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	RunAt       time.Time       `json:"run_at"`
+	LockedBy    *string         `json:"locked_by,omitempty"`
+	LockedUntil *time.Time      `json:"locked_until,omitempty"`
+	LastError   *string         `json:"last_error,omitempty"`
+	CompletedAt *time.Time      `json:"completed_at,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// JobRequest represents a job to be enqueued
+type JobRequest struct {
+	Type    JobType
+	Payload interface{}
+	RunAt   time.Time
+	// DedupKey makes enqueueing idempotent: a job with the same key is only stored once
+	DedupKey    string
+	MaxAttempts int
+}
+
+// JobFilters represents filters for listing jobs
+type JobFilters struct {
+	Status JobStatus `json:"status,omitempty"`
+	Type   JobType   `json:"job_type,omitempty"`
+	Limit  int       `json:"limit"`
+	Offset int       `json:"offset"`
+}
+
+// JobList represents a page of jobs together with the number of jobs per status
+type JobList struct {
+	Jobs   []Job             `json:"jobs"`
+	Counts map[JobStatus]int `json:"counts"`
+}
+
+// JobBackoff returns the delay before the next attempt of a job that failed its n-th attempt
+func JobBackoff(attempt int) time.Duration {
+	backoff := JobBaseBackoff
+	for i := 1; i < attempt && backoff < JobMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > JobMaxBackoff {
+		backoff = JobMaxBackoff
+	}
+	return backoff
+}
+
+// ReminderKind identifies how long before a lesson a reminder is sent
+type ReminderKind string
+
+const (
+	Reminder24h ReminderKind = "24h"
+	Reminder15m ReminderKind = "15m"
+)
+
+// ReminderOffsets lists the reminders sent before every lesson
+var ReminderOffsets = map[ReminderKind]time.Duration{
+	Reminder24h: 24 * time.Hour,
+	Reminder15m: 15 * time.Minute,
+}
+
+// LessonReminderPayload is the payload of a lesson reminder job. The start time is kept so that
+// reminders of a lesson that has been moved since are dropped.
+type LessonReminderPayload struct {
+	LessonID  int          `json:"lesson_id"`
+	Kind      ReminderKind `json:"kind"`
+	StartTime time.Time    `json:"start_time"`
+}
+
+// WeeklySummaryPayload is the payload of the job that emails the weekly summaries. The summaries
+// cover the week before and after WeekStart.
+type WeeklySummaryPayload struct {
+	WeekStart time.Time `json:"week_start"`
+}
+
+// ProcessAvatarPayload is the payload of the job that makes the variants of an uploaded avatar
+// and sets it as the user's profile picture
+type ProcessAvatarPayload struct {
+	UploadID int `json:"upload_id"`
+}
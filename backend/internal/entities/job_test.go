@@ -0,0 +1,24 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJobBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: JobBaseBackoff},
+		{attempt: 2, want: 2 * JobBaseBackoff},
+		{attempt: 3, want: 4 * JobBaseBackoff},
+		{attempt: 100, want: JobMaxBackoff},
+	}
+
+	for _, tt := range tests {
+		if got := JobBackoff(tt.attempt); got != tt.want {
+			t.Errorf("JobBackoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
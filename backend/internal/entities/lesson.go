@@ -16,28 +16,66 @@ type Lesson struct {
 	CancelledBy *int       `json:"cancelled_by,omitempty"`
 	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
 	Notes       *string    `json:"notes,omitempty"`
+	Type        LessonType `json:"lesson_type"`
+	Price       float64    `json:"price"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
 
+	// Fees charged instead of (or in addition to) the lesson price
+	CancellationFee *float64 `json:"cancellation_fee,omitempty"`
+	StudentNoShow   bool     `json:"student_no_show"`
+	NoShowFee       *float64 `json:"no_show_fee,omitempty"`
+
+	// Group lessons only: StudentID is 0 and Price is the price of a single seat
+	Capacity           *int       `json:"capacity,omitempty"`
+	MinParticipants    *int       `json:"min_participants,omitempty"`
+	EnrollmentDeadline *time.Time `json:"enrollment_deadline,omitempty"`
+	ParticipantsCount  int        `json:"participants_count"`
+
 	// Related entities (not in the database)
 	Student  *User     `json:"student,omitempty"`
 	Tutor    *User     `json:"tutor,omitempty"`
 	Language *Language `json:"language,omitempty"`
 	Reviews  []Review  `json:"reviews,omitempty"`
+
+	Participants []LessonParticipant `json:"participants,omitempty"`
 }
 
-// Review represents a lesson review
-type Review struct {
-	ID         int       `json:"id"`
-	LessonID   int       `json:"lesson_id"`
-	ReviewerID int       `json:"reviewer_id"`
-	Rating     int       `json:"rating"`
-	CreatedAt  time.Time `json:"created_at"`
+// LessonParticipant represents a student's seat in a group lesson
+type LessonParticipant struct {
+	ID              int        `json:"id"`
+	LessonID        int        `json:"lesson_id"`
+	StudentID       int        `json:"student_id"`
+	Price           float64    `json:"price"`
+	CancelledAt     *time.Time `json:"cancelled_at,omitempty"`
+	CancellationFee *float64   `json:"cancellation_fee,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
 
 	// Related entities (not in the database)
-	Reviewer *User `json:"reviewer,omitempty"`
+	Student *User `json:"student,omitempty"`
 }
 
+// LessonType represents the kind of a lesson
+type LessonType string
+
+const (
+	LessonTypeRegular LessonType = "regular"
+	LessonTypeTrial   LessonType = "trial"
+	LessonTypeGroup   LessonType = "group"
+)
+
+// TrialLessonDuration is the default (and maximum) length of a trial lesson
+const TrialLessonDuration = 30 * time.Minute
+
+const (
+	// MaxGroupLessonCapacity is the largest number of seats a group lesson can have
+	MaxGroupLessonCapacity = 20
+	// DefaultEnrollmentDeadline is how long before the start of a group lesson the
+	// minimum number of participants must be reached, unless the tutor sets a deadline
+	DefaultEnrollmentDeadline = 24 * time.Hour
+)
+
 // LessonStatus represents the status of a lesson (derived from timestamps)
 type LessonStatus string
 
@@ -48,11 +86,34 @@ const (
 	LessonStatusCancelled  LessonStatus = "cancelled"
 )
 
+const (
+	// LateCancellationWindow is the period before the start of a lesson in which
+	// a cancellation by the student is charged a fee
+	LateCancellationWindow = 24 * time.Hour
+	// LateCancellationFeeRate is the share of the lesson price charged for a late cancellation
+	LateCancellationFeeRate = 0.5
+	// NoShowFeeRate is the share of the lesson price charged when the student does not show up
+	NoShowFeeRate = 1.0
+)
+
 var (
 	ErrInvalidStatusTransition = errors.New("invalid lesson status transition")
 	ErrLessonNotCancellable    = errors.New("lesson cannot be cancelled at this time")
+	ErrLessonNotReschedulable  = errors.New("lesson cannot be rescheduled at this time")
+	ErrInvalidRescheduleTime   = errors.New("a rescheduled lesson must start in the future")
 	ErrLessonNotStartable      = errors.New("lesson cannot be started at this time")
 	ErrLessonNotEndable        = errors.New("lesson cannot be ended at this time")
+	ErrNoShowNotAllowed        = errors.New("no-show cannot be reported for this lesson")
+	ErrTrialNotOffered         = errors.New("tutor does not offer trial lessons")
+	ErrTrialAlreadyBooked      = errors.New("a trial lesson with this tutor has already been booked")
+	ErrTrialTooLong            = errors.New("trial lessons cannot be longer than 30 minutes")
+	ErrNotGroupLesson          = errors.New("lesson is not a group lesson")
+	ErrGroupLessonFull         = errors.New("group lesson is full")
+	ErrAlreadyEnrolled         = errors.New("student is already enrolled in this lesson")
+	ErrNotEnrolled             = errors.New("student is not enrolled in this lesson")
+	ErrEnrollmentClosed        = errors.New("enrollment for this lesson is closed")
+	ErrTutorBusy               = errors.New("tutor is busy at the requested time")
+	ErrLanguageNotFound        = errors.New("language not found")
 )
 
 // GetStatus returns the virtual status of the lesson based on time and cancelled flag
@@ -85,14 +146,110 @@ func (l *Lesson) CanCancel() error {
 		return ErrLessonNotCancellable
 	}
 
-	// Must be at least 24 hours before the start
-	if time.Until(l.StartTime) < 24*time.Hour {
-		return ErrLessonNotCancellable
+	return nil
+}
+
+// CanReschedule checks if the lesson can be moved to another time. Lessons inside the late
+// cancellation window keep their time, as moving them would avoid the late cancellation fee.
+// Group lessons cannot be moved, since their participants booked seats at the announced time.
+func (l *Lesson) CanReschedule() error {
+	if l.CancelledAt != nil || l.IsGroup() {
+		return ErrLessonNotReschedulable
+	}
+
+	if l.IsLateCancellation() {
+		return ErrLessonNotReschedulable
 	}
 
 	return nil
 }
 
+// IsLateCancellation checks if cancelling the lesson now falls into the late cancellation window
+func (l *Lesson) IsLateCancellation() bool {
+	return time.Until(l.StartTime) < LateCancellationWindow
+}
+
+// LateCancellationFee returns the fee charged to the student for a late cancellation
+func (l *Lesson) LateCancellationFee() float64 {
+	return RoundMoney(l.Price * LateCancellationFeeRate)
+}
+
+// CanReportNoShow checks if the student can be reported as a no-show
+func (l *Lesson) CanReportNoShow() error {
+	if l.CancelledAt != nil || l.StudentNoShow || l.IsGroup() {
+		return ErrNoShowNotAllowed
+	}
+
+	// The student can only be reported once the lesson has started
+	if time.Now().Before(l.StartTime) {
+		return ErrNoShowNotAllowed
+	}
+
+	return nil
+}
+
+// NoShowFeeAmount returns the fee charged to the student for not showing up
+func (l *Lesson) NoShowFeeAmount() float64 {
+	return RoundMoney(l.Price * NoShowFeeRate)
+}
+
+// IsGroup checks if the lesson is a group lesson
+func (l *Lesson) IsGroup() bool {
+	return l.Type == LessonTypeGroup
+}
+
+// IsAttendee checks if the user is the tutor, the student or (if participants are loaded) a participant of the lesson
+func (l *Lesson) IsAttendee(userID int) bool {
+	if l.TutorID == userID || (l.StudentID != 0 && l.StudentID == userID) {
+		return true
+	}
+
+	for _, participant := range l.Participants {
+		if participant.StudentID == userID && participant.CancelledAt == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanEnroll checks if a seat in the group lesson can be booked
+func (l *Lesson) CanEnroll() error {
+	if !l.IsGroup() {
+		return ErrNotGroupLesson
+	}
+
+	now := time.Now()
+	if l.CancelledAt != nil || !now.Before(l.StartTime) {
+		return ErrEnrollmentClosed
+	}
+
+	// Lessons still short of participants at the deadline are cancelled, so no later seats are sold
+	if l.EnrollmentDeadline != nil && !now.Before(*l.EnrollmentDeadline) {
+		return ErrEnrollmentClosed
+	}
+
+	if l.Capacity != nil && l.ParticipantsCount >= *l.Capacity {
+		return ErrGroupLessonFull
+	}
+
+	return nil
+}
+
+// SeatCancellationFee returns the fee charged for cancelling a seat in a group lesson now,
+// or nil if the cancellation is free
+func (l *Lesson) SeatCancellationFee(seat *LessonParticipant) *float64 {
+	if !l.IsLateCancellation() {
+		return nil
+	}
+
+	fee := RoundMoney(seat.Price * LateCancellationFeeRate)
+	if fee <= 0 {
+		return nil
+	}
+	return &fee
+}
+
 // CanStart checks if the lesson can be started
 func (l *Lesson) CanStart() error {
 	if l.CancelledAt != nil {
@@ -128,13 +285,30 @@ func (l *Lesson) CanEnd() error {
 	return nil
 }
 
+// CalculateLessonPrice returns the price of a lesson of the given length at an hourly rate
+func CalculateLessonPrice(hourlyRate float64, startTime, endTime time.Time) float64 {
+	return RoundMoney(hourlyRate * endTime.Sub(startTime).Hours())
+}
+
 // LessonBookingRequest represents the data needed to book a new lesson
 type LessonBookingRequest struct {
-	TutorID    int       `json:"tutor_id"`
-	LanguageID int       `json:"language_id"`
-	StartTime  time.Time `json:"start_time"`
-	EndTime    time.Time `json:"end_time"`
-	Notes      *string   `json:"notes,omitempty"`
+	TutorID    int        `json:"tutor_id"`
+	LanguageID int        `json:"language_id"`
+	Type       LessonType `json:"lesson_type,omitempty"`
+	StartTime  time.Time  `json:"start_time"`
+	EndTime    time.Time  `json:"end_time"`
+	Notes      *string    `json:"notes,omitempty"`
+}
+
+// ApplyDefaults fills in the lesson type and, for trial lessons, the end time if they were omitted
+func (r *LessonBookingRequest) ApplyDefaults() {
+	if r.Type == "" {
+		r.Type = LessonTypeRegular
+	}
+
+	if r.Type == LessonTypeTrial && r.EndTime.IsZero() && !r.StartTime.IsZero() {
+		r.EndTime = r.StartTime.Add(TrialLessonDuration)
+	}
 }
 
 // Validate checks if the booking request is valid
@@ -163,6 +337,16 @@ func (r *LessonBookingRequest) Validate() error {
 		return errors.New("start time must be in the future")
 	}
 
+	switch r.Type {
+	case LessonTypeRegular:
+	case LessonTypeTrial:
+		if r.EndTime.Sub(r.StartTime) > TrialLessonDuration {
+			return ErrTrialTooLong
+		}
+	default:
+		return errors.New("invalid lesson type")
+	}
+
 	return nil
 }
 
@@ -178,3 +362,96 @@ func (r *LessonCancellationRequest) Validate() error {
 	}
 	return nil
 }
+
+// LessonRescheduleRequest represents the data needed to move a lesson to another time.
+// The lesson keeps its duration.
+type LessonRescheduleRequest struct {
+	StartTime time.Time `json:"start_time"`
+}
+
+// Validate checks if the reschedule request is valid
+func (r *LessonRescheduleRequest) Validate() error {
+	if r.StartTime.IsZero() || r.StartTime.Before(time.Now()) {
+		return ErrInvalidRescheduleTime
+	}
+	return nil
+}
+
+// GroupLessonRequest represents the data needed for a tutor to schedule a group lesson
+type GroupLessonRequest struct {
+	LanguageID         int        `json:"language_id"`
+	StartTime          time.Time  `json:"start_time"`
+	EndTime            time.Time  `json:"end_time"`
+	Capacity           int        `json:"capacity"`
+	MinParticipants    int        `json:"min_participants"`
+	EnrollmentDeadline *time.Time `json:"enrollment_deadline,omitempty"`
+	Price              float64    `json:"price"` // Price of a single seat
+	Notes              *string    `json:"notes,omitempty"`
+}
+
+// ApplyDefaults fills in the minimum number of participants and the enrollment deadline if they were omitted
+func (r *GroupLessonRequest) ApplyDefaults() {
+	if r.MinParticipants == 0 {
+		r.MinParticipants = 1
+	}
+
+	if r.EnrollmentDeadline == nil && !r.StartTime.IsZero() {
+		deadline := r.StartTime.Add(-DefaultEnrollmentDeadline)
+		if deadline.Before(time.Now()) {
+			deadline = r.StartTime
+		}
+		r.EnrollmentDeadline = &deadline
+	}
+}
+
+// Validate checks if the group lesson request is valid
+func (r *GroupLessonRequest) Validate() error {
+	if r.LanguageID <= 0 {
+		return errors.New("invalid language ID")
+	}
+
+	if r.StartTime.IsZero() || r.EndTime.IsZero() {
+		return errors.New("start and end time are required")
+	}
+
+	if !r.StartTime.Before(r.EndTime) {
+		return errors.New("start time must be before end time")
+	}
+
+	if r.StartTime.Before(time.Now()) {
+		return errors.New("start time must be in the future")
+	}
+
+	if r.Capacity < 2 || r.Capacity > MaxGroupLessonCapacity {
+		return errors.New("capacity must be between 2 and 20")
+	}
+
+	if r.MinParticipants < 1 || r.MinParticipants > r.Capacity {
+		return errors.New("minimum participants must be between 1 and the capacity")
+	}
+
+	if r.EnrollmentDeadline == nil || r.EnrollmentDeadline.After(r.StartTime) {
+		return errors.New("enrollment deadline must not be after the start time")
+	}
+
+	if r.Price < 0 {
+		return errors.New("price cannot be negative")
+	}
+
+	return nil
+}
+
+// LessonRoomAccess describes a user's admission to the video room of a lesson
+type LessonRoomAccess struct {
+	LessonID        int    `json:"lesson_id"`
+	UserID          int    `json:"user_id"`
+	Role            string `json:"role"` // "tutor" or "student"
+	MaxParticipants int    `json:"max_participants"`
+}
+
+// LessonRoomTicket admits a user to the video room of a lesson until it expires. It is passed
+// to the video service in place of the login token.
+type LessonRoomTicket struct {
+	Ticket    string    `json:"ticket"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,253 @@
+package entities
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrLessonNotesNotFound = errors.New("lesson notes not found")
+	ErrHomeworkNotFound    = errors.New("homework not found")
+	ErrNotLessonTutor      = errors.New("only the tutor of the lesson can do this")
+	ErrNotLessonAttendee   = errors.New("user is not an attendee of this lesson")
+	ErrLessonNotesClosed   = errors.New("notes and homework cannot be added to a cancelled lesson")
+	ErrInvalidLessonNotes  = errors.New("lesson notes are too long or have too many entries")
+	ErrInvalidHomework     = errors.New("homework needs a description of at most 2000 characters")
+	ErrTooMuchHomework     = errors.New("too much homework for one lesson")
+	ErrInvalidFeedback     = errors.New("feedback needs a rating between 1 and 5 and at most 2000 characters")
+	ErrFeedbackNotOpen     = errors.New("feedback can only be given after the lesson is completed")
+	ErrFeedbackStudent     = errors.New("feedback must be given to a student of the lesson")
+)
+
+// MaxHomeworkPerLesson is the maximum number of assignments given in one lesson
+const MaxHomeworkPerLesson = 20
+
+const (
+	maxNotesTextLength    = 10000
+	maxNotesEntries       = 200
+	maxNotesEntryLength   = 500
+	maxCorrectionsEntries = 100
+	maxHomeworkLength     = 2000
+	maxFeedbackLength     = 2000
+)
+
+// VocabularyItem represents a word or phrase introduced in a lesson
+type VocabularyItem struct {
+	Term        string `json:"term"`
+	Translation string `json:"translation,omitempty"`
+	Example     string `json:"example,omitempty"`
+}
+
+// Correction represents a mistake the student made and its correction
+type Correction struct {
+	Original    string `json:"original"`
+	Corrected   string `json:"corrected"`
+	Explanation string `json:"explanation,omitempty"`
+}
+
+// LessonNotes represents the structured notes a tutor writes after a lesson.
+// PrivateNotes are only ever returned to the tutor.
+type LessonNotes struct {
+	ID           int              `json:"id"`
+	LessonID     int              `json:"lesson_id"`
+	TutorID      int              `json:"tutor_id"`
+	Summary      string           `json:"summary"`
+	Topics       []string         `json:"topics"`
+	Vocabulary   []VocabularyItem `json:"vocabulary"`
+	Corrections  []Correction     `json:"corrections"`
+	PrivateNotes *string          `json:"private_notes,omitempty"`
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+}
+
+// LessonNotesRequest represents the tutor's request to write the notes of a lesson.
+// PrivateNotes are left unchanged when omitted and removed when empty.
+type LessonNotesRequest struct {
+	Summary      string           `json:"summary"`
+	Topics       []string         `json:"topics"`
+	Vocabulary   []VocabularyItem `json:"vocabulary"`
+	Corrections  []Correction     `json:"corrections"`
+	PrivateNotes *string          `json:"private_notes"`
+}
+
+// Validate checks the size of the lesson notes and drops empty entries
+func (r *LessonNotesRequest) Validate() error {
+	r.Summary = strings.TrimSpace(r.Summary)
+	if utf8.RuneCountInString(r.Summary) > maxNotesTextLength {
+		return ErrInvalidLessonNotes
+	}
+	if r.PrivateNotes != nil {
+		trimmed := strings.TrimSpace(*r.PrivateNotes)
+		if utf8.RuneCountInString(trimmed) > maxNotesTextLength {
+			return ErrInvalidLessonNotes
+		}
+		r.PrivateNotes = &trimmed
+	}
+
+	topics := []string{}
+	for _, topic := range r.Topics {
+		if topic = strings.TrimSpace(topic); topic != "" {
+			topics = append(topics, topic)
+		}
+	}
+	r.Topics = topics
+
+	vocabulary := []VocabularyItem{}
+	for _, item := range r.Vocabulary {
+		item.Term = strings.TrimSpace(item.Term)
+		if item.Term != "" {
+			vocabulary = append(vocabulary, item)
+		}
+	}
+	r.Vocabulary = vocabulary
+
+	corrections := []Correction{}
+	for _, correction := range r.Corrections {
+		correction.Original = strings.TrimSpace(correction.Original)
+		correction.Corrected = strings.TrimSpace(correction.Corrected)
+		if correction.Original != "" || correction.Corrected != "" {
+			corrections = append(corrections, correction)
+		}
+	}
+	r.Corrections = corrections
+
+	if len(r.Topics) > maxNotesEntries || len(r.Vocabulary) > maxNotesEntries || len(r.Corrections) > maxCorrectionsEntries {
+		return ErrInvalidLessonNotes
+	}
+	for _, topic := range r.Topics {
+		if utf8.RuneCountInString(topic) > maxNotesEntryLength {
+			return ErrInvalidLessonNotes
+		}
+	}
+	for _, item := range r.Vocabulary {
+		if utf8.RuneCountInString(item.Term+item.Translation+item.Example) > maxNotesEntryLength*3 {
+			return ErrInvalidLessonNotes
+		}
+	}
+	for _, correction := range r.Corrections {
+		if utf8.RuneCountInString(correction.Original+correction.Corrected+correction.Explanation) > maxNotesEntryLength*3 {
+			return ErrInvalidLessonNotes
+		}
+	}
+
+	return nil
+}
+
+// HomeworkCompletion records when a student marked homework as done
+type HomeworkCompletion struct {
+	StudentID   int       `json:"student_id"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// Homework represents an assignment given in a lesson. Students see their own CompletedAt,
+// tutors see the Completions of every student of the lesson.
+type Homework struct {
+	ID          int                  `json:"id"`
+	LessonID    int                  `json:"lesson_id"`
+	TutorID     int                  `json:"tutor_id"`
+	Description string               `json:"description"`
+	DueAt       *time.Time           `json:"due_at,omitempty"`
+	CompletedAt *time.Time           `json:"completed_at,omitempty"`
+	Completions []HomeworkCompletion `json:"completions,omitempty"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+}
+
+// IsOverdue checks if the homework is past its due date without being done
+func (h *Homework) IsOverdue(now time.Time) bool {
+	return h.DueAt != nil && h.CompletedAt == nil && now.After(*h.DueAt)
+}
+
+// ForStudent returns the homework as seen by a student, with only their own completion
+func (h Homework) ForStudent(studentID int) Homework {
+	h.CompletedAt = nil
+	for _, completion := range h.Completions {
+		if completion.StudentID == studentID {
+			completedAt := completion.CompletedAt
+			h.CompletedAt = &completedAt
+		}
+	}
+	h.Completions = nil
+	return h
+}
+
+// HomeworkRequest represents the tutor's request to assign or change homework
+type HomeworkRequest struct {
+	Description string     `json:"description"`
+	DueAt       *time.Time `json:"due_at"`
+}
+
+// Validate checks if the homework request is valid and trims the description
+func (r *HomeworkRequest) Validate() error {
+	r.Description = strings.TrimSpace(r.Description)
+	if r.Description == "" || utf8.RuneCountInString(r.Description) > maxHomeworkLength {
+		return ErrInvalidHomework
+	}
+	return nil
+}
+
+// HomeworkStatusRequest represents the student's request to mark homework as done or not done
+type HomeworkStatusRequest struct {
+	Done bool `json:"done"`
+}
+
+// StudentFeedback represents the private progress feedback a tutor gives a student after a
+// lesson. Unlike reviews it is only shown to the tutor and the student.
+type StudentFeedback struct {
+	ID        int       `json:"id"`
+	LessonID  int       `json:"lesson_id"`
+	TutorID   int       `json:"tutor_id"`
+	StudentID int       `json:"student_id"`
+	Rating    int       `json:"rating"`
+	Comment   string    `json:"comment"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// StudentFeedbackRequest represents the tutor's request to give feedback to a student of a
+// lesson. StudentID can be left out for one-on-one lessons.
+type StudentFeedbackRequest struct {
+	StudentID int    `json:"student_id"`
+	Rating    int    `json:"rating"`
+	Comment   string `json:"comment"`
+}
+
+// Validate checks the rating and the length of the comment, which it trims
+func (r *StudentFeedbackRequest) Validate() error {
+	r.Comment = strings.TrimSpace(r.Comment)
+	if r.Rating < 1 || r.Rating > 5 || utf8.RuneCountInString(r.Comment) > maxFeedbackLength {
+		return ErrInvalidFeedback
+	}
+	return nil
+}
+
+// LessonHistoryEntry represents one lesson in the history of a student with a tutor
+type LessonHistoryEntry struct {
+	LessonID  int        `json:"lesson_id"`
+	StartTime time.Time  `json:"start_time"`
+	EndTime   time.Time  `json:"end_time"`
+	Type      LessonType `json:"lesson_type"`
+	Language  string     `json:"language"`
+
+	Notes    *LessonNotes     `json:"notes,omitempty"`
+	Homework []Homework       `json:"homework"`
+	Feedback *StudentFeedback `json:"feedback,omitempty"`
+}
+
+// LessonHistory represents the lessons of a student with a tutor, newest first
+type LessonHistory struct {
+	TutorID   int                  `json:"tutor_id"`
+	StudentID int                  `json:"student_id"`
+	Lessons   []LessonHistoryEntry `json:"lessons"`
+}
+
+// StudentHomework represents a homework assignment in the student's list across all tutors
+type StudentHomework struct {
+	Homework
+	LessonStartTime time.Time `json:"lesson_start_time"`
+	Language        string    `json:"language"`
+	Overdue         bool      `json:"overdue"`
+	Tutor           *User     `json:"tutor,omitempty"`
+}
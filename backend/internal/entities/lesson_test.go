@@ -0,0 +1,316 @@
+package entities
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLessonCanCancel(t *testing.T) {
+	now := time.Now()
+	cancelledAt := now.Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		lesson  Lesson
+		wantErr bool
+	}{
+		{name: "future lesson", lesson: Lesson{StartTime: now.Add(time.Hour)}},
+		{name: "started lesson", lesson: Lesson{StartTime: now.Add(-time.Minute)}, wantErr: true},
+		{name: "already cancelled", lesson: Lesson{StartTime: now.Add(time.Hour), CancelledAt: &cancelledAt}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.lesson.CanCancel(); (err != nil) != tt.wantErr {
+				t.Errorf("CanCancel() = %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLessonCanReschedule(t *testing.T) {
+	now := time.Now()
+	cancelledAt := now.Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		lesson  Lesson
+		wantErr bool
+	}{
+		{name: "outside the late cancellation window", lesson: Lesson{StartTime: now.Add(48 * time.Hour)}},
+		{name: "inside the late cancellation window", lesson: Lesson{StartTime: now.Add(time.Hour)}, wantErr: true},
+		{name: "cancelled", lesson: Lesson{StartTime: now.Add(48 * time.Hour), CancelledAt: &cancelledAt}, wantErr: true},
+		{name: "group lesson", lesson: Lesson{Type: LessonTypeGroup, StartTime: now.Add(48 * time.Hour)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.lesson.CanReschedule(); (err != nil) != tt.wantErr {
+				t.Errorf("CanReschedule() = %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLessonRescheduleRequestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		start time.Time
+		want  error
+	}{
+		{name: "future", start: time.Now().Add(48 * time.Hour)},
+		{name: "past", start: time.Now().Add(-time.Hour), want: ErrInvalidRescheduleTime},
+		{name: "missing", want: ErrInvalidRescheduleTime},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := LessonRescheduleRequest{StartTime: tt.start}
+			if err := req.Validate(); !errors.Is(err, tt.want) {
+				t.Errorf("Validate() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLessonLateCancellation(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		start    time.Time
+		price    float64
+		wantLate bool
+		wantFee  float64
+	}{
+		{name: "two days ahead", start: now.Add(48 * time.Hour), price: 30, wantLate: false, wantFee: 15},
+		{name: "just outside the window", start: now.Add(LateCancellationWindow + time.Minute), price: 30, wantLate: false, wantFee: 15},
+		{name: "just inside the window", start: now.Add(LateCancellationWindow - time.Minute), price: 30, wantLate: true, wantFee: 15},
+		{name: "fee is rounded to cents", start: now.Add(time.Hour), price: 25.55, wantLate: true, wantFee: 12.78},
+		{name: "free lesson", start: now.Add(time.Hour), price: 0, wantLate: true, wantFee: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lesson := Lesson{StartTime: tt.start, Price: tt.price}
+			if got := lesson.IsLateCancellation(); got != tt.wantLate {
+				t.Errorf("IsLateCancellation() = %v, want %v", got, tt.wantLate)
+			}
+			if got := lesson.LateCancellationFee(); got != tt.wantFee {
+				t.Errorf("LateCancellationFee() = %v, want %v", got, tt.wantFee)
+			}
+		})
+	}
+}
+
+func TestLessonNoShow(t *testing.T) {
+	now := time.Now()
+	cancelledAt := now.Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		lesson  Lesson
+		wantErr bool
+		wantFee float64
+	}{
+		{name: "started lesson", lesson: Lesson{StartTime: now.Add(-time.Minute), Price: 40}, wantFee: 40},
+		{name: "not started yet", lesson: Lesson{StartTime: now.Add(time.Minute), Price: 40}, wantErr: true, wantFee: 40},
+		{name: "already reported", lesson: Lesson{StartTime: now.Add(-time.Minute), StudentNoShow: true, Price: 40}, wantErr: true, wantFee: 40},
+		{name: "cancelled", lesson: Lesson{StartTime: now.Add(-time.Minute), CancelledAt: &cancelledAt, Price: 40}, wantErr: true, wantFee: 40},
+		{name: "group lesson", lesson: Lesson{StartTime: now.Add(-time.Minute), Type: LessonTypeGroup, Price: 40}, wantErr: true, wantFee: 40},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.lesson.CanReportNoShow()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CanReportNoShow() = %v, want error: %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrNoShowNotAllowed) {
+				t.Errorf("CanReportNoShow() = %v, want %v", err, ErrNoShowNotAllowed)
+			}
+			if got := tt.lesson.NoShowFeeAmount(); got != tt.wantFee {
+				t.Errorf("NoShowFeeAmount() = %v, want %v", got, tt.wantFee)
+			}
+		})
+	}
+}
+
+func TestLessonSeatCancellationFee(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		start     time.Time
+		seatPrice float64
+		want      *float64
+	}{
+		{name: "outside the window", start: now.Add(48 * time.Hour), seatPrice: 20},
+		{name: "inside the window", start: now.Add(time.Hour), seatPrice: 20, want: ptr(10.0)},
+		{name: "free seat", start: now.Add(time.Hour), seatPrice: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lesson := Lesson{Type: LessonTypeGroup, StartTime: tt.start}
+			got := lesson.SeatCancellationFee(&LessonParticipant{Price: tt.seatPrice})
+			switch {
+			case got == nil && tt.want == nil:
+			case got == nil || tt.want == nil || *got != *tt.want:
+				t.Errorf("SeatCancellationFee() = %v, want %v", deref(got), deref(tt.want))
+			}
+		})
+	}
+}
+
+func TestLessonCanEnroll(t *testing.T) {
+	now := time.Now()
+	cancelledAt := now.Add(-time.Hour)
+
+	tests := []struct {
+		name   string
+		lesson Lesson
+		want   error
+	}{
+		{
+			name:   "open seat",
+			lesson: Lesson{Type: LessonTypeGroup, StartTime: now.Add(time.Hour), Capacity: ptr(5), ParticipantsCount: 4},
+		},
+		{
+			name:   "not a group lesson",
+			lesson: Lesson{Type: LessonTypeRegular, StartTime: now.Add(time.Hour)},
+			want:   ErrNotGroupLesson,
+		},
+		{
+			name:   "full",
+			lesson: Lesson{Type: LessonTypeGroup, StartTime: now.Add(time.Hour), Capacity: ptr(5), ParticipantsCount: 5},
+			want:   ErrGroupLessonFull,
+		},
+		{
+			name:   "cancelled",
+			lesson: Lesson{Type: LessonTypeGroup, StartTime: now.Add(time.Hour), CancelledAt: &cancelledAt},
+			want:   ErrEnrollmentClosed,
+		},
+		{
+			name:   "enrollment deadline passed",
+			lesson: Lesson{Type: LessonTypeGroup, StartTime: now.Add(time.Hour), EnrollmentDeadline: ptr(now.Add(-time.Minute))},
+			want:   ErrEnrollmentClosed,
+		},
+		{
+			name:   "before the enrollment deadline",
+			lesson: Lesson{Type: LessonTypeGroup, StartTime: now.Add(2 * time.Hour), EnrollmentDeadline: ptr(now.Add(time.Hour))},
+		},
+		{
+			name:   "started",
+			lesson: Lesson{Type: LessonTypeGroup, StartTime: now.Add(-time.Minute)},
+			want:   ErrEnrollmentClosed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.lesson.CanEnroll(); !errors.Is(err, tt.want) {
+				t.Errorf("CanEnroll() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLessonBookingRequestValidate(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Minute)
+
+	tests := []struct {
+		name    string
+		req     LessonBookingRequest
+		wantErr bool
+	}{
+		{
+			name: "regular lesson",
+			req:  LessonBookingRequest{TutorID: 1, LanguageID: 1, StartTime: start, EndTime: start.Add(time.Hour)},
+		},
+		{
+			name: "trial lesson without end time",
+			req:  LessonBookingRequest{TutorID: 1, LanguageID: 1, Type: LessonTypeTrial, StartTime: start},
+		},
+		{
+			name:    "trial lesson over 30 minutes",
+			req:     LessonBookingRequest{TutorID: 1, LanguageID: 1, Type: LessonTypeTrial, StartTime: start, EndTime: start.Add(45 * time.Minute)},
+			wantErr: true,
+		},
+		{
+			name:    "missing tutor",
+			req:     LessonBookingRequest{LanguageID: 1, StartTime: start, EndTime: start.Add(time.Hour)},
+			wantErr: true,
+		},
+		{
+			name:    "missing language",
+			req:     LessonBookingRequest{TutorID: 1, StartTime: start, EndTime: start.Add(time.Hour)},
+			wantErr: true,
+		},
+		{
+			name:    "missing end time",
+			req:     LessonBookingRequest{TutorID: 1, LanguageID: 1, StartTime: start},
+			wantErr: true,
+		},
+		{
+			name:    "end before start",
+			req:     LessonBookingRequest{TutorID: 1, LanguageID: 1, StartTime: start, EndTime: start.Add(-time.Hour)},
+			wantErr: true,
+		},
+		{
+			name:    "in the past",
+			req:     LessonBookingRequest{TutorID: 1, LanguageID: 1, StartTime: start.AddDate(0, 0, -2), EndTime: start.AddDate(0, 0, -2).Add(time.Hour)},
+			wantErr: true,
+		},
+		{
+			name:    "group lesson booked as a single lesson",
+			req:     LessonBookingRequest{TutorID: 1, LanguageID: 1, Type: LessonTypeGroup, StartTime: start, EndTime: start.Add(time.Hour)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+			req.ApplyDefaults()
+			if err := req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCalculateLessonPrice(t *testing.T) {
+	start := time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		rate     float64
+		duration time.Duration
+		want     float64
+	}{
+		{name: "one hour", rate: 30, duration: time.Hour, want: 30},
+		{name: "half an hour", rate: 30, duration: 30 * time.Minute, want: 15},
+		{name: "rounded to cents", rate: 25, duration: 50 * time.Minute, want: 20.83},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CalculateLessonPrice(tt.rate, start, start.Add(tt.duration)); got != tt.want {
+				t.Errorf("CalculateLessonPrice() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func deref(v *float64) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
@@ -0,0 +1,199 @@
+package entities
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrConversationNotFound    = errors.New("conversation not found")
+	ErrMessageNotFound         = errors.New("message not found")
+	ErrEmptyMessage            = errors.New("message must have text or attachments")
+	ErrMessageTooLong          = errors.New("message is too long")
+	ErrTooManyAttachments      = errors.New("too many attachments")
+	ErrInvalidAttachment       = errors.New("attachments must have a name and an http(s) URL")
+	ErrInvalidRecipient        = errors.New("conversations are between a student and a tutor")
+	ErrConversationNotAllowed  = errors.New("tutors can only start conversations with their students")
+	ErrUserBlocked             = errors.New("messaging between these users is blocked")
+	ErrTooManyNewConversations = errors.New("too many new conversations today, please book a lesson or try again tomorrow")
+	ErrMessageRateLimited      = errors.New("please wait for the tutor to reply or book a lesson before sending more messages")
+	ErrInvalidReport           = errors.New("report reason is required")
+	ErrReportNotFound          = errors.New("report not found")
+	ErrCannotBlockSelf         = errors.New("you cannot block yourself")
+)
+
+const (
+	// MaxMessageLength is the maximum number of characters of a message
+	MaxMessageLength = 4000
+	// MaxMessageAttachments is the maximum number of attachments of a message
+	MaxMessageAttachments = 5
+	// MaxReportReasonLength is the maximum number of characters of a report reason
+	MaxReportReasonLength = 1000
+
+	// Students who have not booked a lesson with a tutor may start a limited number of
+	// conversations a day, and may only send a few messages in a row until the tutor replies
+	MaxPreBookingConversationsPerDay = 5
+	MaxUnansweredPreBookingMessages  = 3
+)
+
+// MessageAttachment references a file uploaded elsewhere, e.g. through the upload API
+type MessageAttachment struct {
+	URL         string `json:"url"`
+	Name        string `json:"name"`
+	ContentType string `json:"content_type,omitempty"`
+	Size        int64  `json:"size,omitempty"`
+}
+
+// Validate checks if the attachment reference is valid
+func (a *MessageAttachment) Validate() error {
+	if strings.TrimSpace(a.Name) == "" || len(a.Name) > 255 || a.Size < 0 {
+		return ErrInvalidAttachment
+	}
+	if !strings.HasPrefix(a.URL, "https://") && !strings.HasPrefix(a.URL, "http://") {
+		return ErrInvalidAttachment
+	}
+	return nil
+}
+
+// Message represents a message in a conversation. ReadAt is set once the recipient has read it.
+type Message struct {
+	ID             int64               `json:"id"`
+	ConversationID int                 `json:"conversation_id"`
+	SenderID       int                 `json:"sender_id"`
+	Body           string              `json:"body"`
+	Attachments    []MessageAttachment `json:"attachments"`
+	ReadAt         *time.Time          `json:"read_at,omitempty"`
+	CreatedAt      time.Time           `json:"created_at"`
+}
+
+// Conversation represents the one-to-one message thread between a student and a tutor
+type Conversation struct {
+	ID            int       `json:"id"`
+	StudentID     int       `json:"student_id"`
+	TutorID       int       `json:"tutor_id"`
+	StartedBy     int       `json:"started_by"`
+	LastMessageAt time.Time `json:"last_message_at"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	// Related entities
+	Student     *User    `json:"student,omitempty"`
+	Tutor       *User    `json:"tutor,omitempty"`
+	LastMessage *Message `json:"last_message,omitempty"`
+
+	// Number of messages the current user has not read
+	UnreadCount int `json:"unread_count"`
+}
+
+// HasParticipant checks if the user takes part in the conversation
+func (c *Conversation) HasParticipant(userID int) bool {
+	return c.StudentID == userID || c.TutorID == userID
+}
+
+// OtherParticipant returns the ID of the user on the other side of the conversation
+func (c *Conversation) OtherParticipant(userID int) int {
+	if c.StudentID == userID {
+		return c.TutorID
+	}
+	return c.StudentID
+}
+
+// MessageRequest represents the request to send a message
+type MessageRequest struct {
+	Body        string              `json:"body"`
+	Attachments []MessageAttachment `json:"attachments"`
+}
+
+// Validate checks if the message request is valid and trims the text
+func (r *MessageRequest) Validate() error {
+	r.Body = strings.TrimSpace(r.Body)
+	if r.Body == "" && len(r.Attachments) == 0 {
+		return ErrEmptyMessage
+	}
+	if utf8.RuneCountInString(r.Body) > MaxMessageLength {
+		return ErrMessageTooLong
+	}
+	if len(r.Attachments) > MaxMessageAttachments {
+		return ErrTooManyAttachments
+	}
+	for i := range r.Attachments {
+		if err := r.Attachments[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StartConversationRequest represents the request to message a user, starting a conversation
+// with them unless one exists
+type StartConversationRequest struct {
+	RecipientID int `json:"recipient_id"`
+	MessageRequest
+}
+
+// ConversationFilters represents pagination of a user's conversations
+type ConversationFilters struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// ConversationList represents a page of a user's conversations, most recently active first
+type ConversationList struct {
+	Conversations []Conversation `json:"conversations"`
+	Total         int            `json:"total"`
+}
+
+// MessagePage represents a page of a conversation's messages, newest first.
+// The next page is requested with before_id set to the ID of the last message.
+type MessagePage struct {
+	Messages []Message `json:"messages"`
+	HasMore  bool      `json:"has_more"`
+}
+
+// BlockedUser represents a user the current user has blocked
+type BlockedUser struct {
+	User      *User     `json:"user"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BlockRequest represents the request to block a user
+type BlockRequest struct {
+	UserID int `json:"user_id"`
+}
+
+// ConversationReport represents a report of an abusive conversation or message
+type ConversationReport struct {
+	ID             int        `json:"id"`
+	ConversationID int        `json:"conversation_id"`
+	MessageID      *int64     `json:"message_id,omitempty"`
+	ReporterID     int        `json:"reporter_id"`
+	Reason         string     `json:"reason"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+
+	// The reported message, for reviewing the report
+	Message *Message `json:"message,omitempty"`
+}
+
+// ReportRequest represents the request to report a conversation or one of its messages
+type ReportRequest struct {
+	MessageID *int64 `json:"message_id"`
+	Reason    string `json:"reason"`
+}
+
+// Validate checks if the report request is valid and trims the reason
+func (r *ReportRequest) Validate() error {
+	r.Reason = strings.TrimSpace(r.Reason)
+	if r.Reason == "" || utf8.RuneCountInString(r.Reason) > MaxReportReasonLength {
+		return ErrInvalidReport
+	}
+	return nil
+}
+
+// ReportFilters represents filters for listing conversation reports
+type ReportFilters struct {
+	OpenOnly bool `json:"open_only"`
+	Limit    int  `json:"limit"`
+	Offset   int  `json:"offset"`
+}
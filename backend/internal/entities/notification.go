@@ -0,0 +1,58 @@
+package entities
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// NotificationType represents the event a notification is about
+type NotificationType string
+
+const (
+	NotificationLessonBooked         NotificationType = "lesson_booked"
+	NotificationLessonCancelled      NotificationType = "lesson_cancelled"
+	NotificationLessonRescheduled    NotificationType = "lesson_rescheduled"
+	NotificationLessonReminder       NotificationType = "lesson_reminder"
+	NotificationReviewReceived       NotificationType = "review_received"
+	NotificationReviewReply          NotificationType = "review_reply"
+	NotificationStreakAtRisk         NotificationType = "streak_at_risk"
+	NotificationMessageReceived      NotificationType = "message_received"
+	NotificationLessonNotes          NotificationType = "lesson_notes"
+	NotificationStudentFeedback      NotificationType = "student_feedback"
+	NotificationWeeklySummary        NotificationType = "weekly_summary" // Email only
+	NotificationSavedSearchMatch     NotificationType = "saved_search_match"
+	NotificationFavoriteAvailability NotificationType = "favorite_availability"
+	NotificationCredentialReviewed   NotificationType = "credential_reviewed"
+)
+
+// Notification represents a message delivered to a user about an event
+type Notification struct {
+	ID        int64                  `json:"id"`
+	UserID    int                    `json:"user_id"`
+	Type      NotificationType       `json:"type"`
+	Title     string                 `json:"title"`
+	Body      string                 `json:"body"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+	ReadAt    *time.Time             `json:"read_at,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+
+	// DedupKey makes delivery idempotent: a notification with the same key is only stored
+	// and emailed once, so that a retried job does not notify twice. Optional.
+	DedupKey string `json:"-"`
+}
+
+// NotificationFilters represents filters for listing a user's notifications
+type NotificationFilters struct {
+	UnreadOnly bool `json:"unread_only"`
+	Limit      int  `json:"limit"`
+	Offset     int  `json:"offset"`
+}
+
+// NotificationList represents a page of a user's notifications
+type NotificationList struct {
+	Notifications []Notification `json:"notifications"`
+	Total         int            `json:"total"`
+	UnreadCount   int            `json:"unread_count"`
+}
@@ -0,0 +1,130 @@
+package entities
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidLocale            = errors.New("locale must be one of en, ru, es")
+	ErrUnknownNotificationEvent = errors.New("unknown notification event")
+)
+
+// DefaultLocale is the language of emails for users who have not chosen one
+const DefaultLocale = "en"
+
+// SupportedLocales lists the languages emails are available in
+var SupportedLocales = []string{"en", "ru", "es"}
+
+// IsSupportedLocale checks if emails are available in a language
+func IsSupportedLocale(locale string) bool {
+	for _, supported := range SupportedLocales {
+		if locale == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationChannels represents the channels through which a user is told about an event
+type NotificationChannels struct {
+	InApp bool `json:"in_app"`
+	Email bool `json:"email"`
+}
+
+// defaultNotificationChannels holds the channels of every event a user can configure.
+// Frequent or minor events are not emailed unless the user asks for it.
+var defaultNotificationChannels = map[NotificationType]NotificationChannels{
+	NotificationLessonBooked:         {InApp: true, Email: true},
+	NotificationLessonCancelled:      {InApp: true, Email: true},
+	NotificationLessonRescheduled:    {InApp: true, Email: true},
+	NotificationLessonReminder:       {InApp: true, Email: true},
+	NotificationReviewReceived:       {InApp: true, Email: false},
+	NotificationReviewReply:          {InApp: true, Email: false},
+	NotificationStreakAtRisk:         {InApp: true, Email: false},
+	NotificationMessageReceived:      {InApp: true, Email: false},
+	NotificationLessonNotes:          {InApp: true, Email: false},
+	NotificationStudentFeedback:      {InApp: true, Email: false},
+	NotificationWeeklySummary:        {InApp: false, Email: true},
+	NotificationSavedSearchMatch:     {InApp: true, Email: false},
+	NotificationFavoriteAvailability: {InApp: true, Email: false},
+	NotificationCredentialReviewed:   {InApp: true, Email: false},
+}
+
+// DefaultNotificationChannels returns the channels used for an event the user has not configured
+func DefaultNotificationChannels(event NotificationType) NotificationChannels {
+	if channels, ok := defaultNotificationChannels[event]; ok {
+		return channels
+	}
+	return NotificationChannels{InApp: true}
+}
+
+// NotificationPreferences represents how and in which language a user wants to be notified
+type NotificationPreferences struct {
+	Locale string                                    `json:"locale"`
+	Events map[NotificationType]NotificationChannels `json:"events"`
+}
+
+// NotificationPreferencesRequest represents a change of a user's notification preferences.
+// Only the given events are changed.
+type NotificationPreferencesRequest struct {
+	Locale *string                                   `json:"locale,omitempty"`
+	Events map[NotificationType]NotificationChannels `json:"events,omitempty"`
+}
+
+// Validate validates the notification preferences request
+func (r *NotificationPreferencesRequest) Validate() error {
+	if r.Locale != nil && !IsSupportedLocale(*r.Locale) {
+		return ErrInvalidLocale
+	}
+	for event := range r.Events {
+		if _, ok := defaultNotificationChannels[event]; !ok {
+			return ErrUnknownNotificationEvent
+		}
+	}
+	return nil
+}
+
+// NewNotificationPreferences returns the default preferences overridden by the user's settings
+func NewNotificationPreferences(locale string, overrides map[NotificationType]NotificationChannels) *NotificationPreferences {
+	prefs := &NotificationPreferences{
+		Locale: locale,
+		Events: make(map[NotificationType]NotificationChannels, len(defaultNotificationChannels)),
+	}
+	for event, channels := range defaultNotificationChannels {
+		prefs.Events[event] = channels
+	}
+	for event, channels := range overrides {
+		if _, ok := prefs.Events[event]; ok {
+			prefs.Events[event] = channels
+		}
+	}
+	return prefs
+}
+
+// EmailRecipient represents the user an email is addressed to
+type EmailRecipient struct {
+	UserID    int
+	Email     string
+	FirstName string
+	Username  string
+	Locale    string
+}
+
+// Name returns how the recipient is greeted
+func (r *EmailRecipient) Name() string {
+	if r.FirstName != "" {
+		return r.FirstName
+	}
+	return r.Username
+}
+
+// WeeklySummary represents the activity of a user that is sent in the weekly summary email
+type WeeklySummary struct {
+	Recipient        EmailRecipient
+	WeekStart        time.Time
+	LessonsCompleted int
+	UpcomingLessons  int
+	NextLessonAt     *time.Time
+	CurrentStreak    int
+}
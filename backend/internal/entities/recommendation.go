@@ -0,0 +1,61 @@
+package entities
+
+// RecommendationWeights are how much each factor counts towards a tutor recommendation score
+type RecommendationWeights struct {
+	Interests    float64 `json:"interests"`
+	Goals        float64 `json:"goals"`
+	Language     float64 `json:"language"`
+	Availability float64 `json:"availability"`
+	Rating       float64 `json:"rating"`
+	Price        float64 `json:"price"`
+}
+
+// RecommendationFactor is how a tutor scores on one factor of a recommendation
+type RecommendationFactor struct {
+	Match  float64 `json:"match"`  // How well the tutor fits, from 0 to 1
+	Weight float64 `json:"weight"` // Weight of the factor; 0 when the student gave nothing to compare with
+	Points float64 `json:"points"` // What the factor adds to the score: the match times its share of the weights
+}
+
+// RecommendationBreakdown explains a recommendation score. The points of the factors add up
+// to the score.
+type RecommendationBreakdown struct {
+	Interests    RecommendationFactor `json:"interests"`
+	Goals        RecommendationFactor `json:"goals"`
+	Language     RecommendationFactor `json:"language"`
+	Availability RecommendationFactor `json:"availability"`
+	Rating       RecommendationFactor `json:"rating"`
+	Price        RecommendationFactor `json:"price"`
+
+	// What the matches are made of
+	SharedInterests  []int `json:"shared_interests"`   // IDs of the student's interests the tutor shares
+	SharedGoals      []int `json:"shared_goals"`       // IDs of the student's goals the tutor works on
+	TaughtLanguages  []int `json:"taught_languages"`   // IDs of the student's languages the tutor speaks better than them
+	OverlapMinutes   int   `json:"overlap_minutes"`    // Minutes a week the tutor is available at the student's preferred times
+	PreferredMinutes int   `json:"preferred_minutes"`  // Minutes a week of the student's preferred times
+	WithinPriceRange bool  `json:"within_price_range"` // Whether the hourly rate is within the student's range
+	RatedByReviews   bool  `json:"rated_by_reviews"`   // Whether the rating comes from reviews rather than the prior
+}
+
+// TutorRecommendation is a tutor recommended to a student with the reasons for it
+type TutorRecommendation struct {
+	Tutor     TutorProfile            `json:"tutor"`
+	Score     float64                 `json:"score"` // From 0 to 1
+	Breakdown RecommendationBreakdown `json:"breakdown"`
+}
+
+// TutorRecommendations is the list of tutors recommended to a student, best first
+type TutorRecommendations struct {
+	Recommendations []TutorRecommendation `json:"recommendations"`
+	Weights         RecommendationWeights `json:"weights"`
+}
+
+// RecommendationCandidate is a tutor that may be recommended, with what the recommendation
+// compares with the student
+type RecommendationCandidate struct {
+	Tutor        TutorProfile
+	InterestIDs  []int
+	GoalIDs      []int
+	Languages    []UserLanguage      // Only the language and proficiency IDs are set
+	Availability []TutorAvailability // Weekly availability only
+}
@@ -0,0 +1,192 @@
+package entities
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrReviewNotFound      = errors.New("review not found")
+	ErrInvalidRating       = errors.New("ratings must be between 1 and 5")
+	ErrReviewTooLong       = errors.New("review text is too long")
+	ErrReviewNotAllowed    = errors.New("only students of a lesson can review it")
+	ErrAlreadyReviewed     = errors.New("user has already reviewed this lesson")
+	ErrInvalidReply        = errors.New("reply must not be empty or too long")
+	ErrReplyNotAllowed     = errors.New("only the tutor of the lesson can reply to reviews of it")
+	ErrCannotVoteOwnReview = errors.New("you cannot mark your own review as helpful")
+)
+
+const (
+	// MaxReviewCommentLength is the maximum number of characters of a review text
+	MaxReviewCommentLength = 2000
+	// MaxReviewReplyLength is the maximum number of characters of a tutor's reply
+	MaxReviewReplyLength = 2000
+)
+
+// ReviewSort represents the order of a tutor's reviews
+type ReviewSort string
+
+const (
+	ReviewSortRecent  ReviewSort = "recent"
+	ReviewSortHelpful ReviewSort = "helpful"
+)
+
+// ReviewModerationStatus selects the reviews in the moderation queue
+type ReviewModerationStatus string
+
+const (
+	ReviewModerationReported ReviewModerationStatus = "reported" // Visible reviews with open reports
+	ReviewModerationHidden   ReviewModerationStatus = "hidden"
+)
+
+// AspectRatings holds the optional 1–5 ratings a student gives to parts of a tutor's lessons
+type AspectRatings struct {
+	PronunciationHelp *int `json:"pronunciation_help,omitempty"`
+	Preparation       *int `json:"preparation,omitempty"`
+	Punctuality       *int `json:"punctuality,omitempty"`
+}
+
+// Validate checks that every given aspect rating is between 1 and 5
+func (a *AspectRatings) Validate() error {
+	for _, rating := range []*int{a.PronunciationHelp, a.Preparation, a.Punctuality} {
+		if rating != nil && (*rating < 1 || *rating > 5) {
+			return ErrInvalidRating
+		}
+	}
+	return nil
+}
+
+// ReviewReply represents the tutor's public answer to a review
+type ReviewReply struct {
+	Text      string    `json:"text"`
+	RepliedAt time.Time `json:"replied_at"`
+}
+
+// Review represents a student's public review of a tutor's lesson
+type Review struct {
+	ID           int           `json:"id"`
+	LessonID     int           `json:"lesson_id"`
+	ReviewerID   int           `json:"reviewer_id"`
+	TutorID      int           `json:"tutor_id"`
+	Rating       int           `json:"rating"`
+	Comment      string        `json:"comment"`
+	Aspects      AspectRatings `json:"aspects"`
+	Reply        *ReviewReply  `json:"reply,omitempty"`
+	HelpfulCount int           `json:"helpful_count"`
+	HiddenAt     *time.Time    `json:"hidden_at,omitempty"`
+	HiddenReason *string       `json:"hidden_reason,omitempty"`
+	CreatedAt    time.Time     `json:"created_at"`
+
+	// Related entities (not in the database)
+	Reviewer *User `json:"reviewer,omitempty"`
+}
+
+// ReviewRequest represents the request to review a lesson
+type ReviewRequest struct {
+	Rating  int           `json:"rating"`
+	Comment string        `json:"comment"`
+	Aspects AspectRatings `json:"aspects"`
+}
+
+// Validate checks the ratings and the length of the text, which it trims
+func (r *ReviewRequest) Validate() error {
+	if r.Rating < 1 || r.Rating > 5 {
+		return ErrInvalidRating
+	}
+	if err := r.Aspects.Validate(); err != nil {
+		return err
+	}
+	r.Comment = strings.TrimSpace(r.Comment)
+	if utf8.RuneCountInString(r.Comment) > MaxReviewCommentLength {
+		return ErrReviewTooLong
+	}
+	return nil
+}
+
+// ReviewReplyRequest represents the tutor's request to reply to a review
+type ReviewReplyRequest struct {
+	Text string `json:"text"`
+}
+
+// Validate checks the length of the reply, which it trims
+func (r *ReviewReplyRequest) Validate() error {
+	r.Text = strings.TrimSpace(r.Text)
+	if r.Text == "" || utf8.RuneCountInString(r.Text) > MaxReviewReplyLength {
+		return ErrInvalidReply
+	}
+	return nil
+}
+
+// ReviewReport represents a user's report of an inappropriate review
+type ReviewReport struct {
+	ID         int        `json:"id"`
+	ReviewID   int        `json:"review_id"`
+	ReporterID int        `json:"reporter_id"`
+	Reason     string     `json:"reason"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ReviewReportRequest represents the request to report a review
+type ReviewReportRequest struct {
+	Reason string `json:"reason"`
+}
+
+// Validate checks if the report request is valid and trims the reason
+func (r *ReviewReportRequest) Validate() error {
+	r.Reason = strings.TrimSpace(r.Reason)
+	if r.Reason == "" || utf8.RuneCountInString(r.Reason) > MaxReportReasonLength {
+		return ErrInvalidReport
+	}
+	return nil
+}
+
+// HideReviewRequest represents an admin's request to hide a review
+type HideReviewRequest struct {
+	Reason string `json:"reason"`
+}
+
+// ReviewFilters represents the page and order of a tutor's reviews
+type ReviewFilters struct {
+	Sort   ReviewSort `json:"sort"`
+	Limit  int        `json:"limit"`
+	Offset int        `json:"offset"`
+}
+
+// AspectAverages holds the average aspect ratings of a tutor; an aspect nobody rated is omitted
+type AspectAverages struct {
+	PronunciationHelp *float64 `json:"pronunciation_help,omitempty"`
+	Preparation       *float64 `json:"preparation,omitempty"`
+	Punctuality       *float64 `json:"punctuality,omitempty"`
+}
+
+// ReviewSummary represents the ratings of a tutor's visible reviews
+type ReviewSummary struct {
+	AverageRating float64        `json:"average_rating"`
+	ReviewsCount  int            `json:"reviews_count"`
+	Aspects       AspectAverages `json:"aspects"`
+}
+
+// ReviewPage represents a page of a tutor's reviews with their summary
+type ReviewPage struct {
+	Reviews []Review      `json:"reviews"`
+	Summary ReviewSummary `json:"summary"`
+	Total   int           `json:"total"`
+	Limit   int           `json:"limit"`
+	Offset  int           `json:"offset"`
+}
+
+// ReviewModerationFilters represents filters for the review moderation queue
+type ReviewModerationFilters struct {
+	Status ReviewModerationStatus `json:"status"`
+	Limit  int                    `json:"limit"`
+	Offset int                    `json:"offset"`
+}
+
+// ModeratedReview represents a review in the moderation queue with its reports
+type ModeratedReview struct {
+	Review
+	Reports []ReviewReport `json:"reports"`
+}
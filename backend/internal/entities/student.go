@@ -1,6 +1,14 @@
 package entities
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+var ErrInvalidStudentPreferences = errors.New("invalid student preferences")
+
+// MaxPreferredTimes is the number of weekly preferred times a student may give
+const MaxPreferredTimes = 28
 
 // StudentProfile represents a student's profile information
 type StudentProfile struct {
@@ -37,3 +45,58 @@ type StudentUpdateRequest struct {
 	Interests         []int                `json:"interests,omitempty"`
 	Goals             []int                `json:"goals,omitempty"`
 }
+
+// StudentPreferences is what a student would like from their lessons, used to recommend
+// tutors. Prices are per hour; a missing bound leaves that side of the range open.
+type StudentPreferences struct {
+	TimeZone       string          `json:"timezone"` // IANA time zone of the preferred times
+	MinPrice       *float64        `json:"min_price,omitempty"`
+	MaxPrice       *float64        `json:"max_price,omitempty"`
+	PreferredTimes []PreferredTime `json:"preferred_times"`
+}
+
+// PreferredTime is a weekly time a student would like to have lessons at
+type PreferredTime struct {
+	DayOfWeek int    `json:"day_of_week"` // 0 is Sunday
+	StartTime string `json:"start_time"`  // HH:MM
+	EndTime   string `json:"end_time"`    // HH:MM; before StartTime for times past midnight
+}
+
+// Window returns the start of the preferred time as an offset from midnight and its length
+func (t *PreferredTime) Window() (start, length time.Duration) {
+	return weeklyWindow(t.StartTime, t.EndTime)
+}
+
+// Validate checks the preferences and defaults the time zone to UTC
+func (p *StudentPreferences) Validate() error {
+	if p.TimeZone == "" {
+		p.TimeZone = "UTC"
+	}
+	if err := ValidateTimeZone(p.TimeZone); err != nil {
+		return err
+	}
+	if (p.MinPrice != nil && *p.MinPrice < 0) || (p.MaxPrice != nil && *p.MaxPrice < 0) {
+		return ErrInvalidStudentPreferences
+	}
+	if p.MinPrice != nil && p.MaxPrice != nil && *p.MinPrice > *p.MaxPrice {
+		return ErrInvalidStudentPreferences
+	}
+	if len(p.PreferredTimes) > MaxPreferredTimes {
+		return ErrInvalidStudentPreferences
+	}
+	for _, t := range p.PreferredTimes {
+		if t.DayOfWeek < 0 || t.DayOfWeek > 6 || t.StartTime == t.EndTime {
+			return ErrInvalidStudentPreferences
+		}
+		if _, err := parseTimeOfDay(t.StartTime); err != nil {
+			return ErrInvalidStudentPreferences
+		}
+		if _, err := parseTimeOfDay(t.EndTime); err != nil {
+			return ErrInvalidStudentPreferences
+		}
+	}
+	if p.PreferredTimes == nil {
+		p.PreferredTimes = []PreferredTime{}
+	}
+	return nil
+}
@@ -1,22 +1,60 @@
 package entities
 
-import "time"
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidTimeZone           = errors.New("invalid time zone")
+	ErrInvalidAvailabilityFilter = errors.New("invalid availability filter")
+	ErrInvalidEducation          = errors.New("degree and institution are required and must be at most 200 characters")
+	ErrInvalidEducationYears     = errors.New("start year must be between 1950 and the current year, and end year between the start year and 10 years from now")
+	ErrTooManyEducationEntries   = errors.New("too many education entries")
+	ErrEducationNotFound         = errors.New("education entry not found")
+)
+
+const (
+	// MaxAvailableWithinDays is how far ahead tutor search looks for open slots
+	MaxAvailableWithinDays = 30
+	// MaxEducationEntries is the maximum number of education entries on a tutor profile
+	MaxEducationEntries = 20
+	// MinEducationYear is the earliest year of an education entry
+	MinEducationYear = 1950
+
+	// maxEducationYearsAhead is how far in the future an education entry can end, for studies
+	// still under way
+	maxEducationYearsAhead = 10
+	maxEducationTextLength = 200
+)
 
 // TutorProfile represents a tutor's profile information
 type TutorProfile struct {
 	UserID          int         `json:"user_id"`
 	Bio             string      `json:"bio"`
-	Education       interface{} `json:"education"` // Stored as JSONB in the database
+	Education       []Education `json:"education"` // Stored as a JSONB array in the database
 	IntroVideoURL   string      `json:"intro_video_url,omitempty"`
 	YearsExperience int         `json:"years_experience"`
+	HourlyRate      float64     `json:"hourly_rate"`
+	OffersTrial     bool        `json:"offers_trial"`
+	TrialPrice      float64     `json:"trial_price"`
+	TimeZone        string      `json:"timezone"` // IANA time zone of the availability times
 	CreatedAt       time.Time   `json:"created_at"`
 	UpdatedAt       time.Time   `json:"updated_at"`
 
+	// Rating stats cached in tutor_stats
+	Rating            float64 `json:"rating,omitempty"`              // Plain average of the visible reviews
+	ReviewsCount      int     `json:"reviews_count,omitempty"`       // Number of visible reviews
+	RatingScore       float64 `json:"rating_score,omitempty"`        // Average pulled towards the mean of all reviews
+	RecentRatingScore float64 `json:"recent_rating_score,omitempty"` // Rating score with older reviews weighing less
+
+	// IsVerified is set when an admin verified one of the tutor's credentials
+	IsVerified bool `json:"is_verified"`
+
 	// Related entities (not in the database)
-	User         *User          `json:"user,omitempty"`
-	Languages    []UserLanguage `json:"languages,omitempty"`
-	Rating       float64        `json:"rating,omitempty"`
-	ReviewsCount int            `json:"reviews_count,omitempty"`
+	User      *User          `json:"user,omitempty"`
+	Languages []UserLanguage `json:"languages,omitempty"`
 }
 
 // TutorAvailability represents a tutor's available time slot
@@ -32,6 +70,11 @@ type TutorAvailability struct {
 	UpdatedAt    time.Time `json:"updated_at"`
 }
 
+// Window returns the start of the availability as an offset from midnight and its length
+func (a *TutorAvailability) Window() (start, length time.Duration) {
+	return weeklyWindow(a.StartTime, a.EndTime)
+}
+
 // TutorRegistrationRequest represents the data needed to register as a tutor
 type TutorRegistrationRequest struct {
 	// Basic user registration data
@@ -41,9 +84,12 @@ type TutorRegistrationRequest struct {
 
 	// Tutor-specific data
 	Bio             string               `json:"bio"`
-	Education       interface{}          `json:"education"`
+	Education       []Education          `json:"education"`
 	IntroVideoURL   string               `json:"intro_video_url,omitempty"`
 	YearsExperience int                  `json:"years_experience"`
+	HourlyRate      float64              `json:"hourly_rate"`
+	OffersTrial     bool                 `json:"offers_trial"`
+	TrialPrice      float64              `json:"trial_price"`
 	Languages       []UserLanguageUpdate `json:"languages"`
 }
 
@@ -59,18 +105,66 @@ type TutorAvailabilityRequest struct {
 // TutorUpdateRequest represents the data needed to update a tutor's profile
 type TutorUpdateRequest struct {
 	Bio             string      `json:"bio,omitempty"`
-	Education       interface{} `json:"education,omitempty"`
+	Education       []Education `json:"education,omitempty"` // Replaces all entries when set
 	IntroVideoURL   string      `json:"intro_video_url,omitempty"`
 	YearsExperience *int        `json:"years_experience,omitempty"`
+	HourlyRate      *float64    `json:"hourly_rate,omitempty"`
+	OffersTrial     *bool       `json:"offers_trial,omitempty"`
+	TrialPrice      *float64    `json:"trial_price,omitempty"`
+	TimeZone        string      `json:"timezone,omitempty"`
 }
 
-// Education represents an educational entry
+// Education represents an educational entry. Years are optional; an entry without an end
+// year is still under way.
 type Education struct {
 	Degree       string `json:"degree"`
 	Institution  string `json:"institution"`
 	FieldOfStudy string `json:"field_of_study"`
-	StartYear    string `json:"start_year"`
-	EndYear      string `json:"end_year"`
+	StartYear    *int   `json:"start_year,omitempty"`
+	EndYear      *int   `json:"end_year,omitempty"`
+}
+
+// Validate checks the education entry and trims its text
+func (e *Education) Validate() error {
+	e.Degree = strings.TrimSpace(e.Degree)
+	e.Institution = strings.TrimSpace(e.Institution)
+	e.FieldOfStudy = strings.TrimSpace(e.FieldOfStudy)
+	if e.Degree == "" || e.Institution == "" {
+		return ErrInvalidEducation
+	}
+	for _, text := range []string{e.Degree, e.Institution, e.FieldOfStudy} {
+		if len([]rune(text)) > maxEducationTextLength {
+			return ErrInvalidEducation
+		}
+	}
+
+	maxYear := time.Now().Year() + maxEducationYearsAhead
+	for _, year := range []*int{e.StartYear, e.EndYear} {
+		if year != nil && (*year < MinEducationYear || *year > maxYear) {
+			return ErrInvalidEducationYears
+		}
+	}
+	if e.StartYear != nil && *e.StartYear > time.Now().Year() {
+		return ErrInvalidEducationYears
+	}
+	if e.StartYear != nil && e.EndYear != nil && *e.EndYear < *e.StartYear {
+		return ErrInvalidEducationYears
+	}
+
+	return nil
+}
+
+// ValidateEducation checks all education entries of a profile
+func ValidateEducation(education []Education) error {
+	if len(education) > MaxEducationEntries {
+		return ErrTooManyEducationEntries
+	}
+	for i := range education {
+		if err := education[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // TutorSearchFilters represents filters for searching tutors
@@ -83,4 +177,141 @@ type TutorSearchFilters struct {
 	MinAge          int      `json:"min_age,omitempty"` // Filter by minimum age
 	MaxAge          int      `json:"max_age,omitempty"` // Filter by maximum age
 	Sex             string   `json:"sex,omitempty"`     // Filter by sex (male, female)
+	OffersTrial     bool     `json:"offers_trial"`      // Only tutors who offer trial lessons
+	VerifiedOnly    bool     `json:"verified_only"`     // Only tutors with a verified credential
+	Query           string   `json:"q,omitempty"`       // Free text matched against names, bios and education
+
+	// Availability, in the student's time zone
+	Days                []int  `json:"days,omitempty"`                  // Days of week the tutor works on any of, 0 is Sunday
+	FromTime            string `json:"from_time,omitempty"`             // Start of the hours the tutor works in, HH:MM
+	ToTime              string `json:"to_time,omitempty"`               // End of those hours, HH:MM; before FromTime for hours past midnight
+	TimeZone            string `json:"tz,omitempty"`                    // IANA time zone of the student, UTC by default
+	AvailableWithinDays int    `json:"available_within_days,omitempty"` // Tutor has an open slot within this many days
+
+	Sort   TutorSort `json:"sort,omitempty"`
+	Limit  int       `json:"limit,omitempty"`
+	Offset int       `json:"offset,omitempty"`
+}
+
+// HasTimeWindow reports whether the filters ask for tutors working on certain days or hours
+func (f *TutorSearchFilters) HasTimeWindow() bool {
+	return len(f.Days) > 0 || f.FromTime != "" || f.ToTime != ""
+}
+
+// TimeWindow returns the start of the hours of the time window as an offset from midnight and
+// their length. Without a start the hours start at midnight, without an end they last until
+// midnight.
+func (f *TutorSearchFilters) TimeWindow() (start, length time.Duration) {
+	from, _ := parseTimeOfDay(f.FromTime)
+	to := 24 * time.Hour
+	if f.ToTime != "" {
+		to, _ = parseTimeOfDay(f.ToTime)
+		if to <= from {
+			to += 24 * time.Hour
+		}
+	}
+	return from, to - from
+}
+
+// ValidateAvailability checks the availability filters
+func (f *TutorSearchFilters) ValidateAvailability() error {
+	if err := ValidateTimeZone(f.TimeZone); err != nil {
+		return err
+	}
+	for _, day := range f.Days {
+		if day < 0 || day > 6 {
+			return ErrInvalidAvailabilityFilter
+		}
+	}
+	for _, value := range []string{f.FromTime, f.ToTime} {
+		if _, err := parseTimeOfDay(value); value != "" && err != nil {
+			return ErrInvalidAvailabilityFilter
+		}
+	}
+	if f.AvailableWithinDays < 0 || f.AvailableWithinDays > MaxAvailableWithinDays {
+		return ErrInvalidAvailabilityFilter
+	}
+	return nil
+}
+
+// ValidateTimeZone checks that a time zone is an IANA time zone name; empty means UTC
+func ValidateTimeZone(name string) error {
+	if name == "Local" {
+		return ErrInvalidTimeZone
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return ErrInvalidTimeZone
+	}
+	return nil
+}
+
+// parseTimeOfDay parses an HH:MM time of day into an offset from midnight
+func parseTimeOfDay(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// weeklyWindow returns the start of a time of day range as an offset from midnight and its
+// length. The times are HH:MM, optionally with seconds; an end not after the start is on the
+// next day.
+func weeklyWindow(startTime, endTime string) (start, length time.Duration) {
+	if len(startTime) > 5 {
+		startTime = startTime[:5]
+	}
+	if len(endTime) > 5 {
+		endTime = endTime[:5]
+	}
+	start, _ = parseTimeOfDay(startTime)
+	end, _ := parseTimeOfDay(endTime)
+	if end <= start {
+		end += 24 * time.Hour
+	}
+	return start, end - start
+}
+
+// TutorSort is the order of tutor search results
+type TutorSort string
+
+const (
+	TutorSortRating     TutorSort = "rating"     // Best rated first
+	TutorSortPrice      TutorSort = "price"      // Cheapest first
+	TutorSortExperience TutorSort = "experience" // Most experienced first
+	TutorSortNewest     TutorSort = "newest"     // Newest profiles first
+	TutorSortRelevance  TutorSort = "relevance"  // Best match of the free-text query first
+)
+
+// FacetCount is the number of tutors one option of a search filter yields
+type FacetCount struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// TutorSearchFacets counts the tutors each option of the language, interest and
+// proficiency filters yields
+type TutorSearchFacets struct {
+	Languages     []FacetCount `json:"languages"`
+	Interests     []FacetCount `json:"interests"`
+	Proficiencies []FacetCount `json:"proficiencies"`
+}
+
+// TutorSearchPage is a page of tutor search results
+type TutorSearchPage struct {
+	Tutors []TutorProfile    `json:"tutors"`
+	Total  int               `json:"total"`
+	Limit  int               `json:"limit"`
+	Offset int               `json:"offset"`
+	Sort   TutorSort         `json:"sort"`
+	Facets TutorSearchFacets `json:"facets"`
+}
+
+// TrialConversionStats represents how many of a tutor's trial students went on to book a paid lesson
+type TrialConversionStats struct {
+	TrialLessons      int     `json:"trial_lessons"`
+	CompletedTrials   int     `json:"completed_trials"`
+	ConvertedStudents int     `json:"converted_students"`
+	ConversionRate    float64 `json:"conversion_rate"`
 }
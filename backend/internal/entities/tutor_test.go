@@ -0,0 +1,84 @@
+package entities
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEducationValidate(t *testing.T) {
+	thisYear := time.Now().Year()
+	maxYear := thisYear + maxEducationYearsAhead
+	longest := strings.Repeat("é", maxEducationTextLength) // Counted in characters, not bytes
+	long := longest + "é"
+
+	tests := []struct {
+		name string
+		edu  Education
+		want error
+	}{
+		{name: "without years", edu: Education{Degree: "BA", Institution: "MSU"}},
+		{name: "finished", edu: Education{Degree: "BA", Institution: "MSU", StartYear: ptr(2010), EndYear: ptr(2014)}},
+		{name: "under way", edu: Education{Degree: "PhD", Institution: "MSU", StartYear: ptr(thisYear)}},
+		{name: "earliest year", edu: Education{Degree: "BA", Institution: "MSU", StartYear: ptr(MinEducationYear), EndYear: ptr(MinEducationYear)}},
+		{name: "latest end year", edu: Education{Degree: "BA", Institution: "MSU", StartYear: ptr(thisYear), EndYear: ptr(maxYear)}},
+		{name: "same start and end year", edu: Education{Degree: "MA", Institution: "MSU", StartYear: ptr(2015), EndYear: ptr(2015)}},
+		{name: "longest text", edu: Education{Degree: longest, Institution: "MSU"}},
+		{name: "start before the earliest year", edu: Education{Degree: "BA", Institution: "MSU", StartYear: ptr(MinEducationYear - 1)}, want: ErrInvalidEducationYears},
+		{name: "end before the earliest year", edu: Education{Degree: "BA", Institution: "MSU", EndYear: ptr(MinEducationYear - 1)}, want: ErrInvalidEducationYears},
+		{name: "end after the latest year", edu: Education{Degree: "BA", Institution: "MSU", StartYear: ptr(thisYear), EndYear: ptr(maxYear + 1)}, want: ErrInvalidEducationYears},
+		{name: "start in the future", edu: Education{Degree: "BA", Institution: "MSU", StartYear: ptr(thisYear + 1)}, want: ErrInvalidEducationYears},
+		{name: "end before start", edu: Education{Degree: "BA", Institution: "MSU", StartYear: ptr(2014), EndYear: ptr(2010)}, want: ErrInvalidEducationYears},
+		{name: "blank degree", edu: Education{Degree: "  ", Institution: "MSU"}, want: ErrInvalidEducation},
+		{name: "blank institution", edu: Education{Degree: "BA", Institution: "\t"}, want: ErrInvalidEducation},
+		{name: "degree too long", edu: Education{Degree: long, Institution: "MSU"}, want: ErrInvalidEducation},
+		{name: "institution too long", edu: Education{Degree: "BA", Institution: long}, want: ErrInvalidEducation},
+		{name: "field of study too long", edu: Education{Degree: "BA", Institution: "MSU", FieldOfStudy: long}, want: ErrInvalidEducation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edu := tt.edu
+			if err := edu.Validate(); !errors.Is(err, tt.want) {
+				t.Errorf("Validate() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEducationValidateTrims(t *testing.T) {
+	edu := Education{Degree: " BA ", Institution: "\tMSU\n", FieldOfStudy: " Linguistics "}
+	if err := edu.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+	if edu.Degree != "BA" || edu.Institution != "MSU" || edu.FieldOfStudy != "Linguistics" {
+		t.Errorf("Validate() left %q, %q, %q", edu.Degree, edu.Institution, edu.FieldOfStudy)
+	}
+}
+
+func TestValidateEducation(t *testing.T) {
+	valid := Education{Degree: "BA", Institution: "MSU"}
+
+	tests := []struct {
+		name      string
+		education []Education
+		want      error
+	}{
+		{name: "none", education: nil},
+		{name: "most entries", education: make([]Education, MaxEducationEntries)},
+		{name: "too many entries", education: make([]Education, MaxEducationEntries+1), want: ErrTooManyEducationEntries},
+		{name: "one invalid entry", education: []Education{valid, {Degree: "BA"}}, want: ErrInvalidEducation},
+	}
+	for i := range tests[1].education {
+		tests[1].education[i] = valid
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateEducation(tt.education); !errors.Is(err, tt.want) {
+				t.Errorf("ValidateEducation() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
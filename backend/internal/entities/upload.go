@@ -0,0 +1,186 @@
+package entities
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	ErrUploadNotFound       = errors.New("upload not found")
+	ErrInvalidUploadPurpose = errors.New("purpose must be one of avatar, intro_video, lesson_material, credential")
+	ErrFileTooLarge         = errors.New("file is too large")
+	ErrEmptyFile            = errors.New("file is empty")
+	ErrUnsupportedFileType  = errors.New("file type is not allowed for this purpose")
+	ErrUploadQuotaExceeded  = errors.New("upload quota exceeded, delete some files first")
+	ErrUploadAccessDenied   = errors.New("you do not have access to this file")
+	ErrWrongUploadPurpose   = errors.New("this file was uploaded for a different purpose")
+	ErrImageTooLarge        = errors.New("image dimensions are too large")
+)
+
+// UploadPurpose represents what an uploaded file is used for. It decides which file types
+// and sizes are accepted and who may download the file.
+type UploadPurpose string
+
+const (
+	UploadAvatar         UploadPurpose = "avatar"
+	UploadIntroVideo     UploadPurpose = "intro_video"
+	UploadLessonMaterial UploadPurpose = "lesson_material"
+	UploadCredential     UploadPurpose = "credential" // Proof of a tutor credential, seen by admins
+)
+
+// MaxUploadSize is the size of the largest file accepted for any purpose
+const MaxUploadSize = 200 << 20
+
+// AvatarSizes lists the widths in pixels of the square variants made of every avatar, smallest first
+var AvatarSizes = []int{64, 128, 512}
+
+const maxFilenameLength = 255
+
+// uploadRules holds the maximum size and the accepted content types, with the file
+// extension stored for each, of every purpose
+var uploadRules = map[UploadPurpose]struct {
+	maxSize int64
+	types   map[string]string
+}{
+	UploadAvatar: {
+		maxSize: 5 << 20,
+		types: map[string]string{
+			"image/jpeg": ".jpg",
+			"image/png":  ".png",
+			"image/gif":  ".gif",
+			"image/webp": ".webp",
+		},
+	},
+	UploadIntroVideo: {
+		maxSize: MaxUploadSize,
+		types: map[string]string{
+			"video/mp4":  ".mp4",
+			"video/webm": ".webm",
+		},
+	},
+	UploadLessonMaterial: {
+		maxSize: 25 << 20,
+		types: map[string]string{
+			"application/pdf": ".pdf",
+			"image/jpeg":      ".jpg",
+			"image/png":       ".png",
+			"image/webp":      ".webp",
+			"audio/mpeg":      ".mp3",
+			"audio/wave":      ".wav",
+			"application/ogg": ".ogg",
+			"text/plain":      ".txt",
+		},
+	},
+	UploadCredential: {
+		maxSize: 10 << 20,
+		types: map[string]string{
+			"application/pdf": ".pdf",
+			"image/jpeg":      ".jpg",
+			"image/png":       ".png",
+		},
+	},
+}
+
+// Validate checks if the purpose is known
+func (p UploadPurpose) Validate() error {
+	if _, ok := uploadRules[p]; !ok {
+		return ErrInvalidUploadPurpose
+	}
+	return nil
+}
+
+// MaxSize returns the size of the largest file accepted for the purpose
+func (p UploadPurpose) MaxSize() int64 {
+	return uploadRules[p].maxSize
+}
+
+// Extension returns the extension files of a content type are stored with, and whether
+// the content type is accepted for the purpose
+func (p UploadPurpose) Extension(contentType string) (string, bool) {
+	ext, ok := uploadRules[p].types[contentType]
+	return ext, ok
+}
+
+// IsPublic checks if files of the purpose are shown on public profiles, so anyone may download them
+func (p UploadPurpose) IsPublic() bool {
+	return p == UploadAvatar || p == UploadIntroVideo
+}
+
+// IsProcessed checks if files of the purpose are turned into variants by a background job.
+// Only the variants are public then, since the originals may carry metadata such as the
+// location a photo was taken at.
+func (p UploadPurpose) IsProcessed() bool {
+	return p == UploadAvatar
+}
+
+// Upload represents a file a user uploaded. URL is a signed download link valid until
+// URLExpiresAt; ContentURL is a permanent link to public files.
+type Upload struct {
+	ID           int           `json:"id"`
+	UserID       int           `json:"user_id"`
+	Purpose      UploadPurpose `json:"purpose"`
+	StorageKey   string        `json:"-"`
+	Filename     string        `json:"filename"`
+	ContentType  string        `json:"content_type"`
+	Size         int64         `json:"size"`
+	CreatedAt    time.Time     `json:"created_at"`
+	URL          string        `json:"url,omitempty"`
+	URLExpiresAt *time.Time    `json:"url_expires_at,omitempty"`
+	ContentURL   string        `json:"content_url,omitempty"`
+}
+
+// UploadVariant represents a processed copy of an upload, such as a square avatar of a given width
+type UploadVariant struct {
+	UploadID    int    `json:"upload_id"`
+	Width       int    `json:"width"`
+	StorageKey  string `json:"-"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+// UploadQuota represents how much of their upload quota a user has used
+type UploadQuota struct {
+	UsedBytes  int64 `json:"used_bytes"`
+	LimitBytes int64 `json:"limit_bytes"`
+}
+
+// UploadList represents a user's uploads with their quota
+type UploadList struct {
+	Uploads []Upload    `json:"uploads"`
+	Quota   UploadQuota `json:"quota"`
+}
+
+// UploadReference represents a request to use an uploaded file, such as a new avatar
+type UploadReference struct {
+	UploadID int `json:"upload_id" binding:"required"`
+}
+
+// LessonMaterial represents an uploaded file shared with the attendees of a lesson
+type LessonMaterial struct {
+	Upload
+	LessonID   int       `json:"lesson_id"`
+	AttachedAt time.Time `json:"attached_at"`
+}
+
+// CleanFilename reduces a client-supplied file name to its base name without control
+// characters, cut to at most 255 characters
+func CleanFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		return "file"
+	}
+	if runes := []rune(name); len(runes) > maxFilenameLength {
+		name = string(runes[:maxFilenameLength])
+	}
+	return name
+}
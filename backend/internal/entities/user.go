@@ -20,6 +20,8 @@ type User struct {
 	Role              string    `json:"role"`
 	CreatedAt         time.Time `json:"created_at"`
 	UpdatedAt         time.Time `json:"updated_at"`
+	// AvatarURLs maps the widths in AvatarSizes to square versions of an uploaded profile picture
+	AvatarURLs map[int]string `json:"avatar_urls,omitempty"`
 }
 
 // UserRegistrationRequest represents data needed for user registration
@@ -0,0 +1,316 @@
+package interfaces
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/usecases"
+	"tongly-backend/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler handles HTTP requests for platform administration.
+// Registration only creates students and tutors; admins are promoted in the database
+// with UPDATE users SET role = 'admin' and must log in again to get a token with the new role.
+type AdminHandler struct {
+	jobUseCase        *usecases.JobUseCase
+	messageUseCase    *usecases.MessageUseCase
+	reviewUseCase     *usecases.ReviewUseCase
+	credentialUseCase *usecases.CredentialUseCase
+}
+
+// NewAdminHandler creates a new AdminHandler
+func NewAdminHandler(
+	jobUseCase *usecases.JobUseCase,
+	messageUseCase *usecases.MessageUseCase,
+	reviewUseCase *usecases.ReviewUseCase,
+	credentialUseCase *usecases.CredentialUseCase,
+) *AdminHandler {
+	return &AdminHandler{
+		jobUseCase:        jobUseCase,
+		messageUseCase:    messageUseCase,
+		reviewUseCase:     reviewUseCase,
+		credentialUseCase: credentialUseCase,
+	}
+}
+
+// ListJobs handles the request to list background jobs, optionally filtered by status and type
+func (h *AdminHandler) ListJobs(c *gin.Context) {
+	filters := &entities.JobFilters{
+		Status: entities.JobStatus(c.Query("status")),
+		Type:   entities.JobType(c.Query("type")),
+	}
+
+	switch filters.Status {
+	case "", entities.JobStatusPending, entities.JobStatusRunning, entities.JobStatusSucceeded, entities.JobStatusFailed:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job status"})
+		return
+	}
+
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil {
+		filters.Limit = limit
+	}
+	if offset, err := strconv.Atoi(c.Query("offset")); err == nil {
+		filters.Offset = offset
+	}
+
+	jobs, err := h.jobUseCase.ListJobs(c.Request.Context(), filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve jobs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, jobs)
+}
+
+// RetryJob handles the request to run a failed job again
+func (h *AdminHandler) RetryJob(c *gin.Context) {
+	jobID, err := strconv.ParseInt(c.Param("jobId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+
+	job, err := h.jobUseCase.RetryJob(c.Request.Context(), jobID)
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrJobNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, entities.ErrJobNotRetryable):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retry job"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// ListConversationReports handles the request to list reported conversations and messages.
+// Only unresolved reports are listed unless status=all is given.
+func (h *AdminHandler) ListConversationReports(c *gin.Context) {
+	filters := &entities.ReportFilters{
+		OpenOnly: c.Query("status") != "all",
+	}
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil {
+		filters.Limit = limit
+	}
+	if offset, err := strconv.Atoi(c.Query("offset")); err == nil {
+		filters.Offset = offset
+	}
+
+	reports, err := h.messageUseCase.ListReports(c.Request.Context(), filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve reports"})
+		return
+	}
+
+	c.JSON(http.StatusOK, reports)
+}
+
+// ResolveConversationReport handles the request to mark a conversation report as handled
+func (h *AdminHandler) ResolveConversationReport(c *gin.Context) {
+	reportID, err := strconv.Atoi(c.Param("reportId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report ID"})
+		return
+	}
+
+	if err := h.messageUseCase.ResolveReport(c.Request.Context(), reportID); err != nil {
+		if errors.Is(err, entities.ErrReportNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve report"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Report resolved"})
+}
+
+// ListReviewReports handles the request to list the review moderation queue: reviews with
+// unresolved reports by default, or hidden reviews with status=hidden
+func (h *AdminHandler) ListReviewReports(c *gin.Context) {
+	filters := &entities.ReviewModerationFilters{
+		Status: entities.ReviewModerationStatus(c.Query("status")),
+	}
+
+	switch filters.Status {
+	case "", entities.ReviewModerationReported, entities.ReviewModerationHidden:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid moderation status"})
+		return
+	}
+
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil {
+		filters.Limit = limit
+	}
+	if offset, err := strconv.Atoi(c.Query("offset")); err == nil {
+		filters.Offset = offset
+	}
+
+	reviews, err := h.reviewUseCase.ListModerationQueue(c.Request.Context(), filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve reviews"})
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// HideReview handles the request to take a review off a tutor's profile
+func (h *AdminHandler) HideReview(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	reviewID, err := strconv.Atoi(c.Param("reviewId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+
+	// The reason is optional, so an empty body is accepted
+	var req entities.HideReviewRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+	}
+
+	if err := h.reviewUseCase.HideReview(c.Request.Context(), adminID.(int), reviewID, &req); err != nil {
+		if errors.Is(err, entities.ErrReviewNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hide review"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Review hidden"})
+}
+
+// RestoreReview handles the request to show a hidden review again or to dismiss its reports
+func (h *AdminHandler) RestoreReview(c *gin.Context) {
+	reviewID, err := strconv.Atoi(c.Param("reviewId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+
+	if err := h.reviewUseCase.RestoreReview(c.Request.Context(), reviewID); err != nil {
+		if errors.Is(err, entities.ErrReviewNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore review"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Review restored"})
+}
+
+// ListCredentials handles the request to list tutor credentials for review: pending ones by
+// default, or verified or rejected ones with status
+func (h *AdminHandler) ListCredentials(c *gin.Context) {
+	filters := &entities.CredentialFilters{
+		Status: entities.CredentialStatus(c.Query("status")),
+	}
+
+	switch filters.Status {
+	case "", entities.CredentialPending, entities.CredentialVerified, entities.CredentialRejected:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid credential status"})
+		return
+	}
+
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil {
+		filters.Limit = limit
+	}
+	if offset, err := strconv.Atoi(c.Query("offset")); err == nil {
+		filters.Offset = offset
+	}
+
+	credentials, err := h.credentialUseCase.ListReviewQueue(c.Request.Context(), filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve credentials"})
+		return
+	}
+
+	c.JSON(http.StatusOK, credentials)
+}
+
+// VerifyCredential handles the request to verify a tutor credential after checking its proof document
+func (h *AdminHandler) VerifyCredential(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	credentialID, err := strconv.Atoi(c.Param("credentialId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid credential ID"})
+		return
+	}
+
+	if err := h.credentialUseCase.VerifyCredential(c.Request.Context(), adminID.(int), credentialID); err != nil {
+		respondCredentialError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Credential verified"})
+}
+
+// RejectCredential handles the request to reject a tutor credential with a reason for the tutor
+func (h *AdminHandler) RejectCredential(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	credentialID, err := strconv.Atoi(c.Param("credentialId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid credential ID"})
+		return
+	}
+
+	var req entities.RejectCredentialRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	if err := h.credentialUseCase.RejectCredential(c.Request.Context(), adminID.(int), credentialID, &req); err != nil {
+		respondCredentialError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Credential rejected"})
+}
+
+// RegisterRoutes registers the admin routes
+func (h *AdminHandler) RegisterRoutes(router *gin.Engine) {
+	admin := router.Group("/api/admin")
+	admin.Use(middleware.AuthMiddleware(), middleware.RoleMiddleware("admin"))
+	{
+		admin.GET("/jobs", h.ListJobs)
+		admin.POST("/jobs/:jobId/retry", h.RetryJob)
+		admin.GET("/conversation-reports", h.ListConversationReports)
+		admin.POST("/conversation-reports/:reportId/resolve", h.ResolveConversationReport)
+		admin.GET("/review-reports", h.ListReviewReports)
+		admin.POST("/reviews/:reviewId/hide", h.HideReview)
+		admin.POST("/reviews/:reviewId/restore", h.RestoreReview)
+		admin.GET("/credentials", h.ListCredentials)
+		admin.POST("/credentials/:credentialId/verify", h.VerifyCredential)
+		admin.POST("/credentials/:credentialId/reject", h.RejectCredential)
+	}
+}
@@ -0,0 +1,81 @@
+package interfaces
+
+import (
+	"errors"
+	"net/http"
+	"time"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/logger"
+	"tongly-backend/internal/usecases"
+	"tongly-backend/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AnalyticsHandler handles HTTP requests for tutor analytics
+type AnalyticsHandler struct {
+	analyticsUseCase *usecases.AnalyticsUseCase
+}
+
+// NewAnalyticsHandler creates a new AnalyticsHandler
+func NewAnalyticsHandler(analyticsUseCase *usecases.AnalyticsUseCase) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsUseCase: analyticsUseCase,
+	}
+}
+
+// GetTutorAnalytics handles the request to retrieve the current tutor's analytics in
+// [from, to), the last 12 months by default, grouped by period=day|week|month. Ranges are
+// limited to a year by day and five years by week or month.
+func (h *AnalyticsHandler) GetTutorAnalytics(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	// Default to the last 12 months
+	to := time.Now()
+	from := to.AddDate(-1, 0, 0)
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		parsed, err := parseTimeParam(fromStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date"})
+			return
+		}
+		from = parsed
+	}
+	if toStr := c.Query("to"); toStr != "" {
+		parsed, err := parseTimeParam(toStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date"})
+			return
+		}
+		to = parsed
+	}
+
+	period := entities.EarningsPeriod(c.DefaultQuery("period", string(entities.EarningsPeriodMonth)))
+
+	analytics, err := h.analyticsUseCase.GetTutorAnalytics(c.Request.Context(), userID.(int), from, to, period)
+	if err != nil {
+		if errors.Is(err, entities.ErrInvalidAnalyticsRange) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("Failed to retrieve tutor analytics", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve analytics"})
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
+
+// RegisterRoutes registers the analytics routes
+func (h *AnalyticsHandler) RegisterRoutes(router *gin.Engine) {
+	tutor := router.Group("/api/tutor")
+	tutor.Use(middleware.AuthMiddleware(), middleware.RoleMiddleware("tutor"))
+	{
+		tutor.GET("/analytics", h.GetTutorAnalytics)
+	}
+}
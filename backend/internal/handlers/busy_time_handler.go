@@ -0,0 +1,227 @@
+package interfaces
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/logger"
+	"tongly-backend/internal/usecases"
+	"tongly-backend/pkg/ical"
+	"tongly-backend/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BusyTimeHandler handles HTTP requests for tutors' external calendars and busy times
+type BusyTimeHandler struct {
+	busyTimeUseCase *usecases.BusyTimeUseCase
+}
+
+// NewBusyTimeHandler creates a new BusyTimeHandler
+func NewBusyTimeHandler(busyTimeUseCase *usecases.BusyTimeUseCase) *BusyTimeHandler {
+	return &BusyTimeHandler{
+		busyTimeUseCase: busyTimeUseCase,
+	}
+}
+
+// GetCalendarSources handles the request to list the current tutor's external calendars
+func (h *BusyTimeHandler) GetCalendarSources(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	sources, err := h.busyTimeUseCase.GetSources(c.Request.Context(), userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve calendars"})
+		return
+	}
+
+	c.JSON(http.StatusOK, sources)
+}
+
+// AddCalendarURL handles the request to subscribe to an external calendar by URL
+func (h *BusyTimeHandler) AddCalendarURL(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req entities.CalendarSourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	source, err := h.busyTimeUseCase.AddURLSource(c.Request.Context(), userID.(int), &req)
+	if err != nil {
+		h.respondCalendarError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, source)
+}
+
+// UploadCalendar handles the request to import an .ics file sent as the multipart field "file"
+func (h *BusyTimeHandler) UploadCalendar(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, entities.MaxCalendarSize+64<<10)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Calendar file is required"})
+		return
+	}
+	if fileHeader.Size > entities.MaxCalendarSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": entities.ErrCalendarTooLarge.Error()})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read calendar file"})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, entities.MaxCalendarSize+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read calendar file"})
+		return
+	}
+
+	name := c.PostForm("name")
+	if name == "" {
+		name = fileHeader.Filename
+	}
+
+	source, err := h.busyTimeUseCase.AddUploadedSource(c.Request.Context(), userID.(int), name, data)
+	if err != nil {
+		h.respondCalendarError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, source)
+}
+
+// SyncCalendar handles the request to refresh an external calendar right away
+func (h *BusyTimeHandler) SyncCalendar(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	sourceID, err := strconv.Atoi(c.Param("sourceId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid calendar ID"})
+		return
+	}
+
+	source, err := h.busyTimeUseCase.SyncSource(c.Request.Context(), userID.(int), sourceID)
+	if err != nil {
+		h.respondCalendarError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, source)
+}
+
+// DeleteCalendar handles the request to remove an external calendar
+func (h *BusyTimeHandler) DeleteCalendar(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	sourceID, err := strconv.Atoi(c.Param("sourceId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid calendar ID"})
+		return
+	}
+
+	if err := h.busyTimeUseCase.DeleteSource(c.Request.Context(), userID.(int), sourceID); err != nil {
+		h.respondCalendarError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Calendar deleted successfully"})
+}
+
+// GetTutorBusyTimes handles the request to retrieve when a tutor is busy according to their
+// external calendars. Only the intervals are returned, never the events behind them.
+func (h *BusyTimeHandler) GetTutorBusyTimes(c *gin.Context) {
+	tutorID, err := strconv.Atoi(c.Param("tutorId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tutor ID"})
+		return
+	}
+
+	// Default to the next 30 days
+	from := time.Now()
+	to := from.AddDate(0, 0, 30)
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		if from, err = parseTimeParam(fromStr); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from time"})
+			return
+		}
+	}
+	if toStr := c.Query("to"); toStr != "" {
+		if to, err = parseTimeParam(toStr); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to time"})
+			return
+		}
+	}
+
+	busyTimes, err := h.busyTimeUseCase.GetBusyTimes(c.Request.Context(), tutorID, from, to)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, busyTimes)
+}
+
+// respondCalendarError maps errors of the external calendar use cases to responses
+func (h *BusyTimeHandler) respondCalendarError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, entities.ErrCalendarSourceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, entities.ErrTooManyCalendarSources):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, entities.ErrInvalidCalendarURL), errors.Is(err, entities.ErrCalendarTooLarge),
+		errors.Is(err, entities.ErrCalendarUnavailable), errors.Is(err, ical.ErrInvalidCalendar):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		logger.Error("External calendar request failed", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process calendar"})
+	}
+}
+
+// RegisterRoutes registers the external calendar routes
+func (h *BusyTimeHandler) RegisterRoutes(router *gin.Engine) {
+	// Public routes (no authentication required)
+	router.GET("/api/tutors/:tutorId/busy-times", h.GetTutorBusyTimes)
+
+	tutor := router.Group("/api/tutor/calendars")
+	tutor.Use(middleware.AuthMiddleware(), middleware.RoleMiddleware("tutor"))
+	{
+		tutor.GET("", h.GetCalendarSources)
+		tutor.POST("", h.AddCalendarURL)
+		tutor.POST("/upload", h.UploadCalendar)
+		tutor.POST("/:sourceId/sync", h.SyncCalendar)
+		tutor.DELETE("/:sourceId", h.DeleteCalendar)
+	}
+}
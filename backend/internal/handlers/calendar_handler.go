@@ -0,0 +1,113 @@
+package interfaces
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/logger"
+	"tongly-backend/internal/usecases"
+	"tongly-backend/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+const calendarContentType = "text/calendar; charset=utf-8"
+
+// CalendarHandler handles HTTP requests for iCalendar feeds
+type CalendarHandler struct {
+	calendarUseCase *usecases.CalendarUseCase
+	apiURL          string
+}
+
+// NewCalendarHandler creates a new CalendarHandler. Subscription URLs point at apiURL, the
+// public address of the API.
+func NewCalendarHandler(calendarUseCase *usecases.CalendarUseCase, apiURL string) *CalendarHandler {
+	return &CalendarHandler{
+		calendarUseCase: calendarUseCase,
+		apiURL:          strings.TrimRight(apiURL, "/"),
+	}
+}
+
+// GetCalendarFeed handles the request to retrieve the current user's calendar subscription URL
+func (h *CalendarHandler) GetCalendarFeed(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	feed, err := h.calendarUseCase.GetFeed(c.Request.Context(), userID.(int))
+	if err != nil {
+		logger.Error("Failed to retrieve calendar feed", "error", err, "user_id", userID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve calendar feed"})
+		return
+	}
+
+	h.setCalendarFeedURLs(feed)
+	c.JSON(http.StatusOK, feed)
+}
+
+// ResetCalendarFeed handles the request to replace the current user's calendar subscription URL
+func (h *CalendarHandler) ResetCalendarFeed(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	feed, err := h.calendarUseCase.ResetFeed(c.Request.Context(), userID.(int))
+	if err != nil {
+		logger.Error("Failed to reset calendar feed", "error", err, "user_id", userID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset calendar feed"})
+		return
+	}
+
+	h.setCalendarFeedURLs(feed)
+	c.JSON(http.StatusOK, feed)
+}
+
+// GetFeedCalendar handles calendar clients polling a subscription URL. The secret token
+// in the URL authenticates the request, since calendar clients cannot send a JWT.
+func (h *CalendarHandler) GetFeedCalendar(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	data, err := h.calendarUseCase.GetFeedCalendar(c.Request.Context(), token)
+	if err != nil {
+		if errors.Is(err, entities.ErrCalendarFeedNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("Failed to render calendar feed", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render calendar feed"})
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, calendarContentType, data)
+}
+
+// setCalendarFeedURLs fills in the subscription URLs of the feed. They are built from the
+// configured API address rather than the request's Host header, which the client controls.
+func (h *CalendarHandler) setCalendarFeedURLs(feed *entities.CalendarFeed) {
+	feed.URL = h.apiURL + "/api/calendar/" + feed.Token + ".ics"
+
+	// webcal:// makes calendar apps offer to subscribe instead of downloading the file once
+	feed.WebcalURL = feed.URL
+	if i := strings.Index(feed.URL, "://"); i >= 0 {
+		feed.WebcalURL = "webcal" + feed.URL[i:]
+	}
+}
+
+// RegisterRoutes registers the calendar routes
+func (h *CalendarHandler) RegisterRoutes(router *gin.Engine) {
+	// Public route authenticated by the feed token
+	router.GET("/api/calendar/:token", h.GetFeedCalendar)
+
+	user := router.Group("/api/user")
+	user.Use(middleware.AuthMiddleware())
+	{
+		user.GET("/calendar-feed", h.GetCalendarFeed)
+		user.POST("/calendar-feed/reset", h.ResetCalendarFeed)
+	}
+}
@@ -0,0 +1,167 @@
+package interfaces
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/logger"
+	"tongly-backend/internal/usecases"
+	"tongly-backend/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CredentialHandler handles HTTP requests for tutors' credentials
+type CredentialHandler struct {
+	credentialUseCase *usecases.CredentialUseCase
+}
+
+// NewCredentialHandler creates a new CredentialHandler
+func NewCredentialHandler(credentialUseCase *usecases.CredentialUseCase) *CredentialHandler {
+	return &CredentialHandler{
+		credentialUseCase: credentialUseCase,
+	}
+}
+
+// GetCredentials handles the request to retrieve the tutor's own credentials and their review status
+func (h *CredentialHandler) GetCredentials(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	credentials, err := h.credentialUseCase.GetCredentials(c.Request.Context(), userID.(int))
+	if err != nil {
+		logger.Error("Failed to get credentials", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve credentials"})
+		return
+	}
+
+	c.JSON(http.StatusOK, credentials)
+}
+
+// AddCredential handles the request to add a credential for review
+func (h *CredentialHandler) AddCredential(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req entities.CredentialRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	credential, err := h.credentialUseCase.AddCredential(c.Request.Context(), userID.(int), &req)
+	if err != nil {
+		respondCredentialError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, credential)
+}
+
+// UpdateCredential handles the request to change a credential, which is then reviewed again
+func (h *CredentialHandler) UpdateCredential(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	credentialID, err := strconv.Atoi(c.Param("credentialId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid credential ID"})
+		return
+	}
+
+	var req entities.CredentialRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	credential, err := h.credentialUseCase.UpdateCredential(c.Request.Context(), userID.(int), credentialID, &req)
+	if err != nil {
+		respondCredentialError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, credential)
+}
+
+// DeleteCredential handles the request to delete a credential
+func (h *CredentialHandler) DeleteCredential(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	credentialID, err := strconv.Atoi(c.Param("credentialId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid credential ID"})
+		return
+	}
+
+	if err := h.credentialUseCase.DeleteCredential(c.Request.Context(), userID.(int), credentialID); err != nil {
+		respondCredentialError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Credential deleted successfully"})
+}
+
+// GetTutorCredentials handles the request to retrieve the verified credentials of a tutor
+func (h *CredentialHandler) GetTutorCredentials(c *gin.Context) {
+	tutorID, err := strconv.Atoi(c.Param("tutorId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tutor ID"})
+		return
+	}
+
+	credentials, err := h.credentialUseCase.GetVerifiedCredentials(c.Request.Context(), tutorID)
+	if err != nil {
+		logger.Error("Failed to get tutor credentials", "tutor_id", tutorID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve credentials"})
+		return
+	}
+
+	c.JSON(http.StatusOK, credentials)
+}
+
+// respondCredentialError maps errors of the credential use cases to responses
+func respondCredentialError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, entities.ErrCredentialNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, entities.ErrTooManyCredentials):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, entities.ErrInvalidCredentialKind), errors.Is(err, entities.ErrInvalidCredential),
+		errors.Is(err, entities.ErrInvalidCredentialYear), errors.Is(err, entities.ErrCredentialDocument),
+		errors.Is(err, entities.ErrRejectionReasonRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		logger.Error("Credential request failed", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process credential"})
+	}
+}
+
+// RegisterRoutes registers the credential routes
+func (h *CredentialHandler) RegisterRoutes(router *gin.Engine) {
+	// Public routes (no authentication required)
+	router.GET("/api/tutors/:tutorId/credentials", h.GetTutorCredentials)
+
+	tutor := router.Group("/api/tutor/credentials")
+	tutor.Use(middleware.AuthMiddleware(), middleware.RoleMiddleware("tutor"))
+	{
+		tutor.GET("", h.GetCredentials)
+		tutor.POST("", h.AddCredential)
+		tutor.PUT("/:credentialId", h.UpdateCredential)
+		tutor.DELETE("/:credentialId", h.DeleteCredential)
+	}
+}
@@ -0,0 +1,166 @@
+package interfaces
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/usecases"
+	"tongly-backend/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EarningsHandler handles HTTP requests for tutor earnings and payouts
+type EarningsHandler struct {
+	earningsUseCase *usecases.EarningsUseCase
+}
+
+// NewEarningsHandler creates a new EarningsHandler
+func NewEarningsHandler(earningsUseCase *usecases.EarningsUseCase) *EarningsHandler {
+	return &EarningsHandler{
+		earningsUseCase: earningsUseCase,
+	}
+}
+
+// GetEarnings handles the request to retrieve the current tutor's earnings
+func (h *EarningsHandler) GetEarnings(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	// Default to the last 12 months
+	to := time.Now()
+	from := to.AddDate(-1, 0, 0)
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		parsed, err := parseTimeParam(fromStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date"})
+			return
+		}
+		from = parsed
+	}
+	if toStr := c.Query("to"); toStr != "" {
+		parsed, err := parseTimeParam(toStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date"})
+			return
+		}
+		to = parsed
+	}
+
+	period := entities.EarningsPeriod(c.DefaultQuery("period", string(entities.EarningsPeriodMonth)))
+
+	earnings, err := h.earningsUseCase.GetEarnings(c.Request.Context(), userID.(int), from, to, period)
+	if err != nil {
+		if errors.Is(err, entities.ErrInvalidEarningsRange) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve earnings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, earnings)
+}
+
+// GetPayoutLedger handles the request to retrieve the current tutor's balances and payout ledger
+func (h *EarningsHandler) GetPayoutLedger(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	ledger, err := h.earningsUseCase.GetPayoutLedger(c.Request.Context(), userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve payout ledger"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ledger)
+}
+
+// RequestPayout handles the request to pay out part of the current tutor's available balance
+func (h *EarningsHandler) RequestPayout(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req entities.PayoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	payout, err := h.earningsUseCase.RequestPayout(c.Request.Context(), userID.(int), &req)
+	if err != nil {
+		if errors.Is(err, entities.ErrInvalidPayoutAmount) || errors.Is(err, entities.ErrInsufficientBalance) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to request payout"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, payout)
+}
+
+// GetMonthlyStatement handles the request to download a monthly statement as CSV or PDF
+func (h *EarningsHandler) GetMonthlyStatement(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	month, err := time.ParseInLocation("2006-01", c.Param("month"), time.UTC)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid month, expected YYYY-MM"})
+		return
+	}
+
+	var (
+		data        []byte
+		contentType string
+	)
+
+	format := c.DefaultQuery("format", "csv")
+	switch format {
+	case "csv":
+		data, err = h.earningsUseCase.GetMonthlyStatementCSV(c.Request.Context(), userID.(int), month)
+		contentType = "text/csv"
+	case "pdf":
+		data, err = h.earningsUseCase.GetMonthlyStatementPDF(c.Request.Context(), userID.(int), month)
+		contentType = "application/pdf"
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format, expected csv or pdf"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate statement"})
+		return
+	}
+
+	filename := fmt.Sprintf("tongly-statement-%s.%s", month.Format("2006-01"), format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// RegisterRoutes registers the earnings routes
+func (h *EarningsHandler) RegisterRoutes(router *gin.Engine) {
+	tutor := router.Group("/api/tutor")
+	tutor.Use(middleware.AuthMiddleware(), middleware.RoleMiddleware("tutor"))
+	{
+		tutor.GET("/earnings", h.GetEarnings)
+		tutor.GET("/earnings/statements/:month", h.GetMonthlyStatement)
+		tutor.GET("/payouts", h.GetPayoutLedger)
+		tutor.POST("/payouts", h.RequestPayout)
+	}
+}
@@ -0,0 +1,212 @@
+package interfaces
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/logger"
+	"tongly-backend/internal/usecases"
+	"tongly-backend/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FavoriteHandler handles HTTP requests for students' favorite tutors and saved searches
+type FavoriteHandler struct {
+	favoriteUseCase *usecases.FavoriteUseCase
+}
+
+// NewFavoriteHandler creates a new FavoriteHandler
+func NewFavoriteHandler(favoriteUseCase *usecases.FavoriteUseCase) *FavoriteHandler {
+	return &FavoriteHandler{
+		favoriteUseCase: favoriteUseCase,
+	}
+}
+
+// GetFavorites handles the request to retrieve the student's favorite tutors
+func (h *FavoriteHandler) GetFavorites(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	favorites, err := h.favoriteUseCase.GetFavorites(c.Request.Context(), userID.(int))
+	if err != nil {
+		logger.Error("Failed to get favorite tutors", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve favorite tutors"})
+		return
+	}
+
+	c.JSON(http.StatusOK, favorites)
+}
+
+// AddFavorite handles the request to add a tutor to the student's favorites
+func (h *FavoriteHandler) AddFavorite(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	tutorID, err := strconv.Atoi(c.Param("tutorId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tutor ID"})
+		return
+	}
+
+	if err := h.favoriteUseCase.AddFavorite(c.Request.Context(), userID.(int), tutorID); err != nil {
+		h.respondFavoriteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tutor added to favorites"})
+}
+
+// RemoveFavorite handles the request to remove a tutor from the student's favorites
+func (h *FavoriteHandler) RemoveFavorite(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	tutorID, err := strconv.Atoi(c.Param("tutorId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tutor ID"})
+		return
+	}
+
+	if err := h.favoriteUseCase.RemoveFavorite(c.Request.Context(), userID.(int), tutorID); err != nil {
+		h.respondFavoriteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tutor removed from favorites"})
+}
+
+// GetSavedSearches handles the request to retrieve the student's saved searches
+func (h *FavoriteHandler) GetSavedSearches(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	searches, err := h.favoriteUseCase.GetSavedSearches(c.Request.Context(), userID.(int))
+	if err != nil {
+		logger.Error("Failed to get saved searches", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve saved searches"})
+		return
+	}
+
+	c.JSON(http.StatusOK, searches)
+}
+
+// CreateSavedSearch handles the request to save a tutor search
+func (h *FavoriteHandler) CreateSavedSearch(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req entities.SavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	search, err := h.favoriteUseCase.CreateSavedSearch(c.Request.Context(), userID.(int), &req)
+	if err != nil {
+		h.respondFavoriteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, search)
+}
+
+// UpdateSavedSearch handles the request to change a saved search
+func (h *FavoriteHandler) UpdateSavedSearch(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	searchID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid saved search ID"})
+		return
+	}
+
+	var req entities.SavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	search, err := h.favoriteUseCase.UpdateSavedSearch(c.Request.Context(), userID.(int), searchID, &req)
+	if err != nil {
+		h.respondFavoriteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, search)
+}
+
+// DeleteSavedSearch handles the request to delete a saved search
+func (h *FavoriteHandler) DeleteSavedSearch(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	searchID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid saved search ID"})
+		return
+	}
+
+	if err := h.favoriteUseCase.DeleteSavedSearch(c.Request.Context(), userID.(int), searchID); err != nil {
+		h.respondFavoriteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Saved search deleted successfully"})
+}
+
+// respondFavoriteError maps errors of the favorite and saved search use cases to responses
+func (h *FavoriteHandler) respondFavoriteError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, entities.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tutor not found"})
+	case errors.Is(err, entities.ErrSavedSearchNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, entities.ErrTooManyFavorites), errors.Is(err, entities.ErrTooManySavedSearches):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, entities.ErrInvalidSavedSearch), errors.Is(err, entities.ErrInvalidAvailabilityFilter),
+		errors.Is(err, entities.ErrInvalidTimeZone):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		logger.Error("Favorite request failed", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process request"})
+	}
+}
+
+// RegisterRoutes registers the favorite tutor and saved search routes
+func (h *FavoriteHandler) RegisterRoutes(router *gin.Engine) {
+	student := router.Group("/api/student")
+	student.Use(middleware.AuthMiddleware(), middleware.RoleMiddleware("student"))
+	{
+		student.GET("/favorites", h.GetFavorites)
+		student.PUT("/favorites/:tutorId", h.AddFavorite)
+		student.DELETE("/favorites/:tutorId", h.RemoveFavorite)
+		student.GET("/saved-searches", h.GetSavedSearches)
+		student.POST("/saved-searches", h.CreateSavedSearch)
+		student.PUT("/saved-searches/:id", h.UpdateSavedSearch)
+		student.DELETE("/saved-searches/:id", h.DeleteSavedSearch)
+	}
+}
@@ -0,0 +1,224 @@
+package interfaces
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/logger"
+	"tongly-backend/internal/usecases"
+	"tongly-backend/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GroupClassHandler handles HTTP requests for the public group class catalog
+type GroupClassHandler struct {
+	groupClassUseCase *usecases.GroupClassUseCase
+	lessonUseCase     *usecases.LessonUseCase
+}
+
+// NewGroupClassHandler creates a new GroupClassHandler
+func NewGroupClassHandler(groupClassUseCase *usecases.GroupClassUseCase, lessonUseCase *usecases.LessonUseCase) *GroupClassHandler {
+	return &GroupClassHandler{
+		groupClassUseCase: groupClassUseCase,
+		lessonUseCase:     lessonUseCase,
+	}
+}
+
+// SearchGroupClasses handles the request to browse the group class catalog
+func (h *GroupClassHandler) SearchGroupClasses(c *gin.Context) {
+	var filters entities.GroupClassFilters
+
+	// Get language filter from query parameters
+	if languages := c.QueryArray("language"); len(languages) > 0 {
+		filters.Languages = languages
+	}
+
+	// Get proficiency filter
+	if proficiencyID, err := strconv.Atoi(c.Query("proficiency_id")); err == nil && proficiencyID > 0 {
+		filters.ProficiencyID = proficiencyID
+	}
+
+	// Get interests filter
+	for _, idStr := range c.QueryArray("interest") {
+		if id, err := strconv.Atoi(idStr); err == nil && id > 0 {
+			filters.Interests = append(filters.Interests, id)
+		}
+	}
+
+	// Get tutor filter
+	if tutorID, err := strconv.Atoi(c.Query("tutor_id")); err == nil && tutorID > 0 {
+		filters.TutorID = tutorID
+	}
+
+	// Get time window filter
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err := parseTimeParam(fromStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from time"})
+			return
+		}
+		filters.From = &from
+	}
+	if toStr := c.Query("to"); toStr != "" {
+		to, err := parseTimeParam(toStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to time"})
+			return
+		}
+		filters.To = &to
+	}
+
+	classes, err := h.groupClassUseCase.SearchGroupClasses(c.Request.Context(), &filters)
+	if err != nil {
+		if errors.Is(err, entities.ErrInvalidTimeWindow) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Error("Failed to search group classes", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search group classes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, classes)
+}
+
+// GetGroupClass handles the request to retrieve a group class from the catalog
+func (h *GroupClassHandler) GetGroupClass(c *gin.Context) {
+	classID, err := strconv.Atoi(c.Param("classId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid class ID"})
+		return
+	}
+
+	class, err := h.groupClassUseCase.GetGroupClass(c.Request.Context(), classID)
+	if err != nil {
+		if errors.Is(err, entities.ErrGroupClassNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve group class"})
+		return
+	}
+
+	c.JSON(http.StatusOK, class)
+}
+
+// CreateGroupClass handles the tutor's request to publish a group class
+func (h *GroupClassHandler) CreateGroupClass(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req entities.GroupClassRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	class, err := h.groupClassUseCase.CreateGroupClass(c.Request.Context(), userID.(int), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrInvalidGroupClass), errors.Is(err, entities.ErrLanguageNotFound),
+			errors.Is(err, entities.ErrUnknownProficiency), errors.Is(err, entities.ErrUnknownInterest):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			logger.Error("Failed to create group class", "user_id", userID, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create group class"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, class)
+}
+
+// Enroll handles the student's request to join a group class
+func (h *GroupClassHandler) Enroll(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	classID, err := strconv.Atoi(c.Param("classId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid class ID"})
+		return
+	}
+
+	// Only published classes can be joined from the catalog
+	if _, err := h.groupClassUseCase.GetGroupClass(c.Request.Context(), classID); err != nil {
+		if errors.Is(err, entities.ErrGroupClassNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve group class"})
+		return
+	}
+
+	seat, err := h.lessonUseCase.BookSeat(c.Request.Context(), classID, userID.(int))
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrGroupLessonFull), errors.Is(err, entities.ErrAlreadyEnrolled):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, entities.ErrEnrollmentClosed):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, seat)
+}
+
+// Unenroll handles the student's request to leave a group class
+func (h *GroupClassHandler) Unenroll(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	classID, err := strconv.Atoi(c.Param("classId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid class ID"})
+		return
+	}
+
+	if err := h.lessonUseCase.CancelSeat(c.Request.Context(), classID, userID.(int)); err != nil {
+		switch {
+		case errors.Is(err, entities.ErrNotFound), errors.Is(err, entities.ErrNotEnrolled):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, entities.ErrNotGroupLesson), errors.Is(err, entities.ErrLessonNotCancellable):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Enrollment cancelled successfully"})
+}
+
+// RegisterRoutes registers the group class routes
+func (h *GroupClassHandler) RegisterRoutes(router *gin.Engine) {
+	// Public routes (no authentication required)
+	public := router.Group("/api/group-classes")
+	{
+		public.GET("", h.SearchGroupClasses)
+		public.GET("/:classId", h.GetGroupClass)
+	}
+
+	// Protected routes (authentication required)
+	protected := router.Group("/api/group-classes")
+	protected.Use(middleware.AuthMiddleware())
+	{
+		protected.POST("", middleware.RoleMiddleware("tutor"), h.CreateGroupClass)
+		protected.POST("/:classId/enrollment", middleware.RoleMiddleware("student"), h.Enroll)
+		protected.DELETE("/:classId/enrollment", h.Unenroll)
+	}
+}
@@ -1,10 +1,15 @@
 package interfaces
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"tongly-backend/internal/entities"
+	"tongly-backend/internal/logger"
 	"tongly-backend/internal/usecases"
+	"tongly-backend/pkg/jwt"
 	"tongly-backend/pkg/middleware"
 
 	"github.com/gin-gonic/gin"
@@ -12,13 +17,15 @@ import (
 
 // LessonHandler handles HTTP requests for lesson-related functionality
 type LessonHandler struct {
-	lessonUseCase *usecases.LessonUseCase
+	lessonUseCase   *usecases.LessonUseCase
+	calendarUseCase *usecases.CalendarUseCase
 }
 
 // NewLessonHandler creates a new LessonHandler
-func NewLessonHandler(lessonUseCase *usecases.LessonUseCase) *LessonHandler {
+func NewLessonHandler(lessonUseCase *usecases.LessonUseCase, calendarUseCase *usecases.CalendarUseCase) *LessonHandler {
 	return &LessonHandler{
-		lessonUseCase: lessonUseCase,
+		lessonUseCase:   lessonUseCase,
+		calendarUseCase: calendarUseCase,
 	}
 }
 
@@ -39,6 +46,14 @@ func (h *LessonHandler) BookLesson(c *gin.Context) {
 	studentID := userID.(int)
 	lesson, err := h.lessonUseCase.BookLesson(c.Request.Context(), studentID, &req)
 	if err != nil {
+		if errors.Is(err, entities.ErrTrialAlreadyBooked) || errors.Is(err, entities.ErrTutorBusy) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, entities.ErrTrialNotOffered) || errors.Is(err, entities.ErrTrialTooLong) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -68,11 +83,24 @@ func (h *LessonHandler) GetLesson(c *gin.Context) {
 	}
 
 	// Check if the user is associated with this lesson
-	if lesson.StudentID != userID.(int) && lesson.TutorID != userID.(int) {
+	if !lesson.IsAttendee(userID.(int)) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to view this lesson"})
 		return
 	}
 
+	// Download the lesson as an .ics file with ?format=ics or an "Accept: text/calendar" header
+	if c.Query("format") == "ics" || strings.Contains(c.GetHeader("Accept"), "text/calendar") {
+		data, err := h.calendarUseCase.GetLessonCalendar(c.Request.Context(), userID.(int), lessonID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export lesson"})
+			return
+		}
+
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"tongly-lesson-%d.ics\"", lessonID))
+		c.Data(http.StatusOK, calendarContentType, data)
+		return
+	}
+
 	c.JSON(http.StatusOK, lesson)
 }
 
@@ -109,8 +137,50 @@ func (h *LessonHandler) CancelLesson(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Lesson cancelled successfully"})
 }
 
-// AddReview handles the request to add a review for a lesson
-func (h *LessonHandler) AddReview(c *gin.Context) {
+// RescheduleLesson handles the request to move a one-on-one lesson to another time
+func (h *LessonHandler) RescheduleLesson(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	lessonIDStr := c.Param("lessonId")
+	lessonID, err := strconv.Atoi(lessonIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lesson ID"})
+		return
+	}
+
+	var req entities.LessonRescheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	lesson, err := h.lessonUseCase.RescheduleLesson(c.Request.Context(), lessonID, userID.(int), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrInvalidRescheduleTime):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, entities.ErrNotLessonAttendee):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, entities.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Lesson not found"})
+		case errors.Is(err, entities.ErrLessonNotReschedulable), errors.Is(err, entities.ErrTutorBusy):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			logger.Error("Failed to reschedule lesson", "lesson_id", lessonID, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reschedule lesson"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, lesson)
+}
+
+// ReportNoShow handles the tutor's request to report that the student did not attend a lesson
+func (h *LessonHandler) ReportNoShow(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
@@ -124,19 +194,201 @@ func (h *LessonHandler) AddReview(c *gin.Context) {
 		return
 	}
 
-	type AddReviewRequest struct {
-		Rating int `json:"rating" binding:"required,min=1,max=5"`
+	if err := h.lessonUseCase.ReportNoShow(c.Request.Context(), lessonID, userID.(int)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
 
-	var req AddReviewRequest
+	c.JSON(http.StatusOK, gin.H{"message": "No-show reported successfully"})
+}
+
+// CreateGroupLesson handles the tutor's request to schedule a group lesson
+func (h *LessonHandler) CreateGroupLesson(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req entities.GroupLessonRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
 		return
 	}
 
-	review, err := h.lessonUseCase.AddReview(c.Request.Context(), lessonID, userID.(int), req.Rating)
+	lesson, err := h.lessonUseCase.CreateGroupLesson(c.Request.Context(), userID.(int), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, lesson)
+}
+
+// BookSeat handles the student's request to book a seat in a group lesson
+func (h *LessonHandler) BookSeat(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	lessonIDStr := c.Param("lessonId")
+	lessonID, err := strconv.Atoi(lessonIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lesson ID"})
+		return
+	}
+
+	seat, err := h.lessonUseCase.BookSeat(c.Request.Context(), lessonID, userID.(int))
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Lesson not found"})
+		case errors.Is(err, entities.ErrGroupLessonFull), errors.Is(err, entities.ErrAlreadyEnrolled):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, entities.ErrNotGroupLesson), errors.Is(err, entities.ErrEnrollmentClosed):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, seat)
+}
+
+// CancelSeat handles the student's request to cancel their seat in a group lesson
+func (h *LessonHandler) CancelSeat(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	lessonIDStr := c.Param("lessonId")
+	lessonID, err := strconv.Atoi(lessonIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lesson ID"})
+		return
+	}
+
+	if err := h.lessonUseCase.CancelSeat(c.Request.Context(), lessonID, userID.(int)); err != nil {
+		switch {
+		case errors.Is(err, entities.ErrNotFound), errors.Is(err, entities.ErrNotEnrolled):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, entities.ErrNotGroupLesson), errors.Is(err, entities.ErrLessonNotCancellable):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Seat cancelled successfully"})
+}
+
+// CreateRoomTicket handles the request for a ticket to join a lesson's video room. Only the
+// attendees of the lesson get one.
+func (h *LessonHandler) CreateRoomTicket(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	lessonIDStr := c.Param("lessonId")
+	lessonID, err := strconv.Atoi(lessonIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lesson ID"})
+		return
+	}
+
+	if _, err := h.lessonUseCase.GetRoomAccess(c.Request.Context(), lessonID, userID.(int)); err != nil {
+		respondRoomAccessError(c, err)
+		return
+	}
+
+	ticket, expiresAt, err := jwt.GenerateRoomTicket(lessonID, userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create room ticket"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, entities.LessonRoomTicket{
+		Ticket:    ticket,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// GetRoomAccess handles the video service's request to check if the holder of a room ticket
+// may join a lesson's room. The ticket is passed as the bearer token.
+func (h *LessonHandler) GetRoomAccess(c *gin.Context) {
+	lessonIDStr := c.Param("lessonId")
+	lessonID, err := strconv.Atoi(lessonIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lesson ID"})
+		return
+	}
+
+	ticket := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	userID, err := jwt.ValidateRoomTicket(ticket, lessonID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired room ticket"})
+		return
+	}
+
+	access, err := h.lessonUseCase.GetRoomAccess(c.Request.Context(), lessonID, userID)
+	if err != nil {
+		respondRoomAccessError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, access)
+}
+
+// respondRoomAccessError maps an error of LessonUseCase.GetRoomAccess to a response
+func respondRoomAccessError(c *gin.Context, err error) {
+	if errors.Is(err, entities.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Lesson not found"})
+		return
+	}
+	c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+}
+
+// AddReview handles the request to add a review for a lesson
+func (h *LessonHandler) AddReview(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	lessonIDStr := c.Param("lessonId")
+	lessonID, err := strconv.Atoi(lessonIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lesson ID"})
+		return
+	}
+
+	var req entities.ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	review, err := h.lessonUseCase.AddReview(c.Request.Context(), lessonID, userID.(int), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrInvalidRating), errors.Is(err, entities.ErrReviewTooLong):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, entities.ErrReviewNotAllowed):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, entities.ErrAlreadyReviewed):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
@@ -245,12 +497,21 @@ func (h *LessonHandler) RegisterRoutes(router *gin.Engine) {
 	lessons.Use(middleware.AuthMiddleware())
 	{
 		lessons.POST("", h.BookLesson)
+		lessons.POST("/group", middleware.RoleMiddleware("tutor"), h.CreateGroupLesson)
 		lessons.GET("/user", h.GetUserLessons)
 		lessons.GET("/user/scheduled", h.GetUserScheduledLessons)
 		lessons.GET("/user/past", h.GetUserPastLessons)
 		lessons.GET("/user/cancelled", h.GetUserCancelledLessons)
 		lessons.GET("/:lessonId", h.GetLesson)
 		lessons.POST("/:lessonId/cancel", h.CancelLesson)
+		lessons.POST("/:lessonId/reschedule", h.RescheduleLesson)
+		lessons.POST("/:lessonId/no-show", h.ReportNoShow)
+		lessons.POST("/:lessonId/seats", middleware.RoleMiddleware("student"), h.BookSeat)
+		lessons.DELETE("/:lessonId/seats", h.CancelSeat)
+		lessons.POST("/:lessonId/room-ticket", h.CreateRoomTicket)
 		lessons.POST("/:lessonId/reviews", h.AddReview)
 	}
+
+	// The video service authenticates with the user's room ticket instead of a login token
+	router.GET("/api/lessons/:lessonId/room-access", h.GetRoomAccess)
 }
@@ -0,0 +1,380 @@
+package interfaces
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/logger"
+	"tongly-backend/internal/usecases"
+	"tongly-backend/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LessonNotesHandler handles HTTP requests for lesson notes, homework and lesson history
+type LessonNotesHandler struct {
+	notesUseCase *usecases.LessonNotesUseCase
+}
+
+// NewLessonNotesHandler creates a new LessonNotesHandler
+func NewLessonNotesHandler(notesUseCase *usecases.LessonNotesUseCase) *LessonNotesHandler {
+	return &LessonNotesHandler{
+		notesUseCase: notesUseCase,
+	}
+}
+
+// GetNotes handles the request to retrieve the notes of a lesson
+func (h *LessonNotesHandler) GetNotes(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	lessonID, err := strconv.Atoi(c.Param("lessonId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lesson ID"})
+		return
+	}
+
+	notes, err := h.notesUseCase.GetNotes(c.Request.Context(), userID.(int), lessonID)
+	if err != nil {
+		h.respondNotesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, notes)
+}
+
+// SaveNotes handles the tutor's request to write the notes of a lesson
+func (h *LessonNotesHandler) SaveNotes(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	lessonID, err := strconv.Atoi(c.Param("lessonId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lesson ID"})
+		return
+	}
+
+	var req entities.LessonNotesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	notes, err := h.notesUseCase.SaveNotes(c.Request.Context(), userID.(int), lessonID, &req)
+	if err != nil {
+		h.respondNotesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, notes)
+}
+
+// GetHomework handles the request to list the homework of a lesson
+func (h *LessonNotesHandler) GetHomework(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	lessonID, err := strconv.Atoi(c.Param("lessonId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lesson ID"})
+		return
+	}
+
+	homework, err := h.notesUseCase.GetHomework(c.Request.Context(), userID.(int), lessonID)
+	if err != nil {
+		h.respondNotesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, homework)
+}
+
+// AddHomework handles the tutor's request to assign homework in a lesson
+func (h *LessonNotesHandler) AddHomework(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	lessonID, err := strconv.Atoi(c.Param("lessonId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lesson ID"})
+		return
+	}
+
+	var req entities.HomeworkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	homework, err := h.notesUseCase.AddHomework(c.Request.Context(), userID.(int), lessonID, &req)
+	if err != nil {
+		h.respondNotesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, homework)
+}
+
+// UpdateHomework handles the tutor's request to change homework
+func (h *LessonNotesHandler) UpdateHomework(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	homeworkID, err := strconv.Atoi(c.Param("homeworkId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid homework ID"})
+		return
+	}
+
+	var req entities.HomeworkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	homework, err := h.notesUseCase.UpdateHomework(c.Request.Context(), userID.(int), homeworkID, &req)
+	if err != nil {
+		h.respondNotesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, homework)
+}
+
+// DeleteHomework handles the tutor's request to remove homework
+func (h *LessonNotesHandler) DeleteHomework(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	homeworkID, err := strconv.Atoi(c.Param("homeworkId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid homework ID"})
+		return
+	}
+
+	if err := h.notesUseCase.DeleteHomework(c.Request.Context(), userID.(int), homeworkID); err != nil {
+		h.respondNotesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Homework deleted"})
+}
+
+// SetHomeworkStatus handles the student's request to mark homework as done or not done
+func (h *LessonNotesHandler) SetHomeworkStatus(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	homeworkID, err := strconv.Atoi(c.Param("homeworkId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid homework ID"})
+		return
+	}
+
+	var req entities.HomeworkStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	homework, err := h.notesUseCase.SetHomeworkDone(c.Request.Context(), userID.(int), homeworkID, req.Done)
+	if err != nil {
+		h.respondNotesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, homework)
+}
+
+// GetFeedback handles the request to retrieve the private student feedback of a lesson
+func (h *LessonNotesHandler) GetFeedback(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	lessonID, err := strconv.Atoi(c.Param("lessonId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lesson ID"})
+		return
+	}
+
+	feedback, err := h.notesUseCase.GetFeedback(c.Request.Context(), userID.(int), lessonID)
+	if err != nil {
+		h.respondNotesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, feedback)
+}
+
+// SaveFeedback handles the tutor's request to give feedback to a student of a lesson
+func (h *LessonNotesHandler) SaveFeedback(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	lessonID, err := strconv.Atoi(c.Param("lessonId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lesson ID"})
+		return
+	}
+
+	var req entities.StudentFeedbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	feedback, err := h.notesUseCase.SaveFeedback(c.Request.Context(), userID.(int), lessonID, &req)
+	if err != nil {
+		h.respondNotesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, feedback)
+}
+
+// GetStudentHistory handles the tutor's request to see their lessons with a student
+func (h *LessonNotesHandler) GetStudentHistory(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	studentID, err := strconv.Atoi(c.Param("studentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+
+	history, err := h.notesUseCase.GetStudentHistory(c.Request.Context(), userID.(int), studentID)
+	if err != nil {
+		h.respondNotesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// GetTutorHistory handles the student's request to see their lessons with a tutor
+func (h *LessonNotesHandler) GetTutorHistory(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	tutorID, err := strconv.Atoi(c.Param("tutorId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tutor ID"})
+		return
+	}
+
+	history, err := h.notesUseCase.GetTutorHistory(c.Request.Context(), userID.(int), tutorID)
+	if err != nil {
+		h.respondNotesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// GetStudentHomework handles the student's request to list their homework across all lessons.
+// With status=open, homework they have done is left out.
+func (h *LessonNotesHandler) GetStudentHomework(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	homework, err := h.notesUseCase.GetStudentHomework(c.Request.Context(), userID.(int), c.Query("status") == "open")
+	if err != nil {
+		h.respondNotesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, homework)
+}
+
+// respondNotesError maps errors of the lesson notes use cases to responses
+func (h *LessonNotesHandler) respondNotesError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, entities.ErrNotFound), errors.Is(err, entities.ErrLessonNotesNotFound),
+		errors.Is(err, entities.ErrHomeworkNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, entities.ErrNotLessonTutor), errors.Is(err, entities.ErrNotLessonAttendee):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, entities.ErrLessonNotesClosed), errors.Is(err, entities.ErrTooMuchHomework),
+		errors.Is(err, entities.ErrFeedbackNotOpen):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, entities.ErrInvalidLessonNotes), errors.Is(err, entities.ErrInvalidHomework),
+		errors.Is(err, entities.ErrInvalidFeedback), errors.Is(err, entities.ErrFeedbackStudent):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		logger.Error("Lesson notes request failed", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process lesson notes request"})
+	}
+}
+
+// RegisterRoutes registers the lesson notes, homework and history routes
+func (h *LessonNotesHandler) RegisterRoutes(router *gin.Engine) {
+	lessons := router.Group("/api/lessons")
+	lessons.Use(middleware.AuthMiddleware())
+	{
+		lessons.GET("/:lessonId/notes", h.GetNotes)
+		lessons.PUT("/:lessonId/notes", middleware.RoleMiddleware("tutor"), h.SaveNotes)
+		lessons.GET("/:lessonId/homework", h.GetHomework)
+		lessons.POST("/:lessonId/homework", middleware.RoleMiddleware("tutor"), h.AddHomework)
+		lessons.GET("/:lessonId/feedback", h.GetFeedback)
+		lessons.PUT("/:lessonId/feedback", middleware.RoleMiddleware("tutor"), h.SaveFeedback)
+	}
+
+	homework := router.Group("/api/homework")
+	homework.Use(middleware.AuthMiddleware())
+	{
+		homework.PUT("/:homeworkId", middleware.RoleMiddleware("tutor"), h.UpdateHomework)
+		homework.DELETE("/:homeworkId", middleware.RoleMiddleware("tutor"), h.DeleteHomework)
+		homework.PUT("/:homeworkId/status", middleware.RoleMiddleware("student"), h.SetHomeworkStatus)
+	}
+
+	tutor := router.Group("/api/tutor")
+	tutor.Use(middleware.AuthMiddleware(), middleware.RoleMiddleware("tutor"))
+	{
+		tutor.GET("/students/:studentId/history", h.GetStudentHistory)
+	}
+
+	student := router.Group("/api/student")
+	student.Use(middleware.AuthMiddleware(), middleware.RoleMiddleware("student"))
+	{
+		student.GET("/tutors/:tutorId/history", h.GetTutorHistory)
+		student.GET("/homework", h.GetStudentHomework)
+	}
+}
@@ -0,0 +1,333 @@
+package interfaces
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/logger"
+	"tongly-backend/internal/usecases"
+	"tongly-backend/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MessageHandler handles HTTP requests for the messaging inbox between students and tutors
+type MessageHandler struct {
+	messageUseCase *usecases.MessageUseCase
+}
+
+// NewMessageHandler creates a new MessageHandler
+func NewMessageHandler(messageUseCase *usecases.MessageUseCase) *MessageHandler {
+	return &MessageHandler{
+		messageUseCase: messageUseCase,
+	}
+}
+
+// GetConversations handles the request to list the current user's conversations
+func (h *MessageHandler) GetConversations(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	filters := &entities.ConversationFilters{}
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil {
+		filters.Limit = limit
+	}
+	if offset, err := strconv.Atoi(c.Query("offset")); err == nil {
+		filters.Offset = offset
+	}
+
+	conversations, err := h.messageUseCase.GetConversations(c.Request.Context(), userID.(int), filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve conversations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, conversations)
+}
+
+// StartConversation handles the request to message a user, starting a conversation if needed
+func (h *MessageHandler) StartConversation(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req entities.StartConversationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	conversation, message, err := h.messageUseCase.StartConversation(c.Request.Context(), userID.(int), &req)
+	if err != nil {
+		h.respondMessageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"conversation": conversation, "message": message})
+}
+
+// GetUnreadCount handles the request to count the current user's unread messages
+func (h *MessageHandler) GetUnreadCount(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	count, err := h.messageUseCase.GetUnreadCount(c.Request.Context(), userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count messages"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unread_count": count})
+}
+
+// GetConversation handles the request to retrieve a conversation
+func (h *MessageHandler) GetConversation(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	conversationID, err := strconv.Atoi(c.Param("conversationId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid conversation ID"})
+		return
+	}
+
+	conversation, err := h.messageUseCase.GetConversation(c.Request.Context(), userID.(int), conversationID)
+	if err != nil {
+		h.respondMessageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, conversation)
+}
+
+// GetMessages handles the request to list a page of a conversation's messages, newest first.
+// Older pages are requested with before_id set to the ID of the oldest message received.
+func (h *MessageHandler) GetMessages(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	conversationID, err := strconv.Atoi(c.Param("conversationId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid conversation ID"})
+		return
+	}
+
+	var beforeID int64
+	if beforeStr := c.Query("before_id"); beforeStr != "" {
+		if beforeID, err = strconv.ParseInt(beforeStr, 10, 64); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid before_id"})
+			return
+		}
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	page, err := h.messageUseCase.GetMessages(c.Request.Context(), userID.(int), conversationID, beforeID, limit)
+	if err != nil {
+		h.respondMessageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// SendMessage handles the request to send a message in a conversation
+func (h *MessageHandler) SendMessage(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	conversationID, err := strconv.Atoi(c.Param("conversationId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid conversation ID"})
+		return
+	}
+
+	var req entities.MessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	message, err := h.messageUseCase.SendMessage(c.Request.Context(), userID.(int), conversationID, &req)
+	if err != nil {
+		h.respondMessageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, message)
+}
+
+// MarkRead handles the request to mark the messages received in a conversation as read
+func (h *MessageHandler) MarkRead(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	conversationID, err := strconv.Atoi(c.Param("conversationId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid conversation ID"})
+		return
+	}
+
+	marked, err := h.messageUseCase.MarkRead(c.Request.Context(), userID.(int), conversationID)
+	if err != nil {
+		h.respondMessageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"marked": marked})
+}
+
+// ReportConversation handles the request to report a conversation or one of its messages
+func (h *MessageHandler) ReportConversation(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	conversationID, err := strconv.Atoi(c.Param("conversationId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid conversation ID"})
+		return
+	}
+
+	var req entities.ReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	report, err := h.messageUseCase.ReportConversation(c.Request.Context(), userID.(int), conversationID, &req)
+	if err != nil {
+		h.respondMessageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)
+}
+
+// GetBlockedUsers handles the request to list the users the current user has blocked
+func (h *MessageHandler) GetBlockedUsers(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	blocked, err := h.messageUseCase.GetBlockedUsers(c.Request.Context(), userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve blocked users"})
+		return
+	}
+
+	c.JSON(http.StatusOK, blocked)
+}
+
+// BlockUser handles the request to block a user from messaging the current user
+func (h *MessageHandler) BlockUser(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req entities.BlockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	if err := h.messageUseCase.BlockUser(c.Request.Context(), userID.(int), req.UserID); err != nil {
+		h.respondMessageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User blocked"})
+}
+
+// UnblockUser handles the request to lift a block placed by the current user
+func (h *MessageHandler) UnblockUser(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	blockedID, err := strconv.Atoi(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	if err := h.messageUseCase.UnblockUser(c.Request.Context(), userID.(int), blockedID); err != nil {
+		h.respondMessageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User unblocked"})
+}
+
+// respondMessageError maps errors of the messaging use cases to responses
+func (h *MessageHandler) respondMessageError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, entities.ErrConversationNotFound), errors.Is(err, entities.ErrMessageNotFound),
+		errors.Is(err, entities.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, entities.ErrUserBlocked), errors.Is(err, entities.ErrConversationNotAllowed):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, entities.ErrTooManyNewConversations), errors.Is(err, entities.ErrMessageRateLimited):
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	case errors.Is(err, entities.ErrEmptyMessage), errors.Is(err, entities.ErrMessageTooLong),
+		errors.Is(err, entities.ErrTooManyAttachments), errors.Is(err, entities.ErrInvalidAttachment),
+		errors.Is(err, entities.ErrInvalidRecipient), errors.Is(err, entities.ErrInvalidReport),
+		errors.Is(err, entities.ErrCannotBlockSelf):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		logger.Error("Messaging request failed", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process message request"})
+	}
+}
+
+// RegisterRoutes registers the messaging routes
+func (h *MessageHandler) RegisterRoutes(router *gin.Engine) {
+	conversations := router.Group("/api/conversations")
+	conversations.Use(middleware.AuthMiddleware())
+	{
+		conversations.GET("", h.GetConversations)
+		conversations.POST("", h.StartConversation)
+		conversations.GET("/unread-count", h.GetUnreadCount)
+		conversations.GET("/:conversationId", h.GetConversation)
+		conversations.GET("/:conversationId/messages", h.GetMessages)
+		conversations.POST("/:conversationId/messages", h.SendMessage)
+		conversations.POST("/:conversationId/read", h.MarkRead)
+		conversations.POST("/:conversationId/report", h.ReportConversation)
+	}
+
+	blocks := router.Group("/api/blocks")
+	blocks.Use(middleware.AuthMiddleware())
+	{
+		blocks.GET("", h.GetBlockedUsers)
+		blocks.POST("", h.BlockUser)
+		blocks.DELETE("/:userId", h.UnblockUser)
+	}
+}
@@ -0,0 +1,232 @@
+package interfaces
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/usecases"
+	"tongly-backend/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// streamKeepAlive is how often a comment is sent on idle streams so that proxies keep them open
+const streamKeepAlive = 25 * time.Second
+
+// NotificationHandler handles HTTP requests for in-app notifications
+type NotificationHandler struct {
+	notificationUseCase *usecases.NotificationUseCase
+}
+
+// NewNotificationHandler creates a new NotificationHandler
+func NewNotificationHandler(notificationUseCase *usecases.NotificationUseCase) *NotificationHandler {
+	return &NotificationHandler{
+		notificationUseCase: notificationUseCase,
+	}
+}
+
+// GetNotifications handles the request to list the current user's notifications
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	filters := &entities.NotificationFilters{
+		UnreadOnly: c.Query("unread") == "true",
+	}
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil {
+		filters.Limit = limit
+	}
+	if offset, err := strconv.Atoi(c.Query("offset")); err == nil {
+		filters.Offset = offset
+	}
+
+	notifications, err := h.notificationUseCase.GetNotifications(c.Request.Context(), userID.(int), filters)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, notifications)
+}
+
+// GetUnreadCount handles the request to count the current user's unread notifications
+func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	count, err := h.notificationUseCase.GetUnreadCount(c.Request.Context(), userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unread_count": count})
+}
+
+// MarkRead handles the request to mark a notification as read
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	notificationID, err := strconv.ParseInt(c.Param("notificationId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	if err := h.notificationUseCase.MarkRead(c.Request.Context(), userID.(int), notificationID); err != nil {
+		if errors.Is(err, entities.ErrNotificationNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark notification as read"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked as read"})
+}
+
+// MarkAllRead handles the request to mark all of the current user's notifications as read
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	marked, err := h.notificationUseCase.MarkAllRead(c.Request.Context(), userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark notifications as read"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"marked": marked})
+}
+
+// StreamNotifications pushes the current user's new notifications as server-sent events.
+// The stream starts with an "unread_count" event and then sends a "notification" event for
+// every new notification. It authenticates with the usual Authorization header, so clients
+// read it with fetch rather than EventSource.
+func (h *NotificationHandler) StreamNotifications(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	// Subscribe before counting, so that nothing created in between is missed
+	notifications, unsubscribe := h.notificationUseCase.Subscribe(userID.(int))
+	defer unsubscribe()
+
+	count, err := h.notificationUseCase.GetUnreadCount(c.Request.Context(), userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count notifications"})
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	c.SSEvent("unread_count", gin.H{"unread_count": count})
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case notification, ok := <-notifications:
+			if !ok {
+				return false
+			}
+			c.SSEvent("notification", notification)
+			return true
+		case <-keepAlive.C:
+			if _, err := io.WriteString(w, ": keep-alive\n\n"); err != nil {
+				return false
+			}
+			return true
+		}
+	})
+}
+
+// GetPreferences handles the request to retrieve the current user's notification preferences
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	prefs, err := h.notificationUseCase.GetPreferences(c.Request.Context(), userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
+
+// UpdatePreferences handles the request to change the email language and the channels of
+// notification events. Events left out of the request keep their current channels.
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req entities.NotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	prefs, err := h.notificationUseCase.UpdatePreferences(c.Request.Context(), userID.(int), &req)
+	if err != nil {
+		if errors.Is(err, entities.ErrInvalidLocale) || errors.Is(err, entities.ErrUnknownNotificationEvent) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
+
+// RegisterRoutes registers the notification routes
+func (h *NotificationHandler) RegisterRoutes(router *gin.Engine) {
+	notifications := router.Group("/api/notifications")
+	notifications.Use(middleware.AuthMiddleware())
+	{
+		notifications.GET("", h.GetNotifications)
+		notifications.GET("/unread-count", h.GetUnreadCount)
+		notifications.GET("/stream", h.StreamNotifications)
+		notifications.POST("/read-all", h.MarkAllRead)
+		notifications.POST("/:notificationId/read", h.MarkRead)
+	}
+
+	preferences := router.Group("/api/user/preferences")
+	preferences.Use(middleware.AuthMiddleware())
+	{
+		preferences.GET("", h.GetPreferences)
+		preferences.PUT("", h.UpdatePreferences)
+	}
+}
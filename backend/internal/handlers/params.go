@@ -0,0 +1,11 @@
+package interfaces
+
+import "time"
+
+// parseTimeParam parses a query parameter given as an RFC3339 timestamp or a plain YYYY-MM-DD date
+func parseTimeParam(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation(time.DateOnly, value, time.UTC)
+}
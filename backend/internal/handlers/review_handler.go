@@ -0,0 +1,205 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/logger"
+	"tongly-backend/internal/usecases"
+	"tongly-backend/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReviewHandler handles HTTP requests for the public reviews of tutors
+type ReviewHandler struct {
+	reviewUseCase *usecases.ReviewUseCase
+}
+
+// NewReviewHandler creates a new ReviewHandler
+func NewReviewHandler(reviewUseCase *usecases.ReviewUseCase) *ReviewHandler {
+	return &ReviewHandler{
+		reviewUseCase: reviewUseCase,
+	}
+}
+
+// GetTutorReviews handles the request to list a tutor's reviews, sorted by recency or
+// helpfulness with sort=recent|helpful
+func (h *ReviewHandler) GetTutorReviews(c *gin.Context) {
+	tutorID, err := strconv.Atoi(c.Param("tutorId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tutor ID"})
+		return
+	}
+
+	filters := &entities.ReviewFilters{
+		Sort: entities.ReviewSort(c.Query("sort")),
+	}
+	switch filters.Sort {
+	case "", entities.ReviewSortRecent, entities.ReviewSortHelpful:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort"})
+		return
+	}
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil {
+		filters.Limit = limit
+	}
+	if offset, err := strconv.Atoi(c.Query("offset")); err == nil {
+		filters.Offset = offset
+	}
+
+	page, err := h.reviewUseCase.GetTutorReviews(c.Request.Context(), tutorID, filters)
+	if err != nil {
+		h.respondReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// ReplyToReview handles the tutor's request to reply to a review, replacing an earlier reply
+func (h *ReviewHandler) ReplyToReview(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	reviewID, err := strconv.Atoi(c.Param("reviewId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+
+	var req entities.ReviewReplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	review, err := h.reviewUseCase.ReplyToReview(c.Request.Context(), userID.(int), reviewID, &req)
+	if err != nil {
+		h.respondReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// DeleteReply handles the tutor's request to remove their reply to a review
+func (h *ReviewHandler) DeleteReply(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	reviewID, err := strconv.Atoi(c.Param("reviewId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+
+	if err := h.reviewUseCase.DeleteReply(c.Request.Context(), userID.(int), reviewID); err != nil {
+		h.respondReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reply deleted"})
+}
+
+// MarkHelpful handles the request to mark a review as helpful
+func (h *ReviewHandler) MarkHelpful(c *gin.Context) {
+	h.vote(c, h.reviewUseCase.MarkHelpful)
+}
+
+// UnmarkHelpful handles the request to withdraw a helpful vote
+func (h *ReviewHandler) UnmarkHelpful(c *gin.Context) {
+	h.vote(c, h.reviewUseCase.UnmarkHelpful)
+}
+
+// ReportReview handles the request to report an inappropriate review
+func (h *ReviewHandler) ReportReview(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	reviewID, err := strconv.Atoi(c.Param("reviewId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+
+	var req entities.ReviewReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	report, err := h.reviewUseCase.ReportReview(c.Request.Context(), userID.(int), reviewID, &req)
+	if err != nil {
+		h.respondReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)
+}
+
+// vote runs a helpful vote change and responds with the new number of votes
+func (h *ReviewHandler) vote(c *gin.Context, change func(ctx context.Context, userID, reviewID int) (int, error)) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	reviewID, err := strconv.Atoi(c.Param("reviewId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+
+	count, err := change(c.Request.Context(), userID.(int), reviewID)
+	if err != nil {
+		h.respondReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"helpful_count": count})
+}
+
+// respondReviewError maps review errors to HTTP responses
+func (h *ReviewHandler) respondReviewError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, entities.ErrReviewNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, entities.ErrReplyNotAllowed):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, entities.ErrInvalidReply), errors.Is(err, entities.ErrInvalidReport),
+		errors.Is(err, entities.ErrCannotVoteOwnReview):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		logger.Error("Review request failed", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process review request"})
+	}
+}
+
+// RegisterRoutes registers the review routes
+func (h *ReviewHandler) RegisterRoutes(router *gin.Engine) {
+	// Public routes (no authentication required)
+	router.GET("/api/tutors/:tutorId/reviews", h.GetTutorReviews)
+
+	reviews := router.Group("/api/reviews")
+	reviews.Use(middleware.AuthMiddleware())
+	{
+		reviews.PUT("/:reviewId/reply", middleware.RoleMiddleware("tutor"), h.ReplyToReview)
+		reviews.DELETE("/:reviewId/reply", middleware.RoleMiddleware("tutor"), h.DeleteReply)
+		reviews.POST("/:reviewId/helpful", h.MarkHelpful)
+		reviews.DELETE("/:reviewId/helpful", h.UnmarkHelpful)
+		reviews.POST("/:reviewId/report", h.ReportReview)
+	}
+}
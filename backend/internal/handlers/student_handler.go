@@ -1,8 +1,11 @@
 package interfaces
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 	"tongly-backend/internal/entities"
+	"tongly-backend/internal/logger"
 	"tongly-backend/internal/usecases"
 	"tongly-backend/pkg/middleware"
 
@@ -11,13 +14,15 @@ import (
 
 // StudentHandler handles HTTP requests for student-related functionality
 type StudentHandler struct {
-	studentUseCase *usecases.StudentUseCase
+	studentUseCase        *usecases.StudentUseCase
+	recommendationUseCase *usecases.RecommendationUseCase
 }
 
 // NewStudentHandler creates a new StudentHandler
-func NewStudentHandler(studentUseCase *usecases.StudentUseCase) *StudentHandler {
+func NewStudentHandler(studentUseCase *usecases.StudentUseCase, recommendationUseCase *usecases.RecommendationUseCase) *StudentHandler {
 	return &StudentHandler{
-		studentUseCase: studentUseCase,
+		studentUseCase:        studentUseCase,
+		recommendationUseCase: recommendationUseCase,
 	}
 }
 
@@ -89,6 +94,76 @@ func (h *StudentHandler) UpdateStreak(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Streak updated successfully"})
 }
 
+// GetPreferences handles the request to retrieve what the student would like from their lessons
+func (h *StudentHandler) GetPreferences(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	prefs, err := h.studentUseCase.GetPreferences(c.Request.Context(), userID.(int))
+	if err != nil {
+		logger.Error("Failed to get student preferences", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
+
+// UpdatePreferences handles the request to replace the student's time zone, price range and
+// preferred lesson times
+func (h *StudentHandler) UpdatePreferences(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req entities.StudentPreferences
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	prefs, err := h.studentUseCase.UpdatePreferences(c.Request.Context(), userID.(int), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, entities.ErrInvalidStudentPreferences), errors.Is(err, entities.ErrInvalidTimeZone):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, entities.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Student profile not found"})
+		default:
+			logger.Error("Failed to update student preferences", "user_id", userID, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update preferences"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
+
+// GetRecommendedTutors handles the request to recommend tutors to the student, with the
+// breakdown of each tutor's score
+func (h *StudentHandler) GetRecommendedTutors(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	recommendations, err := h.recommendationUseCase.RecommendTutors(c.Request.Context(), userID.(int), limit)
+	if err != nil {
+		logger.Error("Failed to recommend tutors", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to recommend tutors"})
+		return
+	}
+
+	c.JSON(http.StatusOK, recommendations)
+}
+
 // RegisterRoutes registers the student routes
 func (h *StudentHandler) RegisterRoutes(router *gin.Engine) {
 	student := router.Group("/api/student")
@@ -97,5 +172,8 @@ func (h *StudentHandler) RegisterRoutes(router *gin.Engine) {
 		student.GET("/profile", h.GetProfile)
 		student.PUT("/profile", h.UpdateProfile)
 		student.POST("/streak", h.UpdateStreak)
+		student.GET("/preferences", middleware.RoleMiddleware("student"), h.GetPreferences)
+		student.PUT("/preferences", middleware.RoleMiddleware("student"), h.UpdatePreferences)
+		student.GET("/recommended-tutors", middleware.RoleMiddleware("student"), h.GetRecommendedTutors)
 	}
 }
@@ -1,6 +1,7 @@
 package interfaces
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -14,13 +15,15 @@ import (
 
 // TutorHandler handles HTTP requests for tutor-related functionality
 type TutorHandler struct {
-	tutorUseCase *usecases.TutorUseCase
+	tutorUseCase     *usecases.TutorUseCase
+	analyticsUseCase *usecases.AnalyticsUseCase
 }
 
 // NewTutorHandler creates a new TutorHandler
-func NewTutorHandler(tutorUseCase *usecases.TutorUseCase) *TutorHandler {
+func NewTutorHandler(tutorUseCase *usecases.TutorUseCase, analyticsUseCase *usecases.AnalyticsUseCase) *TutorHandler {
 	return &TutorHandler{
-		tutorUseCase: tutorUseCase,
+		tutorUseCase:     tutorUseCase,
+		analyticsUseCase: analyticsUseCase,
 	}
 }
 
@@ -58,6 +61,10 @@ func (h *TutorHandler) UpdateProfile(c *gin.Context) {
 
 	tutorID := userID.(int)
 	if err := h.tutorUseCase.UpdateTutorProfile(c.Request.Context(), tutorID, &req); err != nil {
+		if errors.Is(err, entities.ErrInvalidTimeZone) || isEducationError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile"})
 		return
 	}
@@ -65,6 +72,105 @@ func (h *TutorHandler) UpdateProfile(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Profile updated successfully"})
 }
 
+// AddEducation handles the request to add an entry to the tutor's education
+func (h *TutorHandler) AddEducation(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req entities.Education
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	education, err := h.tutorUseCase.AddEducation(c.Request.Context(), userID.(int), &req)
+	if err != nil {
+		respondEducationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, education)
+}
+
+// UpdateEducation handles the request to change an entry of the tutor's education, identified
+// by its position
+func (h *TutorHandler) UpdateEducation(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid education index"})
+		return
+	}
+
+	var req entities.Education
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	education, err := h.tutorUseCase.UpdateEducation(c.Request.Context(), userID.(int), index, &req)
+	if err != nil {
+		respondEducationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, education)
+}
+
+// RemoveEducation handles the request to remove an entry of the tutor's education, identified
+// by its position
+func (h *TutorHandler) RemoveEducation(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid education index"})
+		return
+	}
+
+	education, err := h.tutorUseCase.RemoveEducation(c.Request.Context(), userID.(int), index)
+	if err != nil {
+		respondEducationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, education)
+}
+
+// isEducationError reports whether err rejects the education entries a tutor sent
+func isEducationError(err error) bool {
+	return errors.Is(err, entities.ErrInvalidEducation) ||
+		errors.Is(err, entities.ErrInvalidEducationYears) ||
+		errors.Is(err, entities.ErrTooManyEducationEntries)
+}
+
+// respondEducationError maps errors of the education use cases to responses
+func respondEducationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, entities.ErrEducationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, entities.ErrTooManyEducationEntries):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case isEducationError(err):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		logger.Error("Education request failed", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update education"})
+	}
+}
+
 // AddAvailability handles the request to add an availability slot for a tutor
 func (h *TutorHandler) AddAvailability(c *gin.Context) {
 	userID, exists := c.Get("user_id")
@@ -173,7 +279,8 @@ func (h *TutorHandler) GetAvailabilities(c *gin.Context) {
 	c.JSON(http.StatusOK, availabilities)
 }
 
-// SearchTutors handles the request to search for tutors
+// SearchTutors handles the request to search for tutors, a page at a time with limit and
+// offset, sorted with sort=rating|price|experience|newest|relevance
 func (h *TutorHandler) SearchTutors(c *gin.Context) {
 	var filters entities.TutorSearchFilters
 
@@ -254,20 +361,73 @@ func (h *TutorHandler) SearchTutors(c *gin.Context) {
 		filters.Sex = sex
 	}
 
+	// Get trial lessons filter
+	if offersTrial, err := strconv.ParseBool(c.Query("offers_trial")); err == nil {
+		filters.OffersTrial = offersTrial
+	}
+
+	// Filter by tutors with a verified credential
+	if verifiedOnly, err := strconv.ParseBool(c.Query("verified_only")); err == nil {
+		filters.VerifiedOnly = verifiedOnly
+	}
+
+	// Get free-text search
+	filters.Query = c.Query("q")
+
+	// Get availability filters, in the student's time zone
+	for _, dayStr := range c.QueryArray("day") {
+		day, err := strconv.Atoi(dayStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": entities.ErrInvalidAvailabilityFilter.Error()})
+			return
+		}
+		filters.Days = append(filters.Days, day)
+	}
+	filters.FromTime = c.Query("from_time")
+	filters.ToTime = c.Query("to_time")
+	filters.TimeZone = c.Query("tz")
+	if withinDays, err := strconv.Atoi(c.Query("available_within_days")); err == nil {
+		filters.AvailableWithinDays = withinDays
+	}
+
+	// Get sort and page
+	filters.Sort = entities.TutorSort(c.Query("sort"))
+	switch filters.Sort {
+	case "", entities.TutorSortRating, entities.TutorSortPrice, entities.TutorSortExperience,
+		entities.TutorSortNewest, entities.TutorSortRelevance:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort"})
+		return
+	}
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil {
+		filters.Limit = limit
+	}
+	if offset, err := strconv.Atoi(c.Query("offset")); err == nil {
+		filters.Offset = offset
+	}
+
 	// Log filter information for debugging
 	logger.Info("SearchTutors called with filters: %+v", filters)
 
-	tutors, err := h.tutorUseCase.SearchTutors(c.Request.Context(), &filters)
+	page, err := h.tutorUseCase.SearchTutors(c.Request.Context(), &filters)
+	if errors.Is(err, entities.ErrInvalidAvailabilityFilter) || errors.Is(err, entities.ErrInvalidTimeZone) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		logger.Error("Error in SearchTutors: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to search tutors: %v", err)})
 		return
 	}
 
-	c.JSON(http.StatusOK, tutors)
+	// The listed tutors count it in their analytics
+	h.analyticsUseCase.RecordSearchImpressions(page.Tutors)
+
+	c.JSON(http.StatusOK, page)
 }
 
-// GetTutorByID handles the request to retrieve a tutor's profile by ID
+// GetTutorByID handles the request to retrieve a tutor's profile by ID. The profile page
+// sends view=profile so that the view is counted in the tutor's analytics.
 func (h *TutorHandler) GetTutorByID(c *gin.Context) {
 	tutorIDStr := c.Param("tutorId")
 	tutorID, err := strconv.Atoi(tutorIDStr)
@@ -283,6 +443,13 @@ func (h *TutorHandler) GetTutorByID(c *gin.Context) {
 		return
 	}
 
+	// Only the profile page counts as a view, and tutors looking at their own profile don't count
+	if c.Query("view") == "profile" {
+		if viewerID, ok := c.Get("user_id"); !ok || viewerID.(int) != tutorID {
+			h.analyticsUseCase.RecordProfileView(tutorID)
+		}
+	}
+
 	c.JSON(http.StatusOK, profile)
 }
 
@@ -309,13 +476,31 @@ func (h *TutorHandler) GetTutorAvailabilitiesByID(c *gin.Context) {
 	c.JSON(http.StatusOK, availabilities)
 }
 
+// GetTrialConversionStats handles the request to retrieve the current tutor's trial conversion
+func (h *TutorHandler) GetTrialConversionStats(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	stats, err := h.tutorUseCase.GetTrialConversionStats(c.Request.Context(), userID.(int))
+	if err != nil {
+		logger.Error("Failed to retrieve trial conversion stats", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve trial conversion"})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
 // RegisterRoutes registers the tutor routes
 func (h *TutorHandler) RegisterRoutes(router *gin.Engine) {
 	// Public routes (no authentication required)
 	public := router.Group("/api/tutors")
 	{
 		public.GET("/search", h.SearchTutors)
-		public.GET("/:tutorId", h.GetTutorByID)
+		public.GET("/:tutorId", middleware.OptionalAuthMiddleware(), h.GetTutorByID)
 		public.GET("/:tutorId/availabilities", h.GetTutorAvailabilitiesByID)
 	}
 
@@ -325,10 +510,14 @@ func (h *TutorHandler) RegisterRoutes(router *gin.Engine) {
 	{
 		tutor.GET("/profile", h.GetProfile)
 		tutor.PUT("/profile", h.UpdateProfile)
+		tutor.POST("/education", middleware.RoleMiddleware("tutor"), h.AddEducation)
+		tutor.PUT("/education/:index", middleware.RoleMiddleware("tutor"), h.UpdateEducation)
+		tutor.DELETE("/education/:index", middleware.RoleMiddleware("tutor"), h.RemoveEducation)
 		tutor.GET("/availabilities", h.GetAvailabilities)
 		tutor.POST("/availabilities", h.AddAvailability)
 		tutor.PUT("/availabilities/:availabilityId", h.UpdateAvailability)
 		tutor.DELETE("/availabilities/:availabilityId", h.DeleteAvailability)
+		tutor.GET("/analytics/trials", middleware.RoleMiddleware("tutor"), h.GetTrialConversionStats)
 	}
 
 	// Additional routes to match the frontend API calls
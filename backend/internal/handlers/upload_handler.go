@@ -0,0 +1,378 @@
+package interfaces
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/logger"
+	"tongly-backend/internal/usecases"
+	"tongly-backend/pkg/middleware"
+	"tongly-backend/pkg/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UploadHandler handles HTTP requests for uploaded files
+type UploadHandler struct {
+	uploadUseCase *usecases.UploadUseCase
+}
+
+// NewUploadHandler creates a new UploadHandler
+func NewUploadHandler(uploadUseCase *usecases.UploadUseCase) *UploadHandler {
+	return &UploadHandler{
+		uploadUseCase: uploadUseCase,
+	}
+}
+
+// Upload handles the request to upload a file sent as the multipart field "file", with
+// the field "purpose" set to avatar, intro_video or lesson_material
+func (h *UploadHandler) Upload(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, entities.MaxUploadSize+1<<20)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			h.respondUploadError(c, entities.ErrFileTooLarge)
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is required"})
+		return
+	}
+
+	purpose := entities.UploadPurpose(c.PostForm("purpose"))
+	if err := purpose.Validate(); err != nil {
+		h.respondUploadError(c, err)
+		return
+	}
+	if fileHeader.Size > purpose.MaxSize() {
+		h.respondUploadError(c, entities.ErrFileTooLarge)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	upload, err := h.uploadUseCase.Upload(c.Request.Context(), userID.(int), purpose, fileHeader.Filename, file)
+	if err != nil {
+		h.respondUploadError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, upload)
+}
+
+// GetUploads handles the request to list the current user's uploads and quota
+func (h *UploadHandler) GetUploads(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uploads, err := h.uploadUseCase.GetUploads(c.Request.Context(), userID.(int))
+	if err != nil {
+		h.respondUploadError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, uploads)
+}
+
+// GetUpload handles the request to retrieve an upload with a fresh download URL
+func (h *UploadHandler) GetUpload(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uploadID, err := strconv.Atoi(c.Param("uploadId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid upload ID"})
+		return
+	}
+
+	upload, err := h.uploadUseCase.GetUpload(c.Request.Context(), userID.(int), uploadID)
+	if err != nil {
+		h.respondUploadError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, upload)
+}
+
+// DeleteUpload handles the request to delete one of the current user's uploads
+func (h *UploadHandler) DeleteUpload(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	uploadID, err := strconv.Atoi(c.Param("uploadId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid upload ID"})
+		return
+	}
+
+	if err := h.uploadUseCase.DeleteUpload(c.Request.Context(), userID.(int), uploadID); err != nil {
+		h.respondUploadError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Upload deleted"})
+}
+
+// GetContent handles the public request for an avatar or intro video by redirecting to a
+// signed URL. Profiles link here so that their media never expires. Avatars take an optional
+// "size" query parameter with the width of the variant.
+func (h *UploadHandler) GetContent(c *gin.Context) {
+	uploadID, err := strconv.Atoi(c.Param("uploadId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid upload ID"})
+		return
+	}
+
+	width := 0
+	if size := c.Query("size"); size != "" {
+		width, err = strconv.Atoi(size)
+		if err != nil || !slices.Contains(entities.AvatarSizes, width) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid size"})
+			return
+		}
+	}
+
+	// Browsers that can show WebP say so when requesting images
+	acceptsWebP := strings.Contains(c.GetHeader("Accept"), "image/webp")
+
+	url, expiresAt, err := h.uploadUseCase.GetPublicURL(c.Request.Context(), uploadID, width, acceptsWebP)
+	if err != nil {
+		h.respondUploadError(c, err)
+		return
+	}
+
+	// Let browsers reuse the redirect, but only for half the time the signed URL has left
+	c.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", int(time.Until(expiresAt).Seconds())/2))
+	c.Header("Vary", "Accept")
+	c.Redirect(http.StatusFound, url)
+}
+
+// ServeFile handles a signed URL of the local storage backend
+func (h *UploadHandler) ServeFile(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+
+	file, err := h.uploadUseCase.OpenSignedFile(c.Request.Context(), key, c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		h.respondUploadError(c, err)
+		return
+	}
+	defer file.Close()
+
+	c.Header("X-Content-Type-Options", "nosniff")
+	if expires, err := strconv.ParseInt(c.Query("expires"), 10, 64); err == nil {
+		c.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", max(expires-time.Now().Unix(), 0)))
+	}
+
+	// The extension of the key was chosen from the detected content type, so it decides the Content-Type
+	if seeker, ok := file.(io.ReadSeeker); ok {
+		http.ServeContent(c.Writer, c.Request, path.Base(key), time.Time{}, seeker)
+		return
+	}
+	c.DataFromReader(http.StatusOK, -1, "application/octet-stream", file, nil)
+}
+
+// SetAvatar handles the request to make an uploaded image the current user's profile picture.
+// The picture is processed in the background and replaces the current one when it is ready.
+func (h *UploadHandler) SetAvatar(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req entities.UploadReference
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	upload, err := h.uploadUseCase.SetAvatar(c.Request.Context(), userID.(int), req.UploadID)
+	if err != nil {
+		h.respondUploadError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, upload)
+}
+
+// SetIntroVideo handles the tutor's request to make an uploaded video their intro video
+func (h *UploadHandler) SetIntroVideo(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req entities.UploadReference
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	upload, err := h.uploadUseCase.SetIntroVideo(c.Request.Context(), userID.(int), req.UploadID)
+	if err != nil {
+		h.respondUploadError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, upload)
+}
+
+// GetLessonMaterials handles the request to list the files shared with a lesson
+func (h *UploadHandler) GetLessonMaterials(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	lessonID, err := strconv.Atoi(c.Param("lessonId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lesson ID"})
+		return
+	}
+
+	materials, err := h.uploadUseCase.GetLessonMaterials(c.Request.Context(), userID.(int), lessonID)
+	if err != nil {
+		h.respondUploadError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, materials)
+}
+
+// AddLessonMaterial handles the request to share an uploaded file with a lesson
+func (h *UploadHandler) AddLessonMaterial(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	lessonID, err := strconv.Atoi(c.Param("lessonId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lesson ID"})
+		return
+	}
+
+	var req entities.UploadReference
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	material, err := h.uploadUseCase.AddLessonMaterial(c.Request.Context(), userID.(int), lessonID, req.UploadID)
+	if err != nil {
+		h.respondUploadError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, material)
+}
+
+// RemoveLessonMaterial handles the request to stop sharing a file with a lesson
+func (h *UploadHandler) RemoveLessonMaterial(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	lessonID, err := strconv.Atoi(c.Param("lessonId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lesson ID"})
+		return
+	}
+	uploadID, err := strconv.Atoi(c.Param("uploadId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid upload ID"})
+		return
+	}
+
+	if err := h.uploadUseCase.RemoveLessonMaterial(c.Request.Context(), userID.(int), lessonID, uploadID); err != nil {
+		h.respondUploadError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Material removed"})
+}
+
+// respondUploadError maps errors of the upload use cases to responses
+func (h *UploadHandler) respondUploadError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, entities.ErrUploadNotFound), errors.Is(err, entities.ErrNotFound),
+		errors.Is(err, entities.ErrUserNotFound), errors.Is(err, storage.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, entities.ErrUploadAccessDenied), errors.Is(err, entities.ErrNotLessonAttendee),
+		errors.Is(err, storage.ErrInvalidSignature), errors.Is(err, storage.ErrURLExpired),
+		errors.Is(err, storage.ErrInvalidKey):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, entities.ErrFileTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, entities.ErrUnsupportedFileType):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case errors.Is(err, entities.ErrUploadQuotaExceeded):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, entities.ErrInvalidUploadPurpose), errors.Is(err, entities.ErrEmptyFile),
+		errors.Is(err, entities.ErrWrongUploadPurpose), errors.Is(err, entities.ErrImageTooLarge):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		logger.Error("Upload request failed", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process upload request"})
+	}
+}
+
+// RegisterRoutes registers the upload routes
+func (h *UploadHandler) RegisterRoutes(router *gin.Engine) {
+	// Public routes: profile media and signed URLs carry their own authorization
+	router.GET("/api/uploads/:uploadId/content", h.GetContent)
+	router.GET("/api/files/*key", h.ServeFile)
+
+	uploads := router.Group("/api/uploads")
+	uploads.Use(middleware.AuthMiddleware())
+	{
+		uploads.GET("", h.GetUploads)
+		uploads.POST("", h.Upload)
+		uploads.GET("/:uploadId", h.GetUpload)
+		uploads.DELETE("/:uploadId", h.DeleteUpload)
+	}
+
+	router.PUT("/api/user/avatar", middleware.AuthMiddleware(), h.SetAvatar)
+	router.PUT("/api/tutor/intro-video", middleware.AuthMiddleware(), middleware.RoleMiddleware("tutor"), h.SetIntroVideo)
+
+	lessons := router.Group("/api/lessons")
+	lessons.Use(middleware.AuthMiddleware())
+	{
+		lessons.GET("/:lessonId/materials", h.GetLessonMaterials)
+		lessons.POST("/:lessonId/materials", h.AddLessonMaterial)
+		lessons.DELETE("/:lessonId/materials/:uploadId", h.RemoveLessonMaterial)
+	}
+}
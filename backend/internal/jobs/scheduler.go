@@ -0,0 +1,256 @@
+// Package jobs runs durable background jobs stored in the jobs table.
+//
+// Every backend replica runs a Scheduler. Due jobs are leased with FOR UPDATE SKIP LOCKED,
+// so a job is only run by one replica at a time. A job whose worker dies is run again once
+// its lease expires, so handlers must tolerate being run more than once. The lease of each job
+// is renewed right before it runs, and a job runs for at most jobTimeout, well within the lease.
+//
+// Periodic work is registered with Every. Each replica enqueues a job per interval, deduplicated
+// by job type and interval, so the work runs once per interval and shows up with the other jobs.
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"sync"
+	"time"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/logger"
+	"tongly-backend/internal/repositories"
+)
+
+const (
+	defaultPollInterval  = 5 * time.Second
+	defaultLeaseDuration = 5 * time.Minute
+	defaultJobTimeout    = 2 * time.Minute
+	defaultBatchSize     = 10
+)
+
+// Handler runs a job. Returning an error schedules a retry with exponential backoff
+// until the job runs out of attempts.
+type Handler func(ctx context.Context, job *entities.Job) error
+
+// Scheduler enqueues jobs and runs the registered handlers for due jobs
+type Scheduler struct {
+	jobRepo  *repositories.JobRepository
+	workerID string
+
+	mu        sync.RWMutex
+	handlers  map[entities.JobType]Handler
+	recurring []*recurringJob
+
+	pollInterval  time.Duration
+	leaseDuration time.Duration
+	jobTimeout    time.Duration
+	batchSize     int
+}
+
+// NewScheduler creates a new Scheduler with a unique worker ID
+func NewScheduler(jobRepo *repositories.JobRepository) *Scheduler {
+	return &Scheduler{
+		jobRepo:       jobRepo,
+		workerID:      newWorkerID(),
+		handlers:      make(map[entities.JobType]Handler),
+		pollInterval:  defaultPollInterval,
+		leaseDuration: defaultLeaseDuration,
+		jobTimeout:    defaultJobTimeout,
+		batchSize:     defaultBatchSize,
+	}
+}
+
+// recurringJob is a job type enqueued once per interval
+type recurringJob struct {
+	jobType  entities.JobType
+	interval time.Duration
+	// enqueued is the start of the last interval a job was enqueued for by this scheduler
+	enqueued time.Time
+}
+
+// Register sets the handler of a job type. Only registered job types are leased by this scheduler.
+func (s *Scheduler) Register(jobType entities.JobType, handler Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[jobType] = handler
+}
+
+// Every registers handler for a job type that is run once per interval. Intervals start at
+// multiples of interval since the zero time, so all replicas agree on them. A failed run is not
+// retried, since the next interval's run takes its place.
+func (s *Scheduler) Every(jobType entities.JobType, interval time.Duration, handler Handler) {
+	s.Register(jobType, handler)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recurring = append(s.recurring, &recurringJob{jobType: jobType, interval: interval})
+}
+
+// Enqueue stores a job to be run at req.RunAt (or right away). Enqueueing a job with the
+// dedup key of an existing job is a no-op that returns nil.
+func (s *Scheduler) Enqueue(ctx context.Context, req *entities.JobRequest) (*entities.Job, error) {
+	job, _, err := s.jobRepo.Enqueue(ctx, req)
+	return job, err
+}
+
+// Run polls for due jobs until ctx is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	logger.Info("Job scheduler started", "worker_id", s.workerID)
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.enqueueRecurring(ctx); err != nil && ctx.Err() == nil {
+			logger.Error("Failed to enqueue recurring jobs", "error", err)
+		}
+
+		// Keep leasing while full batches come back, so a backlog drains without waiting
+		for {
+			processed, err := s.poll(ctx)
+			if err != nil && ctx.Err() == nil {
+				logger.Error("Failed to poll jobs", "error", err)
+			}
+			if err != nil || processed < s.batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Info("Job scheduler stopped", "worker_id", s.workerID)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// enqueueRecurring enqueues the job of the current interval of every recurring job type, unless
+// this scheduler already has. The dedup key stops other replicas from enqueueing it again.
+func (s *Scheduler) enqueueRecurring(ctx context.Context) error {
+	s.mu.RLock()
+	recurring := s.recurring
+	s.mu.RUnlock()
+
+	now := time.Now()
+	for _, job := range recurring {
+		start := now.Truncate(job.interval)
+		if !start.After(job.enqueued) {
+			continue
+		}
+
+		_, err := s.Enqueue(ctx, &entities.JobRequest{
+			Type:        job.jobType,
+			RunAt:       start,
+			DedupKey:    fmt.Sprintf("%s:%d", job.jobType, start.Unix()),
+			MaxAttempts: 1,
+		})
+		if err != nil {
+			return err
+		}
+		job.enqueued = start
+	}
+
+	return nil
+}
+
+// poll leases a batch of due jobs and runs them, returning the number of leased jobs
+func (s *Scheduler) poll(ctx context.Context) (int, error) {
+	if _, err := s.jobRepo.FailExpiredLeases(ctx); err != nil {
+		return 0, err
+	}
+
+	s.mu.RLock()
+	types := make([]entities.JobType, 0, len(s.handlers))
+	for jobType := range s.handlers {
+		types = append(types, jobType)
+	}
+	s.mu.RUnlock()
+
+	if len(types) == 0 {
+		return 0, nil
+	}
+
+	leased, err := s.jobRepo.Lease(ctx, s.workerID, types, s.batchSize, s.leaseDuration)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range leased {
+		s.run(ctx, &leased[i])
+	}
+
+	return len(leased), nil
+}
+
+// run runs a leased job and records the outcome
+func (s *Scheduler) run(ctx context.Context, job *entities.Job) {
+	s.mu.RLock()
+	handler := s.handlers[job.Type]
+	s.mu.RUnlock()
+
+	// The jobs of a batch run one after another, so the lease taken with the batch may have
+	// run out by now. Renew it, and leave the job alone if another worker has taken it over.
+	if err := s.jobRepo.RenewLease(ctx, job.ID, s.workerID, s.leaseDuration); err != nil {
+		if errors.Is(err, entities.ErrJobLeaseLost) {
+			logger.Warn("Skipping job leased by another worker", "job_id", job.ID, "type", job.Type)
+		} else if ctx.Err() == nil {
+			logger.Error("Failed to renew job lease", "job_id", job.ID, "error", err)
+		}
+		return
+	}
+
+	// The handler must finish before the lease expires, or another worker may pick the job up
+	jobCtx, cancel := context.WithTimeout(ctx, s.jobTimeout)
+	err := safeRun(jobCtx, handler, job)
+	cancel()
+
+	// Record the outcome even if the scheduler is being stopped
+	recordCtx, cancelRecord := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelRecord()
+
+	if err == nil {
+		if err := s.jobRepo.Complete(recordCtx, job.ID, s.workerID); err != nil {
+			logger.Error("Failed to complete job", "job_id", job.ID, "error", err)
+		}
+		return
+	}
+
+	retryAt := time.Now().Add(entities.JobBackoff(job.Attempts))
+	logger.Error("Job failed", "job_id", job.ID, "type", job.Type, "attempt", job.Attempts,
+		"max_attempts", job.MaxAttempts, "error", err)
+
+	if err := s.jobRepo.Fail(recordCtx, job.ID, s.workerID, err.Error(), retryAt); err != nil {
+		logger.Error("Failed to record job failure", "job_id", job.ID, "error", err)
+	}
+}
+
+// safeRun runs the handler, turning a panic into an error so that one bad job cannot stop the scheduler
+func safeRun(ctx context.Context, handler Handler, job *entities.Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+
+	if handler == nil {
+		return fmt.Errorf("no handler registered for job type %q", job.Type)
+	}
+	return handler(ctx, job)
+}
+
+// newWorkerID identifies this process in the locked_by column
+func newWorkerID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	b := make([]byte, 4)
+	rand.Read(b)
+
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(b))
+}
@@ -0,0 +1,168 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+	"tongly-backend/internal/entities"
+
+	"github.com/lib/pq"
+)
+
+// tutorAnalyticsBucketsQuery aggregates a tutor's activity in [$2, $3) by $4, which is
+// day, week or month. Every period of the range gets a row, even one with no activity.
+const tutorAnalyticsBucketsQuery = `
+	WITH buckets AS (
+		SELECT generate_series(date_trunc($4, $2::timestamp), $3::timestamp - INTERVAL '1 microsecond', ('1 ' || $4)::interval) AS bucket
+	),
+	taught AS (
+		SELECT date_trunc($4, l.start_time) AS bucket,
+		       COUNT(*) AS lessons,
+		       SUM(EXTRACT(EPOCH FROM l.end_time - l.start_time))::float8 / 3600 AS hours
+		FROM lessons l
+		WHERE l.tutor_id = $1 AND l.start_time >= $2 AND l.start_time < $3
+		  AND l.cancelled_at IS NULL AND NOT l.student_no_show AND l.end_time <= NOW()
+		GROUP BY 1
+	),
+	scheduled AS (
+		SELECT date_trunc($4, l.start_time) AS bucket,
+		       COUNT(*) AS lessons,
+		       COUNT(*) FILTER (WHERE l.cancelled_at IS NOT NULL AND l.cancelled_by = l.tutor_id) AS by_tutor,
+		       COUNT(*) FILTER (WHERE l.cancelled_at IS NOT NULL AND l.cancelled_by = l.student_id) AS by_student,
+		       COUNT(*) FILTER (WHERE l.cancelled_at IS NOT NULL
+		                          AND l.cancelled_by IS DISTINCT FROM l.tutor_id
+		                          AND l.cancelled_by IS DISTINCT FROM l.student_id) AS other
+		FROM lessons l
+		WHERE l.tutor_id = $1 AND l.lesson_type <> 'group' AND l.start_time >= $2 AND l.start_time < $3
+		GROUP BY 1
+	),
+	booked AS (
+		SELECT date_trunc($4, l.created_at) AS bucket, COUNT(*) AS lessons
+		FROM lessons l
+		WHERE l.tutor_id = $1 AND l.lesson_type <> 'group' AND l.created_at >= $2 AND l.created_at < $3
+		GROUP BY 1
+	),
+	rated AS (
+		SELECT date_trunc($4, r.created_at) AS bucket, COUNT(*) AS reviews, AVG(r.rating)::float8 AS rating
+		FROM reviews r
+		JOIN lessons l ON l.id = r.lesson_id
+		WHERE l.tutor_id = $1 AND r.hidden_at IS NULL AND r.created_at >= $2 AND r.created_at < $3
+		GROUP BY 1
+	),
+	seen AS (
+		SELECT date_trunc($4, e.day::timestamp) AS bucket,
+		       COALESCE(SUM(e.count) FILTER (WHERE e.event_type = 'profile_view'), 0) AS views,
+		       COALESCE(SUM(e.count) FILTER (WHERE e.event_type = 'search_impression'), 0) AS impressions
+		FROM tutor_event_counts e
+		WHERE e.tutor_id = $1 AND e.day >= $2::date AND e.day < $3
+		GROUP BY 1
+	)
+	SELECT b.bucket,
+	       COALESCE(t.lessons, 0), COALESCE(t.hours, 0),
+	       COALESCE(s.lessons, 0), COALESCE(s.by_tutor, 0), COALESCE(s.by_student, 0), COALESCE(s.other, 0),
+	       COALESCE(bk.lessons, 0),
+	       COALESCE(rt.reviews, 0), rt.rating,
+	       COALESCE(sn.views, 0), COALESCE(sn.impressions, 0)
+	FROM buckets b
+	LEFT JOIN taught t ON t.bucket = b.bucket
+	LEFT JOIN scheduled s ON s.bucket = b.bucket
+	LEFT JOIN booked bk ON bk.bucket = b.bucket
+	LEFT JOIN rated rt ON rt.bucket = b.bucket
+	LEFT JOIN seen sn ON sn.bucket = b.bucket
+	ORDER BY b.bucket
+`
+
+// AnalyticsRepository handles database operations for tutor analytics
+type AnalyticsRepository struct {
+	db *sql.DB
+}
+
+// NewAnalyticsRepository creates a new AnalyticsRepository
+func NewAnalyticsRepository(db *sql.DB) *AnalyticsRepository {
+	return &AnalyticsRepository{
+		db: db,
+	}
+}
+
+// RecordEvents counts an event for each of the tutors today. A tutor listed more than once
+// is counted more than once.
+func (r *AnalyticsRepository) RecordEvents(ctx context.Context, eventType entities.TutorEventType, tutorIDs []int) error {
+	if len(tutorIDs) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO tutor_event_counts (tutor_id, event_type, day, count)
+		SELECT tutor_id, $2, CURRENT_DATE, COUNT(*)
+		FROM unnest($1::int[]) AS tutor_id
+		GROUP BY tutor_id
+		ON CONFLICT (tutor_id, event_type, day) DO UPDATE
+		SET count = tutor_event_counts.count + EXCLUDED.count
+	`
+
+	_, err := r.db.ExecContext(ctx, query, pq.Array(tutorIDs), eventType)
+	return err
+}
+
+// GetTutorAnalyticsBuckets retrieves a tutor's activity in [from, to) period by period
+func (r *AnalyticsRepository) GetTutorAnalyticsBuckets(ctx context.Context, tutorID int, from, to time.Time, period entities.EarningsPeriod) ([]entities.TutorAnalyticsBucket, error) {
+	rows, err := r.db.QueryContext(ctx, tutorAnalyticsBucketsQuery, tutorID, from, to, period)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	buckets := []entities.TutorAnalyticsBucket{}
+	for rows.Next() {
+		var bucket entities.TutorAnalyticsBucket
+		err := rows.Scan(
+			&bucket.Start,
+			&bucket.LessonsTaught,
+			&bucket.HoursTaught,
+			&bucket.ScheduledLessons,
+			&bucket.CancelledByTutor,
+			&bucket.CancelledByStudent,
+			&bucket.CancelledOther,
+			&bucket.Bookings,
+			&bucket.ReviewsCount,
+			&bucket.AverageRating,
+			&bucket.ProfileViews,
+			&bucket.SearchImpressions,
+		)
+		if err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, bucket)
+	}
+
+	return buckets, rows.Err()
+}
+
+// GetRepeatStudents counts the students a tutor taught in [from, to), and how many of them
+// had more than one lesson with the tutor by the end of the range, group lessons included
+func (r *AnalyticsRepository) GetRepeatStudents(ctx context.Context, tutorID int, from, to time.Time) (students, repeatStudents int, err error) {
+	query := `
+		WITH taught AS (
+			SELECT l.student_id, l.start_time
+			FROM lessons l
+			WHERE l.tutor_id = $1 AND l.lesson_type <> 'group' AND l.start_time < $3
+			  AND l.cancelled_at IS NULL AND NOT l.student_no_show AND l.end_time <= NOW()
+			UNION ALL
+			SELECT p.student_id, l.start_time
+			FROM lesson_participants p
+			JOIN lessons l ON l.id = p.lesson_id
+			WHERE l.tutor_id = $1 AND l.lesson_type = 'group' AND l.start_time < $3
+			  AND l.cancelled_at IS NULL AND p.cancelled_at IS NULL AND l.end_time <= NOW()
+		)
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE lessons > 1)
+		FROM (
+			SELECT student_id, COUNT(*) AS lessons
+			FROM taught
+			GROUP BY student_id
+			HAVING MAX(start_time) >= $2
+		) AS students
+	`
+
+	err = r.db.QueryRowContext(ctx, query, tutorID, from, to).Scan(&students, &repeatStudents)
+	return students, repeatStudents, err
+}
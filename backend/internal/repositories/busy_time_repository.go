@@ -0,0 +1,292 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+	"tongly-backend/internal/entities"
+
+	"github.com/lib/pq"
+)
+
+// calendarSourceColumns lists the columns in the order expected by scanCalendarSource
+const calendarSourceColumns = `s.id, s.tutor_id, s.name, s.source_type, s.url, s.content,
+	s.last_synced_at, s.last_error,
+	(SELECT COUNT(*) FROM tutor_busy_times b WHERE b.source_id = s.id),
+	s.created_at, s.updated_at`
+
+// BusyTimeRepository handles database operations for external calendars and the busy times imported from them
+type BusyTimeRepository struct {
+	db *sql.DB
+}
+
+// NewBusyTimeRepository creates a new BusyTimeRepository
+func NewBusyTimeRepository(db *sql.DB) *BusyTimeRepository {
+	return &BusyTimeRepository{
+		db: db,
+	}
+}
+
+// CreateSource inserts an external calendar, failing with ErrTooManyCalendarSources when the tutor has too many
+func (r *BusyTimeRepository) CreateSource(ctx context.Context, source *entities.CalendarSource) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Serialize changes to the tutor's calendars
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, source.TutorID); err != nil {
+		return err
+	}
+
+	var count int
+	err = tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM tutor_calendar_sources WHERE tutor_id = $1`, source.TutorID).Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count >= entities.MaxCalendarSourcesPerTutor {
+		return entities.ErrTooManyCalendarSources
+	}
+
+	query := `
+		INSERT INTO tutor_calendar_sources (tutor_id, name, source_type, url, content)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`
+
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		source.TutorID,
+		source.Name,
+		source.Type,
+		source.URL,
+		source.Content,
+	).Scan(&source.ID, &source.CreatedAt, &source.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetSource retrieves an external calendar of a tutor
+func (r *BusyTimeRepository) GetSource(ctx context.Context, sourceID, tutorID int) (*entities.CalendarSource, error) {
+	query := `SELECT ` + calendarSourceColumns + ` FROM tutor_calendar_sources s WHERE s.id = $1 AND s.tutor_id = $2`
+
+	source, err := scanCalendarSource(r.db.QueryRowContext(ctx, query, sourceID, tutorID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrCalendarSourceNotFound
+		}
+		return nil, err
+	}
+
+	return source, nil
+}
+
+// GetSources retrieves the external calendars of a tutor
+func (r *BusyTimeRepository) GetSources(ctx context.Context, tutorID int) ([]entities.CalendarSource, error) {
+	query := `SELECT ` + calendarSourceColumns + ` FROM tutor_calendar_sources s WHERE s.tutor_id = $1 ORDER BY s.created_at`
+
+	return r.getSourcesByQuery(ctx, query, tutorID)
+}
+
+// ClaimSourcesDueForSync claims up to limit calendars that have not been synced since the
+// given time for claimTTL, and returns them. Calendars claimed by another replica are skipped,
+// so that each one is fetched once however many replicas sync at the same time.
+func (r *BusyTimeRepository) ClaimSourcesDueForSync(ctx context.Context, syncedBefore time.Time, limit int, claimTTL time.Duration) ([]entities.CalendarSource, error) {
+	query := `
+		UPDATE tutor_calendar_sources s
+		SET sync_claimed_until = NOW() + $3 * INTERVAL '1 second'
+		WHERE s.id IN (
+			SELECT id FROM tutor_calendar_sources
+			WHERE (last_synced_at IS NULL OR last_synced_at < $1)
+			  AND (sync_claimed_until IS NULL OR sync_claimed_until < NOW())
+			ORDER BY last_synced_at ASC NULLS FIRST
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + calendarSourceColumns
+
+	rows, err := r.db.QueryContext(ctx, query, syncedBefore, limit, claimTTL.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanCalendarSources(rows)
+}
+
+// getSourcesByQuery retrieves calendar sources selected with calendarSourceColumns
+func (r *BusyTimeRepository) getSourcesByQuery(ctx context.Context, query string, arg interface{}) ([]entities.CalendarSource, error) {
+	rows, err := r.db.QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanCalendarSources(rows)
+}
+
+// scanCalendarSources scans rows of calendar sources selected with calendarSourceColumns
+func scanCalendarSources(rows *sql.Rows) ([]entities.CalendarSource, error) {
+	sources := []entities.CalendarSource{}
+	for rows.Next() {
+		source, err := scanCalendarSource(rows)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, *source)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sources, nil
+}
+
+// DeleteSource deletes an external calendar of a tutor together with its busy times
+func (r *BusyTimeRepository) DeleteSource(ctx context.Context, sourceID, tutorID int) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM tutor_calendar_sources WHERE id = $1 AND tutor_id = $2`, sourceID, tutorID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return entities.ErrCalendarSourceNotFound
+	}
+
+	return nil
+}
+
+// ReplaceBusyTimes replaces the busy times of a calendar with the result of a successful sync
+func (r *BusyTimeRepository) ReplaceBusyTimes(ctx context.Context, source *entities.CalendarSource, busyTimes []entities.BusyTime) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM tutor_busy_times WHERE source_id = $1`, source.ID); err != nil {
+		return err
+	}
+
+	if len(busyTimes) > 0 {
+		starts := make([]time.Time, len(busyTimes))
+		ends := make([]time.Time, len(busyTimes))
+		for i, busy := range busyTimes {
+			starts[i] = busy.StartTime
+			ends[i] = busy.EndTime
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO tutor_busy_times (source_id, tutor_id, start_time, end_time)
+			SELECT $1, $2, UNNEST($3::timestamp[]), UNNEST($4::timestamp[])
+		`, source.ID, source.TutorID, pq.Array(starts), pq.Array(ends))
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE tutor_calendar_sources SET last_synced_at = NOW(), last_error = NULL, sync_claimed_until = NULL
+		WHERE id = $1
+	`, source.ID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// MarkSyncFailed records a failed sync, keeping the busy times of the last successful one
+func (r *BusyTimeRepository) MarkSyncFailed(ctx context.Context, sourceID int, syncErr string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE tutor_calendar_sources SET last_synced_at = NOW(), last_error = $2, sync_claimed_until = NULL
+		WHERE id = $1
+	`, sourceID, syncErr)
+	return err
+}
+
+// GetBusyTimes retrieves the busy times of a tutor that overlap the given window, merged across calendars
+func (r *BusyTimeRepository) GetBusyTimes(ctx context.Context, tutorID int, from, to time.Time) ([]entities.BusyTime, error) {
+	query := `
+		SELECT start_time, end_time
+		FROM tutor_busy_times
+		WHERE tutor_id = $1 AND end_time > $2 AND start_time < $3
+		ORDER BY start_time
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, tutorID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	busyTimes := []entities.BusyTime{}
+	for rows.Next() {
+		var busy entities.BusyTime
+		if err := rows.Scan(&busy.StartTime, &busy.EndTime); err != nil {
+			return nil, err
+		}
+
+		// Merge with the previous interval if they overlap
+		if n := len(busyTimes); n > 0 && !busy.StartTime.After(busyTimes[n-1].EndTime) {
+			if busy.EndTime.After(busyTimes[n-1].EndTime) {
+				busyTimes[n-1].EndTime = busy.EndTime
+			}
+			continue
+		}
+		busyTimes = append(busyTimes, busy)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return busyTimes, nil
+}
+
+// HasBusyTime checks if any busy time of the tutor overlaps the given interval
+func (r *BusyTimeRepository) HasBusyTime(ctx context.Context, tutorID int, start, end time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM tutor_busy_times
+			WHERE tutor_id = $1 AND start_time < $3 AND end_time > $2
+		)
+	`
+
+	var busy bool
+	err := r.db.QueryRowContext(ctx, query, tutorID, start, end).Scan(&busy)
+	return busy, err
+}
+
+// scanCalendarSource scans a row selected with calendarSourceColumns
+func scanCalendarSource(row rowScanner) (*entities.CalendarSource, error) {
+	var source entities.CalendarSource
+	err := row.Scan(
+		&source.ID,
+		&source.TutorID,
+		&source.Name,
+		&source.Type,
+		&source.URL,
+		&source.Content,
+		&source.LastSyncedAt,
+		&source.LastError,
+		&source.BusyCount,
+		&source.CreatedAt,
+		&source.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &source, nil
+}
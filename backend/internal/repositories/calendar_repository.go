@@ -0,0 +1,145 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+	"tongly-backend/internal/entities"
+)
+
+// calendarEntrySelect selects the lessons of the user given as $1 in the order expected by
+// scanCalendarEntry. For group lessons the user's latest seat decides whether the lesson is
+// cancelled for them, and a seat update counts as an update of the entry.
+const calendarEntrySelect = `
+	SELECT l.id, l.tutor_id, l.lesson_type, l.start_time, l.end_time, COALESCE(l.topic, ''), lang.name,
+	       TRIM(t.first_name || ' ' || t.last_name), COALESCE(TRIM(s.first_name || ' ' || s.last_name), ''),
+	       (l.cancelled_at IS NOT NULL OR seat.cancelled_at IS NOT NULL),
+	       l.created_at, GREATEST(l.updated_at, COALESCE(seat.updated_at, l.updated_at))
+	FROM lessons l
+	JOIN users t ON l.tutor_id = t.id
+	LEFT JOIN users s ON l.student_id = s.id
+	JOIN languages lang ON l.language_id = lang.id
+	LEFT JOIN LATERAL (
+		SELECT p.lesson_id, p.cancelled_at, p.updated_at
+		FROM lesson_participants p
+		WHERE p.lesson_id = l.id AND p.student_id = $1
+		ORDER BY p.cancelled_at IS NULL DESC, p.id DESC
+		LIMIT 1
+	) seat ON TRUE
+	WHERE (l.tutor_id = $1 OR l.student_id = $1 OR seat.lesson_id IS NOT NULL)
+`
+
+// CalendarRepository handles database operations for calendar feeds
+type CalendarRepository struct {
+	db *sql.DB
+}
+
+// NewCalendarRepository creates a new CalendarRepository
+func NewCalendarRepository(db *sql.DB) *CalendarRepository {
+	return &CalendarRepository{
+		db: db,
+	}
+}
+
+// GetFeedByUserID retrieves the calendar feed of a user
+func (r *CalendarRepository) GetFeedByUserID(ctx context.Context, userID int) (*entities.CalendarFeed, error) {
+	query := `SELECT user_id, token, created_at FROM calendar_feeds WHERE user_id = $1`
+
+	var feed entities.CalendarFeed
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&feed.UserID, &feed.Token, &feed.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrCalendarFeedNotFound
+		}
+		return nil, err
+	}
+
+	return &feed, nil
+}
+
+// GetFeedByToken retrieves a calendar feed by its secret token
+func (r *CalendarRepository) GetFeedByToken(ctx context.Context, token string) (*entities.CalendarFeed, error) {
+	query := `SELECT user_id, token, created_at FROM calendar_feeds WHERE token = $1`
+
+	var feed entities.CalendarFeed
+	err := r.db.QueryRowContext(ctx, query, token).Scan(&feed.UserID, &feed.Token, &feed.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrCalendarFeedNotFound
+		}
+		return nil, err
+	}
+
+	return &feed, nil
+}
+
+// SaveFeed stores the calendar feed of a user, replacing the token of an existing feed
+func (r *CalendarRepository) SaveFeed(ctx context.Context, feed *entities.CalendarFeed) error {
+	query := `
+		INSERT INTO calendar_feeds (user_id, token)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET token = EXCLUDED.token, created_at = NOW()
+		RETURNING created_at
+	`
+
+	return r.db.QueryRowContext(ctx, query, feed.UserID, feed.Token).Scan(&feed.CreatedAt)
+}
+
+// GetEntries retrieves the lessons of a user that overlap the given time window, including cancelled ones
+func (r *CalendarRepository) GetEntries(ctx context.Context, userID int, from, to time.Time) ([]entities.CalendarEntry, error) {
+	query := calendarEntrySelect + ` AND l.end_time >= $2 AND l.start_time < $3 ORDER BY l.start_time ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []entities.CalendarEntry{}
+	for rows.Next() {
+		var entry entities.CalendarEntry
+		if err := scanCalendarEntry(rows, &entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// GetEntry retrieves a single lesson of a user as a calendar entry
+func (r *CalendarRepository) GetEntry(ctx context.Context, userID, lessonID int) (*entities.CalendarEntry, error) {
+	query := calendarEntrySelect + ` AND l.id = $2`
+
+	var entry entities.CalendarEntry
+	if err := scanCalendarEntry(r.db.QueryRowContext(ctx, query, userID, lessonID), &entry); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// scanCalendarEntry scans a row selected with calendarEntrySelect
+func scanCalendarEntry(row rowScanner, entry *entities.CalendarEntry) error {
+	return row.Scan(
+		&entry.LessonID,
+		&entry.TutorID,
+		&entry.Type,
+		&entry.StartTime,
+		&entry.EndTime,
+		&entry.Topic,
+		&entry.Language,
+		&entry.TutorName,
+		&entry.StudentName,
+		&entry.Cancelled,
+		&entry.CreatedAt,
+		&entry.UpdatedAt,
+	)
+}
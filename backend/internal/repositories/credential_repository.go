@@ -0,0 +1,244 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"tongly-backend/internal/entities"
+)
+
+// credentialColumns lists the columns of a credential and its proof document in the order
+// expected by scanCredential. It selects from credentialFrom.
+const credentialColumns = `c.id, c.tutor_id, c.kind, c.title, c.issuer, c.field_of_study, c.year, c.document_id,
+	c.status, c.rejection_reason, c.reviewed_at, c.created_at, c.updated_at, ` + uploadColumns
+
+// credentialFrom is the FROM clause of the credential queries
+const credentialFrom = ` FROM tutor_credentials c JOIN uploads u ON u.id = c.document_id`
+
+// CredentialRepository handles database operations for tutor credentials
+type CredentialRepository struct {
+	db *sql.DB
+}
+
+// NewCredentialRepository creates a new CredentialRepository
+func NewCredentialRepository(db *sql.DB) *CredentialRepository {
+	return &CredentialRepository{
+		db: db,
+	}
+}
+
+// Create inserts a pending credential, failing with ErrTooManyCredentials when the tutor has too many
+func (r *CredentialRepository) Create(ctx context.Context, credential *entities.TutorCredential) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Serialize changes to the tutor's credentials
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, credential.TutorID); err != nil {
+		return err
+	}
+
+	var count int
+	err = tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM tutor_credentials WHERE tutor_id = $1`, credential.TutorID).Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count >= entities.MaxCredentialsPerTutor {
+		return entities.ErrTooManyCredentials
+	}
+
+	query := `
+		INSERT INTO tutor_credentials (tutor_id, kind, title, issuer, field_of_study, year, document_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, status, created_at, updated_at
+	`
+
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		credential.TutorID,
+		credential.Kind,
+		credential.Title,
+		credential.Issuer,
+		credential.FieldOfStudy,
+		credential.Year,
+		credential.DocumentID,
+	).Scan(&credential.ID, &credential.Status, &credential.CreatedAt, &credential.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Update replaces a tutor's credential and puts it back in the review queue
+func (r *CredentialRepository) Update(ctx context.Context, credential *entities.TutorCredential) error {
+	query := `
+		UPDATE tutor_credentials
+		SET kind = $1, title = $2, issuer = $3, field_of_study = $4, year = $5, document_id = $6,
+		    status = 'pending', rejection_reason = NULL, reviewed_by = NULL, reviewed_at = NULL
+		WHERE id = $7 AND tutor_id = $8
+		RETURNING status, created_at, updated_at
+	`
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		credential.Kind,
+		credential.Title,
+		credential.Issuer,
+		credential.FieldOfStudy,
+		credential.Year,
+		credential.DocumentID,
+		credential.ID,
+		credential.TutorID,
+	).Scan(&credential.Status, &credential.CreatedAt, &credential.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return entities.ErrCredentialNotFound
+	}
+	return err
+}
+
+// Delete deletes a tutor's credential. Its proof document stays among the tutor's uploads.
+func (r *CredentialRepository) Delete(ctx context.Context, credentialID, tutorID int) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM tutor_credentials WHERE id = $1 AND tutor_id = $2`, credentialID, tutorID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return entities.ErrCredentialNotFound
+	}
+
+	return nil
+}
+
+// GetByID retrieves a credential
+func (r *CredentialRepository) GetByID(ctx context.Context, credentialID int) (*entities.TutorCredential, error) {
+	query := `SELECT ` + credentialColumns + credentialFrom + ` WHERE c.id = $1`
+
+	credential, err := scanCredential(r.db.QueryRowContext(ctx, query, credentialID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrCredentialNotFound
+		}
+		return nil, err
+	}
+
+	return credential, nil
+}
+
+// GetByTutor retrieves a tutor's credentials, degrees first and the most recent first
+func (r *CredentialRepository) GetByTutor(ctx context.Context, tutorID int) ([]entities.TutorCredential, error) {
+	query := `SELECT ` + credentialColumns + credentialFrom + `
+		WHERE c.tutor_id = $1
+		ORDER BY c.kind DESC, c.year DESC NULLS LAST, c.id
+	`
+
+	return r.getCredentialsByQuery(ctx, query, tutorID)
+}
+
+// GetVerifiedByTutor retrieves a tutor's verified credentials, degrees first and the most recent first
+func (r *CredentialRepository) GetVerifiedByTutor(ctx context.Context, tutorID int) ([]entities.TutorCredential, error) {
+	query := `SELECT ` + credentialColumns + credentialFrom + `
+		WHERE c.tutor_id = $1 AND c.status = 'verified'
+		ORDER BY c.kind DESC, c.year DESC NULLS LAST, c.id
+	`
+
+	return r.getCredentialsByQuery(ctx, query, tutorID)
+}
+
+// ListForReview retrieves a page of the credentials with a status for the admins. Pending
+// credentials come oldest first, so that they are reviewed in the order they were submitted;
+// reviewed ones come most recently reviewed first.
+func (r *CredentialRepository) ListForReview(ctx context.Context, filters *entities.CredentialFilters) ([]entities.TutorCredential, error) {
+	query := `SELECT ` + credentialColumns + credentialFrom + `
+		WHERE c.status = $1
+		ORDER BY c.reviewed_at DESC NULLS LAST, c.updated_at, c.id
+		LIMIT $2 OFFSET $3
+	`
+
+	return r.getCredentialsByQuery(ctx, query, filters.Status, filters.Limit, filters.Offset)
+}
+
+// Review records an admin's decision on a credential. The reason is only kept for rejections.
+func (r *CredentialRepository) Review(ctx context.Context, credentialID, adminID int, status entities.CredentialStatus, reason *string) error {
+	query := `
+		UPDATE tutor_credentials
+		SET status = $1, rejection_reason = $2, reviewed_by = $3, reviewed_at = NOW()
+		WHERE id = $4
+	`
+
+	if status != entities.CredentialRejected {
+		reason = nil
+	}
+	result, err := r.db.ExecContext(ctx, query, status, reason, adminID, credentialID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return entities.ErrCredentialNotFound
+	}
+
+	return nil
+}
+
+// getCredentialsByQuery retrieves credentials selected with credentialColumns
+func (r *CredentialRepository) getCredentialsByQuery(ctx context.Context, query string, args ...interface{}) ([]entities.TutorCredential, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	credentials := []entities.TutorCredential{}
+	for rows.Next() {
+		credential, err := scanCredential(rows)
+		if err != nil {
+			return nil, err
+		}
+		credentials = append(credentials, *credential)
+	}
+
+	return credentials, rows.Err()
+}
+
+// scanCredential reads a credential selected with credentialColumns, with its proof document
+func scanCredential(row rowScanner) (*entities.TutorCredential, error) {
+	var credential entities.TutorCredential
+	var document entities.Upload
+
+	err := row.Scan(
+		&credential.ID,
+		&credential.TutorID,
+		&credential.Kind,
+		&credential.Title,
+		&credential.Issuer,
+		&credential.FieldOfStudy,
+		&credential.Year,
+		&credential.DocumentID,
+		&credential.Status,
+		&credential.RejectionReason,
+		&credential.ReviewedAt,
+		&credential.CreatedAt,
+		&credential.UpdatedAt,
+		&document.ID, &document.UserID, &document.Purpose, &document.StorageKey,
+		&document.Filename, &document.ContentType, &document.Size, &document.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	credential.Document = &document
+	return &credential, nil
+}
@@ -0,0 +1,195 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+	"tongly-backend/internal/entities"
+)
+
+// earningsItemsQuery selects the earnings line items of the tutor given as $1.
+// Completed lessons are earned at their end time, late cancellation fees at the time of
+// cancellation and no-show fees at the end of the missed lesson. Every seat of a group
+// lesson is a separate line item.
+const earningsItemsQuery = `
+	SELECT id AS lesson_id, student_id, 'lesson' AS item_type, price AS amount, end_time AS occurred_at
+	FROM lessons
+	WHERE tutor_id = $1 AND lesson_type <> 'group' AND cancelled_at IS NULL AND NOT student_no_show
+	  AND end_time < NOW()
+	UNION ALL
+	SELECT id, student_id, 'late_cancellation_fee', cancellation_fee, cancelled_at
+	FROM lessons
+	WHERE tutor_id = $1 AND lesson_type <> 'group' AND cancellation_fee > 0
+	UNION ALL
+	SELECT id, student_id, 'no_show_fee', no_show_fee, end_time
+	FROM lessons
+	WHERE tutor_id = $1 AND lesson_type <> 'group' AND student_no_show AND no_show_fee > 0
+	  AND end_time < NOW()
+	UNION ALL
+	SELECT l.id, p.student_id, 'lesson', p.price, l.end_time
+	FROM lesson_participants p
+	JOIN lessons l ON p.lesson_id = l.id
+	WHERE l.tutor_id = $1 AND l.cancelled_at IS NULL AND p.cancelled_at IS NULL AND l.end_time < NOW()
+	UNION ALL
+	SELECT l.id, p.student_id, 'late_cancellation_fee', p.cancellation_fee, p.cancelled_at
+	FROM lesson_participants p
+	JOIN lessons l ON p.lesson_id = l.id
+	WHERE l.tutor_id = $1 AND p.cancellation_fee > 0
+`
+
+// EarningsRepository handles database operations for tutor earnings and payouts
+type EarningsRepository struct {
+	db *sql.DB
+}
+
+// NewEarningsRepository creates a new EarningsRepository
+func NewEarningsRepository(db *sql.DB) *EarningsRepository {
+	return &EarningsRepository{
+		db: db,
+	}
+}
+
+// GetLineItems retrieves the earnings line items of a tutor that occurred in [from, to)
+func (r *EarningsRepository) GetLineItems(ctx context.Context, tutorID int, from, to time.Time) ([]entities.EarningsLineItem, error) {
+	query := `
+		SELECT items.lesson_id, items.item_type, items.amount, items.occurred_at,
+		       items.student_id, TRIM(CONCAT(s.first_name, ' ', s.last_name)), s.username,
+		       l.language_id, lang.name
+		FROM (` + earningsItemsQuery + `) items
+		JOIN lessons l ON items.lesson_id = l.id
+		JOIN users s ON items.student_id = s.id
+		JOIN languages lang ON l.language_id = lang.id
+		WHERE items.occurred_at >= $2 AND items.occurred_at < $3
+		ORDER BY items.occurred_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, tutorID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []entities.EarningsLineItem
+	for rows.Next() {
+		var item entities.EarningsLineItem
+		var username string
+
+		err := rows.Scan(
+			&item.LessonID,
+			&item.Type,
+			&item.Amount,
+			&item.OccurredAt,
+			&item.StudentID,
+			&item.StudentName,
+			&username,
+			&item.LanguageID,
+			&item.LanguageName,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if item.StudentName == "" {
+			item.StudentName = username
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// CreatePayout inserts a new payout request for a tutor. The tutor's available balance
+// (earnings older than the hold period minus payouts that were not rejected) is checked
+// within the same transaction so that concurrent requests cannot overdraw it.
+func (r *EarningsRepository) CreatePayout(ctx context.Context, payout *entities.TutorPayout, holdPeriod time.Duration) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Serialize payout requests of the same tutor
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, payout.TutorID); err != nil {
+		return err
+	}
+
+	balanceQuery := `
+		SELECT
+			COALESCE((SELECT SUM(amount) FROM (` + earningsItemsQuery + `) items
+			          WHERE occurred_at <= NOW() - $2::double precision * INTERVAL '1 second'), 0)
+			- COALESCE((SELECT SUM(amount) FROM tutor_payouts
+			            WHERE tutor_id = $1 AND status <> 'rejected'), 0)
+	`
+
+	var available float64
+	if err := tx.QueryRowContext(ctx, balanceQuery, payout.TutorID, holdPeriod.Seconds()).Scan(&available); err != nil {
+		return err
+	}
+	if payout.Amount > available+0.005 {
+		return entities.ErrInsufficientBalance
+	}
+
+	query := `
+		INSERT INTO tutor_payouts (tutor_id, amount, status)
+		VALUES ($1, $2, $3)
+		RETURNING id, requested_at, created_at, updated_at
+	`
+
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		payout.TutorID,
+		payout.Amount,
+		payout.Status,
+	).Scan(&payout.ID, &payout.RequestedAt, &payout.CreatedAt, &payout.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetPayouts retrieves all payouts of a tutor, newest first
+func (r *EarningsRepository) GetPayouts(ctx context.Context, tutorID int) ([]entities.TutorPayout, error) {
+	query := `
+		SELECT id, tutor_id, amount, status, requested_at, processed_at, created_at, updated_at
+		FROM tutor_payouts
+		WHERE tutor_id = $1
+		ORDER BY requested_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, tutorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payouts []entities.TutorPayout
+	for rows.Next() {
+		var payout entities.TutorPayout
+		err := rows.Scan(
+			&payout.ID,
+			&payout.TutorID,
+			&payout.Amount,
+			&payout.Status,
+			&payout.RequestedAt,
+			&payout.ProcessedAt,
+			&payout.CreatedAt,
+			&payout.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		payouts = append(payouts, payout)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return payouts, nil
+}
@@ -0,0 +1,363 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+	"tongly-backend/internal/entities"
+
+	"github.com/lib/pq"
+)
+
+// savedSearchColumns lists the columns in the order expected by scanSavedSearch
+const savedSearchColumns = `id, student_id, name, filters, notify, created_at, updated_at`
+
+// FavoriteRepository handles database operations for students' favorite tutors and saved searches
+type FavoriteRepository struct {
+	db *sql.DB
+}
+
+// NewFavoriteRepository creates a new FavoriteRepository
+func NewFavoriteRepository(db *sql.DB) *FavoriteRepository {
+	return &FavoriteRepository{
+		db: db,
+	}
+}
+
+// AddFavorite adds a tutor to a student's favorites, failing with ErrTooManyFavorites when the
+// student has too many. Adding a tutor who is already a favorite changes nothing.
+func (r *FavoriteRepository) AddFavorite(ctx context.Context, studentID, tutorID int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Serialize changes to the student's favorites
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, studentID); err != nil {
+		return err
+	}
+
+	var count int
+	var exists bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(BOOL_OR(tutor_id = $2), FALSE)
+		FROM favorite_tutors WHERE student_id = $1
+	`, studentID, tutorID).Scan(&count, &exists)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+	if count >= entities.MaxFavoriteTutors {
+		return entities.ErrTooManyFavorites
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO favorite_tutors (student_id, tutor_id) VALUES ($1, $2)`, studentID, tutorID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RemoveFavorite removes a tutor from a student's favorites
+func (r *FavoriteRepository) RemoveFavorite(ctx context.Context, studentID, tutorID int) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM favorite_tutors WHERE student_id = $1 AND tutor_id = $2`, studentID, tutorID)
+	return err
+}
+
+// GetFavorites retrieves a student's favorite tutors, the most recently added first
+func (r *FavoriteRepository) GetFavorites(ctx context.Context, studentID int) ([]entities.FavoriteTutor, error) {
+	query := `SELECT ` + tutorListColumns + `, f.created_at` + tutorSearchFrom + `
+		JOIN favorite_tutors f ON f.tutor_id = tp.user_id
+		WHERE f.student_id = $1
+		ORDER BY f.created_at DESC, tp.user_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	favorites := []entities.FavoriteTutor{}
+	for rows.Next() {
+		var favorite entities.FavoriteTutor
+		if err := scanTutorListRow(rows, &favorite.Tutor, &favorite.AddedAt); err != nil {
+			return nil, err
+		}
+		favorites = append(favorites, favorite)
+	}
+
+	return favorites, rows.Err()
+}
+
+// GetFavoriteAvailabilityChanges retrieves the favorite tutors who added or changed upcoming
+// availability since their students were last told about it. CheckedAt is the time of the
+// latest change, to be passed to MarkFavoriteAvailabilityChecked.
+func (r *FavoriteRepository) GetFavoriteAvailabilityChanges(ctx context.Context) ([]entities.FavoriteAvailability, error) {
+	query := `
+		SELECT f.student_id, f.tutor_id, MAX(a.updated_at)
+		FROM favorite_tutors f
+		JOIN tutor_availability a ON a.tutor_id = f.tutor_id
+		WHERE a.updated_at > f.availability_checked_at
+		  AND (a.is_recurring OR a.specific_date >= CURRENT_DATE)
+		GROUP BY f.student_id, f.tutor_id
+		ORDER BY f.student_id, f.tutor_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []entities.FavoriteAvailability{}
+	for rows.Next() {
+		var change entities.FavoriteAvailability
+		if err := rows.Scan(&change.StudentID, &change.TutorID, &change.CheckedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
+
+// MarkFavoriteAvailabilityChecked records that a student was told about a favorite tutor's
+// availability as it was at checkedAt
+func (r *FavoriteRepository) MarkFavoriteAvailabilityChecked(ctx context.Context, studentID, tutorID int, checkedAt time.Time) error {
+	query := `
+		UPDATE favorite_tutors
+		SET availability_checked_at = GREATEST(availability_checked_at, $3)
+		WHERE student_id = $1 AND tutor_id = $2
+	`
+	_, err := r.db.ExecContext(ctx, query, studentID, tutorID, checkedAt)
+	return err
+}
+
+// CreateSavedSearch inserts a saved search and records the tutors it matches now, failing with
+// ErrTooManySavedSearches when the student has too many
+func (r *FavoriteRepository) CreateSavedSearch(ctx context.Context, search *entities.SavedSearch) error {
+	filtersJSON, err := json.Marshal(search.Filters)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Serialize changes to the student's saved searches
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, search.StudentID); err != nil {
+		return err
+	}
+
+	var count int
+	err = tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM saved_searches WHERE student_id = $1`, search.StudentID).Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count >= entities.MaxSavedSearchesPerStudent {
+		return entities.ErrTooManySavedSearches
+	}
+
+	query := `
+		INSERT INTO saved_searches (student_id, name, filters, notify)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at
+	`
+
+	err = tx.QueryRowContext(ctx, query, search.StudentID, search.Name, filtersJSON, search.Notify).
+		Scan(&search.ID, &search.CreatedAt, &search.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := recordSavedSearchMatches(ctx, tx, search); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateSavedSearch updates a student's saved search. The tutors matching its filters now are
+// recorded again, so that only tutors matching later are announced.
+func (r *FavoriteRepository) UpdateSavedSearch(ctx context.Context, search *entities.SavedSearch) error {
+	filtersJSON, err := json.Marshal(search.Filters)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE saved_searches
+		SET name = $1, filters = $2, notify = $3
+		WHERE id = $4 AND student_id = $5
+		RETURNING created_at, updated_at
+	`
+
+	err = tx.QueryRowContext(ctx, query, search.Name, filtersJSON, search.Notify, search.ID, search.StudentID).
+		Scan(&search.CreatedAt, &search.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return entities.ErrSavedSearchNotFound
+		}
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM saved_search_matches WHERE saved_search_id = $1`, search.ID); err != nil {
+		return err
+	}
+	if err := recordSavedSearchMatches(ctx, tx, search); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteSavedSearch deletes a student's saved search
+func (r *FavoriteRepository) DeleteSavedSearch(ctx context.Context, searchID, studentID int) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM saved_searches WHERE id = $1 AND student_id = $2`, searchID, studentID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return entities.ErrSavedSearchNotFound
+	}
+
+	return nil
+}
+
+// GetSavedSearches retrieves a student's saved searches, the oldest first
+func (r *FavoriteRepository) GetSavedSearches(ctx context.Context, studentID int) ([]entities.SavedSearch, error) {
+	query := `SELECT ` + savedSearchColumns + ` FROM saved_searches WHERE student_id = $1 ORDER BY created_at, id`
+
+	return r.getSavedSearchesByQuery(ctx, query, studentID)
+}
+
+// GetAlertingSavedSearches retrieves the saved searches whose students want to hear about new matches
+func (r *FavoriteRepository) GetAlertingSavedSearches(ctx context.Context) ([]entities.SavedSearch, error) {
+	query := `SELECT ` + savedSearchColumns + ` FROM saved_searches WHERE notify ORDER BY id`
+
+	return r.getSavedSearchesByQuery(ctx, query)
+}
+
+// GetNewSavedSearchMatches retrieves the tutors matching a saved search that are not recorded
+// as its matches yet, the newest first
+func (r *FavoriteRepository) GetNewSavedSearchMatches(ctx context.Context, search *entities.SavedSearch) ([]int, error) {
+	fromClause, args := newSavedSearchMatchesFrom(search)
+	query := `SELECT tp.user_id` + fromClause + ` ORDER BY tp.created_at DESC, tp.user_id`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tutorIDs := []int{}
+	for rows.Next() {
+		var tutorID int
+		if err := rows.Scan(&tutorID); err != nil {
+			return nil, err
+		}
+		tutorIDs = append(tutorIDs, tutorID)
+	}
+
+	return tutorIDs, rows.Err()
+}
+
+// AddSavedSearchMatches records tutors as matches of a saved search
+func (r *FavoriteRepository) AddSavedSearchMatches(ctx context.Context, searchID int, tutorIDs []int) error {
+	query := `
+		INSERT INTO saved_search_matches (saved_search_id, tutor_id)
+		SELECT $1, UNNEST($2::int[])
+		ON CONFLICT DO NOTHING
+	`
+	_, err := r.db.ExecContext(ctx, query, searchID, pq.Array(tutorIDs))
+	return err
+}
+
+// getSavedSearchesByQuery retrieves saved searches selected with savedSearchColumns
+func (r *FavoriteRepository) getSavedSearchesByQuery(ctx context.Context, query string, args ...interface{}) ([]entities.SavedSearch, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	searches := []entities.SavedSearch{}
+	for rows.Next() {
+		search, err := scanSavedSearch(rows)
+		if err != nil {
+			return nil, err
+		}
+		searches = append(searches, *search)
+	}
+
+	return searches, rows.Err()
+}
+
+// scanSavedSearch scans a row selected with savedSearchColumns
+func scanSavedSearch(row rowScanner) (*entities.SavedSearch, error) {
+	var search entities.SavedSearch
+	var filtersJSON []byte
+	err := row.Scan(
+		&search.ID,
+		&search.StudentID,
+		&search.Name,
+		&filtersJSON,
+		&search.Notify,
+		&search.CreatedAt,
+		&search.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(filtersJSON, &search.Filters); err != nil {
+		return nil, err
+	}
+
+	return &search, nil
+}
+
+// recordSavedSearchMatches records the tutors matching a saved search now as its matches
+func recordSavedSearchMatches(ctx context.Context, tx *sql.Tx, search *entities.SavedSearch) error {
+	fromClause, args := newSavedSearchMatchesFrom(search)
+	query := fmt.Sprintf(`INSERT INTO saved_search_matches (saved_search_id, tutor_id) SELECT $%d::int, tp.user_id`, len(args)) +
+		fromClause + ` ON CONFLICT DO NOTHING`
+
+	_, err := tx.ExecContext(ctx, query, args...)
+	return err
+}
+
+// newSavedSearchMatchesFrom builds the FROM and WHERE clauses selecting the tutors that match
+// the filters of a saved search but are not recorded as its matches yet. The ID of the search
+// is the last argument.
+func newSavedSearchMatchesFrom(search *entities.SavedSearch) (string, []interface{}) {
+	whereClause, args := tutorSearchWhere(&search.Filters, noFacet)
+	args = append(args, search.ID)
+	condition := fmt.Sprintf("tp.user_id NOT IN (SELECT tutor_id FROM saved_search_matches WHERE saved_search_id = $%d)", len(args))
+
+	if whereClause == "" {
+		return tutorSearchFrom + " WHERE " + condition, args
+	}
+	return tutorSearchFrom + whereClause + " AND " + condition, args
+}
@@ -147,6 +147,51 @@ func (r *GameRepository) UpdateStudentStreak(ctx context.Context, userID int) er
 	return tx.Commit()
 }
 
+// GetStreaksAtRisk retrieves the students with a streak who played yesterday but not yet today
+// and have not been reminded about it today
+func (r *GameRepository) GetStreaksAtRisk(ctx context.Context) ([]entities.StreakAtRisk, error) {
+	query := `
+		SELECT sp.user_id, sp.current_streak
+		FROM student_profiles sp
+		WHERE sp.current_streak > 0
+		AND EXISTS (
+			SELECT 1 FROM game_results gr
+			WHERE gr.user_id = sp.user_id
+			AND gr.completed_at >= CURRENT_DATE - INTERVAL '1 day'
+			AND gr.completed_at < CURRENT_DATE
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM game_results gr
+			WHERE gr.user_id = sp.user_id AND gr.completed_at >= CURRENT_DATE
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM notifications n
+			WHERE n.user_id = sp.user_id AND n.type = 'streak_at_risk' AND n.created_at >= CURRENT_DATE
+		)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var streaks []entities.StreakAtRisk
+	for rows.Next() {
+		var streak entities.StreakAtRisk
+		if err := rows.Scan(&streak.UserID, &streak.CurrentStreak); err != nil {
+			return nil, err
+		}
+		streaks = append(streaks, streak)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return streaks, nil
+}
+
 // GetLeaderboard retrieves the top players by total score
 func (r *GameRepository) GetLeaderboard(ctx context.Context, limit int) ([]entities.LeaderboardEntry, error) {
 	query := `
@@ -0,0 +1,296 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"tongly-backend/internal/entities"
+
+	"github.com/lib/pq"
+)
+
+// groupClassSelect selects published group lessons in the order expected by scanGroupClass
+const groupClassSelect = `
+	SELECT l.id, l.tutor_id, l.language_id, l.topic, COALESCE(l.description, ''),
+	       l.min_proficiency_id, l.max_proficiency_id, minp.name, maxp.name,
+	       l.start_time, l.end_time, l.enrollment_deadline, l.capacity, l.min_participants,
+	       (SELECT COUNT(*) FROM lesson_participants p WHERE p.lesson_id = l.id AND p.cancelled_at IS NULL),
+	       l.price, l.created_at,
+	       t.username, t.first_name, t.last_name, t.profile_picture_url, lang.name
+	FROM lessons l
+	JOIN users t ON l.tutor_id = t.id
+	JOIN languages lang ON l.language_id = lang.id
+	JOIN language_proficiency minp ON l.min_proficiency_id = minp.id
+	JOIN language_proficiency maxp ON l.max_proficiency_id = maxp.id
+`
+
+// GroupClassRepository handles database operations for the public group class catalog
+type GroupClassRepository struct {
+	db *sql.DB
+}
+
+// NewGroupClassRepository creates a new GroupClassRepository
+func NewGroupClassRepository(db *sql.DB) *GroupClassRepository {
+	return &GroupClassRepository{
+		db: db,
+	}
+}
+
+// Create inserts a group lesson published in the catalog together with its interests
+func (r *GroupClassRepository) Create(ctx context.Context, lesson *entities.Lesson, req *entities.GroupClassRequest) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Serialize bookings of the same tutor
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, lesson.TutorID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO lessons
+		(tutor_id, language_id, start_time, end_time, notes, lesson_type, price,
+		 capacity, min_participants, enrollment_deadline,
+		 is_published, topic, description, min_proficiency_id, max_proficiency_id)
+		VALUES ($1, $2, $3, $4, $5, 'group', $6, $7, $8, $9, TRUE, $10, $11, $12, $13)
+		RETURNING id, created_at, updated_at
+	`
+
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		lesson.TutorID,
+		lesson.LanguageID,
+		lesson.StartTime,
+		lesson.EndTime,
+		lesson.Notes,
+		lesson.Price,
+		lesson.Capacity,
+		lesson.MinParticipants,
+		lesson.EnrollmentDeadline,
+		req.Topic,
+		req.Description,
+		req.MinProficiencyID,
+		req.MaxProficiencyID,
+	).Scan(&lesson.ID, &lesson.CreatedAt, &lesson.UpdatedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && (pqErr.Constraint == "lessons_min_proficiency_id_fkey" || pqErr.Constraint == "lessons_max_proficiency_id_fkey") {
+			return entities.ErrUnknownProficiency
+		}
+		return err
+	}
+
+	if len(req.InterestIDs) > 0 {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO lesson_interests (lesson_id, interest_id)
+			SELECT $1, UNNEST($2::int[])
+			ON CONFLICT DO NOTHING
+		`, lesson.ID, pq.Array(req.InterestIDs))
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Constraint == "lesson_interests_interest_id_fkey" {
+				return entities.ErrUnknownInterest
+			}
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetByID retrieves a published group class by its lesson ID
+func (r *GroupClassRepository) GetByID(ctx context.Context, lessonID int) (*entities.GroupClass, error) {
+	query := groupClassSelect + ` WHERE l.id = $1 AND l.is_published`
+
+	class, err := scanGroupClass(r.db.QueryRowContext(ctx, query, lessonID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrGroupClassNotFound
+		}
+		return nil, err
+	}
+
+	classes := []entities.GroupClass{*class}
+	if err := r.loadInterests(ctx, classes); err != nil {
+		return nil, err
+	}
+
+	return &classes[0], nil
+}
+
+// Search retrieves upcoming published group classes that match the filters, soonest first
+func (r *GroupClassRepository) Search(ctx context.Context, filters *entities.GroupClassFilters) ([]entities.GroupClass, error) {
+	conditions := []string{"l.is_published", "l.cancelled_at IS NULL", "l.start_time > NOW()"}
+	var args []interface{}
+	var argCounter int
+
+	if filters != nil {
+		// Filter by languages
+		if len(filters.Languages) > 0 {
+			argCounter++
+			conditions = append(conditions, fmt.Sprintf("lang.name = ANY($%d)", argCounter))
+			args = append(args, pq.Array(filters.Languages))
+		}
+
+		// Filter by classes suitable for a proficiency level
+		if filters.ProficiencyID > 0 {
+			argCounter++
+			conditions = append(conditions, fmt.Sprintf("$%d BETWEEN l.min_proficiency_id AND l.max_proficiency_id", argCounter))
+			args = append(args, filters.ProficiencyID)
+		}
+
+		// Filter by interests
+		if len(filters.Interests) > 0 {
+			argCounter++
+			conditions = append(conditions, fmt.Sprintf("l.id IN (SELECT lesson_id FROM lesson_interests WHERE interest_id = ANY($%d))", argCounter))
+			args = append(args, pq.Array(filters.Interests))
+		}
+
+		// Filter by time window
+		if filters.From != nil {
+			argCounter++
+			conditions = append(conditions, fmt.Sprintf("l.start_time >= $%d", argCounter))
+			args = append(args, *filters.From)
+		}
+		if filters.To != nil {
+			argCounter++
+			conditions = append(conditions, fmt.Sprintf("l.start_time < $%d", argCounter))
+			args = append(args, *filters.To)
+		}
+
+		// Filter by tutor
+		if filters.TutorID > 0 {
+			argCounter++
+			conditions = append(conditions, fmt.Sprintf("l.tutor_id = $%d", argCounter))
+			args = append(args, filters.TutorID)
+		}
+	}
+
+	query := groupClassSelect + " WHERE " + strings.Join(conditions, " AND ") + " ORDER BY l.start_time ASC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	classes := []entities.GroupClass{}
+	for rows.Next() {
+		class, err := scanGroupClass(rows)
+		if err != nil {
+			return nil, err
+		}
+		classes = append(classes, *class)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadInterests(ctx, classes); err != nil {
+		return nil, err
+	}
+
+	return classes, nil
+}
+
+// loadInterests loads the interests of all classes with a single query
+func (r *GroupClassRepository) loadInterests(ctx context.Context, classes []entities.GroupClass) error {
+	if len(classes) == 0 {
+		return nil
+	}
+
+	ids := make([]int, len(classes))
+	index := make(map[int]int, len(classes))
+	for i := range classes {
+		ids[i] = classes[i].LessonID
+		index[classes[i].LessonID] = i
+		classes[i].Interests = []entities.Interest{}
+	}
+
+	query := `
+		SELECT li.lesson_id, i.id, i.name, i.created_at
+		FROM lesson_interests li
+		JOIN interests i ON li.interest_id = i.id
+		WHERE li.lesson_id = ANY($1)
+		ORDER BY i.name
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var lessonID int
+		var interest entities.Interest
+		if err := rows.Scan(&lessonID, &interest.ID, &interest.Name, &interest.CreatedAt); err != nil {
+			return err
+		}
+		i := index[lessonID]
+		classes[i].Interests = append(classes[i].Interests, interest)
+	}
+
+	return rows.Err()
+}
+
+// scanGroupClass scans a row selected with groupClassSelect
+func scanGroupClass(row rowScanner) (*entities.GroupClass, error) {
+	var class entities.GroupClass
+	var tutor entities.User
+	var language entities.Language
+	var minProficiency, maxProficiency entities.LanguageProficiency
+
+	err := row.Scan(
+		&class.LessonID,
+		&class.TutorID,
+		&class.LanguageID,
+		&class.Topic,
+		&class.Description,
+		&class.MinProficiencyID,
+		&class.MaxProficiencyID,
+		&minProficiency.Name,
+		&maxProficiency.Name,
+		&class.StartTime,
+		&class.EndTime,
+		&class.EnrollmentDeadline,
+		&class.Capacity,
+		&class.MinParticipants,
+		&class.SeatsTaken,
+		&class.Price,
+		&class.CreatedAt,
+		&tutor.Username,
+		&tutor.FirstName,
+		&tutor.LastName,
+		&tutor.ProfilePictureURL,
+		&language.Name,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	class.SeatsLeft = class.Capacity - class.SeatsTaken
+	if class.SeatsLeft < 0 {
+		class.SeatsLeft = 0
+	}
+	class.LevelRange = entities.FormatLevelRange(minProficiency.Name, maxProficiency.Name)
+
+	tutor.ID = class.TutorID
+	tutor.Role = "tutor"
+	language.ID = class.LanguageID
+	minProficiency.ID = class.MinProficiencyID
+	maxProficiency.ID = class.MaxProficiencyID
+
+	class.Tutor = &tutor
+	class.Language = &language
+	class.MinProficiency = &minProficiency
+	class.MaxProficiency = &maxProficiency
+
+	return &class, nil
+}
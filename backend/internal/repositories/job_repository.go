@@ -0,0 +1,334 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"tongly-backend/internal/entities"
+
+	"github.com/lib/pq"
+)
+
+// jobColumns lists the job columns in the order expected by scanJob
+const jobColumns = `id, job_type, payload, status, dedup_key, attempts, max_attempts, run_at,
+	locked_by, locked_until, last_error, completed_at, created_at, updated_at`
+
+// JobRepository handles database operations for background jobs
+type JobRepository struct {
+	db *sql.DB
+}
+
+// NewJobRepository creates a new JobRepository
+func NewJobRepository(db *sql.DB) *JobRepository {
+	return &JobRepository{
+		db: db,
+	}
+}
+
+// Enqueue stores a new job. If a job with the same dedup key exists, nothing is stored and
+// created is false.
+func (r *JobRepository) Enqueue(ctx context.Context, req *entities.JobRequest) (job *entities.Job, created bool, err error) {
+	payload, err := json.Marshal(req.Payload)
+	if err != nil {
+		return nil, false, err
+	}
+
+	maxAttempts := req.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = entities.DefaultJobMaxAttempts
+	}
+
+	runAt := req.RunAt
+	if runAt.IsZero() {
+		runAt = time.Now()
+	}
+
+	var dedupKey *string
+	if req.DedupKey != "" {
+		dedupKey = &req.DedupKey
+	}
+
+	query := `
+		INSERT INTO jobs (job_type, payload, dedup_key, max_attempts, run_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (dedup_key) DO NOTHING
+		RETURNING ` + jobColumns
+
+	job, err = scanJob(r.db.QueryRowContext(ctx, query, req.Type, string(payload), dedupKey, maxAttempts, runAt))
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return job, true, nil
+}
+
+// Lease claims up to limit due jobs of the given types for the worker until the lease expires.
+// Jobs whose lease expired without completion (e.g. the worker crashed) are claimed again.
+// SKIP LOCKED lets concurrent workers claim disjoint sets of jobs. A worker that runs the jobs
+// one after another must renew each lease with RenewLease before running the job.
+func (r *JobRepository) Lease(ctx context.Context, workerID string, types []entities.JobType, limit int, lease time.Duration) ([]entities.Job, error) {
+	typeNames := make([]string, len(types))
+	for i, t := range types {
+		typeNames[i] = string(t)
+	}
+
+	query := `
+		UPDATE jobs SET
+			status = 'running',
+			locked_by = $1,
+			locked_until = NOW() + $2 * INTERVAL '1 second',
+			attempts = attempts + 1
+		WHERE id IN (
+			SELECT id FROM jobs
+			WHERE job_type = ANY($3)
+			  AND ((status = 'pending' AND run_at <= NOW())
+			    OR (status = 'running' AND locked_until < NOW() AND attempts < max_attempts))
+			ORDER BY run_at
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + jobColumns
+
+	rows, err := r.db.QueryContext(ctx, query, workerID, int(lease.Seconds()), pq.Array(typeNames), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []entities.Job{}
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, *job)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// FailExpiredLeases marks running jobs whose lease expired after their last attempt as failed
+func (r *JobRepository) FailExpiredLeases(ctx context.Context) (int, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE jobs SET
+			status = 'failed',
+			locked_by = NULL,
+			locked_until = NULL,
+			last_error = COALESCE(last_error, 'lease expired')
+		WHERE status = 'running' AND locked_until < NOW() AND attempts >= max_attempts
+	`)
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := result.RowsAffected()
+	return int(affected), err
+}
+
+// RenewLease extends the worker's lease of a job, or returns ErrJobLeaseLost if the job has
+// since been leased by another worker or is no longer running
+func (r *JobRepository) RenewLease(ctx context.Context, jobID int64, workerID string, lease time.Duration) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE jobs SET locked_until = NOW() + $3 * INTERVAL '1 second'
+		WHERE id = $1 AND locked_by = $2 AND status = 'running'
+	`, jobID, workerID, int(lease.Seconds()))
+	if err != nil {
+		return err
+	}
+	return checkLeaseHeld(result)
+}
+
+// Complete marks a job leased by the worker as succeeded, or returns ErrJobLeaseLost if the
+// worker no longer holds the lease
+func (r *JobRepository) Complete(ctx context.Context, jobID int64, workerID string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE jobs SET
+			status = 'succeeded',
+			locked_by = NULL,
+			locked_until = NULL,
+			completed_at = NOW()
+		WHERE id = $1 AND locked_by = $2 AND status = 'running'
+	`, jobID, workerID)
+	if err != nil {
+		return err
+	}
+	return checkLeaseHeld(result)
+}
+
+// Fail records a failed attempt of a job leased by the worker. The job is retried at retryAt,
+// or marked as failed if it has no attempts left. Returns ErrJobLeaseLost if the worker no
+// longer holds the lease.
+func (r *JobRepository) Fail(ctx context.Context, jobID int64, workerID string, jobErr string, retryAt time.Time) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE jobs SET
+			status = CASE WHEN attempts >= max_attempts THEN 'failed' ELSE 'pending' END,
+			run_at = CASE WHEN attempts >= max_attempts THEN run_at ELSE $4 END,
+			locked_by = NULL,
+			locked_until = NULL,
+			last_error = $3
+		WHERE id = $1 AND locked_by = $2 AND status = 'running'
+	`, jobID, workerID, jobErr, retryAt)
+	if err != nil {
+		return err
+	}
+	return checkLeaseHeld(result)
+}
+
+// DeleteSucceededBefore deletes the jobs that succeeded before the given time and returns how
+// many were deleted. Failed jobs are kept for inspection.
+func (r *JobRepository) DeleteSucceededBefore(ctx context.Context, before time.Time) (int, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM jobs WHERE status = 'succeeded' AND completed_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	deleted, err := result.RowsAffected()
+	return int(deleted), err
+}
+
+// checkLeaseHeld returns ErrJobLeaseLost if an update of a leased job matched no row
+func checkLeaseHeld(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return entities.ErrJobLeaseLost
+	}
+	return nil
+}
+
+// Retry puts a failed job back in the queue with a fresh set of attempts
+func (r *JobRepository) Retry(ctx context.Context, jobID int64) (*entities.Job, error) {
+	query := `
+		UPDATE jobs SET status = 'pending', attempts = 0, run_at = NOW(), completed_at = NULL
+		WHERE id = $1 AND status = 'failed'
+		RETURNING ` + jobColumns
+
+	job, err := scanJob(r.db.QueryRowContext(ctx, query, jobID))
+	if err == sql.ErrNoRows {
+		var exists bool
+		if err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM jobs WHERE id = $1)`, jobID).Scan(&exists); err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, entities.ErrJobNotRetryable
+		}
+		return nil, entities.ErrJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// List retrieves jobs matching the filters, most recently scheduled first
+func (r *JobRepository) List(ctx context.Context, filters *entities.JobFilters) ([]entities.Job, error) {
+	var conditions []string
+	var args []interface{}
+	argCounter := 0
+
+	if filters.Status != "" {
+		argCounter++
+		conditions = append(conditions, fmt.Sprintf("status = $%d", argCounter))
+		args = append(args, filters.Status)
+	}
+	if filters.Type != "" {
+		argCounter++
+		conditions = append(conditions, fmt.Sprintf("job_type = $%d", argCounter))
+		args = append(args, filters.Type)
+	}
+
+	query := `SELECT ` + jobColumns + ` FROM jobs`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += fmt.Sprintf(" ORDER BY run_at DESC, id DESC LIMIT $%d OFFSET $%d", argCounter+1, argCounter+2)
+	args = append(args, filters.Limit, filters.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []entities.Job{}
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, *job)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// CountByStatus counts the jobs in every status
+func (r *JobRepository) CountByStatus(ctx context.Context) (map[entities.JobStatus]int, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT status, COUNT(*) FROM jobs GROUP BY status`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[entities.JobStatus]int{
+		entities.JobStatusPending:   0,
+		entities.JobStatusRunning:   0,
+		entities.JobStatusSucceeded: 0,
+		entities.JobStatusFailed:    0,
+	}
+	for rows.Next() {
+		var status entities.JobStatus
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[status] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// scanJob scans a row selected with jobColumns
+func scanJob(row rowScanner) (*entities.Job, error) {
+	var job entities.Job
+	var payload []byte
+
+	err := row.Scan(
+		&job.ID,
+		&job.Type,
+		&payload,
+		&job.Status,
+		&job.DedupKey,
+		&job.Attempts,
+		&job.MaxAttempts,
+		&job.RunAt,
+		&job.LockedBy,
+		&job.LockedUntil,
+		&job.LastError,
+		&job.CompletedAt,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	job.Payload = payload
+
+	return &job, nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"tongly-backend/internal/entities"
+
+	"github.com/lib/pq"
 )
 
 // LanguageRepository handles database operations for languages
@@ -113,6 +115,36 @@ func (r *LanguageRepository) GetLanguageByID(ctx context.Context, id int) (*enti
 	return &language, nil
 }
 
+// GetLanguagesByIDs retrieves the languages with the given IDs in a single query, keyed by ID
+func (r *LanguageRepository) GetLanguagesByIDs(ctx context.Context, ids []int) (map[int]*entities.Language, error) {
+	languages := make(map[int]*entities.Language, len(ids))
+	if len(ids) == 0 {
+		return languages, nil
+	}
+
+	query := `
+		SELECT id, name, created_at
+		FROM languages
+		WHERE id = ANY($1)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var language entities.Language
+		if err := rows.Scan(&language.ID, &language.Name, &language.CreatedAt); err != nil {
+			return nil, err
+		}
+		languages[language.ID] = &language
+	}
+
+	return languages, rows.Err()
+}
+
 // GetProficiencyByID retrieves a proficiency level by ID
 func (r *LanguageRepository) GetProficiencyByID(ctx context.Context, id int) (*entities.LanguageProficiency, error) {
 	query := `
@@ -0,0 +1,566 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"tongly-backend/internal/entities"
+
+	"github.com/lib/pq"
+)
+
+// lessonNotesColumns lists the lesson notes columns in the order expected by scanLessonNotes
+const lessonNotesColumns = `n.id, n.lesson_id, n.tutor_id, n.summary, n.topics, n.vocabulary, n.corrections, n.created_at, n.updated_at`
+
+// homeworkColumns lists the homework columns in the order expected by scanHomework
+const homeworkColumns = `h.id, h.lesson_id, h.tutor_id, h.description, h.due_at, h.created_at, h.updated_at`
+
+// studentFeedbackColumns lists the student feedback columns in the order expected by scanStudentFeedback
+const studentFeedbackColumns = `f.id, f.lesson_id, f.tutor_id, f.student_id, f.rating, f.comment, f.created_at, f.updated_at`
+
+// LessonNotesRepository handles database operations for lesson notes, homework and student feedback
+type LessonNotesRepository struct {
+	db *sql.DB
+}
+
+// NewLessonNotesRepository creates a new LessonNotesRepository
+func NewLessonNotesRepository(db *sql.DB) *LessonNotesRepository {
+	return &LessonNotesRepository{
+		db: db,
+	}
+}
+
+// GetNotes retrieves the shared notes of a lesson
+func (r *LessonNotesRepository) GetNotes(ctx context.Context, lessonID int) (*entities.LessonNotes, error) {
+	query := `SELECT ` + lessonNotesColumns + ` FROM lesson_notes n WHERE n.lesson_id = $1`
+
+	notes, err := scanLessonNotes(r.db.QueryRowContext(ctx, query, lessonID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrLessonNotesNotFound
+		}
+		return nil, err
+	}
+
+	return notes, nil
+}
+
+// GetPrivateNotes retrieves the tutor's private notes of a lesson, or nil if there are none
+func (r *LessonNotesRepository) GetPrivateNotes(ctx context.Context, lessonID int) (*string, error) {
+	var body string
+	err := r.db.QueryRowContext(ctx, `SELECT body FROM lesson_private_notes WHERE lesson_id = $1`, lessonID).Scan(&body)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &body, nil
+}
+
+// SaveNotes creates or replaces the shared notes of a lesson. The private notes are replaced
+// when privateNotes is not nil, and removed when it is empty.
+func (r *LessonNotesRepository) SaveNotes(ctx context.Context, notes *entities.LessonNotes, privateNotes *string) error {
+	topics, err := json.Marshal(notes.Topics)
+	if err != nil {
+		return err
+	}
+	vocabulary, err := json.Marshal(notes.Vocabulary)
+	if err != nil {
+		return err
+	}
+	corrections, err := json.Marshal(notes.Corrections)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO lesson_notes (lesson_id, tutor_id, summary, topics, vocabulary, corrections)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (lesson_id) DO UPDATE
+		SET summary = EXCLUDED.summary, topics = EXCLUDED.topics,
+			vocabulary = EXCLUDED.vocabulary, corrections = EXCLUDED.corrections
+		RETURNING id, created_at, updated_at
+	`
+
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		notes.LessonID,
+		notes.TutorID,
+		notes.Summary,
+		string(topics),
+		string(vocabulary),
+		string(corrections),
+	).Scan(&notes.ID, &notes.CreatedAt, &notes.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case privateNotes == nil:
+	case *privateNotes == "":
+		if _, err := tx.ExecContext(ctx, `DELETE FROM lesson_private_notes WHERE lesson_id = $1`, notes.LessonID); err != nil {
+			return err
+		}
+	default:
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO lesson_private_notes (lesson_id, tutor_id, body)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (lesson_id) DO UPDATE SET body = EXCLUDED.body, updated_at = NOW()
+		`, notes.LessonID, notes.TutorID, *privateNotes)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ListNotes retrieves the shared notes of the given lessons by lesson ID, with the private
+// notes if withPrivate is set
+func (r *LessonNotesRepository) ListNotes(ctx context.Context, lessonIDs []int, withPrivate bool) (map[int]*entities.LessonNotes, error) {
+	query := `
+		SELECT ` + lessonNotesColumns + `, CASE WHEN $2 THEN p.body END
+		FROM lesson_notes n
+		LEFT JOIN lesson_private_notes p ON p.lesson_id = n.lesson_id
+		WHERE n.lesson_id = ANY($1)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(lessonIDs), withPrivate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notesByLesson := make(map[int]*entities.LessonNotes)
+	for rows.Next() {
+		var notes entities.LessonNotes
+		var topics, vocabulary, corrections []byte
+		err := rows.Scan(
+			&notes.ID, &notes.LessonID, &notes.TutorID, &notes.Summary,
+			&topics, &vocabulary, &corrections, &notes.CreatedAt, &notes.UpdatedAt,
+			&notes.PrivateNotes,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if err := unmarshalLessonNotes(&notes, topics, vocabulary, corrections); err != nil {
+			return nil, err
+		}
+		notesByLesson[notes.LessonID] = &notes
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return notesByLesson, nil
+}
+
+// CreateHomework assigns homework in a lesson, unless the lesson already has limit assignments
+func (r *LessonNotesRepository) CreateHomework(ctx context.Context, homework *entities.Homework, limit int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Lock the lesson so that concurrent requests are counted against the limit
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM lessons WHERE id = $1 FOR UPDATE`, homework.LessonID); err != nil {
+		return err
+	}
+
+	var count int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM homework WHERE lesson_id = $1`, homework.LessonID).Scan(&count); err != nil {
+		return err
+	}
+	if count >= limit {
+		return entities.ErrTooMuchHomework
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO homework (lesson_id, tutor_id, description, due_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at
+	`, homework.LessonID, homework.TutorID, homework.Description, homework.DueAt).Scan(
+		&homework.ID, &homework.CreatedAt, &homework.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetHomework retrieves a homework assignment with its completions
+func (r *LessonNotesRepository) GetHomework(ctx context.Context, homeworkID int) (*entities.Homework, error) {
+	query := `SELECT ` + homeworkColumns + ` FROM homework h WHERE h.id = $1`
+
+	homework, err := scanHomework(r.db.QueryRowContext(ctx, query, homeworkID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrHomeworkNotFound
+		}
+		return nil, err
+	}
+
+	completions, err := r.getCompletions(ctx, []int{homework.ID})
+	if err != nil {
+		return nil, err
+	}
+	homework.Completions = completions[homework.ID]
+
+	return homework, nil
+}
+
+// UpdateHomework changes the description and due date of a homework assignment
+func (r *LessonNotesRepository) UpdateHomework(ctx context.Context, homework *entities.Homework) error {
+	query := `
+		UPDATE homework SET description = $1, due_at = $2
+		WHERE id = $3
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query, homework.Description, homework.DueAt, homework.ID).Scan(&homework.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return entities.ErrHomeworkNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// DeleteHomework removes a homework assignment
+func (r *LessonNotesRepository) DeleteHomework(ctx context.Context, homeworkID int) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM homework WHERE id = $1`, homeworkID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return entities.ErrHomeworkNotFound
+	}
+	return nil
+}
+
+// ListHomework retrieves the homework of the given lessons with its completions, by lesson ID
+func (r *LessonNotesRepository) ListHomework(ctx context.Context, lessonIDs []int) (map[int][]entities.Homework, error) {
+	query := `
+		SELECT ` + homeworkColumns + `
+		FROM homework h
+		WHERE h.lesson_id = ANY($1)
+		ORDER BY h.created_at, h.id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(lessonIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	homework := []entities.Homework{}
+	homeworkIDs := []int{}
+	for rows.Next() {
+		assignment, err := scanHomework(rows)
+		if err != nil {
+			return nil, err
+		}
+		homework = append(homework, *assignment)
+		homeworkIDs = append(homeworkIDs, assignment.ID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	completions, err := r.getCompletions(ctx, homeworkIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	homeworkByLesson := make(map[int][]entities.Homework)
+	for _, assignment := range homework {
+		assignment.Completions = completions[assignment.ID]
+		homeworkByLesson[assignment.LessonID] = append(homeworkByLesson[assignment.LessonID], assignment)
+	}
+
+	return homeworkByLesson, nil
+}
+
+// SetHomeworkCompleted marks homework as done or not done for a student
+func (r *LessonNotesRepository) SetHomeworkCompleted(ctx context.Context, homeworkID, studentID int, done bool) error {
+	if !done {
+		_, err := r.db.ExecContext(ctx, `
+			DELETE FROM homework_completions WHERE homework_id = $1 AND student_id = $2
+		`, homeworkID, studentID)
+		return err
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO homework_completions (homework_id, student_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, homeworkID, studentID)
+	return err
+}
+
+// SaveFeedback creates or replaces the tutor's feedback to a student of a lesson
+func (r *LessonNotesRepository) SaveFeedback(ctx context.Context, feedback *entities.StudentFeedback) error {
+	query := `
+		INSERT INTO student_feedback (lesson_id, tutor_id, student_id, rating, comment)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (lesson_id, student_id)
+		DO UPDATE SET rating = EXCLUDED.rating, comment = EXCLUDED.comment
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.db.QueryRowContext(ctx, query,
+		feedback.LessonID, feedback.TutorID, feedback.StudentID, feedback.Rating, feedback.Comment,
+	).Scan(&feedback.ID, &feedback.CreatedAt, &feedback.UpdatedAt)
+}
+
+// ListLessonFeedback retrieves the feedback given to the students of a lesson
+func (r *LessonNotesRepository) ListLessonFeedback(ctx context.Context, lessonID int) ([]entities.StudentFeedback, error) {
+	query := `SELECT ` + studentFeedbackColumns + ` FROM student_feedback f WHERE f.lesson_id = $1 ORDER BY f.student_id`
+
+	rows, err := r.db.QueryContext(ctx, query, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	feedback := []entities.StudentFeedback{}
+	for rows.Next() {
+		item, err := scanStudentFeedback(rows)
+		if err != nil {
+			return nil, err
+		}
+		feedback = append(feedback, *item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return feedback, nil
+}
+
+// ListStudentFeedback retrieves the feedback a student was given in the given lessons by lesson ID
+func (r *LessonNotesRepository) ListStudentFeedback(ctx context.Context, lessonIDs []int, studentID int) (map[int]*entities.StudentFeedback, error) {
+	query := `SELECT ` + studentFeedbackColumns + ` FROM student_feedback f WHERE f.lesson_id = ANY($1) AND f.student_id = $2`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(lessonIDs), studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	feedbackByLesson := make(map[int]*entities.StudentFeedback)
+	for rows.Next() {
+		feedback, err := scanStudentFeedback(rows)
+		if err != nil {
+			return nil, err
+		}
+		feedbackByLesson[feedback.LessonID] = feedback
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return feedbackByLesson, nil
+}
+
+// GetHistoryLessons retrieves the lessons a student had with a tutor, one-on-one or in a group,
+// newest first. Cancelled lessons and seats are left out.
+func (r *LessonNotesRepository) GetHistoryLessons(ctx context.Context, tutorID, studentID int) ([]entities.LessonHistoryEntry, error) {
+	query := `
+		SELECT l.id, l.start_time, l.end_time, l.lesson_type, lang.name
+		FROM lessons l
+		JOIN languages lang ON l.language_id = lang.id
+		WHERE l.tutor_id = $1 AND l.cancelled_at IS NULL
+		  AND (l.student_id = $2 OR EXISTS (
+			SELECT 1 FROM lesson_participants p
+			WHERE p.lesson_id = l.id AND p.student_id = $2 AND p.cancelled_at IS NULL))
+		ORDER BY l.start_time DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, tutorID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []entities.LessonHistoryEntry{}
+	for rows.Next() {
+		entry := entities.LessonHistoryEntry{Homework: []entities.Homework{}}
+		if err := rows.Scan(&entry.LessonID, &entry.StartTime, &entry.EndTime, &entry.Type, &entry.Language); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// ListStudentHomework retrieves the homework of all lessons a student attended, the ones
+// without a due date last. With openOnly, homework the student has done is left out.
+func (r *LessonNotesRepository) ListStudentHomework(ctx context.Context, studentID int, openOnly bool) ([]entities.StudentHomework, error) {
+	query := `
+		SELECT ` + homeworkColumns + `, c.completed_at, l.start_time, lang.name,
+			t.id, t.username, t.first_name, t.last_name, t.profile_picture_url
+		FROM homework h
+		JOIN lessons l ON h.lesson_id = l.id
+		JOIN languages lang ON l.language_id = lang.id
+		JOIN users t ON h.tutor_id = t.id
+		LEFT JOIN homework_completions c ON c.homework_id = h.id AND c.student_id = $1
+		WHERE l.cancelled_at IS NULL
+		  AND (l.student_id = $1 OR EXISTS (
+			SELECT 1 FROM lesson_participants p
+			WHERE p.lesson_id = l.id AND p.student_id = $1 AND p.cancelled_at IS NULL))
+		  AND (NOT $2 OR c.completed_at IS NULL)
+		ORDER BY h.due_at NULLS LAST, h.created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, studentID, openOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	homework := []entities.StudentHomework{}
+	for rows.Next() {
+		var assignment entities.StudentHomework
+		var tutor entities.User
+		err := rows.Scan(
+			&assignment.ID, &assignment.LessonID, &assignment.TutorID, &assignment.Description,
+			&assignment.DueAt, &assignment.CreatedAt, &assignment.UpdatedAt,
+			&assignment.CompletedAt, &assignment.LessonStartTime, &assignment.Language,
+			&tutor.ID, &tutor.Username, &tutor.FirstName, &tutor.LastName, &tutor.ProfilePictureURL,
+		)
+		if err != nil {
+			return nil, err
+		}
+		assignment.Tutor = &tutor
+		homework = append(homework, assignment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return homework, nil
+}
+
+// getCompletions retrieves the completions of the given homework by homework ID
+func (r *LessonNotesRepository) getCompletions(ctx context.Context, homeworkIDs []int) (map[int][]entities.HomeworkCompletion, error) {
+	completions := make(map[int][]entities.HomeworkCompletion)
+	if len(homeworkIDs) == 0 {
+		return completions, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT homework_id, student_id, completed_at
+		FROM homework_completions
+		WHERE homework_id = ANY($1)
+		ORDER BY completed_at
+	`, pq.Array(homeworkIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var homeworkID int
+		var completion entities.HomeworkCompletion
+		if err := rows.Scan(&homeworkID, &completion.StudentID, &completion.CompletedAt); err != nil {
+			return nil, err
+		}
+		completions[homeworkID] = append(completions[homeworkID], completion)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return completions, nil
+}
+
+// scanLessonNotes reads lesson notes selected with lessonNotesColumns
+func scanLessonNotes(row rowScanner) (*entities.LessonNotes, error) {
+	var notes entities.LessonNotes
+	var topics, vocabulary, corrections []byte
+
+	err := row.Scan(
+		&notes.ID, &notes.LessonID, &notes.TutorID, &notes.Summary,
+		&topics, &vocabulary, &corrections, &notes.CreatedAt, &notes.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := unmarshalLessonNotes(&notes, topics, vocabulary, corrections); err != nil {
+		return nil, err
+	}
+
+	return &notes, nil
+}
+
+// unmarshalLessonNotes decodes the JSONB columns of lesson notes
+func unmarshalLessonNotes(notes *entities.LessonNotes, topics, vocabulary, corrections []byte) error {
+	if err := json.Unmarshal(topics, &notes.Topics); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(vocabulary, &notes.Vocabulary); err != nil {
+		return err
+	}
+	return json.Unmarshal(corrections, &notes.Corrections)
+}
+
+// scanHomework reads homework selected with homeworkColumns
+func scanHomework(row rowScanner) (*entities.Homework, error) {
+	var homework entities.Homework
+
+	err := row.Scan(
+		&homework.ID, &homework.LessonID, &homework.TutorID, &homework.Description,
+		&homework.DueAt, &homework.CreatedAt, &homework.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &homework, nil
+}
+
+// scanStudentFeedback reads student feedback selected with studentFeedbackColumns
+func scanStudentFeedback(row rowScanner) (*entities.StudentFeedback, error) {
+	var feedback entities.StudentFeedback
+
+	err := row.Scan(
+		&feedback.ID, &feedback.LessonID, &feedback.TutorID, &feedback.StudentID,
+		&feedback.Rating, &feedback.Comment, &feedback.CreatedAt, &feedback.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &feedback, nil
+}
@@ -3,10 +3,57 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"time"
 	"tongly-backend/internal/entities"
+
+	"github.com/lib/pq"
 )
 
+// lessonColumns lists the lesson columns in the order expected by scanLesson. It must be
+// selected from the lessons table without an alias. Group lessons have no student_id.
+const lessonColumns = `id, COALESCE(student_id, 0), tutor_id, language_id, start_time, end_time,
+	cancelled_by, cancelled_at, notes, lesson_type, price, cancellation_fee, student_no_show, no_show_fee,
+	capacity, min_participants, enrollment_deadline,
+	(SELECT COUNT(*) FROM lesson_participants p WHERE p.lesson_id = lessons.id AND p.cancelled_at IS NULL),
+	created_at, updated_at`
+
+// studentLessonsCondition matches the lessons of the student given as $1: one-on-one lessons
+// and group lessons in which the student holds a seat
+const studentLessonsCondition = `(student_id = $1 OR id IN (
+	SELECT lesson_id FROM lesson_participants WHERE student_id = $1 AND cancelled_at IS NULL))`
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanLesson scans a row selected with lessonColumns into a lesson
+func scanLesson(row rowScanner, lesson *entities.Lesson) error {
+	return row.Scan(
+		&lesson.ID,
+		&lesson.StudentID,
+		&lesson.TutorID,
+		&lesson.LanguageID,
+		&lesson.StartTime,
+		&lesson.EndTime,
+		&lesson.CancelledBy,
+		&lesson.CancelledAt,
+		&lesson.Notes,
+		&lesson.Type,
+		&lesson.Price,
+		&lesson.CancellationFee,
+		&lesson.StudentNoShow,
+		&lesson.NoShowFee,
+		&lesson.Capacity,
+		&lesson.MinParticipants,
+		&lesson.EnrollmentDeadline,
+		&lesson.ParticipantsCount,
+		&lesson.CreatedAt,
+		&lesson.UpdatedAt,
+	)
+}
+
 // LessonRepository handles database operations for lessons
 type LessonRepository struct {
 	db *sql.DB
@@ -19,16 +66,45 @@ func NewLessonRepository(db *sql.DB) *LessonRepository {
 	}
 }
 
-// Create inserts a new lesson into the database
+// Create inserts a new lesson into the database. Bookings with the same tutor are
+// serialized, so that the one-trial-per-tutor limit holds under concurrent requests.
 func (r *LessonRepository) Create(ctx context.Context, lesson *entities.Lesson) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Serialize bookings of the same tutor
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, lesson.TutorID); err != nil {
+		return err
+	}
+
+	if lesson.Type == entities.LessonTypeTrial {
+		var hasTrial bool
+		err := tx.QueryRowContext(ctx, `
+			SELECT EXISTS (
+				SELECT 1 FROM lessons
+				WHERE student_id = $1 AND tutor_id = $2 AND lesson_type = 'trial' AND cancelled_at IS NULL
+			)
+		`, lesson.StudentID, lesson.TutorID).Scan(&hasTrial)
+		if err != nil {
+			return err
+		}
+		if hasTrial {
+			return entities.ErrTrialAlreadyBooked
+		}
+	}
+
 	query := `
 		INSERT INTO lessons
-		(student_id, tutor_id, language_id, start_time, end_time, notes)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		(student_id, tutor_id, language_id, start_time, end_time, notes, lesson_type, price,
+		 capacity, min_participants, enrollment_deadline)
+		VALUES (NULLIF($1, 0), $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		RETURNING id, created_at, updated_at
 	`
 
-	err := r.db.QueryRowContext(
+	err = tx.QueryRowContext(
 		ctx,
 		query,
 		lesson.StudentID,
@@ -37,26 +113,41 @@ func (r *LessonRepository) Create(ctx context.Context, lesson *entities.Lesson)
 		lesson.StartTime,
 		lesson.EndTime,
 		lesson.Notes,
+		lesson.Type,
+		lesson.Price,
+		lesson.Capacity,
+		lesson.MinParticipants,
+		lesson.EnrollmentDeadline,
 	).Scan(&lesson.ID, &lesson.CreatedAt, &lesson.UpdatedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Constraint == "idx_lessons_one_trial_per_tutor" {
+			return entities.ErrTrialAlreadyBooked
+		}
+		return err
+	}
 
-	return err
+	return tx.Commit()
 }
 
 // GetByID retrieves a lesson by ID
 func (r *LessonRepository) GetByID(ctx context.Context, id int) (*entities.Lesson, error) {
 	query := `
 		SELECT 
-			l.id, l.student_id, l.tutor_id, l.language_id, l.start_time, l.end_time,
-			l.cancelled_by, l.cancelled_at, l.notes, l.created_at, l.updated_at,
-			s.username as student_username, s.email as student_email, 
-			s.first_name as student_first_name, s.last_name as student_last_name,
-			s.profile_picture_url as student_profile_picture_url, s.role as student_role,
+			l.id, COALESCE(l.student_id, 0), l.tutor_id, l.language_id, l.start_time, l.end_time,
+			l.cancelled_by, l.cancelled_at, l.notes, l.lesson_type, l.price, l.cancellation_fee,
+			l.student_no_show, l.no_show_fee, l.capacity, l.min_participants, l.enrollment_deadline,
+			(SELECT COUNT(*) FROM lesson_participants p WHERE p.lesson_id = l.id AND p.cancelled_at IS NULL),
+			l.created_at, l.updated_at,
+			COALESCE(s.username, '') as student_username, COALESCE(s.email, '') as student_email, 
+			COALESCE(s.first_name, '') as student_first_name, COALESCE(s.last_name, '') as student_last_name,
+			s.profile_picture_url as student_profile_picture_url, COALESCE(s.role, '') as student_role,
 			t.username as tutor_username, t.email as tutor_email, 
 			t.first_name as tutor_first_name, t.last_name as tutor_last_name,
 			t.profile_picture_url as tutor_profile_picture_url, t.role as tutor_role,
 			lang.name as language_name
 		FROM lessons l
-		JOIN users s ON l.student_id = s.id
+		LEFT JOIN users s ON l.student_id = s.id
 		JOIN users t ON l.tutor_id = t.id
 		JOIN languages lang ON l.language_id = lang.id
 		WHERE l.id = $1
@@ -75,6 +166,8 @@ func (r *LessonRepository) GetByID(ctx context.Context, id int) (*entities.Lesso
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&lesson.ID, &lesson.StudentID, &lesson.TutorID, &lesson.LanguageID,
 		&lesson.StartTime, &lesson.EndTime, &cancelledBy, &cancelledAt, &notes,
+		&lesson.Type, &lesson.Price, &lesson.CancellationFee, &lesson.StudentNoShow, &lesson.NoShowFee,
+		&lesson.Capacity, &lesson.MinParticipants, &lesson.EnrollmentDeadline, &lesson.ParticipantsCount,
 		&lesson.CreatedAt, &lesson.UpdatedAt,
 		&student.Username, &student.Email, &student.FirstName, &student.LastName,
 		&studentProfilePictureURL, &student.Role,
@@ -115,7 +208,9 @@ func (r *LessonRepository) GetByID(ctx context.Context, id int) (*entities.Lesso
 	tutor.ID = lesson.TutorID
 	language.ID = lesson.LanguageID
 
-	lesson.Student = &student
+	if lesson.StudentID != 0 {
+		lesson.Student = &student
+	}
 	lesson.Tutor = &tutor
 	lesson.Language = &language
 
@@ -125,10 +220,9 @@ func (r *LessonRepository) GetByID(ctx context.Context, id int) (*entities.Lesso
 // GetByStudentID retrieves all lessons for a student
 func (r *LessonRepository) GetByStudentID(ctx context.Context, studentID int) ([]entities.Lesson, error) {
 	query := `
-		SELECT id, student_id, tutor_id, language_id, start_time, end_time, 
-		       cancelled_by, cancelled_at, notes, created_at, updated_at
+		SELECT ` + lessonColumns + `
 		FROM lessons
-		WHERE student_id = $1
+		WHERE ` + studentLessonsCondition + `
 		ORDER BY start_time DESC
 	`
 
@@ -138,8 +232,7 @@ func (r *LessonRepository) GetByStudentID(ctx context.Context, studentID int) ([
 // GetByTutorID retrieves all lessons for a tutor
 func (r *LessonRepository) GetByTutorID(ctx context.Context, tutorID int) ([]entities.Lesson, error) {
 	query := `
-		SELECT id, student_id, tutor_id, language_id, start_time, end_time, 
-		       cancelled_by, cancelled_at, notes, created_at, updated_at
+		SELECT ` + lessonColumns + `
 		FROM lessons
 		WHERE tutor_id = $1
 		ORDER BY start_time DESC
@@ -153,18 +246,16 @@ func (r *LessonRepository) GetUpcomingLessons(ctx context.Context, userID int, i
 	var query string
 	if isStudent {
 		query = `
-			SELECT id, student_id, tutor_id, language_id, start_time, end_time, 
-				   cancelled_by, cancelled_at, notes, created_at, updated_at
+			SELECT ` + lessonColumns + `
 			FROM lessons
-			WHERE student_id = $1 AND end_time >= NOW() AND cancelled_by IS NULL
+			WHERE ` + studentLessonsCondition + ` AND end_time >= NOW() AND cancelled_at IS NULL
 			ORDER BY start_time ASC
 		`
 	} else {
 		query = `
-			SELECT id, student_id, tutor_id, language_id, start_time, end_time, 
-				   cancelled_by, cancelled_at, notes, created_at, updated_at
+			SELECT ` + lessonColumns + `
 			FROM lessons
-			WHERE tutor_id = $1 AND end_time >= NOW() AND cancelled_by IS NULL
+			WHERE tutor_id = $1 AND end_time >= NOW() AND cancelled_at IS NULL
 			ORDER BY start_time ASC
 		`
 	}
@@ -177,18 +268,16 @@ func (r *LessonRepository) GetPastLessons(ctx context.Context, userID int, isStu
 	var query string
 	if isStudent {
 		query = `
-			SELECT id, student_id, tutor_id, language_id, start_time, end_time, 
-				   cancelled_by, cancelled_at, notes, created_at, updated_at
+			SELECT ` + lessonColumns + `
 			FROM lessons
-			WHERE student_id = $1 AND end_time < NOW() AND cancelled_by IS NULL
+			WHERE ` + studentLessonsCondition + ` AND end_time < NOW() AND cancelled_at IS NULL
 			ORDER BY start_time DESC
 		`
 	} else {
 		query = `
-			SELECT id, student_id, tutor_id, language_id, start_time, end_time, 
-				   cancelled_by, cancelled_at, notes, created_at, updated_at
+			SELECT ` + lessonColumns + `
 			FROM lessons
-			WHERE tutor_id = $1 AND end_time < NOW() AND cancelled_by IS NULL
+			WHERE tutor_id = $1 AND end_time < NOW() AND cancelled_at IS NULL
 			ORDER BY start_time DESC
 		`
 	}
@@ -201,18 +290,16 @@ func (r *LessonRepository) GetCancelledLessons(ctx context.Context, userID int,
 	var query string
 	if isStudent {
 		query = `
-			SELECT id, student_id, tutor_id, language_id, start_time, end_time, 
-				   cancelled_by, cancelled_at, notes, created_at, updated_at
+			SELECT ` + lessonColumns + `
 			FROM lessons
-			WHERE student_id = $1 AND cancelled_by IS NOT NULL
+			WHERE ` + studentLessonsCondition + ` AND cancelled_at IS NOT NULL
 			ORDER BY start_time DESC
 		`
 	} else {
 		query = `
-			SELECT id, student_id, tutor_id, language_id, start_time, end_time, 
-				   cancelled_by, cancelled_at, notes, created_at, updated_at
+			SELECT ` + lessonColumns + `
 			FROM lessons
-			WHERE tutor_id = $1 AND cancelled_by IS NOT NULL
+			WHERE tutor_id = $1 AND cancelled_at IS NOT NULL
 			ORDER BY start_time DESC
 		`
 	}
@@ -220,6 +307,19 @@ func (r *LessonRepository) GetCancelledLessons(ctx context.Context, userID int,
 	return r.getLessonsByQuery(ctx, query, userID)
 }
 
+// GetLessonsStartingBefore retrieves the lessons that have not started or been cancelled yet
+// and start before the given time
+func (r *LessonRepository) GetLessonsStartingBefore(ctx context.Context, until time.Time) ([]entities.Lesson, error) {
+	query := `
+		SELECT ` + lessonColumns + `
+		FROM lessons
+		WHERE start_time > NOW() AND start_time <= $1 AND cancelled_at IS NULL
+		ORDER BY start_time
+	`
+
+	return r.getLessonsByQuery(ctx, query, until)
+}
+
 // Helper function to retrieve lessons by a query and argument
 func (r *LessonRepository) getLessonsByQuery(ctx context.Context, query string, arg interface{}) ([]entities.Lesson, error) {
 	rows, err := r.db.QueryContext(ctx, query, arg)
@@ -231,20 +331,7 @@ func (r *LessonRepository) getLessonsByQuery(ctx context.Context, query string,
 	var lessons []entities.Lesson
 	for rows.Next() {
 		var lesson entities.Lesson
-		err := rows.Scan(
-			&lesson.ID,
-			&lesson.StudentID,
-			&lesson.TutorID,
-			&lesson.LanguageID,
-			&lesson.StartTime,
-			&lesson.EndTime,
-			&lesson.CancelledBy,
-			&lesson.CancelledAt,
-			&lesson.Notes,
-			&lesson.CreatedAt,
-			&lesson.UpdatedAt,
-		)
-		if err != nil {
+		if err := scanLesson(rows, &lesson); err != nil {
 			return nil, err
 		}
 		lessons = append(lessons, lesson)
@@ -257,43 +344,161 @@ func (r *LessonRepository) getLessonsByQuery(ctx context.Context, query string,
 	return lessons, nil
 }
 
-// CancelLesson cancels a lesson
-func (r *LessonRepository) CancelLesson(ctx context.Context, lessonID, userID int) error {
+// CancelLesson cancels a lesson, charging the given cancellation fee if it is not nil
+func (r *LessonRepository) CancelLesson(ctx context.Context, lessonID, userID int, fee *float64) error {
 	query := `
 		UPDATE lessons
-		SET cancelled_by = $1, cancelled_at = NOW()
+		SET cancelled_by = $1, cancelled_at = NOW(), cancellation_fee = $3
 		WHERE id = $2
 		RETURNING updated_at
 	`
 
 	var updatedAt time.Time
-	return r.db.QueryRowContext(ctx, query, userID, lessonID).Scan(&updatedAt)
+	return r.db.QueryRowContext(ctx, query, userID, lessonID, fee).Scan(&updatedAt)
 }
 
-// AddReview adds a review for a lesson
-func (r *LessonRepository) AddReview(ctx context.Context, review *entities.Review) error {
+// RescheduleLesson moves a lesson to another time. The update only applies if the lesson
+// still starts at previousStart, so that two concurrent changes cannot both succeed.
+func (r *LessonRepository) RescheduleLesson(ctx context.Context, lessonID int, previousStart, startTime, endTime time.Time) error {
 	query := `
-		INSERT INTO reviews
-		(lesson_id, reviewer_id, rating)
+		UPDATE lessons
+		SET start_time = $3, end_time = $4
+		WHERE id = $1 AND start_time = $2 AND cancelled_at IS NULL
+		RETURNING updated_at
+	`
+
+	var updatedAt time.Time
+	err := r.db.QueryRowContext(ctx, query, lessonID, previousStart, startTime, endTime).Scan(&updatedAt)
+	if err == sql.ErrNoRows {
+		return entities.ErrLessonNotReschedulable
+	}
+	return err
+}
+
+// MarkStudentNoShow records that the student did not show up for a lesson
+func (r *LessonRepository) MarkStudentNoShow(ctx context.Context, lessonID int, fee float64) error {
+	query := `
+		UPDATE lessons
+		SET student_no_show = TRUE, no_show_fee = $2
+		WHERE id = $1 AND cancelled_at IS NULL
+		RETURNING updated_at
+	`
+
+	var updatedAt time.Time
+	err := r.db.QueryRowContext(ctx, query, lessonID, fee).Scan(&updatedAt)
+	if err == sql.ErrNoRows {
+		return entities.ErrNotFound
+	}
+	return err
+}
+
+// AddParticipant books a seat in a group lesson. The lesson row is locked so that
+// concurrent bookings cannot exceed the capacity.
+func (r *LessonRepository) AddParticipant(ctx context.Context, participant *entities.LessonParticipant) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// The deadline is compared with the database clock, like in CancelUnderfilledGroupLessons,
+	// so that no seat is booked in a lesson the sweep is about to cancel
+	var capacity sql.NullInt64
+	var cancelledAt sql.NullTime
+	var startTime time.Time
+	var deadlinePassed bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT capacity, cancelled_at, start_time, COALESCE(enrollment_deadline <= NOW(), FALSE)
+		FROM lessons
+		WHERE id = $1 AND lesson_type = 'group'
+		FOR UPDATE
+	`, participant.LessonID).Scan(&capacity, &cancelledAt, &startTime, &deadlinePassed)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return entities.ErrNotGroupLesson
+		}
+		return err
+	}
+	if cancelledAt.Valid || deadlinePassed || !time.Now().Before(startTime) {
+		return entities.ErrEnrollmentClosed
+	}
+
+	var seats int
+	var enrolled bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(BOOL_OR(student_id = $2), FALSE)
+		FROM lesson_participants
+		WHERE lesson_id = $1 AND cancelled_at IS NULL
+	`, participant.LessonID, participant.StudentID).Scan(&seats, &enrolled)
+	if err != nil {
+		return err
+	}
+	if enrolled {
+		return entities.ErrAlreadyEnrolled
+	}
+	if seats >= int(capacity.Int64) {
+		return entities.ErrGroupLessonFull
+	}
+
+	query := `
+		INSERT INTO lesson_participants (lesson_id, student_id, price)
 		VALUES ($1, $2, $3)
-		RETURNING id, created_at
+		RETURNING id, created_at, updated_at
 	`
 
-	return r.db.QueryRowContext(
+	err = tx.QueryRowContext(
 		ctx,
 		query,
-		review.LessonID,
-		review.ReviewerID,
-		review.Rating,
-	).Scan(&review.ID, &review.CreatedAt)
+		participant.LessonID,
+		participant.StudentID,
+		participant.Price,
+	).Scan(&participant.ID, &participant.CreatedAt, &participant.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-// GetReviewsByLessonID retrieves all reviews for a lesson
-func (r *LessonRepository) GetReviewsByLessonID(ctx context.Context, lessonID int) ([]entities.Review, error) {
+// GetParticipant retrieves a student's active seat in a group lesson
+func (r *LessonRepository) GetParticipant(ctx context.Context, lessonID, studentID int) (*entities.LessonParticipant, error) {
+	query := `
+		SELECT id, lesson_id, student_id, price, cancelled_at, cancellation_fee, created_at, updated_at
+		FROM lesson_participants
+		WHERE lesson_id = $1 AND student_id = $2 AND cancelled_at IS NULL
+	`
+
+	var participant entities.LessonParticipant
+	err := r.db.QueryRowContext(ctx, query, lessonID, studentID).Scan(
+		&participant.ID,
+		&participant.LessonID,
+		&participant.StudentID,
+		&participant.Price,
+		&participant.CancelledAt,
+		&participant.CancellationFee,
+		&participant.CreatedAt,
+		&participant.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &participant, nil
+}
+
+// GetParticipants retrieves the active seats of a group lesson together with the students
+func (r *LessonRepository) GetParticipants(ctx context.Context, lessonID int) ([]entities.LessonParticipant, error) {
 	query := `
-		SELECT id, lesson_id, reviewer_id, rating, created_at
-		FROM reviews
-		WHERE lesson_id = $1
+		SELECT p.id, p.lesson_id, p.student_id, p.price, p.cancelled_at, p.cancellation_fee,
+		       p.created_at, p.updated_at,
+		       u.username, u.first_name, u.last_name, u.profile_picture_url
+		FROM lesson_participants p
+		JOIN users u ON p.student_id = u.id
+		WHERE p.lesson_id = $1 AND p.cancelled_at IS NULL
+		ORDER BY p.created_at ASC
 	`
 
 	rows, err := r.db.QueryContext(ctx, query, lessonID)
@@ -302,65 +507,181 @@ func (r *LessonRepository) GetReviewsByLessonID(ctx context.Context, lessonID in
 	}
 	defer rows.Close()
 
-	var reviews []entities.Review
+	var participants []entities.LessonParticipant
 	for rows.Next() {
-		var review entities.Review
+		var participant entities.LessonParticipant
+		var student entities.User
 		err := rows.Scan(
-			&review.ID,
-			&review.LessonID,
-			&review.ReviewerID,
-			&review.Rating,
-			&review.CreatedAt,
+			&participant.ID,
+			&participant.LessonID,
+			&participant.StudentID,
+			&participant.Price,
+			&participant.CancelledAt,
+			&participant.CancellationFee,
+			&participant.CreatedAt,
+			&participant.UpdatedAt,
+			&student.Username,
+			&student.FirstName,
+			&student.LastName,
+			&student.ProfilePictureURL,
 		)
 		if err != nil {
 			return nil, err
 		}
-		reviews = append(reviews, review)
+
+		student.ID = participant.StudentID
+		student.Role = "student"
+		participant.Student = &student
+		participants = append(participants, participant)
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
 
-	return reviews, nil
+	return participants, nil
 }
 
-// GetTutorAverageRating calculates the average rating for a tutor
-func (r *LessonRepository) GetTutorAverageRating(ctx context.Context, tutorID int) (float64, error) {
+// CancelParticipant cancels a seat in a group lesson, charging the given cancellation fee if it is not nil
+func (r *LessonRepository) CancelParticipant(ctx context.Context, participantID int, fee *float64) error {
 	query := `
-		SELECT AVG(r.rating)
-		FROM reviews r
-		JOIN lessons l ON r.lesson_id = l.id
-		WHERE l.tutor_id = $1
+		UPDATE lesson_participants
+		SET cancelled_at = NOW(), cancellation_fee = $2
+		WHERE id = $1 AND cancelled_at IS NULL
+		RETURNING updated_at
 	`
 
-	var avgRating sql.NullFloat64
-	err := r.db.QueryRowContext(ctx, query, tutorID).Scan(&avgRating)
+	var updatedAt time.Time
+	err := r.db.QueryRowContext(ctx, query, participantID, fee).Scan(&updatedAt)
+	if err == sql.ErrNoRows {
+		return entities.ErrNotEnrolled
+	}
+	return err
+}
+
+// CancelUnderfilledGroupLessons cancels group lessons whose enrollment deadline has passed
+// without reaching the minimum number of participants, and returns the cancelled lessons
+func (r *LessonRepository) CancelUnderfilledGroupLessons(ctx context.Context) ([]entities.Lesson, error) {
+	query := `
+		UPDATE lessons
+		SET cancelled_at = NOW()
+		WHERE lesson_type = 'group' AND cancelled_at IS NULL AND enrollment_deadline <= NOW()
+		  AND (SELECT COUNT(*) FROM lesson_participants p
+		       WHERE p.lesson_id = lessons.id AND p.cancelled_at IS NULL) < min_participants
+		RETURNING ` + lessonColumns
+
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
+	defer rows.Close()
 
-	if !avgRating.Valid {
-		return 0, nil
+	var lessons []entities.Lesson
+	for rows.Next() {
+		var lesson entities.Lesson
+		if err := scanLesson(rows, &lesson); err != nil {
+			return nil, err
+		}
+		lessons = append(lessons, lesson)
 	}
 
-	return avgRating.Float64, nil
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return lessons, nil
 }
 
-// GetTutorReviewsCount counts the number of reviews for a tutor
-func (r *LessonRepository) GetTutorReviewsCount(ctx context.Context, tutorID int) (int, error) {
+// AddReview adds a review for a lesson and refreshes the rating stats of the review's tutor
+func (r *LessonRepository) AddReview(ctx context.Context, review *entities.Review) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO reviews
+		(lesson_id, reviewer_id, rating, comment, pronunciation_help_rating, preparation_rating, punctuality_rating)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		review.LessonID,
+		review.ReviewerID,
+		review.Rating,
+		review.Comment,
+		review.Aspects.PronunciationHelp,
+		review.Aspects.Preparation,
+		review.Aspects.Punctuality,
+	).Scan(&review.ID, &review.CreatedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Constraint == "reviews_lesson_reviewer_key" {
+			return entities.ErrAlreadyReviewed
+		}
+		return err
+	}
+
+	if _, err := refreshTutorStats(ctx, tx, []int{review.TutorID}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetReviewsByLessonID retrieves all reviews for a lesson, including hidden ones
+func (r *LessonRepository) GetReviewsByLessonID(ctx context.Context, lessonID int) ([]entities.Review, error) {
 	query := `
-		SELECT COUNT(r.id)
+		SELECT ` + reviewColumns + `
 		FROM reviews r
 		JOIN lessons l ON r.lesson_id = l.id
-		WHERE l.tutor_id = $1
+		JOIN users u ON r.reviewer_id = u.id
+		WHERE r.lesson_id = $1
+		ORDER BY r.created_at, r.id
 	`
 
-	var count int
-	err := r.db.QueryRowContext(ctx, query, tutorID).Scan(&count)
+	rows, err := r.db.QueryContext(ctx, query, lessonID)
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanReviews(rows)
+}
+
+// GetTrialConversionStats counts a tutor's trial lessons and the students who booked a
+// regular lesson with the tutor after completing their trial
+func (r *LessonRepository) GetTrialConversionStats(ctx context.Context, tutorID int) (*entities.TrialConversionStats, error) {
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE t.cancelled_at IS NULL),
+			COUNT(*) FILTER (WHERE t.cancelled_at IS NULL AND NOT t.student_no_show AND t.end_time < NOW()),
+			COUNT(*) FILTER (WHERE t.cancelled_at IS NULL AND NOT t.student_no_show AND t.end_time < NOW() AND EXISTS (
+				SELECT 1 FROM lessons p
+				WHERE p.tutor_id = t.tutor_id AND p.student_id = t.student_id
+				  AND p.lesson_type = 'regular' AND p.cancelled_at IS NULL AND p.start_time >= t.start_time
+			))
+		FROM lessons t
+		WHERE t.tutor_id = $1 AND t.lesson_type = 'trial'
+	`
+
+	stats := &entities.TrialConversionStats{}
+	err := r.db.QueryRowContext(ctx, query, tutorID).Scan(
+		&stats.TrialLessons,
+		&stats.CompletedTrials,
+		&stats.ConvertedStudents,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if stats.CompletedTrials > 0 {
+		stats.ConversionRate = float64(stats.ConvertedStudents) / float64(stats.CompletedTrials)
 	}
 
-	return count, nil
+	return stats, nil
 }
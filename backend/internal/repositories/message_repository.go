@@ -0,0 +1,570 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"tongly-backend/internal/entities"
+)
+
+// messageColumns lists the message columns in the order expected by scanMessage
+const messageColumns = `m.id, m.conversation_id, m.sender_id, m.body, m.attachments, m.read_at, m.created_at`
+
+// conversationColumns lists the conversation columns in the order expected by scanConversation.
+// Queries select them from conversations c joined with the student s and the tutor t.
+const conversationColumns = `
+	c.id, c.student_id, c.tutor_id, c.started_by, c.last_message_at, c.created_at,
+	s.username, s.first_name, s.last_name, s.profile_picture_url, s.role,
+	t.username, t.first_name, t.last_name, t.profile_picture_url, t.role`
+
+// MessageRepository handles database operations for conversations, messages, blocks and reports
+type MessageRepository struct {
+	db *sql.DB
+}
+
+// NewMessageRepository creates a new MessageRepository
+func NewMessageRepository(db *sql.DB) *MessageRepository {
+	return &MessageRepository{
+		db: db,
+	}
+}
+
+// CreateConversation starts a conversation, or loads it if one already exists between the
+// student and the tutor. If dailyLimit is positive, the user starting it may only have started
+// that many conversations in the last 24 hours.
+func (r *MessageRepository) CreateConversation(ctx context.Context, conversation *entities.Conversation, dailyLimit int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Lock the user starting the conversation so that concurrent requests cannot exceed the limit
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, conversation.StartedBy); err != nil {
+		return err
+	}
+
+	if dailyLimit > 0 {
+		var started int
+		err := tx.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM conversations
+			WHERE started_by = $1 AND created_at > NOW() - INTERVAL '1 day'
+		`, conversation.StartedBy).Scan(&started)
+		if err != nil {
+			return err
+		}
+		if started >= dailyLimit {
+			return entities.ErrTooManyNewConversations
+		}
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO conversations (student_id, tutor_id, started_by)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (student_id, tutor_id) DO UPDATE SET student_id = EXCLUDED.student_id
+		RETURNING id, started_by, last_message_at, created_at
+	`, conversation.StudentID, conversation.TutorID, conversation.StartedBy).Scan(
+		&conversation.ID, &conversation.StartedBy, &conversation.LastMessageAt, &conversation.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetConversation retrieves a conversation with its participants
+func (r *MessageRepository) GetConversation(ctx context.Context, conversationID int) (*entities.Conversation, error) {
+	query := `
+		SELECT ` + conversationColumns + `
+		FROM conversations c
+		JOIN users s ON c.student_id = s.id
+		JOIN users t ON c.tutor_id = t.id
+		WHERE c.id = $1
+	`
+
+	conversation, err := scanConversation(r.db.QueryRowContext(ctx, query, conversationID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrConversationNotFound
+		}
+		return nil, err
+	}
+
+	return conversation, nil
+}
+
+// GetConversationBetween retrieves the conversation between a student and a tutor
+func (r *MessageRepository) GetConversationBetween(ctx context.Context, studentID, tutorID int) (*entities.Conversation, error) {
+	query := `
+		SELECT ` + conversationColumns + `
+		FROM conversations c
+		JOIN users s ON c.student_id = s.id
+		JOIN users t ON c.tutor_id = t.id
+		WHERE c.student_id = $1 AND c.tutor_id = $2
+	`
+
+	conversation, err := scanConversation(r.db.QueryRowContext(ctx, query, studentID, tutorID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrConversationNotFound
+		}
+		return nil, err
+	}
+
+	return conversation, nil
+}
+
+// ListConversations retrieves a page of a user's conversations, most recently active first,
+// with their last message and the number of messages the user has not read
+func (r *MessageRepository) ListConversations(ctx context.Context, userID int, filters *entities.ConversationFilters) ([]entities.Conversation, error) {
+	query := `
+		SELECT ` + conversationColumns + `,
+			(SELECT COUNT(*) FROM messages u
+			 WHERE u.conversation_id = c.id AND u.sender_id <> $1 AND u.read_at IS NULL),
+			` + messageColumns + `
+		FROM conversations c
+		JOIN users s ON c.student_id = s.id
+		JOIN users t ON c.tutor_id = t.id
+		JOIN LATERAL (
+			SELECT * FROM messages
+			WHERE conversation_id = c.id
+			ORDER BY id DESC
+			LIMIT 1
+		) m ON TRUE
+		WHERE c.student_id = $1 OR c.tutor_id = $1
+		ORDER BY c.last_message_at DESC, c.id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, filters.Limit, filters.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	conversations := []entities.Conversation{}
+	for rows.Next() {
+		conversation, err := scanConversationWithLastMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		conversations = append(conversations, *conversation)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return conversations, nil
+}
+
+// CountConversations counts the conversations of a user that have messages
+func (r *MessageRepository) CountConversations(ctx context.Context, userID int) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM conversations c
+		WHERE (c.student_id = $1 OR c.tutor_id = $1)
+		  AND EXISTS (SELECT 1 FROM messages m WHERE m.conversation_id = c.id)
+	`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CreateMessage stores a message and moves its conversation to the top of the inbox.
+// If unansweredLimit is positive, the sender may only have that many messages in a row
+// that the other participant has not replied to.
+func (r *MessageRepository) CreateMessage(ctx context.Context, message *entities.Message, unansweredLimit int) error {
+	attachments := message.Attachments
+	if attachments == nil {
+		attachments = []entities.MessageAttachment{}
+	}
+	payload, err := json.Marshal(attachments)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Lock the conversation so that concurrent messages are counted against the limit
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM conversations WHERE id = $1 FOR UPDATE`, message.ConversationID); err != nil {
+		return err
+	}
+
+	if unansweredLimit > 0 {
+		var unanswered int
+		err := tx.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM messages
+			WHERE conversation_id = $1 AND sender_id = $2
+			  AND id > COALESCE((SELECT MAX(id) FROM messages WHERE conversation_id = $1 AND sender_id <> $2), 0)
+		`, message.ConversationID, message.SenderID).Scan(&unanswered)
+		if err != nil {
+			return err
+		}
+		if unanswered >= unansweredLimit {
+			return entities.ErrMessageRateLimited
+		}
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO messages (conversation_id, sender_id, body, attachments)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`, message.ConversationID, message.SenderID, message.Body, string(payload)).Scan(&message.ID, &message.CreatedAt)
+	if err != nil {
+		return err
+	}
+	message.Attachments = attachments
+
+	_, err = tx.ExecContext(ctx, `UPDATE conversations SET last_message_at = $1 WHERE id = $2`, message.CreatedAt, message.ConversationID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetMessage retrieves a message of a conversation
+func (r *MessageRepository) GetMessage(ctx context.Context, conversationID int, messageID int64) (*entities.Message, error) {
+	query := `SELECT ` + messageColumns + ` FROM messages m WHERE m.id = $1 AND m.conversation_id = $2`
+
+	message, err := scanMessage(r.db.QueryRowContext(ctx, query, messageID, conversationID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrMessageNotFound
+		}
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// ListMessages retrieves up to limit messages of a conversation older than beforeID, newest first.
+// A beforeID of 0 starts from the newest message.
+func (r *MessageRepository) ListMessages(ctx context.Context, conversationID int, beforeID int64, limit int) ([]entities.Message, error) {
+	query := `
+		SELECT ` + messageColumns + `
+		FROM messages m
+		WHERE m.conversation_id = $1 AND ($2 = 0 OR m.id < $2)
+		ORDER BY m.id DESC
+		LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, conversationID, beforeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := []entities.Message{}
+	for rows.Next() {
+		message, err := scanMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, *message)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// MarkRead sets the read receipt of the messages a user received in a conversation
+func (r *MessageRepository) MarkRead(ctx context.Context, conversationID int, userID int) (int, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE messages SET read_at = NOW()
+		WHERE conversation_id = $1 AND sender_id <> $2 AND read_at IS NULL
+	`, conversationID, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	marked, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(marked), nil
+}
+
+// CountUnread counts the messages a user has received and not read in all conversations
+func (r *MessageRepository) CountUnread(ctx context.Context, userID int) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM messages m
+		JOIN conversations c ON m.conversation_id = c.id
+		WHERE (c.student_id = $1 OR c.tutor_id = $1) AND m.sender_id <> $1 AND m.read_at IS NULL
+	`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// HasBooking checks if a student has booked a one-on-one lesson or a group lesson seat with a tutor
+func (r *MessageRepository) HasBooking(ctx context.Context, studentID, tutorID int) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM lessons WHERE student_id = $1 AND tutor_id = $2
+		) OR EXISTS (
+			SELECT 1 FROM lesson_participants p
+			JOIN lessons l ON p.lesson_id = l.id
+			WHERE p.student_id = $1 AND l.tutor_id = $2
+		)
+	`
+
+	var booked bool
+	if err := r.db.QueryRowContext(ctx, query, studentID, tutorID).Scan(&booked); err != nil {
+		return false, err
+	}
+	return booked, nil
+}
+
+// Block stops two users from messaging each other
+func (r *MessageRepository) Block(ctx context.Context, blockerID, blockedID int) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO user_blocks (blocker_id, blocked_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, blockerID, blockedID)
+	return err
+}
+
+// Unblock lifts a block a user has placed
+func (r *MessageRepository) Unblock(ctx context.Context, blockerID, blockedID int) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM user_blocks WHERE blocker_id = $1 AND blocked_id = $2`, blockerID, blockedID)
+	return err
+}
+
+// IsBlocked checks if either user has blocked the other
+func (r *MessageRepository) IsBlocked(ctx context.Context, userID, otherID int) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM user_blocks
+			WHERE (blocker_id = $1 AND blocked_id = $2) OR (blocker_id = $2 AND blocked_id = $1)
+		)
+	`
+
+	var blocked bool
+	if err := r.db.QueryRowContext(ctx, query, userID, otherID).Scan(&blocked); err != nil {
+		return false, err
+	}
+	return blocked, nil
+}
+
+// ListBlocked retrieves the users a user has blocked, most recent first
+func (r *MessageRepository) ListBlocked(ctx context.Context, blockerID int) ([]entities.BlockedUser, error) {
+	query := `
+		SELECT u.id, u.username, u.first_name, u.last_name, u.profile_picture_url, u.role, b.created_at
+		FROM user_blocks b
+		JOIN users u ON b.blocked_id = u.id
+		WHERE b.blocker_id = $1
+		ORDER BY b.created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, blockerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blocked := []entities.BlockedUser{}
+	for rows.Next() {
+		var user entities.User
+		var entry entities.BlockedUser
+		err := rows.Scan(&user.ID, &user.Username, &user.FirstName, &user.LastName,
+			&user.ProfilePictureURL, &user.Role, &entry.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		entry.User = &user
+		blocked = append(blocked, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return blocked, nil
+}
+
+// CreateReport stores a report of a conversation or one of its messages
+func (r *MessageRepository) CreateReport(ctx context.Context, report *entities.ConversationReport) error {
+	query := `
+		INSERT INTO conversation_reports (conversation_id, message_id, reporter_id, reason)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRowContext(ctx, query, report.ConversationID, report.MessageID, report.ReporterID, report.Reason).
+		Scan(&report.ID, &report.CreatedAt)
+}
+
+// ListReports retrieves a page of conversation reports with the reported messages, oldest first
+func (r *MessageRepository) ListReports(ctx context.Context, filters *entities.ReportFilters) ([]entities.ConversationReport, error) {
+	query := `
+		SELECT r.id, r.conversation_id, r.message_id, r.reporter_id, r.reason, r.resolved_at, r.created_at,
+			` + messageColumns + `
+		FROM conversation_reports r
+		LEFT JOIN messages m ON r.message_id = m.id
+		WHERE (NOT $1 OR r.resolved_at IS NULL)
+		ORDER BY r.created_at, r.id
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, filters.OpenOnly, filters.Limit, filters.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := []entities.ConversationReport{}
+	for rows.Next() {
+		var report entities.ConversationReport
+		var messageID sql.NullInt64
+		var conversationID, senderID sql.NullInt64
+		var body sql.NullString
+		var attachments []byte
+		var readAt, createdAt sql.NullTime
+
+		err := rows.Scan(
+			&report.ID, &report.ConversationID, &report.MessageID, &report.ReporterID,
+			&report.Reason, &report.ResolvedAt, &report.CreatedAt,
+			&messageID, &conversationID, &senderID, &body, &attachments, &readAt, &createdAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if messageID.Valid {
+			message := &entities.Message{
+				ID:             messageID.Int64,
+				ConversationID: int(conversationID.Int64),
+				SenderID:       int(senderID.Int64),
+				Body:           body.String,
+				CreatedAt:      createdAt.Time,
+			}
+			if readAt.Valid {
+				message.ReadAt = &readAt.Time
+			}
+			if err := json.Unmarshal(attachments, &message.Attachments); err != nil {
+				return nil, err
+			}
+			report.Message = message
+		}
+
+		reports = append(reports, report)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+// ResolveReport marks a conversation report as handled
+func (r *MessageRepository) ResolveReport(ctx context.Context, reportID int) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE conversation_reports SET resolved_at = COALESCE(resolved_at, NOW()) WHERE id = $1
+	`, reportID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return entities.ErrReportNotFound
+	}
+	return nil
+}
+
+// scanMessage reads a message selected with messageColumns
+func scanMessage(row rowScanner) (*entities.Message, error) {
+	var message entities.Message
+	var attachments []byte
+
+	err := row.Scan(
+		&message.ID, &message.ConversationID, &message.SenderID, &message.Body,
+		&attachments, &message.ReadAt, &message.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(attachments, &message.Attachments); err != nil {
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+// scanConversation reads a conversation selected with conversationColumns
+func scanConversation(row rowScanner) (*entities.Conversation, error) {
+	conversation, dest := conversationScanDest()
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	conversation.Student.ID = conversation.StudentID
+	conversation.Tutor.ID = conversation.TutorID
+	return conversation, nil
+}
+
+// scanConversationWithLastMessage reads a conversation selected with conversationColumns,
+// followed by the unread count and the last message selected with messageColumns
+func scanConversationWithLastMessage(row rowScanner) (*entities.Conversation, error) {
+	conversation, dest := conversationScanDest()
+
+	var message entities.Message
+	var attachments []byte
+	dest = append(dest,
+		&conversation.UnreadCount,
+		&message.ID, &message.ConversationID, &message.SenderID, &message.Body,
+		&attachments, &message.ReadAt, &message.CreatedAt,
+	)
+
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(attachments, &message.Attachments); err != nil {
+		return nil, err
+	}
+	conversation.LastMessage = &message
+	conversation.Student.ID = conversation.StudentID
+	conversation.Tutor.ID = conversation.TutorID
+
+	return conversation, nil
+}
+
+// conversationScanDest returns a conversation with its participants and the scan destinations
+// of conversationColumns
+func conversationScanDest() (*entities.Conversation, []interface{}) {
+	conversation := &entities.Conversation{
+		Student: &entities.User{},
+		Tutor:   &entities.User{},
+	}
+
+	return conversation, []interface{}{
+		&conversation.ID, &conversation.StudentID, &conversation.TutorID, &conversation.StartedBy,
+		&conversation.LastMessageAt, &conversation.CreatedAt,
+		&conversation.Student.Username, &conversation.Student.FirstName, &conversation.Student.LastName,
+		&conversation.Student.ProfilePictureURL, &conversation.Student.Role,
+		&conversation.Tutor.Username, &conversation.Tutor.FirstName, &conversation.Tutor.LastName,
+		&conversation.Tutor.ProfilePictureURL, &conversation.Tutor.Role,
+	}
+}
@@ -0,0 +1,176 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+	"tongly-backend/internal/entities"
+)
+
+// NotificationPreferenceRepository handles database operations for users' notification
+// preferences and the data needed to email them
+type NotificationPreferenceRepository struct {
+	db *sql.DB
+}
+
+// NewNotificationPreferenceRepository creates a new NotificationPreferenceRepository
+func NewNotificationPreferenceRepository(db *sql.DB) *NotificationPreferenceRepository {
+	return &NotificationPreferenceRepository{
+		db: db,
+	}
+}
+
+// GetPreferences retrieves a user's notification preferences, filled in with the defaults
+func (r *NotificationPreferenceRepository) GetPreferences(ctx context.Context, userID int) (*entities.NotificationPreferences, error) {
+	var locale string
+	err := r.db.QueryRowContext(ctx, `SELECT locale FROM users WHERE id = $1`, userID).Scan(&locale)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrNotFound
+		}
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT event, in_app, email FROM notification_preferences WHERE user_id = $1
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	overrides := make(map[entities.NotificationType]entities.NotificationChannels)
+	for rows.Next() {
+		var event entities.NotificationType
+		var channels entities.NotificationChannels
+		if err := rows.Scan(&event, &channels.InApp, &channels.Email); err != nil {
+			return nil, err
+		}
+		overrides[event] = channels
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entities.NewNotificationPreferences(locale, overrides), nil
+}
+
+// GetChannels retrieves the channels through which a user wants to be told about an event
+func (r *NotificationPreferenceRepository) GetChannels(ctx context.Context, userID int, event entities.NotificationType) (entities.NotificationChannels, error) {
+	var channels entities.NotificationChannels
+	err := r.db.QueryRowContext(ctx, `
+		SELECT in_app, email FROM notification_preferences WHERE user_id = $1 AND event = $2
+	`, userID, event).Scan(&channels.InApp, &channels.Email)
+	if err == sql.ErrNoRows {
+		return entities.DefaultNotificationChannels(event), nil
+	}
+	if err != nil {
+		return channels, err
+	}
+
+	return channels, nil
+}
+
+// SavePreferences stores the locale and the event channels given in the request
+func (r *NotificationPreferenceRepository) SavePreferences(ctx context.Context, userID int, req *entities.NotificationPreferencesRequest) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if req.Locale != nil {
+		if _, err := tx.ExecContext(ctx, `UPDATE users SET locale = $1 WHERE id = $2`, *req.Locale, userID); err != nil {
+			return err
+		}
+	}
+
+	for event, channels := range req.Events {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO notification_preferences (user_id, event, in_app, email)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (user_id, event) DO UPDATE
+			SET in_app = EXCLUDED.in_app, email = EXCLUDED.email, updated_at = NOW()
+		`, userID, event, channels.InApp, channels.Email)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetRecipient retrieves the address, name and locale used to email a user
+func (r *NotificationPreferenceRepository) GetRecipient(ctx context.Context, userID int) (*entities.EmailRecipient, error) {
+	var recipient entities.EmailRecipient
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, email, first_name, username, locale FROM users WHERE id = $1
+	`, userID).Scan(&recipient.UserID, &recipient.Email, &recipient.FirstName, &recipient.Username, &recipient.Locale)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &recipient, nil
+}
+
+// GetWeeklySummaries retrieves the activity around weekStart of the users who get the weekly
+// summary email. Users without lessons in the past or coming week and without a streak are left out.
+func (r *NotificationPreferenceRepository) GetWeeklySummaries(ctx context.Context, weekStart time.Time) ([]entities.WeeklySummary, error) {
+	// Users who never configured the weekly summary get it, matching DefaultNotificationChannels
+	query := `
+		SELECT u.id, u.email, u.first_name, u.username, u.locale,
+			s.completed, s.upcoming, s.next_lesson_at, COALESCE(sp.current_streak, 0)
+		FROM users u
+		LEFT JOIN notification_preferences np ON np.user_id = u.id AND np.event = 'weekly_summary'
+		LEFT JOIN student_profiles sp ON sp.user_id = u.id
+		CROSS JOIN LATERAL (
+			SELECT
+				COUNT(*) FILTER (WHERE l.end_time >= $1::timestamp - INTERVAL '7 days' AND l.end_time < $1) AS completed,
+				COUNT(*) FILTER (WHERE l.start_time >= $1 AND l.start_time < $1::timestamp + INTERVAL '7 days') AS upcoming,
+				MIN(l.start_time) FILTER (WHERE l.start_time >= $1) AS next_lesson_at
+			FROM lessons l
+			WHERE l.cancelled_at IS NULL
+			  AND (l.tutor_id = u.id OR l.student_id = u.id OR l.id IN (
+				SELECT p.lesson_id FROM lesson_participants p WHERE p.student_id = u.id AND p.cancelled_at IS NULL))
+		) s
+		WHERE COALESCE(np.email, TRUE)
+		  AND (s.completed > 0 OR s.upcoming > 0 OR COALESCE(sp.current_streak, 0) > 0)
+		ORDER BY u.id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, weekStart)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summaries := []entities.WeeklySummary{}
+	for rows.Next() {
+		summary := entities.WeeklySummary{WeekStart: weekStart}
+		err := rows.Scan(
+			&summary.Recipient.UserID,
+			&summary.Recipient.Email,
+			&summary.Recipient.FirstName,
+			&summary.Recipient.Username,
+			&summary.Recipient.Locale,
+			&summary.LessonsCompleted,
+			&summary.UpcomingLessons,
+			&summary.NextLessonAt,
+			&summary.CurrentStreak,
+		)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, summary)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return summaries, nil
+}
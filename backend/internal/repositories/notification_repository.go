@@ -0,0 +1,252 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/logger"
+
+	"github.com/lib/pq"
+)
+
+// notificationChannel is the Postgres channel on which new notifications are announced
+// as "<user_id>:<notification_id>"
+const notificationChannel = "notifications"
+
+// notificationColumns lists the notification columns in the order expected by scanNotification
+const notificationColumns = `id, user_id, type, title, body, data, read_at, created_at`
+
+// NotificationRepository handles database operations for in-app notifications
+type NotificationRepository struct {
+	db *sql.DB
+}
+
+// NewNotificationRepository creates a new NotificationRepository
+func NewNotificationRepository(db *sql.DB) *NotificationRepository {
+	return &NotificationRepository{
+		db: db,
+	}
+}
+
+// Create stores a notification and announces it to the listeners of every backend replica
+func (r *NotificationRepository) Create(ctx context.Context, notification *entities.Notification) error {
+	data := notification.Data
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var dedupKey *string
+	if notification.DedupKey != "" {
+		dedupKey = &notification.DedupKey
+	}
+
+	query := `
+		INSERT INTO notifications (user_id, type, title, body, data, dedup_key)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (dedup_key) DO NOTHING
+		RETURNING id, created_at
+	`
+
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		notification.UserID,
+		notification.Type,
+		notification.Title,
+		notification.Body,
+		string(payload),
+		dedupKey,
+	).Scan(&notification.ID, &notification.CreatedAt)
+	if err == sql.ErrNoRows {
+		// Stored before under the same dedup key
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// Delivered to listeners when the transaction commits
+	message := fmt.Sprintf("%d:%d", notification.UserID, notification.ID)
+	if _, err := tx.ExecContext(ctx, `SELECT pg_notify($1, $2)`, notificationChannel, message); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetByID retrieves a notification of a user
+func (r *NotificationRepository) GetByID(ctx context.Context, notificationID int64, userID int) (*entities.Notification, error) {
+	query := `SELECT ` + notificationColumns + ` FROM notifications WHERE id = $1 AND user_id = $2`
+
+	notification, err := scanNotification(r.db.QueryRowContext(ctx, query, notificationID, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrNotificationNotFound
+		}
+		return nil, err
+	}
+
+	return notification, nil
+}
+
+// List retrieves a page of a user's notifications, newest first
+func (r *NotificationRepository) List(ctx context.Context, userID int, filters *entities.NotificationFilters) ([]entities.Notification, error) {
+	query := `
+		SELECT ` + notificationColumns + `
+		FROM notifications
+		WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, filters.UnreadOnly, filters.Limit, filters.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := []entities.Notification{}
+	for rows.Next() {
+		notification, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, *notification)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// Count counts a user's notifications, or only the unread ones
+func (r *NotificationRepository) Count(ctx context.Context, userID int, unreadOnly bool) (int, error) {
+	query := `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)`
+
+	var count int
+	err := r.db.QueryRowContext(ctx, query, userID, unreadOnly).Scan(&count)
+	return count, err
+}
+
+// MarkRead marks a notification of a user as read. Notifications that are already read keep their read time.
+func (r *NotificationRepository) MarkRead(ctx context.Context, notificationID int64, userID int) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE notifications SET read_at = COALESCE(read_at, NOW())
+		WHERE id = $1 AND user_id = $2
+	`, notificationID, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return entities.ErrNotificationNotFound
+	}
+
+	return nil
+}
+
+// MarkAllRead marks all unread notifications of a user as read and returns how many were marked
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID int) (int, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL
+	`, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := result.RowsAffected()
+	return int(affected), err
+}
+
+// Listen calls handle for every notification created by any backend replica until ctx is cancelled.
+// Notifications created while the connection is being re-established are not reported.
+func (r *NotificationRepository) Listen(ctx context.Context, dsn string, handle func(userID int, notificationID int64)) error {
+	listener := pq.NewListener(dsn, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			logger.Error("Notification listener connection problem", "event", event, "error", err)
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(notificationChannel); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n := <-listener.Notify:
+			// A nil notification means the connection was re-established
+			if n == nil {
+				continue
+			}
+
+			userIDStr, idStr, ok := strings.Cut(n.Extra, ":")
+			if !ok {
+				continue
+			}
+			userID, err := strconv.Atoi(userIDStr)
+			if err != nil {
+				continue
+			}
+			notificationID, err := strconv.ParseInt(idStr, 10, 64)
+			if err != nil {
+				continue
+			}
+			handle(userID, notificationID)
+		case <-time.After(90 * time.Second):
+			// Check that the connection is still alive
+			go listener.Ping()
+		}
+	}
+}
+
+// scanNotification scans a row selected with notificationColumns
+func scanNotification(row rowScanner) (*entities.Notification, error) {
+	var notification entities.Notification
+	var data []byte
+
+	err := row.Scan(
+		&notification.ID,
+		&notification.UserID,
+		&notification.Type,
+		&notification.Title,
+		&notification.Body,
+		&data,
+		&notification.ReadAt,
+		&notification.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &notification.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	return &notification, nil
+}
@@ -0,0 +1,397 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"tongly-backend/internal/entities"
+
+	"github.com/lib/pq"
+)
+
+// reviewColumns lists the review columns in the order expected by scanReview. Queries
+// select them from reviews r joined with the lesson l and the reviewer u.
+const reviewColumns = `
+	r.id, r.lesson_id, r.reviewer_id, l.tutor_id, r.rating, r.comment,
+	r.pronunciation_help_rating, r.preparation_rating, r.punctuality_rating,
+	r.reply, r.replied_at, r.helpful_count, r.hidden_at, r.hidden_reason, r.created_at,
+	u.first_name, u.last_name, u.profile_picture_url, u.avatar_urls`
+
+// ReviewRepository handles database operations for written reviews, replies, votes and reports
+type ReviewRepository struct {
+	db *sql.DB
+}
+
+// NewReviewRepository creates a new ReviewRepository
+func NewReviewRepository(db *sql.DB) *ReviewRepository {
+	return &ReviewRepository{
+		db: db,
+	}
+}
+
+// GetByID retrieves a review
+func (r *ReviewRepository) GetByID(ctx context.Context, reviewID int) (*entities.Review, error) {
+	query := `
+		SELECT ` + reviewColumns + `
+		FROM reviews r
+		JOIN lessons l ON r.lesson_id = l.id
+		JOIN users u ON r.reviewer_id = u.id
+		WHERE r.id = $1
+	`
+
+	review, err := scanReview(r.db.QueryRowContext(ctx, query, reviewID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrReviewNotFound
+		}
+		return nil, err
+	}
+
+	return review, nil
+}
+
+// ListTutorReviews retrieves a page of the visible reviews students wrote about a tutor's
+// lessons, newest or most helpful first
+func (r *ReviewRepository) ListTutorReviews(ctx context.Context, tutorID int, filters *entities.ReviewFilters) ([]entities.Review, error) {
+	query := `
+		SELECT ` + reviewColumns + `
+		FROM reviews r
+		JOIN lessons l ON r.lesson_id = l.id
+		JOIN users u ON r.reviewer_id = u.id
+		WHERE l.tutor_id = $1 AND r.hidden_at IS NULL
+		ORDER BY CASE WHEN $2 THEN r.helpful_count END DESC NULLS LAST, r.created_at DESC, r.id DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, tutorID, filters.Sort == entities.ReviewSortHelpful, filters.Limit, filters.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanReviews(rows)
+}
+
+// GetTutorSummary computes the average ratings and count of the visible reviews students
+// wrote about a tutor's lessons
+func (r *ReviewRepository) GetTutorSummary(ctx context.Context, tutorID int) (*entities.ReviewSummary, error) {
+	query := `
+		SELECT COALESCE(AVG(r.rating), 0), COUNT(*),
+		       AVG(r.pronunciation_help_rating), AVG(r.preparation_rating), AVG(r.punctuality_rating)
+		FROM reviews r
+		JOIN lessons l ON r.lesson_id = l.id
+		WHERE l.tutor_id = $1 AND r.hidden_at IS NULL
+	`
+
+	var summary entities.ReviewSummary
+	var pronunciationHelp, preparation, punctuality sql.NullFloat64
+	err := r.db.QueryRowContext(ctx, query, tutorID).Scan(
+		&summary.AverageRating, &summary.ReviewsCount, &pronunciationHelp, &preparation, &punctuality,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	summary.Aspects.PronunciationHelp = nullFloat(pronunciationHelp)
+	summary.Aspects.Preparation = nullFloat(preparation)
+	summary.Aspects.Punctuality = nullFloat(punctuality)
+	return &summary, nil
+}
+
+// SetReply sets or replaces the tutor's reply to a review
+func (r *ReviewRepository) SetReply(ctx context.Context, reviewID int, text string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE reviews SET reply = $1, replied_at = NOW() WHERE id = $2
+	`, text, reviewID)
+	if err != nil {
+		return err
+	}
+	return expectReviewRow(result)
+}
+
+// DeleteReply removes the tutor's reply to a review
+func (r *ReviewRepository) DeleteReply(ctx context.Context, reviewID int) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE reviews SET reply = NULL, replied_at = NULL WHERE id = $1
+	`, reviewID)
+	if err != nil {
+		return err
+	}
+	return expectReviewRow(result)
+}
+
+// AddVote records that the user found a review helpful and returns the new number of votes.
+// Voting twice counts once.
+func (r *ReviewRepository) AddVote(ctx context.Context, reviewID, userID int) (int, error) {
+	return r.changeVote(ctx, reviewID, userID, `
+		INSERT INTO review_votes (review_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING
+	`, 1)
+}
+
+// RemoveVote withdraws the user's helpful vote for a review and returns the new number of votes
+func (r *ReviewRepository) RemoveVote(ctx context.Context, reviewID, userID int) (int, error) {
+	return r.changeVote(ctx, reviewID, userID, `
+		DELETE FROM review_votes WHERE review_id = $1 AND user_id = $2
+	`, -1)
+}
+
+// changeVote runs a statement on the user's vote and moves helpful_count by delta if it changed a row
+func (r *ReviewRepository) changeVote(ctx context.Context, reviewID, userID int, statement string, delta int) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Lock the review so that the count stays in step with the votes
+	var count int
+	err = tx.QueryRowContext(ctx, `SELECT helpful_count FROM reviews WHERE id = $1 FOR UPDATE`, reviewID).Scan(&count)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, entities.ErrReviewNotFound
+		}
+		return 0, err
+	}
+
+	result, err := tx.ExecContext(ctx, statement, reviewID, userID)
+	if err != nil {
+		return 0, err
+	}
+	changed, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if changed > 0 {
+		err = tx.QueryRowContext(ctx, `
+			UPDATE reviews SET helpful_count = helpful_count + $1 WHERE id = $2 RETURNING helpful_count
+		`, delta, reviewID).Scan(&count)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return count, tx.Commit()
+}
+
+// CreateReport records a report of a review. A user reporting a review again before the
+// report is handled updates the reason of their open report.
+func (r *ReviewRepository) CreateReport(ctx context.Context, report *entities.ReviewReport) error {
+	query := `
+		INSERT INTO review_reports (review_id, reporter_id, reason)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (review_id, reporter_id) WHERE resolved_at IS NULL
+		DO UPDATE SET reason = EXCLUDED.reason
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRowContext(ctx, query, report.ReviewID, report.ReporterID, report.Reason).Scan(
+		&report.ID, &report.CreatedAt,
+	)
+}
+
+// ListModerationQueue retrieves a page of reviews that have open reports, or of hidden
+// reviews, together with their reports. Reported reviews come oldest report first.
+func (r *ReviewRepository) ListModerationQueue(ctx context.Context, filters *entities.ReviewModerationFilters) ([]entities.ModeratedReview, error) {
+	query := `
+		SELECT ` + reviewColumns + `
+		FROM reviews r
+		JOIN lessons l ON r.lesson_id = l.id
+		JOIN users u ON r.reviewer_id = u.id
+		LEFT JOIN LATERAL (
+			SELECT MIN(rr.created_at) AS reported_at
+			FROM review_reports rr
+			WHERE rr.review_id = r.id AND rr.resolved_at IS NULL
+		) open ON TRUE
+		WHERE CASE WHEN $1 THEN r.hidden_at IS NOT NULL ELSE r.hidden_at IS NULL AND open.reported_at IS NOT NULL END
+		ORDER BY CASE WHEN $1 THEN r.hidden_at END DESC, open.reported_at, r.id
+		LIMIT $2 OFFSET $3
+	`
+
+	hidden := filters.Status == entities.ReviewModerationHidden
+	rows, err := r.db.QueryContext(ctx, query, hidden, filters.Limit, filters.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews, err := scanReviews(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	queue := make([]entities.ModeratedReview, len(reviews))
+	reviewIDs := make([]int64, len(reviews))
+	index := make(map[int]int, len(reviews))
+	for i := range reviews {
+		queue[i] = entities.ModeratedReview{Review: reviews[i], Reports: []entities.ReviewReport{}}
+		reviewIDs[i] = int64(reviews[i].ID)
+		index[reviews[i].ID] = i
+	}
+	if len(queue) == 0 {
+		return queue, nil
+	}
+
+	reportRows, err := r.db.QueryContext(ctx, `
+		SELECT id, review_id, reporter_id, reason, resolved_at, created_at
+		FROM review_reports
+		WHERE review_id = ANY($1)
+		ORDER BY created_at, id
+	`, pq.Array(reviewIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer reportRows.Close()
+
+	for reportRows.Next() {
+		var report entities.ReviewReport
+		err := reportRows.Scan(
+			&report.ID, &report.ReviewID, &report.ReporterID, &report.Reason, &report.ResolvedAt, &report.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		item := &queue[index[report.ReviewID]]
+		item.Reports = append(item.Reports, report)
+	}
+
+	if err = reportRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return queue, nil
+}
+
+// Hide takes a review off the tutor's profile and resolves its open reports
+func (r *ReviewRepository) Hide(ctx context.Context, reviewID, adminID int, reason string) error {
+	return r.moderate(ctx, reviewID, `
+		UPDATE reviews SET hidden_at = NOW(), hidden_by = $2, hidden_reason = NULLIF($3, '') WHERE id = $1
+	`, reviewID, adminID, reason)
+}
+
+// Restore shows a hidden review again and resolves its open reports, which also dismisses
+// the reports of a review that was never hidden
+func (r *ReviewRepository) Restore(ctx context.Context, reviewID int) error {
+	return r.moderate(ctx, reviewID, `
+		UPDATE reviews SET hidden_at = NULL, hidden_by = NULL, hidden_reason = NULL WHERE id = $1
+	`, reviewID)
+}
+
+// moderate runs a statement on a review, resolves the review's open reports and refreshes
+// the rating stats of the review's tutor
+func (r *ReviewRepository) moderate(ctx context.Context, reviewID int, statement string, args ...interface{}) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, statement, args...)
+	if err != nil {
+		return err
+	}
+	if err := expectReviewRow(result); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE review_reports SET resolved_at = NOW() WHERE review_id = $1 AND resolved_at IS NULL
+	`, reviewID)
+	if err != nil {
+		return err
+	}
+
+	var tutorID int
+	err = tx.QueryRowContext(ctx, `
+		SELECT l.tutor_id FROM reviews r JOIN lessons l ON l.id = r.lesson_id WHERE r.id = $1
+	`, reviewID).Scan(&tutorID)
+	if err != nil {
+		return err
+	}
+	if _, err := refreshTutorStats(ctx, tx, []int{tutorID}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// expectReviewRow fails with ErrReviewNotFound if a statement changed no review
+func expectReviewRow(result sql.Result) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return entities.ErrReviewNotFound
+	}
+	return nil
+}
+
+// scanReviews reads all rows of reviews selected with reviewColumns
+func scanReviews(rows *sql.Rows) ([]entities.Review, error) {
+	reviews := []entities.Review{}
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, *review)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+// scanReview reads a review selected with reviewColumns, with the public details of the reviewer
+func scanReview(row rowScanner) (*entities.Review, error) {
+	var review entities.Review
+	var reply sql.NullString
+	var repliedAt sql.NullTime
+	var pronunciationHelp, preparation, punctuality sql.NullInt64
+	var avatarURLs []byte
+	reviewer := &entities.User{}
+
+	err := row.Scan(
+		&review.ID, &review.LessonID, &review.ReviewerID, &review.TutorID, &review.Rating, &review.Comment,
+		&pronunciationHelp, &preparation, &punctuality,
+		&reply, &repliedAt, &review.HelpfulCount, &review.HiddenAt, &review.HiddenReason, &review.CreatedAt,
+		&reviewer.FirstName, &reviewer.LastName, &reviewer.ProfilePictureURL, &avatarURLs,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	review.Aspects.PronunciationHelp = nullInt(pronunciationHelp)
+	review.Aspects.Preparation = nullInt(preparation)
+	review.Aspects.Punctuality = nullInt(punctuality)
+	if reply.Valid {
+		review.Reply = &entities.ReviewReply{Text: reply.String, RepliedAt: repliedAt.Time}
+	}
+
+	reviewer.ID = review.ReviewerID
+	if err := decodeAvatarURLs(reviewer, avatarURLs); err != nil {
+		return nil, err
+	}
+	review.Reviewer = reviewer
+
+	return &review, nil
+}
+
+// nullInt returns a pointer to the value of a nullable integer column, or nil if it is NULL
+func nullInt(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+	v := int(value.Int64)
+	return &v
+}
+
+// nullFloat returns a pointer to the value of a nullable float column, or nil if it is NULL
+func nullFloat(value sql.NullFloat64) *float64 {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Float64
+}
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"errors"
 	"tongly-backend/internal/entities"
+
+	"github.com/lib/pq"
 )
 
 // StudentRepository handles database operations for student profiles
@@ -104,17 +106,47 @@ func (r *StudentRepository) AddLanguage(ctx context.Context, userID, languageID,
 	return err
 }
 
+// userLanguageColumns lists the user language columns, with the language and proficiency
+// they refer to, in the order expected by scanUserLanguage
+const userLanguageColumns = `ul.user_id, ul.language_id, ul.proficiency_id, ul.created_at,
+	l.id, l.name, l.created_at,
+	lp.id, lp.name, lp.created_at`
+
+// userLanguagesFrom joins the user languages with their language and proficiency
+const userLanguagesFrom = `user_languages ul
+	JOIN languages l ON ul.language_id = l.id
+	JOIN language_proficiency lp ON ul.proficiency_id = lp.id`
+
+// scanUserLanguage scans a row selected with userLanguageColumns into a user language
+func scanUserLanguage(row rowScanner) (entities.UserLanguage, error) {
+	var ul entities.UserLanguage
+	var language entities.Language
+	var proficiency entities.LanguageProficiency
+
+	err := row.Scan(
+		&ul.UserID,
+		&ul.LanguageID,
+		&ul.ProficiencyID,
+		&ul.CreatedAt,
+		&language.ID,
+		&language.Name,
+		&language.CreatedAt,
+		&proficiency.ID,
+		&proficiency.Name,
+		&proficiency.CreatedAt,
+	)
+	if err != nil {
+		return ul, err
+	}
+
+	ul.Language = &language
+	ul.Proficiency = &proficiency
+	return ul, nil
+}
+
 // GetLanguages retrieves all languages for a student
 func (r *StudentRepository) GetLanguages(ctx context.Context, userID int) ([]entities.UserLanguage, error) {
-	query := `
-		SELECT ul.user_id, ul.language_id, ul.proficiency_id, ul.created_at,
-		       l.id, l.name, l.created_at,
-		       lp.id, lp.name, lp.created_at
-		FROM user_languages ul
-		JOIN languages l ON ul.language_id = l.id
-		JOIN language_proficiency lp ON ul.proficiency_id = lp.id
-		WHERE ul.user_id = $1
-	`
+	query := `SELECT ` + userLanguageColumns + ` FROM ` + userLanguagesFrom + ` WHERE ul.user_id = $1`
 
 	rows, err := r.db.QueryContext(ctx, query, userID)
 	if err != nil {
@@ -124,28 +156,10 @@ func (r *StudentRepository) GetLanguages(ctx context.Context, userID int) ([]ent
 
 	var languages []entities.UserLanguage
 	for rows.Next() {
-		var ul entities.UserLanguage
-		var language entities.Language
-		var proficiency entities.LanguageProficiency
-
-		err := rows.Scan(
-			&ul.UserID,
-			&ul.LanguageID,
-			&ul.ProficiencyID,
-			&ul.CreatedAt,
-			&language.ID,
-			&language.Name,
-			&language.CreatedAt,
-			&proficiency.ID,
-			&proficiency.Name,
-			&proficiency.CreatedAt,
-		)
+		ul, err := scanUserLanguage(rows)
 		if err != nil {
 			return nil, err
 		}
-
-		ul.Language = &language
-		ul.Proficiency = &proficiency
 		languages = append(languages, ul)
 	}
 
@@ -156,6 +170,33 @@ func (r *StudentRepository) GetLanguages(ctx context.Context, userID int) ([]ent
 	return languages, nil
 }
 
+// GetLanguagesByUserIDs retrieves the languages of several users in a single query, keyed
+// by user ID
+func (r *StudentRepository) GetLanguagesByUserIDs(ctx context.Context, userIDs []int) (map[int][]entities.UserLanguage, error) {
+	languages := make(map[int][]entities.UserLanguage, len(userIDs))
+	if len(userIDs) == 0 {
+		return languages, nil
+	}
+
+	query := `SELECT ` + userLanguageColumns + ` FROM ` + userLanguagesFrom + ` WHERE ul.user_id = ANY($1)`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(userIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		ul, err := scanUserLanguage(rows)
+		if err != nil {
+			return nil, err
+		}
+		languages[ul.UserID] = append(languages[ul.UserID], ul)
+	}
+
+	return languages, rows.Err()
+}
+
 // RemoveLanguage removes a language from a student's profile
 func (r *StudentRepository) RemoveLanguage(ctx context.Context, userID, languageID int) error {
 	query := `DELETE FROM user_languages WHERE user_id = $1 AND language_id = $2`
@@ -288,3 +329,84 @@ func (r *StudentRepository) RemoveGoal(ctx context.Context, userID, goalID int)
 	_, err := r.db.ExecContext(ctx, query, userID, goalID)
 	return err
 }
+
+// GetPreferences retrieves what a student would like from their lessons. A student without a
+// profile gets the defaults.
+func (r *StudentRepository) GetPreferences(ctx context.Context, studentID int) (*entities.StudentPreferences, error) {
+	prefs := &entities.StudentPreferences{TimeZone: "UTC", PreferredTimes: []entities.PreferredTime{}}
+
+	var minPrice, maxPrice sql.NullFloat64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT timezone, min_price, max_price
+		FROM student_profiles
+		WHERE user_id = $1
+	`, studentID).Scan(&prefs.TimeZone, &minPrice, &maxPrice)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if minPrice.Valid {
+		prefs.MinPrice = &minPrice.Float64
+	}
+	if maxPrice.Valid {
+		prefs.MaxPrice = &maxPrice.Float64
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT day_of_week, to_char(start_time, 'HH24:MI'), to_char(end_time, 'HH24:MI')
+		FROM student_preferred_times
+		WHERE student_id = $1
+		ORDER BY day_of_week, start_time
+	`, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t entities.PreferredTime
+		if err := rows.Scan(&t.DayOfWeek, &t.StartTime, &t.EndTime); err != nil {
+			return nil, err
+		}
+		prefs.PreferredTimes = append(prefs.PreferredTimes, t)
+	}
+
+	return prefs, rows.Err()
+}
+
+// SavePreferences replaces what a student would like from their lessons
+func (r *StudentRepository) SavePreferences(ctx context.Context, studentID int, prefs *entities.StudentPreferences) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE student_profiles
+		SET timezone = $1, min_price = $2, max_price = $3, updated_at = NOW()
+		WHERE user_id = $4
+	`, prefs.TimeZone, prefs.MinPrice, prefs.MaxPrice, studentID)
+	if err != nil {
+		return err
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return err
+	} else if rows == 0 {
+		return entities.ErrNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM student_preferred_times WHERE student_id = $1`, studentID); err != nil {
+		return err
+	}
+	for _, t := range prefs.PreferredTimes {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO student_preferred_times (student_id, day_of_week, start_time, end_time)
+			VALUES ($1, $2, $3, $4)
+		`, studentID, t.DayOfWeek, t.StartTime, t.EndTime)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -7,11 +7,153 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 	"tongly-backend/internal/entities"
 
 	"github.com/lib/pq"
 )
 
+// Ratings are ranked by a Bayesian average: every tutor starts with ratingPriorWeight
+// reviews' worth of the mean of all reviews, so a single 5-star review does not outrank
+// hundreds of 4.9s. The recent score additionally halves a review's weight every
+// ratingHalfLifeDays. Migration 017 backfilled tutor_stats with the same values.
+const (
+	ratingPriorWeight   = 10
+	ratingPriorFallback = 4.5 // Prior mean while nobody has written a review yet
+	ratingHalfLifeDays  = 180
+)
+
+// refreshTutorStatsQuery recomputes the rating stats of the tutors in $1, or of every tutor
+// when $1 is NULL
+const refreshTutorStatsQuery = `
+	WITH prior AS (
+		SELECT COALESCE(AVG(rating)::float8, $2::float8) AS mean
+		FROM reviews
+		WHERE hidden_at IS NULL
+	),
+	visible AS (
+		SELECT l.tutor_id, r.rating, r.created_at,
+		       POWER(0.5, EXTRACT(EPOCH FROM NOW() - r.created_at)::float8 / 86400 / $4::float8) AS weight
+		FROM reviews r
+		JOIN lessons l ON l.id = r.lesson_id
+		WHERE r.hidden_at IS NULL AND ($1::int[] IS NULL OR l.tutor_id = ANY($1))
+	)
+	INSERT INTO tutor_stats (tutor_id, reviews_count, average_rating, bayesian_score, recent_score, last_review_at, updated_at)
+	SELECT tp.user_id,
+	       COUNT(v.rating),
+	       COALESCE(AVG(v.rating)::float8, 0),
+	       (p.mean * $3::float8 + COALESCE(SUM(v.rating), 0)) / ($3::float8 + COUNT(v.rating)),
+	       (p.mean * $3::float8 + COALESCE(SUM(v.rating * v.weight), 0)) / ($3::float8 + COALESCE(SUM(v.weight), 0)),
+	       MAX(v.created_at),
+	       NOW()
+	FROM tutor_profiles tp
+	CROSS JOIN prior p
+	LEFT JOIN visible v ON v.tutor_id = tp.user_id
+	WHERE $1::int[] IS NULL OR tp.user_id = ANY($1)
+	GROUP BY tp.user_id, p.mean
+	ON CONFLICT (tutor_id) DO UPDATE SET
+		reviews_count = EXCLUDED.reviews_count,
+		average_rating = EXCLUDED.average_rating,
+		bayesian_score = EXCLUDED.bayesian_score,
+		recent_score = EXCLUDED.recent_score,
+		last_review_at = EXCLUDED.last_review_at,
+		updated_at = EXCLUDED.updated_at
+`
+
+// A free-text tutor search ranks by how well a tutor matches, blended with their recent
+// rating score scaled to the 0-1 range of the match
+const (
+	searchRelevanceWeight = 0.7
+	searchRatingWeight    = 0.3
+)
+
+// tutorSearchQueryFormat turns the search text in the numbered argument into a query in
+// every configuration the search vector is built with
+const tutorSearchQueryFormat = `(websearch_to_tsquery('russian', $%[1]d) || websearch_to_tsquery('english', $%[1]d) || websearch_to_tsquery('simple', $%[1]d))`
+
+// tutorTimeWindowFormat matches tutors whose weekly availability overlaps the hours a student
+// asked for by at least a slot on any of the days they asked for. Both are laid out over the
+// coming week, the availability in the tutor's time zone and the hours in the student's, and
+// compared as absolute times. Arguments: the student's time zone, the days, the start of the
+// hours and their length in minutes, and the slot length in minutes.
+const tutorTimeWindowFormat = `EXISTS (
+	SELECT 1
+	FROM tutor_availability a,
+	     generate_series(-1, 7) AS ad(n),
+	     generate_series(0, 6) AS wd(n),
+	     LATERAL (SELECT
+	         ((NOW() AT TIME ZONE tp.timezone)::date + ad.n + a.start_time) AT TIME ZONE tp.timezone AS start_at,
+	         ((NOW() AT TIME ZONE tp.timezone)::date + ad.n + a.end_time) AT TIME ZONE tp.timezone AS end_at,
+	         EXTRACT(DOW FROM (NOW() AT TIME ZONE tp.timezone)::date + ad.n)::int AS day_of_week
+	     ) available,
+	     LATERAL (SELECT
+	         ((NOW() AT TIME ZONE $%[1]d)::date + wd.n + make_interval(mins => $%[3]d::int)) AT TIME ZONE $%[1]d AS start_at,
+	         ((NOW() AT TIME ZONE $%[1]d)::date + wd.n + make_interval(mins => $%[3]d::int + $%[4]d::int)) AT TIME ZONE $%[1]d AS end_at,
+	         EXTRACT(DOW FROM (NOW() AT TIME ZONE $%[1]d)::date + wd.n)::int AS day_of_week
+	     ) wanted
+	WHERE a.tutor_id = tp.user_id AND a.is_recurring IS NOT FALSE
+	  AND available.day_of_week = a.day_of_week
+	  AND wanted.day_of_week = ANY($%[2]d::int[])
+	  AND LEAST(available.end_at, wanted.end_at) - GREATEST(available.start_at, wanted.start_at) >= make_interval(mins => $%[5]d::int)
+)`
+
+// tutorOpenSlotFormat matches tutors with a slot free of lessons and busy times in their
+// availability within the coming days. A date with one-off availability uses only that, like
+// the booking calendar does. Slots start every slot length from the start of an availability.
+// Arguments: the number of days and the slot length in minutes.
+const tutorOpenSlotFormat = `EXISTS (
+	SELECT 1
+	FROM tutor_availability a,
+	     generate_series(0, $%[1]d::int) AS ad(n),
+	     LATERAL (SELECT (NOW() AT TIME ZONE tp.timezone)::date + ad.n AS day) tutor_day,
+	     LATERAL generate_series(
+	         (tutor_day.day + a.start_time) AT TIME ZONE tp.timezone,
+	         (tutor_day.day + a.end_time) AT TIME ZONE tp.timezone - make_interval(mins => $%[2]d::int),
+	         make_interval(mins => $%[2]d::int)
+	     ) AS slot(start_at)
+	WHERE a.tutor_id = tp.user_id
+	  AND CASE WHEN a.is_recurring IS NOT FALSE
+	      THEN a.day_of_week = EXTRACT(DOW FROM tutor_day.day)::int AND NOT EXISTS (
+	          SELECT 1 FROM tutor_availability s
+	          WHERE s.tutor_id = a.tutor_id AND s.is_recurring IS FALSE AND s.specific_date = tutor_day.day
+	      )
+	      ELSE a.specific_date = tutor_day.day
+	      END
+	  AND slot.start_at > NOW()
+	  AND slot.start_at < NOW() + make_interval(days => $%[1]d::int)
+	  AND NOT EXISTS (
+	      SELECT 1 FROM lessons l
+	      WHERE l.tutor_id = tp.user_id AND l.cancelled_at IS NULL
+	        AND l.start_time < (slot.start_at + make_interval(mins => $%[2]d::int)) AT TIME ZONE 'UTC'
+	        AND l.end_time > slot.start_at AT TIME ZONE 'UTC'
+	  )
+	  AND NOT EXISTS (
+	      SELECT 1 FROM tutor_busy_times b
+	      WHERE b.tutor_id = tp.user_id
+	        AND b.start_time < (slot.start_at + make_interval(mins => $%[2]d::int)) AT TIME ZONE 'UTC'
+	        AND b.end_time > slot.start_at AT TIME ZONE 'UTC'
+	  )
+)`
+
+// availabilitySlotMinutes is the shortest lesson, which an open slot or working hours must fit
+var availabilitySlotMinutes = int(entities.TrialLessonDuration / time.Minute)
+
+// execer is implemented by both *sql.DB and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// refreshTutorStats recomputes the cached rating stats of the given tutors, or of every tutor
+// when tutorIDs is nil, and returns how many tutors were refreshed
+func refreshTutorStats(ctx context.Context, db execer, tutorIDs []int) (int64, error) {
+	result, err := db.ExecContext(ctx, refreshTutorStatsQuery,
+		pq.Array(tutorIDs), ratingPriorFallback, ratingPriorWeight, ratingHalfLifeDays)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 // TutorRepository handles database operations for tutor profiles
 type TutorRepository struct {
 	db *sql.DB
@@ -24,22 +166,28 @@ func NewTutorRepository(db *sql.DB) *TutorRepository {
 	}
 }
 
-// Create inserts a new tutor profile into the database
+// Create inserts a new tutor profile into the database, with rating stats at the prior so
+// that the tutor ranks among the others from the start
 func (r *TutorRepository) Create(ctx context.Context, tutorProfile *entities.TutorProfile) error {
-	// Convert education to JSONB
-	educationJSON, err := json.Marshal(tutorProfile.Education)
+	educationJSON, err := marshalEducation(tutorProfile.Education)
 	if err != nil {
 		return err
 	}
 
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `
 		INSERT INTO tutor_profiles
-		(user_id, bio, education, intro_video_url, years_experience)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING created_at, updated_at
+		(user_id, bio, education, intro_video_url, years_experience, hourly_rate, offers_trial, trial_price, timezone)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, COALESCE(NULLIF($9, ''), 'UTC'))
+		RETURNING timezone, created_at, updated_at
 	`
 
-	err = r.db.QueryRowContext(
+	err = tx.QueryRowContext(
 		ctx,
 		query,
 		tutorProfile.UserID,
@@ -47,62 +195,49 @@ func (r *TutorRepository) Create(ctx context.Context, tutorProfile *entities.Tut
 		educationJSON,
 		tutorProfile.IntroVideoURL,
 		tutorProfile.YearsExperience,
-	).Scan(&tutorProfile.CreatedAt, &tutorProfile.UpdatedAt)
+		tutorProfile.HourlyRate,
+		tutorProfile.OffersTrial,
+		tutorProfile.TrialPrice,
+		tutorProfile.TimeZone,
+	).Scan(&tutorProfile.TimeZone, &tutorProfile.CreatedAt, &tutorProfile.UpdatedAt)
+	if err != nil {
+		return err
+	}
 
-	return err
+	if _, err := refreshTutorStats(ctx, tx, []int{tutorProfile.UserID}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 // GetByUserID retrieves a tutor profile by user ID
 func (r *TutorRepository) GetByUserID(ctx context.Context, userID int) (*entities.TutorProfile, error) {
-	query := `
-		SELECT user_id, bio, education, intro_video_url, years_experience, created_at, updated_at
-		FROM tutor_profiles
-		WHERE user_id = $1
-	`
+	query := `SELECT ` + tutorListColumns + tutorSearchFrom + ` WHERE tp.user_id = $1`
 
-	var educationJSON []byte
 	profile := &entities.TutorProfile{}
-	err := r.db.QueryRowContext(ctx, query, userID).Scan(
-		&profile.UserID,
-		&profile.Bio,
-		&educationJSON,
-		&profile.IntroVideoURL,
-		&profile.YearsExperience,
-		&profile.CreatedAt,
-		&profile.UpdatedAt,
-	)
-
-	if err != nil {
+	if err := scanTutorListRow(r.db.QueryRowContext(ctx, query, userID), profile); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, err
 	}
 
-	// Parse education JSON
-	if educationJSON != nil {
-		var education interface{}
-		if err := json.Unmarshal(educationJSON, &education); err != nil {
-			return nil, err
-		}
-		profile.Education = education
-	}
-
 	return profile, nil
 }
 
 // Update updates a tutor profile
 func (r *TutorRepository) Update(ctx context.Context, tutorProfile *entities.TutorProfile) error {
-	// Convert education to JSONB
-	educationJSON, err := json.Marshal(tutorProfile.Education)
+	educationJSON, err := marshalEducation(tutorProfile.Education)
 	if err != nil {
 		return err
 	}
 
 	query := `
 		UPDATE tutor_profiles
-		SET bio = $1, education = $2, intro_video_url = $3, years_experience = $4
-		WHERE user_id = $5
+		SET bio = $1, education = $2, intro_video_url = $3, years_experience = $4, hourly_rate = $5,
+		    offers_trial = $6, trial_price = $7, timezone = $8
+		WHERE user_id = $9
 		RETURNING updated_at
 	`
 
@@ -113,10 +248,109 @@ func (r *TutorRepository) Update(ctx context.Context, tutorProfile *entities.Tut
 		educationJSON,
 		tutorProfile.IntroVideoURL,
 		tutorProfile.YearsExperience,
+		tutorProfile.HourlyRate,
+		tutorProfile.OffersTrial,
+		tutorProfile.TrialPrice,
+		tutorProfile.TimeZone,
 		tutorProfile.UserID,
 	).Scan(&tutorProfile.UpdatedAt)
 }
 
+// AddEducation appends an entry to a tutor's education, failing with
+// ErrTooManyEducationEntries when the tutor has too many, and returns the updated entries
+func (r *TutorRepository) AddEducation(ctx context.Context, tutorID int, education *entities.Education) ([]entities.Education, error) {
+	entryJSON, err := json.Marshal(education)
+	if err != nil {
+		return nil, err
+	}
+
+	// The length check and the append happen in one statement, so concurrent additions
+	// cannot exceed the limit
+	query := `
+		UPDATE tutor_profiles
+		SET education = education || jsonb_build_array($2::jsonb)
+		WHERE user_id = $1 AND jsonb_array_length(education) < $3
+		RETURNING education
+	`
+
+	var educationJSON []byte
+	err = r.db.QueryRowContext(ctx, query, tutorID, entryJSON, entities.MaxEducationEntries).Scan(&educationJSON)
+	if err == sql.ErrNoRows {
+		return nil, entities.ErrTooManyEducationEntries
+	}
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalEducation(educationJSON)
+}
+
+// UpdateEducation replaces the entry at a position of a tutor's education and returns the
+// updated entries
+func (r *TutorRepository) UpdateEducation(ctx context.Context, tutorID, index int, education *entities.Education) ([]entities.Education, error) {
+	entryJSON, err := json.Marshal(education)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE tutor_profiles
+		SET education = jsonb_set(education, ARRAY[$2::int::text], $3::jsonb)
+		WHERE user_id = $1 AND $2::int < jsonb_array_length(education)
+		RETURNING education
+	`
+
+	var educationJSON []byte
+	err = r.db.QueryRowContext(ctx, query, tutorID, index, entryJSON).Scan(&educationJSON)
+	if err == sql.ErrNoRows {
+		return nil, entities.ErrEducationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalEducation(educationJSON)
+}
+
+// RemoveEducation removes the entry at a position of a tutor's education and returns the
+// remaining entries
+func (r *TutorRepository) RemoveEducation(ctx context.Context, tutorID, index int) ([]entities.Education, error) {
+	query := `
+		UPDATE tutor_profiles
+		SET education = education - $2::int
+		WHERE user_id = $1 AND $2::int < jsonb_array_length(education)
+		RETURNING education
+	`
+
+	var educationJSON []byte
+	err := r.db.QueryRowContext(ctx, query, tutorID, index).Scan(&educationJSON)
+	if err == sql.ErrNoRows {
+		return nil, entities.ErrEducationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalEducation(educationJSON)
+}
+
+// marshalEducation converts education entries to the JSONB array stored in tutor_profiles
+func marshalEducation(education []entities.Education) ([]byte, error) {
+	if education == nil {
+		education = []entities.Education{}
+	}
+	return json.Marshal(education)
+}
+
+// unmarshalEducation reads the education entries stored in tutor_profiles
+func unmarshalEducation(educationJSON []byte) ([]entities.Education, error) {
+	education := []entities.Education{}
+	if len(educationJSON) == 0 {
+		return education, nil
+	}
+	if err := json.Unmarshal(educationJSON, &education); err != nil {
+		return nil, err
+	}
+	return education, nil
+}
+
 // Delete deletes a tutor profile
 func (r *TutorRepository) Delete(ctx context.Context, userID int) error {
 	query := `DELETE FROM tutor_profiles WHERE user_id = $1`
@@ -224,144 +458,404 @@ func (r *TutorRepository) DeleteAvailability(ctx context.Context, availabilityID
 	return err
 }
 
-// SearchTutors searches for tutors based on filters
+// tutorFacet is a filter whose options the search facets count
+type tutorFacet int
+
+const (
+	noFacet tutorFacet = iota
+	languageFacet
+	interestFacet
+	proficiencyFacet
+)
+
+// tutorSearchFrom is the FROM clause every tutor search query filters
+const tutorSearchFrom = `
+	FROM tutor_profiles tp
+	JOIN users u ON tp.user_id = u.id
+	LEFT JOIN tutor_stats ts ON ts.tutor_id = tp.user_id
+`
+
+// tutorListColumns lists the columns of the tutors in search results, in the order expected
+// by scanTutorListRow. It selects from tutorSearchFrom.
+const tutorListColumns = `tp.user_id, tp.bio, tp.education, tp.intro_video_url, tp.years_experience, tp.hourly_rate,
+	tp.offers_trial, tp.trial_price, tp.timezone, tp.created_at, tp.updated_at,
+	COALESCE(ts.average_rating, 0), COALESCE(ts.reviews_count, 0),
+	COALESCE(ts.bayesian_score, 0), COALESCE(ts.recent_score, 0),
+	` + tutorVerifiedCondition
+
+// tutorVerifiedCondition holds when an admin verified one of the credentials of the tutor tp
+const tutorVerifiedCondition = `EXISTS (SELECT 1 FROM tutor_credentials tc WHERE tc.tutor_id = tp.user_id AND tc.status = 'verified')`
+
+// scanTutorListRow scans a row selected with tutorListColumns into a tutor, and any columns
+// selected after them into extra
+func scanTutorListRow(row rowScanner, tutor *entities.TutorProfile, extra ...interface{}) error {
+	var educationJSON []byte
+	dest := []interface{}{
+		&tutor.UserID,
+		&tutor.Bio,
+		&educationJSON,
+		&tutor.IntroVideoURL,
+		&tutor.YearsExperience,
+		&tutor.HourlyRate,
+		&tutor.OffersTrial,
+		&tutor.TrialPrice,
+		&tutor.TimeZone,
+		&tutor.CreatedAt,
+		&tutor.UpdatedAt,
+		&tutor.Rating,
+		&tutor.ReviewsCount,
+		&tutor.RatingScore,
+		&tutor.RecentRatingScore,
+		&tutor.IsVerified,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+
+	education, err := unmarshalEducation(educationJSON)
+	if err != nil {
+		return err
+	}
+	tutor.Education = education
+	return nil
+}
+
+// SearchTutors retrieves a page of the tutors matching the filters in the order they ask for
 func (r *TutorRepository) SearchTutors(ctx context.Context, filters *entities.TutorSearchFilters) ([]entities.TutorProfile, error) {
-	// Base query to get all tutors
-	baseQuery := `
-		SELECT tp.user_id, tp.bio, tp.education, tp.intro_video_url, tp.years_experience, tp.created_at, tp.updated_at
-		FROM tutor_profiles tp
-		JOIN users u ON tp.user_id = u.id
-	`
+	whereClause, args := tutorSearchWhere(filters, noFacet)
+	orderClause, args := tutorSearchOrder(filters, args)
+	args = append(args, filters.Limit, filters.Offset)
+	pageClause := fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)-1, len(args))
 
-	// Additional filters
-	var conditions []string
-	var args []interface{}
-	var argCounter int
+	query := `SELECT ` + tutorListColumns + tutorSearchFrom + whereClause + orderClause + pageClause
 
-	// Add a WHERE clause if we have any filters
-	whereClause := ""
-	orderClause := " ORDER BY tp.created_at DESC"
-
-	// Process filters if provided
-	if filters != nil {
-		// Filter by languages
-		if len(filters.Languages) > 0 {
-			argCounter++
-			conditions = append(conditions, fmt.Sprintf("tp.user_id IN (SELECT user_id FROM user_languages WHERE language_id IN (SELECT id FROM languages WHERE name = ANY($%d)))", argCounter))
-			args = append(args, pq.Array(filters.Languages))
-		}
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("database query error: %w", err)
+	}
+	defer rows.Close()
 
-		// Filter by years of experience
-		if filters.YearsExperience > 0 {
-			argCounter++
-			conditions = append(conditions, fmt.Sprintf("tp.years_experience >= $%d", argCounter))
-			args = append(args, filters.YearsExperience)
+	tutors := []entities.TutorProfile{}
+	for rows.Next() {
+		var tutor entities.TutorProfile
+		if err := scanTutorListRow(rows, &tutor); err != nil {
+			return nil, err
 		}
+		tutors = append(tutors, tutor)
+	}
 
-		// Filter by proficiency level
-		if filters.ProficiencyID > 0 {
-			argCounter++
-			conditions = append(conditions, fmt.Sprintf("tp.user_id IN (SELECT user_id FROM user_languages WHERE proficiency_id >= $%d)", argCounter))
-			args = append(args, filters.ProficiencyID)
-		}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
 
-		// Filter by interests
-		if len(filters.Interests) > 0 {
-			argCounter++
-			conditions = append(conditions, fmt.Sprintf("tp.user_id IN (SELECT user_id FROM user_interests WHERE interest_id = ANY($%d))", argCounter))
-			args = append(args, pq.Array(filters.Interests))
-		}
+	return tutors, nil
+}
 
-		// Filter by goals
-		if len(filters.Goals) > 0 {
-			argCounter++
-			conditions = append(conditions, fmt.Sprintf("tp.user_id IN (SELECT user_id FROM user_goals WHERE goal_id = ANY($%d))", argCounter))
-			args = append(args, pq.Array(filters.Goals))
-		}
+// GetRecommendationCandidates retrieves the best rated tutors who speak any of the given
+// languages, or any tutors without languages, with the interests, goals, languages and weekly
+// availability recommendations compare with the student's, in a single query
+func (r *TutorRepository) GetRecommendationCandidates(ctx context.Context, languageIDs []int, limit int) ([]entities.RecommendationCandidate, error) {
+	var languageFilter interface{}
+	if len(languageIDs) > 0 {
+		languageFilter = pq.Array(languageIDs)
+	}
 
-		// Filter by age (minimum)
-		if filters.MinAge > 0 {
-			argCounter++
-			conditions = append(conditions, fmt.Sprintf("u.age >= $%d", argCounter))
-			args = append(args, filters.MinAge)
-		}
+	query := `SELECT ` + tutorListColumns + `,
+		       ARRAY(SELECT interest_id FROM user_interests WHERE user_id = tp.user_id ORDER BY interest_id),
+		       ARRAY(SELECT goal_id FROM user_goals WHERE user_id = tp.user_id ORDER BY goal_id),
+		       ARRAY(SELECT language_id FROM user_languages WHERE user_id = tp.user_id ORDER BY language_id),
+		       ARRAY(SELECT proficiency_id FROM user_languages WHERE user_id = tp.user_id ORDER BY language_id),
+		       ARRAY(SELECT day_of_week FROM tutor_availability a
+		             WHERE a.tutor_id = tp.user_id AND a.is_recurring IS NOT FALSE ORDER BY a.id),
+		       ARRAY(SELECT to_char(start_time, 'HH24:MI') FROM tutor_availability a
+		             WHERE a.tutor_id = tp.user_id AND a.is_recurring IS NOT FALSE ORDER BY a.id),
+		       ARRAY(SELECT to_char(end_time, 'HH24:MI') FROM tutor_availability a
+		             WHERE a.tutor_id = tp.user_id AND a.is_recurring IS NOT FALSE ORDER BY a.id)
+	` + tutorSearchFrom + `
+		WHERE $1::int[] IS NULL OR EXISTS (
+			SELECT 1 FROM user_languages ul WHERE ul.user_id = tp.user_id AND ul.language_id = ANY($1)
+		)
+		ORDER BY COALESCE(ts.bayesian_score, 0) DESC, tp.user_id
+		LIMIT $2
+	`
 
-		// Filter by age (maximum)
-		if filters.MaxAge > 0 {
-			argCounter++
-			conditions = append(conditions, fmt.Sprintf("u.age <= $%d", argCounter))
-			args = append(args, filters.MaxAge)
+	rows, err := r.db.QueryContext(ctx, query, languageFilter, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	candidates := []entities.RecommendationCandidate{}
+	for rows.Next() {
+		var candidate entities.RecommendationCandidate
+		var interestIDs, goalIDs, languageIDs, proficiencyIDs, days pq.Int64Array
+		var startTimes, endTimes pq.StringArray
+
+		err := scanTutorListRow(rows, &candidate.Tutor,
+			&interestIDs, &goalIDs, &languageIDs, &proficiencyIDs, &days, &startTimes, &endTimes)
+		if err != nil {
+			return nil, err
 		}
 
-		// Filter by sex
-		if filters.Sex != "" {
-			argCounter++
-			conditions = append(conditions, fmt.Sprintf("u.sex = $%d", argCounter))
-			args = append(args, filters.Sex)
+		candidate.InterestIDs = intsOf(interestIDs)
+		candidate.GoalIDs = intsOf(goalIDs)
+		for i := range languageIDs {
+			candidate.Languages = append(candidate.Languages, entities.UserLanguage{
+				UserID:        candidate.Tutor.UserID,
+				LanguageID:    int(languageIDs[i]),
+				ProficiencyID: int(proficiencyIDs[i]),
+			})
+		}
+		for i := range days {
+			candidate.Availability = append(candidate.Availability, entities.TutorAvailability{
+				TutorID:     candidate.Tutor.UserID,
+				DayOfWeek:   int(days[i]),
+				StartTime:   startTimes[i],
+				EndTime:     endTimes[i],
+				IsRecurring: true,
+			})
 		}
+		candidates = append(candidates, candidate)
 	}
 
-	// Combine all conditions
-	if len(conditions) > 0 {
-		whereClause = " WHERE " + strings.Join(conditions, " AND ")
-	}
+	return candidates, rows.Err()
+}
 
-	// Build the final query
-	finalQuery := baseQuery + whereClause + orderClause
+// intsOf converts a scanned integer array
+func intsOf(values pq.Int64Array) []int {
+	ints := make([]int, len(values))
+	for i, value := range values {
+		ints[i] = int(value)
+	}
+	return ints
+}
 
-	// Log the generated SQL and args for debugging
-	fmt.Printf("SQL Query: %s\n", finalQuery)
-	fmt.Printf("Args: %+v\n", args)
+// CountTutors counts all tutors matching the filters
+func (r *TutorRepository) CountTutors(ctx context.Context, filters *entities.TutorSearchFilters) (int, error) {
+	whereClause, args := tutorSearchWhere(filters, noFacet)
 
-	// Execute the query
-	var rows *sql.Rows
-	var err error
+	var total int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*)`+tutorSearchFrom+whereClause, args...).Scan(&total)
+	return total, err
+}
 
-	if len(args) == 0 {
-		rows, err = r.db.QueryContext(ctx, finalQuery)
-	} else {
-		rows, err = r.db.QueryContext(ctx, finalQuery, args...)
+// GetSearchFacets counts how many tutors each language, interest and proficiency option
+// yields. Every facet applies all filters except its own, so the counts of the options
+// next to a selected one show what adding them to the selection would yield.
+func (r *TutorRepository) GetSearchFacets(ctx context.Context, filters *entities.TutorSearchFilters) (*entities.TutorSearchFacets, error) {
+	facets := &entities.TutorSearchFacets{}
+
+	whereClause, args := tutorSearchWhere(filters, languageFacet)
+	languages, err := r.countFacet(ctx, `
+		SELECT l.id, l.name, COUNT(DISTINCT tp.user_id)
+	`+tutorSearchFrom+`
+		JOIN user_languages fl ON fl.user_id = tp.user_id
+		JOIN languages l ON l.id = fl.language_id
+	`+whereClause+`
+		GROUP BY l.id, l.name
+		ORDER BY l.name
+	`, args)
+	if err != nil {
+		return nil, err
+	}
+	facets.Languages = languages
+
+	whereClause, args = tutorSearchWhere(filters, interestFacet)
+	interests, err := r.countFacet(ctx, `
+		SELECT i.id, i.name, COUNT(DISTINCT tp.user_id)
+	`+tutorSearchFrom+`
+		JOIN user_interests fi ON fi.user_id = tp.user_id
+		JOIN interests i ON i.id = fi.interest_id
+	`+whereClause+`
+		GROUP BY i.id, i.name
+		ORDER BY i.name
+	`, args)
+	if err != nil {
+		return nil, err
 	}
+	facets.Interests = interests
+
+	// The proficiency filter is a minimum, so an option counts every tutor who speaks a
+	// language at that level or above
+	whereClause, args = tutorSearchWhere(filters, proficiencyFacet)
+	proficiencies, err := r.countFacet(ctx, `
+		SELECT p.id, p.name, COUNT(DISTINCT tp.user_id)
+	`+tutorSearchFrom+`
+		JOIN user_languages fp ON fp.user_id = tp.user_id
+		JOIN language_proficiency p ON p.id <= fp.proficiency_id
+	`+whereClause+`
+		GROUP BY p.id, p.name
+		ORDER BY p.id
+	`, args)
+	if err != nil {
+		return nil, err
+	}
+	facets.Proficiencies = proficiencies
 
+	return facets, nil
+}
+
+// countFacet runs a query selecting the ID, name and tutor count of facet options
+func (r *TutorRepository) countFacet(ctx context.Context, query string, args []interface{}) ([]entities.FacetCount, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		fmt.Printf("Query error: %v\n", err)
-		return nil, fmt.Errorf("database query error: %w", err)
+		return nil, err
 	}
 	defer rows.Close()
 
-	var tutors []entities.TutorProfile
+	counts := []entities.FacetCount{}
 	for rows.Next() {
-		var tutor entities.TutorProfile
-		var educationJSON []byte
-
-		err := rows.Scan(
-			&tutor.UserID,
-			&tutor.Bio,
-			&educationJSON,
-			&tutor.IntroVideoURL,
-			&tutor.YearsExperience,
-			&tutor.CreatedAt,
-			&tutor.UpdatedAt,
-		)
-		if err != nil {
+		var count entities.FacetCount
+		if err := rows.Scan(&count.ID, &count.Name, &count.Count); err != nil {
 			return nil, err
 		}
+		counts = append(counts, count)
+	}
 
-		// Parse education JSON
-		if educationJSON != nil {
-			var education interface{}
-			if err := json.Unmarshal(educationJSON, &education); err != nil {
-				return nil, err
-			}
-			tutor.Education = education
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// tutorSearchWhere builds the WHERE clause of a tutor search over tutorSearchFrom and its
+// arguments, leaving out the filter of the given facet
+func tutorSearchWhere(filters *entities.TutorSearchFilters, except tutorFacet) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	var argCounter int
+
+	// Filter by languages
+	if len(filters.Languages) > 0 && except != languageFacet {
+		argCounter++
+		conditions = append(conditions, fmt.Sprintf("tp.user_id IN (SELECT user_id FROM user_languages WHERE language_id IN (SELECT id FROM languages WHERE name = ANY($%d)))", argCounter))
+		args = append(args, pq.Array(filters.Languages))
+	}
+
+	// Filter by years of experience
+	if filters.YearsExperience > 0 {
+		argCounter++
+		conditions = append(conditions, fmt.Sprintf("tp.years_experience >= $%d", argCounter))
+		args = append(args, filters.YearsExperience)
+	}
+
+	// Filter by proficiency level
+	if filters.ProficiencyID > 0 && except != proficiencyFacet {
+		argCounter++
+		conditions = append(conditions, fmt.Sprintf("tp.user_id IN (SELECT user_id FROM user_languages WHERE proficiency_id >= $%d)", argCounter))
+		args = append(args, filters.ProficiencyID)
+	}
+
+	// Filter by interests
+	if len(filters.Interests) > 0 && except != interestFacet {
+		argCounter++
+		conditions = append(conditions, fmt.Sprintf("tp.user_id IN (SELECT user_id FROM user_interests WHERE interest_id = ANY($%d))", argCounter))
+		args = append(args, pq.Array(filters.Interests))
+	}
+
+	// Filter by goals
+	if len(filters.Goals) > 0 {
+		argCounter++
+		conditions = append(conditions, fmt.Sprintf("tp.user_id IN (SELECT user_id FROM user_goals WHERE goal_id = ANY($%d))", argCounter))
+		args = append(args, pq.Array(filters.Goals))
+	}
+
+	// Filter by age (minimum)
+	if filters.MinAge > 0 {
+		argCounter++
+		conditions = append(conditions, fmt.Sprintf("u.age >= $%d", argCounter))
+		args = append(args, filters.MinAge)
+	}
+
+	// Filter by age (maximum)
+	if filters.MaxAge > 0 {
+		argCounter++
+		conditions = append(conditions, fmt.Sprintf("u.age <= $%d", argCounter))
+		args = append(args, filters.MaxAge)
+	}
+
+	// Filter by sex
+	if filters.Sex != "" {
+		argCounter++
+		conditions = append(conditions, fmt.Sprintf("u.sex = $%d", argCounter))
+		args = append(args, filters.Sex)
+	}
+
+	// Filter by tutors who offer trial lessons
+	if filters.OffersTrial {
+		conditions = append(conditions, "tp.offers_trial")
+	}
+
+	// Filter by tutors with a verified credential
+	if filters.VerifiedOnly {
+		conditions = append(conditions, tutorVerifiedCondition)
+	}
+
+	// Filter by the days and hours the tutor works
+	if filters.HasTimeWindow() {
+		days := filters.Days
+		if len(days) == 0 {
+			days = []int{0, 1, 2, 3, 4, 5, 6}
 		}
+		start, length := filters.TimeWindow()
+		conditions = append(conditions, fmt.Sprintf(tutorTimeWindowFormat,
+			argCounter+1, argCounter+2, argCounter+3, argCounter+4, argCounter+5))
+		args = append(args, filters.TimeZone, pq.Array(days), int(start/time.Minute), int(length/time.Minute), availabilitySlotMinutes)
+		argCounter += 5
+	}
 
-		tutors = append(tutors, tutor)
+	// Filter by tutors with an open slot soon
+	if filters.AvailableWithinDays > 0 {
+		conditions = append(conditions, fmt.Sprintf(tutorOpenSlotFormat, argCounter+1, argCounter+2))
+		args = append(args, filters.AvailableWithinDays, availabilitySlotMinutes)
+		argCounter += 2
 	}
 
-	if err = rows.Err(); err != nil {
-		return nil, err
+	// Match the free text through the search vector, or by trigram similarity to
+	// still find tutors when the text has a typo
+	if filters.Query != "" {
+		argCounter++
+		conditions = append(conditions, fmt.Sprintf("(tp.search_vector @@ %s OR $%d <%% tp.search_text)",
+			fmt.Sprintf(tutorSearchQueryFormat, argCounter), argCounter))
+		args = append(args, filters.Query)
 	}
 
-	return tutors, nil
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// tutorSearchOrder builds the ORDER BY clause of a tutor search, adding the arguments it
+// needs to args. Ties are broken by user ID so that pages don't overlap.
+func tutorSearchOrder(filters *entities.TutorSearchFilters, args []interface{}) (string, []interface{}) {
+	switch filters.Sort {
+	case entities.TutorSortPrice:
+		return " ORDER BY tp.hourly_rate, ts.recent_score DESC NULLS LAST, tp.user_id", args
+	case entities.TutorSortExperience:
+		return " ORDER BY tp.years_experience DESC, ts.recent_score DESC NULLS LAST, tp.user_id", args
+	case entities.TutorSortNewest:
+		return " ORDER BY tp.created_at DESC, tp.user_id", args
+	case entities.TutorSortRelevance:
+		if filters.Query != "" {
+			args = append(args, filters.Query, searchRelevanceWeight, searchRatingWeight)
+			n := len(args)
+			return fmt.Sprintf(
+				" ORDER BY $%d * GREATEST(ts_rank_cd(tp.search_vector, %s, 32), word_similarity($%d, tp.search_text))"+
+					" + $%d * COALESCE(ts.recent_score, 0) / 5 DESC, tp.user_id",
+				n-1, fmt.Sprintf(tutorSearchQueryFormat, n-2), n-2, n,
+			), args
+		}
+	}
+
+	// Best rated first; tutors whose stats were not computed yet go last
+	return " ORDER BY ts.recent_score DESC NULLS LAST, tp.user_id", args
+}
+
+// RefreshStats recomputes the cached rating stats of every tutor, which moves the scores
+// along with the mean of all reviews and lets old reviews decay, and returns how many
+// tutors were refreshed
+func (r *TutorRepository) RefreshStats(ctx context.Context) (int64, error) {
+	return refreshTutorStats(ctx, r.db, nil)
 }
@@ -0,0 +1,443 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"tongly-backend/internal/entities"
+
+	"github.com/lib/pq"
+)
+
+// uploadColumns lists the upload columns in the order expected by scanUpload
+const uploadColumns = `u.id, u.user_id, u.purpose, u.storage_key, u.filename, u.content_type, u.size_bytes, u.created_at`
+
+// UploadRepository handles database operations for uploaded files and lesson materials
+type UploadRepository struct {
+	db *sql.DB
+}
+
+// NewUploadRepository creates a new UploadRepository
+func NewUploadRepository(db *sql.DB) *UploadRepository {
+	return &UploadRepository{
+		db: db,
+	}
+}
+
+// Create records an upload, unless it would take the user's uploads past quotaBytes
+func (r *UploadRepository) Create(ctx context.Context, upload *entities.Upload, quotaBytes int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Lock the user so that parallel uploads are counted against the quota
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, upload.UserID); err != nil {
+		return err
+	}
+
+	var used int64
+	err = tx.QueryRowContext(ctx, `SELECT COALESCE(SUM(size_bytes), 0) FROM uploads WHERE user_id = $1`, upload.UserID).Scan(&used)
+	if err != nil {
+		return err
+	}
+	if used+upload.Size > quotaBytes {
+		return entities.ErrUploadQuotaExceeded
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO uploads (user_id, purpose, storage_key, filename, content_type, size_bytes)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`, upload.UserID, upload.Purpose, upload.StorageKey, upload.Filename, upload.ContentType, upload.Size).Scan(
+		&upload.ID, &upload.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetByID retrieves an upload
+func (r *UploadRepository) GetByID(ctx context.Context, uploadID int) (*entities.Upload, error) {
+	query := `SELECT ` + uploadColumns + ` FROM uploads u WHERE u.id = $1`
+
+	upload, err := scanUpload(r.db.QueryRowContext(ctx, query, uploadID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrUploadNotFound
+		}
+		return nil, err
+	}
+
+	return upload, nil
+}
+
+// ListByUser retrieves a user's uploads, newest first
+func (r *UploadRepository) ListByUser(ctx context.Context, userID int) ([]entities.Upload, error) {
+	query := `SELECT ` + uploadColumns + ` FROM uploads u WHERE u.user_id = $1 ORDER BY u.created_at DESC, u.id DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	uploads := []entities.Upload{}
+	for rows.Next() {
+		upload, err := scanUpload(rows)
+		if err != nil {
+			return nil, err
+		}
+		uploads = append(uploads, *upload)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return uploads, nil
+}
+
+// Delete removes an upload and clears the avatar or intro video of its owner if they
+// link to it through contentURL. It returns the storage keys of the file and its variants.
+func (r *UploadRepository) Delete(ctx context.Context, upload *entities.Upload, contentURL string) ([]string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	keys, err := deleteUploads(ctx, tx, []int64{int64(upload.ID)})
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, entities.ErrUploadNotFound
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE users SET profile_picture_url = NULL, avatar_urls = NULL, updated_at = NOW()
+		WHERE id = $1 AND profile_picture_url = $2
+	`, upload.UserID, contentURL)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE tutor_profiles SET intro_video_url = NULL, updated_at = NOW()
+		WHERE user_id = $1 AND intro_video_url = $2
+	`, upload.UserID, contentURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, tx.Commit()
+}
+
+// GetVariant retrieves the variant of an upload with the given width and content type
+func (r *UploadRepository) GetVariant(ctx context.Context, uploadID, width int, contentType string) (*entities.UploadVariant, error) {
+	var variant entities.UploadVariant
+	err := r.db.QueryRowContext(ctx, `
+		SELECT upload_id, width, storage_key, content_type, size_bytes
+		FROM upload_variants
+		WHERE upload_id = $1 AND width = $2 AND content_type = $3
+	`, uploadID, width, contentType).Scan(
+		&variant.UploadID, &variant.Width, &variant.StorageKey, &variant.ContentType, &variant.Size,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrUploadNotFound
+		}
+		return nil, err
+	}
+
+	return &variant, nil
+}
+
+// SetAvatar records the processed variants of an avatar upload and makes it the user's
+// profile picture. The user's other avatar uploads are removed, and the storage keys of their
+// files returned so they can be deleted. It fails with ErrUploadNotFound if the upload was
+// deleted while it was being processed.
+func (r *UploadRepository) SetAvatar(ctx context.Context, upload *entities.Upload, variants []entities.UploadVariant, contentURL string, avatarURLs map[int]string) ([]string, error) {
+	urls, err := json.Marshal(avatarURLs)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Lock the upload so that it cannot be deleted before the user links to it
+	var id int
+	err = tx.QueryRowContext(ctx, `SELECT id FROM uploads WHERE id = $1 FOR UPDATE`, upload.ID).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrUploadNotFound
+		}
+		return nil, err
+	}
+
+	for _, variant := range variants {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO upload_variants (upload_id, width, storage_key, content_type, size_bytes)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (upload_id, width, content_type) DO UPDATE
+			SET storage_key = EXCLUDED.storage_key,
+			    size_bytes = EXCLUDED.size_bytes,
+			    created_at = NOW()
+		`, upload.ID, variant.Width, variant.StorageKey, variant.ContentType, variant.Size)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE users SET profile_picture_url = $1, avatar_urls = $2, updated_at = NOW() WHERE id = $3
+	`, contentURL, string(urls), upload.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return nil, err
+	} else if rows == 0 {
+		return nil, entities.ErrUserNotFound
+	}
+
+	staleKeys, err := deleteOtherUploads(ctx, tx, upload)
+	if err != nil {
+		return nil, err
+	}
+
+	return staleKeys, tx.Commit()
+}
+
+// SetIntroVideo makes an upload the tutor's intro video. The tutor's other intro video
+// uploads are removed, and their storage keys returned so the files can be deleted.
+func (r *UploadRepository) SetIntroVideo(ctx context.Context, upload *entities.Upload, contentURL string) ([]string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE tutor_profiles SET intro_video_url = $1, updated_at = NOW() WHERE user_id = $2
+	`, contentURL, upload.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return nil, err
+	} else if rows == 0 {
+		return nil, entities.ErrNotFound
+	}
+
+	staleKeys, err := deleteOtherUploads(ctx, tx, upload)
+	if err != nil {
+		return nil, err
+	}
+
+	return staleKeys, tx.Commit()
+}
+
+// AttachToLesson shares an upload with the attendees of a lesson
+func (r *UploadRepository) AttachToLesson(ctx context.Context, lessonID, uploadID int) (*entities.LessonMaterial, error) {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO lesson_materials (lesson_id, upload_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, lessonID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetLessonMaterial(ctx, lessonID, uploadID)
+}
+
+// GetLessonMaterial retrieves an upload shared with a lesson
+func (r *UploadRepository) GetLessonMaterial(ctx context.Context, lessonID, uploadID int) (*entities.LessonMaterial, error) {
+	query := `
+		SELECT ` + uploadColumns + `, m.lesson_id, m.created_at
+		FROM lesson_materials m
+		JOIN uploads u ON m.upload_id = u.id
+		WHERE m.lesson_id = $1 AND m.upload_id = $2
+	`
+
+	material, err := scanLessonMaterial(r.db.QueryRowContext(ctx, query, lessonID, uploadID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrUploadNotFound
+		}
+		return nil, err
+	}
+
+	return material, nil
+}
+
+// ListLessonMaterials retrieves the uploads shared with a lesson in the order they were shared
+func (r *UploadRepository) ListLessonMaterials(ctx context.Context, lessonID int) ([]entities.LessonMaterial, error) {
+	query := `
+		SELECT ` + uploadColumns + `, m.lesson_id, m.created_at
+		FROM lesson_materials m
+		JOIN uploads u ON m.upload_id = u.id
+		WHERE m.lesson_id = $1
+		ORDER BY m.created_at, u.id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	materials := []entities.LessonMaterial{}
+	for rows.Next() {
+		material, err := scanLessonMaterial(rows)
+		if err != nil {
+			return nil, err
+		}
+		materials = append(materials, *material)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return materials, nil
+}
+
+// DetachFromLesson stops sharing an upload with a lesson
+func (r *UploadRepository) DetachFromLesson(ctx context.Context, lessonID, uploadID int) error {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM lesson_materials WHERE lesson_id = $1 AND upload_id = $2
+	`, lessonID, uploadID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return entities.ErrUploadNotFound
+	}
+	return nil
+}
+
+// IsSharedWith checks if an upload is shared with a lesson the user attends, as the tutor,
+// the student or a participant with an active seat
+func (r *UploadRepository) IsSharedWith(ctx context.Context, uploadID, userID int) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM lesson_materials m
+			JOIN lessons l ON m.lesson_id = l.id
+			WHERE m.upload_id = $1
+			  AND (l.tutor_id = $2 OR l.student_id = $2 OR EXISTS (
+				SELECT 1 FROM lesson_participants p
+				WHERE p.lesson_id = l.id AND p.student_id = $2 AND p.cancelled_at IS NULL))
+		)
+	`
+
+	var shared bool
+	err := r.db.QueryRowContext(ctx, query, uploadID, userID).Scan(&shared)
+	return shared, err
+}
+
+// deleteOtherUploads removes the owner's other uploads with the same purpose as upload
+func deleteOtherUploads(ctx context.Context, tx *sql.Tx, upload *entities.Upload) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id FROM uploads WHERE user_id = $1 AND purpose = $2 AND id <> $3
+	`, upload.UserID, upload.Purpose, upload.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	return deleteUploads(ctx, tx, ids)
+}
+
+// deleteUploads removes uploads and returns the storage keys of their files and of their
+// variants. The variants go with the cascade, but the outer SELECT still sees them since it
+// reads the snapshot taken before the DELETE.
+func deleteUploads(ctx context.Context, tx *sql.Tx, ids []int64) ([]string, error) {
+	keys := []string{}
+	if len(ids) == 0 {
+		return keys, nil
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		WITH deleted AS (
+			DELETE FROM uploads WHERE id = ANY($1) RETURNING id, storage_key
+		)
+		SELECT storage_key FROM deleted
+		UNION ALL
+		SELECT v.storage_key FROM upload_variants v JOIN deleted d ON v.upload_id = d.id
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// scanUpload reads an upload selected with uploadColumns
+func scanUpload(row rowScanner) (*entities.Upload, error) {
+	var upload entities.Upload
+
+	err := row.Scan(
+		&upload.ID, &upload.UserID, &upload.Purpose, &upload.StorageKey,
+		&upload.Filename, &upload.ContentType, &upload.Size, &upload.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &upload, nil
+}
+
+// scanLessonMaterial reads an upload selected with uploadColumns followed by the lesson ID
+// and the time it was shared
+func scanLessonMaterial(row rowScanner) (*entities.LessonMaterial, error) {
+	var material entities.LessonMaterial
+
+	err := row.Scan(
+		&material.ID, &material.UserID, &material.Purpose, &material.StorageKey,
+		&material.Filename, &material.ContentType, &material.Size, &material.CreatedAt,
+		&material.LessonID, &material.AttachedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &material, nil
+}
@@ -3,8 +3,11 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"tongly-backend/internal/entities"
+
+	"github.com/lib/pq"
 )
 
 // UserRepository handles database operations for users
@@ -45,17 +48,15 @@ func (r *UserRepository) Create(ctx context.Context, user *entities.User) error
 	return err
 }
 
-// GetByID retrieves a user by ID
-func (r *UserRepository) GetByID(ctx context.Context, id int) (*entities.User, error) {
-	query := `
-		SELECT id, username, password_hash, email, first_name, last_name, 
-		       profile_picture_url, sex, age, role, created_at, updated_at
-		FROM users
-		WHERE id = $1
-	`
+// userColumns lists the user columns in the order expected by scanUser
+const userColumns = `id, username, password_hash, email, first_name, last_name,
+	profile_picture_url, sex, age, role, created_at, updated_at, avatar_urls`
 
+// scanUser scans a row selected with userColumns into a user
+func scanUser(row rowScanner) (*entities.User, error) {
 	user := &entities.User{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	var avatarURLs []byte
+	err := row.Scan(
 		&user.ID,
 		&user.Username,
 		&user.PasswordHash,
@@ -68,89 +69,74 @@ func (r *UserRepository) GetByID(ctx context.Context, id int) (*entities.User, e
 		&user.Role,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&avatarURLs,
 	)
-
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
 		return nil, err
 	}
 
+	if err := decodeAvatarURLs(user, avatarURLs); err != nil {
+		return nil, err
+	}
 	return user, nil
 }
 
-// GetByUsername retrieves a user by username
-func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*entities.User, error) {
-	query := `
-		SELECT id, username, password_hash, email, first_name, last_name,
-		       profile_picture_url, sex, age, role, created_at, updated_at
-		FROM users
-		WHERE username = $1
-	`
-
-	user := &entities.User{}
-	err := r.db.QueryRowContext(ctx, query, username).Scan(
-		&user.ID,
-		&user.Username,
-		&user.PasswordHash,
-		&user.Email,
-		&user.FirstName,
-		&user.LastName,
-		&user.ProfilePictureURL,
-		&user.Sex,
-		&user.Age,
-		&user.Role,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
-
+// getUser retrieves the user selected by a query over userColumns, or nil if there is none
+func (r *UserRepository) getUser(ctx context.Context, query string, arg interface{}) (*entities.User, error) {
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, arg))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, err
 	}
-
 	return user, nil
 }
 
-// GetByEmail retrieves a user by email
-func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
-	query := `
-		SELECT id, username, password_hash, email, first_name, last_name,
-		       profile_picture_url, sex, age, role, created_at, updated_at
-		FROM users
-		WHERE email = $1
-	`
+// GetByID retrieves a user by ID
+func (r *UserRepository) GetByID(ctx context.Context, id int) (*entities.User, error) {
+	return r.getUser(ctx, `SELECT `+userColumns+` FROM users WHERE id = $1`, id)
+}
 
-	user := &entities.User{}
-	err := r.db.QueryRowContext(ctx, query, email).Scan(
-		&user.ID,
-		&user.Username,
-		&user.PasswordHash,
-		&user.Email,
-		&user.FirstName,
-		&user.LastName,
-		&user.ProfilePictureURL,
-		&user.Sex,
-		&user.Age,
-		&user.Role,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+// GetByIDs retrieves the users with the given IDs in a single query, keyed by ID. IDs
+// without a user are left out of the map.
+func (r *UserRepository) GetByIDs(ctx context.Context, ids []int) (map[int]*entities.User, error) {
+	users := make(map[int]*entities.User, len(ids))
+	if len(ids) == 0 {
+		return users, nil
+	}
 
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = ANY($1)`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
 		return nil, err
 	}
+	defer rows.Close()
 
-	return user, nil
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users[user.ID] = user
+	}
+
+	return users, rows.Err()
+}
+
+// GetByUsername retrieves a user by username
+func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*entities.User, error) {
+	return r.getUser(ctx, `SELECT `+userColumns+` FROM users WHERE username = $1`, username)
 }
 
-// Update updates a user in the database
+// GetByEmail retrieves a user by email
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
+	return r.getUser(ctx, `SELECT `+userColumns+` FROM users WHERE email = $1`, email)
+}
+
+// Update updates a user in the database. The avatar variants are dropped when the profile
+// picture changes, since they were made from the previous one.
 func (r *UserRepository) Update(ctx context.Context, user *entities.User) error {
 	query := `
 		UPDATE users
@@ -160,12 +146,14 @@ func (r *UserRepository) Update(ctx context.Context, user *entities.User) error
 		    last_name = $4, 
 		    profile_picture_url = $5, 
 		    sex = $6, 
-		    age = $7
+		    age = $7,
+		    avatar_urls = CASE WHEN profile_picture_url IS DISTINCT FROM $5 THEN NULL ELSE avatar_urls END
 		WHERE id = $8
-		RETURNING updated_at
+		RETURNING updated_at, avatar_urls
 	`
 
-	return r.db.QueryRowContext(
+	var avatarURLs []byte
+	err := r.db.QueryRowContext(
 		ctx,
 		query,
 		user.Username,
@@ -176,7 +164,12 @@ func (r *UserRepository) Update(ctx context.Context, user *entities.User) error
 		user.Sex,
 		user.Age,
 		user.ID,
-	).Scan(&user.UpdatedAt)
+	).Scan(&user.UpdatedAt, &avatarURLs)
+	if err != nil {
+		return err
+	}
+
+	return decodeAvatarURLs(user, avatarURLs)
 }
 
 // UpdatePassword updates a user's password
@@ -190,3 +183,12 @@ func (r *UserRepository) UpdatePassword(ctx context.Context, userID int, passwor
 	_, err := r.db.ExecContext(ctx, query, passwordHash, userID)
 	return err
 }
+
+// decodeAvatarURLs reads the avatar_urls column, which is NULL until an uploaded avatar is processed
+func decodeAvatarURLs(user *entities.User, data []byte) error {
+	user.AvatarURLs = nil
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, &user.AvatarURLs)
+}
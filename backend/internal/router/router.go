@@ -21,6 +21,19 @@ func SetupRouter(
 	userHandler *interfaces.UserHandler,
 	preferencesHandler *interfaces.UserPreferencesHandler,
 	gameHandler *interfaces.GameHandler,
+	earningsHandler *interfaces.EarningsHandler,
+	groupClassHandler *interfaces.GroupClassHandler,
+	calendarHandler *interfaces.CalendarHandler,
+	busyTimeHandler *interfaces.BusyTimeHandler,
+	adminHandler *interfaces.AdminHandler,
+	notificationHandler *interfaces.NotificationHandler,
+	messageHandler *interfaces.MessageHandler,
+	lessonNotesHandler *interfaces.LessonNotesHandler,
+	uploadHandler *interfaces.UploadHandler,
+	reviewHandler *interfaces.ReviewHandler,
+	favoriteHandler *interfaces.FavoriteHandler,
+	credentialHandler *interfaces.CredentialHandler,
+	analyticsHandler *interfaces.AnalyticsHandler,
 ) {
 	// Add CORS middleware first
 	r.Use(cors.New(cors.Config{
@@ -55,7 +68,8 @@ func SetupRouter(
 
 	// Add logger middleware
 	r.Use(middleware.Logger(middleware.LoggerConfig{
-		SkipPaths: []string{"/health", "/metrics"},
+		SkipPaths:    []string{"/health", "/metrics"},
+		RedactParams: []string{"token"},
 	}))
 
 	// Common routes (public)
@@ -87,11 +101,21 @@ func SetupRouter(
 			userHandler.RegisterRoutes(r)
 			preferencesHandler.RegisterRoutes(r)
 			gameHandler.RegisterRoutes(r)
+			earningsHandler.RegisterRoutes(r)
+			groupClassHandler.RegisterRoutes(r)
+			calendarHandler.RegisterRoutes(r)
+			busyTimeHandler.RegisterRoutes(r)
+			adminHandler.RegisterRoutes(r)
+			notificationHandler.RegisterRoutes(r)
+			messageHandler.RegisterRoutes(r)
+			lessonNotesHandler.RegisterRoutes(r)
+			uploadHandler.RegisterRoutes(r)
+			reviewHandler.RegisterRoutes(r)
+			favoriteHandler.RegisterRoutes(r)
+			credentialHandler.RegisterRoutes(r)
+			analyticsHandler.RegisterRoutes(r)
 		}
 	}
-
-	// Setup static file serving
-	r.Static("/uploads", "./uploads")
 }
 
 func NewRouter(
@@ -103,6 +127,19 @@ func NewRouter(
 	userHandler *interfaces.UserHandler,
 	preferencesHandler *interfaces.UserPreferencesHandler,
 	gameHandler *interfaces.GameHandler,
+	earningsHandler *interfaces.EarningsHandler,
+	groupClassHandler *interfaces.GroupClassHandler,
+	calendarHandler *interfaces.CalendarHandler,
+	busyTimeHandler *interfaces.BusyTimeHandler,
+	adminHandler *interfaces.AdminHandler,
+	notificationHandler *interfaces.NotificationHandler,
+	messageHandler *interfaces.MessageHandler,
+	lessonNotesHandler *interfaces.LessonNotesHandler,
+	uploadHandler *interfaces.UploadHandler,
+	reviewHandler *interfaces.ReviewHandler,
+	favoriteHandler *interfaces.FavoriteHandler,
+	credentialHandler *interfaces.CredentialHandler,
+	analyticsHandler *interfaces.AnalyticsHandler,
 ) *gin.Engine {
 	router := gin.Default()
 
@@ -117,6 +154,19 @@ func NewRouter(
 		userHandler,
 		preferencesHandler,
 		gameHandler,
+		earningsHandler,
+		groupClassHandler,
+		calendarHandler,
+		busyTimeHandler,
+		adminHandler,
+		notificationHandler,
+		messageHandler,
+		lessonNotesHandler,
+		uploadHandler,
+		reviewHandler,
+		favoriteHandler,
+		credentialHandler,
+		analyticsHandler,
 	)
 
 	return router
@@ -0,0 +1,160 @@
+package usecases
+
+import (
+	"context"
+	"math"
+	"time"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/logger"
+	"tongly-backend/internal/repositories"
+)
+
+const (
+	// maxPendingEventWrites caps the event writes in flight. Events beyond it are dropped.
+	maxPendingEventWrites = 32
+	eventWriteTimeout     = 5 * time.Second
+)
+
+// AnalyticsUseCase handles business logic for tutor analytics and the events they count
+type AnalyticsUseCase struct {
+	analyticsRepo *repositories.AnalyticsRepository
+	lessonRepo    *repositories.LessonRepository
+
+	eventWrites chan struct{}
+}
+
+// NewAnalyticsUseCase creates a new AnalyticsUseCase
+func NewAnalyticsUseCase(
+	analyticsRepo *repositories.AnalyticsRepository,
+	lessonRepo *repositories.LessonRepository,
+) *AnalyticsUseCase {
+	return &AnalyticsUseCase{
+		analyticsRepo: analyticsRepo,
+		lessonRepo:    lessonRepo,
+		eventWrites:   make(chan struct{}, maxPendingEventWrites),
+	}
+}
+
+// RecordProfileView counts a view of a tutor's profile in the background
+func (uc *AnalyticsUseCase) RecordProfileView(tutorID int) {
+	uc.recordEvents(entities.TutorEventProfileView, []int{tutorID})
+}
+
+// RecordSearchImpressions counts an appearance in search results for each listed tutor in the background
+func (uc *AnalyticsUseCase) RecordSearchImpressions(tutors []entities.TutorProfile) {
+	if len(tutors) == 0 {
+		return
+	}
+
+	tutorIDs := make([]int, len(tutors))
+	for i := range tutors {
+		tutorIDs[i] = tutors[i].UserID
+	}
+	uc.recordEvents(entities.TutorEventSearchImpression, tutorIDs)
+}
+
+// recordEvents writes events without holding up the request that caused them. Counting is
+// best-effort: when the database is slow and too many writes are pending, events are dropped.
+func (uc *AnalyticsUseCase) recordEvents(eventType entities.TutorEventType, tutorIDs []int) {
+	select {
+	case uc.eventWrites <- struct{}{}:
+	default:
+		logger.Warn("Dropped tutor events", "event_type", eventType, "tutors", len(tutorIDs))
+		return
+	}
+
+	go func() {
+		defer func() { <-uc.eventWrites }()
+
+		ctx, cancel := context.WithTimeout(context.Background(), eventWriteTimeout)
+		defer cancel()
+
+		if err := uc.analyticsRepo.RecordEvents(ctx, eventType, tutorIDs); err != nil {
+			logger.Error("Failed to record tutor events", "event_type", eventType, "error", err)
+		}
+	}()
+}
+
+// GetTutorAnalytics retrieves how a tutor has been doing in [from, to), overall and by period,
+// with the tutor's trial conversion
+func (uc *AnalyticsUseCase) GetTutorAnalytics(ctx context.Context, tutorID int, from, to time.Time, period entities.EarningsPeriod) (*entities.TutorAnalytics, error) {
+	if err := entities.ValidateAnalyticsRange(from, to, period); err != nil {
+		return nil, err
+	}
+
+	buckets, err := uc.analyticsRepo.GetTutorAnalyticsBuckets(ctx, tutorID, from, to, period)
+	if err != nil {
+		return nil, err
+	}
+	students, repeatStudents, err := uc.analyticsRepo.GetRepeatStudents(ctx, tutorID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	trials, err := uc.lessonRepo.GetTrialConversionStats(ctx, tutorID)
+	if err != nil {
+		return nil, err
+	}
+
+	analytics := &entities.TutorAnalytics{
+		From:     from,
+		To:       to,
+		Period:   period,
+		ByPeriod: buckets,
+		Trials:   trials,
+	}
+
+	summary := &analytics.Summary
+	summary.Students = students
+	summary.RepeatStudents = repeatStudents
+	summary.RepeatStudentRatio = ratio(repeatStudents, students)
+
+	var ratingSum float64
+	for i := range buckets {
+		bucket := &buckets[i]
+		bucket.Key = periodKey(bucket.Start, period)
+		bucket.HoursTaught = roundHours(bucket.HoursTaught)
+
+		summary.LessonsTaught += bucket.LessonsTaught
+		summary.HoursTaught += bucket.HoursTaught
+		summary.Cancellations.Scheduled += bucket.ScheduledLessons
+		summary.Cancellations.ByTutor += bucket.CancelledByTutor
+		summary.Cancellations.ByStudent += bucket.CancelledByStudent
+		summary.Cancellations.Other += bucket.CancelledOther
+		summary.Bookings += bucket.Bookings
+		summary.ReviewsCount += bucket.ReviewsCount
+		summary.ProfileViews += bucket.ProfileViews
+		summary.SearchImpressions += bucket.SearchImpressions
+		if bucket.AverageRating != nil {
+			ratingSum += *bucket.AverageRating * float64(bucket.ReviewsCount)
+		}
+	}
+	summary.HoursTaught = roundHours(summary.HoursTaught)
+
+	cancellations := &summary.Cancellations
+	cancellations.TutorRate = ratio(cancellations.ByTutor, cancellations.Scheduled)
+	cancellations.StudentRate = ratio(cancellations.ByStudent, cancellations.Scheduled)
+	cancellations.OtherRate = ratio(cancellations.Other, cancellations.Scheduled)
+	cancellations.Rate = ratio(cancellations.ByTutor+cancellations.ByStudent+cancellations.Other, cancellations.Scheduled)
+
+	if summary.ReviewsCount > 0 {
+		averageRating := ratingSum / float64(summary.ReviewsCount)
+		summary.AverageRating = &averageRating
+	}
+	summary.ViewRate = ratio(summary.ProfileViews, summary.SearchImpressions)
+	summary.BookingConversion = ratio(summary.Bookings, summary.ProfileViews)
+
+	return analytics, nil
+}
+
+// ratio divides part by whole, or returns 0 when whole is 0
+func ratio(part, whole int) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return float64(part) / float64(whole)
+}
+
+// roundHours rounds hours to two decimals
+func roundHours(hours float64) float64 {
+	return math.Round(hours*100) / 100
+}
@@ -82,7 +82,7 @@ func (uc *AuthUseCase) Register(ctx context.Context, username, email, password,
 		tutorProfile := &entities.TutorProfile{
 			UserID:          user.ID,
 			Bio:             "",
-			Education:       []map[string]string{},
+			Education:       []entities.Education{},
 			YearsExperience: 0,
 		}
 		if err := uc.tutorRepo.Create(ctx, tutorProfile); err != nil {
@@ -150,6 +150,10 @@ func (uc *AuthUseCase) RegisterStudent(ctx context.Context, req *entities.Studen
 
 // RegisterTutor registers a new tutor with additional profile information
 func (uc *AuthUseCase) RegisterTutor(ctx context.Context, req *entities.TutorRegistrationRequest) (*entities.User, error) {
+	if err := entities.ValidateEducation(req.Education); err != nil {
+		return nil, err
+	}
+
 	// Register the base user first
 	user, err := uc.Register(ctx, req.Username, req.Email, req.Password, "tutor")
 	if err != nil {
@@ -166,6 +170,13 @@ func (uc *AuthUseCase) RegisterTutor(ctx context.Context, req *entities.TutorReg
 	tutorProfile.Education = req.Education
 	tutorProfile.IntroVideoURL = req.IntroVideoURL
 	tutorProfile.YearsExperience = req.YearsExperience
+	if req.HourlyRate > 0 {
+		tutorProfile.HourlyRate = entities.RoundMoney(req.HourlyRate)
+	}
+	tutorProfile.OffersTrial = req.OffersTrial
+	if req.TrialPrice > 0 {
+		tutorProfile.TrialPrice = entities.RoundMoney(req.TrialPrice)
+	}
 
 	if err := uc.tutorRepo.Update(ctx, tutorProfile); err != nil {
 		return nil, err
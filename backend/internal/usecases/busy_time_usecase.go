@@ -0,0 +1,265 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/logger"
+	"tongly-backend/internal/repositories"
+	"tongly-backend/pkg/ical"
+)
+
+const (
+	calendarFetchTimeout = 20 * time.Second
+
+	// calendarSyncBatchSize is the number of calendars claimed for syncing at a time
+	calendarSyncBatchSize = 10
+	// calendarSyncClaimTTL is how long a claimed calendar is left to the replica syncing it,
+	// long enough for a whole batch to time out
+	calendarSyncClaimTTL = 2 * calendarSyncBatchSize * calendarFetchTimeout
+)
+
+// errPrivateAddress is returned when a calendar URL resolves to an address inside our network
+var errPrivateAddress = errors.New("calendar URL points to a private address")
+
+// BusyTimeUseCase handles business logic for tutors' external calendars
+type BusyTimeUseCase struct {
+	busyTimeRepo *repositories.BusyTimeRepository
+	httpClient   *http.Client
+}
+
+// NewBusyTimeUseCase creates a new BusyTimeUseCase
+func NewBusyTimeUseCase(busyTimeRepo *repositories.BusyTimeRepository) *BusyTimeUseCase {
+	// Calendar URLs are supplied by users, so never connect to internal services
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+				ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() {
+				return errPrivateAddress
+			}
+			return nil
+		},
+	}
+
+	return &BusyTimeUseCase{
+		busyTimeRepo: busyTimeRepo,
+		httpClient: &http.Client{
+			Timeout:   calendarFetchTimeout,
+			Transport: &http.Transport{DialContext: dialer.DialContext},
+		},
+	}
+}
+
+// GetSources retrieves the external calendars of a tutor
+func (uc *BusyTimeUseCase) GetSources(ctx context.Context, tutorID int) ([]entities.CalendarSource, error) {
+	return uc.busyTimeRepo.GetSources(ctx, tutorID)
+}
+
+// AddURLSource subscribes a tutor to an external calendar. The calendar is fetched right away
+// so that an unreachable or invalid URL is reported to the tutor instead of being stored.
+func (uc *BusyTimeUseCase) AddURLSource(ctx context.Context, tutorID int, req *entities.CalendarSourceRequest) (*entities.CalendarSource, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	data, err := uc.fetch(ctx, req.URL)
+	if err != nil {
+		return nil, err
+	}
+	busyTimes, err := expandBusyTimes(data)
+	if err != nil {
+		return nil, err
+	}
+
+	source := &entities.CalendarSource{
+		TutorID: tutorID,
+		Name:    req.Name,
+		Type:    entities.CalendarSourceURL,
+		URL:     &req.URL,
+	}
+	if err := uc.busyTimeRepo.CreateSource(ctx, source); err != nil {
+		return nil, err
+	}
+	if err := uc.busyTimeRepo.ReplaceBusyTimes(ctx, source, busyTimes); err != nil {
+		return nil, err
+	}
+
+	return uc.busyTimeRepo.GetSource(ctx, source.ID, tutorID)
+}
+
+// AddUploadedSource imports an uploaded .ics file. Its content is kept so that recurring
+// events keep being expanded as time passes.
+func (uc *BusyTimeUseCase) AddUploadedSource(ctx context.Context, tutorID int, name string, data []byte) (*entities.CalendarSource, error) {
+	if len(data) > entities.MaxCalendarSize {
+		return nil, entities.ErrCalendarTooLarge
+	}
+
+	name, err := entities.NormalizeCalendarSourceName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	busyTimes, err := expandBusyTimes(data)
+	if err != nil {
+		return nil, err
+	}
+
+	content := string(data)
+	source := &entities.CalendarSource{
+		TutorID: tutorID,
+		Name:    name,
+		Type:    entities.CalendarSourceUpload,
+		Content: &content,
+	}
+	if err := uc.busyTimeRepo.CreateSource(ctx, source); err != nil {
+		return nil, err
+	}
+	if err := uc.busyTimeRepo.ReplaceBusyTimes(ctx, source, busyTimes); err != nil {
+		return nil, err
+	}
+
+	return uc.busyTimeRepo.GetSource(ctx, source.ID, tutorID)
+}
+
+// DeleteSource removes an external calendar and its busy times
+func (uc *BusyTimeUseCase) DeleteSource(ctx context.Context, tutorID, sourceID int) error {
+	return uc.busyTimeRepo.DeleteSource(ctx, sourceID, tutorID)
+}
+
+// SyncSource refreshes the busy times of one of the tutor's calendars on demand
+func (uc *BusyTimeUseCase) SyncSource(ctx context.Context, tutorID, sourceID int) (*entities.CalendarSource, error) {
+	source, err := uc.busyTimeRepo.GetSource(ctx, sourceID, tutorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.sync(ctx, source); err != nil {
+		return nil, err
+	}
+
+	return uc.busyTimeRepo.GetSource(ctx, sourceID, tutorID)
+}
+
+// SyncDueSources refreshes the calendars that have not been synced within CalendarSyncInterval.
+// Calendars are claimed in batches, so that replicas syncing at the same time share the work.
+// A failing calendar is recorded and keeps its previous busy times; it does not stop the others.
+func (uc *BusyTimeUseCase) SyncDueSources(ctx context.Context) (int, error) {
+	synced := 0
+	for {
+		sources, err := uc.busyTimeRepo.ClaimSourcesDueForSync(ctx, time.Now().Add(-entities.CalendarSyncInterval), calendarSyncBatchSize, calendarSyncClaimTTL)
+		if err != nil {
+			return synced, err
+		}
+
+		for i := range sources {
+			if ctx.Err() != nil {
+				return synced, ctx.Err()
+			}
+			if err := uc.sync(ctx, &sources[i]); err != nil {
+				logger.Error("Failed to sync external calendar", "source_id", sources[i].ID, "error", err)
+				continue
+			}
+			synced++
+		}
+
+		if len(sources) < calendarSyncBatchSize {
+			return synced, nil
+		}
+	}
+}
+
+// GetBusyTimes retrieves the times in which a tutor is busy according to their external calendars
+func (uc *BusyTimeUseCase) GetBusyTimes(ctx context.Context, tutorID int, from, to time.Time) ([]entities.BusyTime, error) {
+	if !from.Before(to) || to.Sub(from) > entities.BusyTimeHorizon {
+		return nil, errors.New("invalid time range")
+	}
+	return uc.busyTimeRepo.GetBusyTimes(ctx, tutorID, from, to)
+}
+
+// sync re-reads a calendar and replaces its busy times, recording the error if it fails
+func (uc *BusyTimeUseCase) sync(ctx context.Context, source *entities.CalendarSource) error {
+	var data []byte
+	var err error
+
+	switch {
+	case source.Type == entities.CalendarSourceUpload && source.Content != nil:
+		data = []byte(*source.Content)
+	case source.URL != nil:
+		data, err = uc.fetch(ctx, *source.URL)
+	default:
+		err = errors.New("calendar source has nothing to sync")
+	}
+
+	var busyTimes []entities.BusyTime
+	if err == nil {
+		busyTimes, err = expandBusyTimes(data)
+	}
+	if err != nil {
+		if markErr := uc.busyTimeRepo.MarkSyncFailed(ctx, source.ID, err.Error()); markErr != nil {
+			return markErr
+		}
+		return err
+	}
+
+	return uc.busyTimeRepo.ReplaceBusyTimes(ctx, source, busyTimes)
+}
+
+// fetch downloads a calendar, refusing responses larger than MaxCalendarSize
+func (uc *BusyTimeUseCase) fetch(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/calendar")
+
+	resp, err := uc.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", entities.ErrCalendarUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s", entities.ErrCalendarUnavailable, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, entities.MaxCalendarSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", entities.ErrCalendarUnavailable, err)
+	}
+	if len(data) > entities.MaxCalendarSize {
+		return nil, entities.ErrCalendarTooLarge
+	}
+
+	return data, nil
+}
+
+// expandBusyTimes parses a calendar and expands its events into busy times from now until BusyTimeHorizon
+func expandBusyTimes(data []byte) ([]entities.BusyTime, error) {
+	events, err := ical.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	periods := ical.BusyPeriods(events, now.Add(-24*time.Hour), now.Add(entities.BusyTimeHorizon))
+
+	busyTimes := make([]entities.BusyTime, len(periods))
+	for i, p := range periods {
+		busyTimes[i] = entities.BusyTime{StartTime: p.Start, EndTime: p.End}
+	}
+
+	return busyTimes, nil
+}
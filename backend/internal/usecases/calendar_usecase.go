@@ -0,0 +1,170 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/repositories"
+	"tongly-backend/pkg/ical"
+)
+
+const (
+	calendarProductID       = "-//Tongly//Lessons//EN"
+	calendarName            = "Tongly lessons"
+	calendarRefreshInterval = time.Hour
+	calendarTokenBytes      = 32
+)
+
+// CalendarUseCase handles business logic for iCalendar feeds and exports
+type CalendarUseCase struct {
+	calendarRepo *repositories.CalendarRepository
+}
+
+// NewCalendarUseCase creates a new CalendarUseCase
+func NewCalendarUseCase(calendarRepo *repositories.CalendarRepository) *CalendarUseCase {
+	return &CalendarUseCase{
+		calendarRepo: calendarRepo,
+	}
+}
+
+// GetFeed retrieves the calendar feed of a user, creating it on first use
+func (uc *CalendarUseCase) GetFeed(ctx context.Context, userID int) (*entities.CalendarFeed, error) {
+	feed, err := uc.calendarRepo.GetFeedByUserID(ctx, userID)
+	if err == nil {
+		return feed, nil
+	}
+	if !errors.Is(err, entities.ErrCalendarFeedNotFound) {
+		return nil, err
+	}
+
+	return uc.ResetFeed(ctx, userID)
+}
+
+// ResetFeed generates a new secret token for the calendar feed of a user, invalidating the old URL
+func (uc *CalendarUseCase) ResetFeed(ctx context.Context, userID int) (*entities.CalendarFeed, error) {
+	token, err := generateCalendarToken()
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &entities.CalendarFeed{
+		UserID: userID,
+		Token:  token,
+	}
+	if err := uc.calendarRepo.SaveFeed(ctx, feed); err != nil {
+		return nil, err
+	}
+
+	return feed, nil
+}
+
+// GetFeedCalendar renders the calendar of the feed with the given token, with recent and upcoming lessons
+func (uc *CalendarUseCase) GetFeedCalendar(ctx context.Context, token string) ([]byte, error) {
+	feed, err := uc.calendarRepo.GetFeedByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	entries, err := uc.calendarRepo.GetEntries(ctx, feed.UserID,
+		now.Add(-entities.CalendarFeedPastWindow), now.Add(entities.CalendarFeedFutureWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &ical.Calendar{
+		ProductID:       calendarProductID,
+		Name:            calendarName,
+		RefreshInterval: calendarRefreshInterval,
+	}
+	for i := range entries {
+		calendar.Events = append(calendar.Events, lessonCalendarEvent(&entries[i], feed.UserID))
+	}
+
+	return calendar.Encode(), nil
+}
+
+// GetLessonCalendar renders a single lesson of the user as an iCalendar file
+func (uc *CalendarUseCase) GetLessonCalendar(ctx context.Context, userID, lessonID int) ([]byte, error) {
+	entry, err := uc.calendarRepo.GetEntry(ctx, userID, lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &ical.Calendar{
+		ProductID: calendarProductID,
+		Events:    []ical.Event{lessonCalendarEvent(entry, userID)},
+	}
+
+	return calendar.Encode(), nil
+}
+
+// lessonCalendarEvent converts a calendar entry into an event as seen by the given user.
+// The UID only depends on the lesson, so feeds and single-lesson downloads update the same entry.
+func lessonCalendarEvent(entry *entities.CalendarEntry, userID int) ical.Event {
+	status := ical.StatusConfirmed
+	if entry.Cancelled {
+		status = ical.StatusCancelled
+	}
+
+	return ical.Event{
+		UID:          entry.CalendarUID(),
+		Sequence:     entry.CalendarSequence(),
+		Start:        entry.StartTime,
+		End:          entry.EndTime,
+		Summary:      lessonCalendarSummary(entry, userID),
+		Description:  lessonCalendarDescription(entry),
+		Status:       status,
+		Created:      entry.CreatedAt,
+		LastModified: entry.UpdatedAt,
+	}
+}
+
+// lessonCalendarSummary names the lesson after the other party from the user's point of view
+func lessonCalendarSummary(entry *entities.CalendarEntry, userID int) string {
+	isTutor := entry.TutorID == userID
+
+	if entry.Type == entities.LessonTypeGroup {
+		if entry.Topic != "" {
+			return fmt.Sprintf("%s (%s group class)", entry.Topic, entry.Language)
+		}
+		if isTutor {
+			return fmt.Sprintf("%s group lesson", entry.Language)
+		}
+		return fmt.Sprintf("%s group lesson with %s", entry.Language, entry.TutorName)
+	}
+
+	other := entry.TutorName
+	if isTutor {
+		other = entry.StudentName
+	}
+	if entry.Type == entities.LessonTypeTrial {
+		return fmt.Sprintf("Trial %s lesson with %s", entry.Language, other)
+	}
+	return fmt.Sprintf("%s lesson with %s", entry.Language, other)
+}
+
+// lessonCalendarDescription describes the lesson in the event body
+func lessonCalendarDescription(entry *entities.CalendarEntry) string {
+	description := fmt.Sprintf("Tongly lesson #%d\nTutor: %s", entry.LessonID, entry.TutorName)
+	if entry.StudentName != "" {
+		description += "\nStudent: " + entry.StudentName
+	}
+	if entry.Cancelled {
+		description += "\nThis lesson has been cancelled."
+	}
+	return description
+}
+
+// generateCalendarToken generates a random secret for a calendar feed URL
+func generateCalendarToken() (string, error) {
+	b := make([]byte, calendarTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,230 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/logger"
+	"tongly-backend/internal/repositories"
+)
+
+// CredentialUseCase handles business logic for tutor credentials and their review by admins
+type CredentialUseCase struct {
+	credentialRepo *repositories.CredentialRepository
+	uploadRepo     *repositories.UploadRepository
+	userRepo       *repositories.UserRepository
+	uploadUseCase  *UploadUseCase
+	notifier       Notifier
+}
+
+// NewCredentialUseCase creates a new CredentialUseCase
+func NewCredentialUseCase(
+	credentialRepo *repositories.CredentialRepository,
+	uploadRepo *repositories.UploadRepository,
+	userRepo *repositories.UserRepository,
+	uploadUseCase *UploadUseCase,
+	notifier Notifier,
+) *CredentialUseCase {
+	return &CredentialUseCase{
+		credentialRepo: credentialRepo,
+		uploadRepo:     uploadRepo,
+		userRepo:       userRepo,
+		uploadUseCase:  uploadUseCase,
+		notifier:       notifier,
+	}
+}
+
+// GetCredentials retrieves a tutor's own credentials with links to their proof documents
+func (uc *CredentialUseCase) GetCredentials(ctx context.Context, tutorID int) ([]entities.TutorCredential, error) {
+	credentials, err := uc.credentialRepo.GetByTutor(ctx, tutorID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range credentials {
+		if err := uc.uploadUseCase.SignUpload(credentials[i].Document); err != nil {
+			return nil, err
+		}
+	}
+	return credentials, nil
+}
+
+// GetVerifiedCredentials retrieves the verified credentials shown on a tutor's profile,
+// without their proof documents
+func (uc *CredentialUseCase) GetVerifiedCredentials(ctx context.Context, tutorID int) ([]entities.TutorCredential, error) {
+	credentials, err := uc.credentialRepo.GetVerifiedByTutor(ctx, tutorID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range credentials {
+		credentials[i].DocumentID = 0
+		credentials[i].Document = nil
+	}
+	return credentials, nil
+}
+
+// AddCredential adds a credential of the tutor, which waits for an admin to review its proof document
+func (uc *CredentialUseCase) AddCredential(ctx context.Context, tutorID int, req *entities.CredentialRequest) (*entities.TutorCredential, error) {
+	credential, err := uc.newCredential(ctx, tutorID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.credentialRepo.Create(ctx, credential); err != nil {
+		return nil, err
+	}
+	return uc.getOwnCredential(ctx, tutorID, credential.ID)
+}
+
+// UpdateCredential replaces a credential of the tutor. A changed credential is reviewed again,
+// so a verified one stops counting until then.
+func (uc *CredentialUseCase) UpdateCredential(ctx context.Context, tutorID, credentialID int, req *entities.CredentialRequest) (*entities.TutorCredential, error) {
+	credential, err := uc.newCredential(ctx, tutorID, req)
+	if err != nil {
+		return nil, err
+	}
+	credential.ID = credentialID
+
+	if err := uc.credentialRepo.Update(ctx, credential); err != nil {
+		return nil, err
+	}
+	return uc.getOwnCredential(ctx, tutorID, credential.ID)
+}
+
+// DeleteCredential deletes a credential of the tutor
+func (uc *CredentialUseCase) DeleteCredential(ctx context.Context, tutorID, credentialID int) error {
+	return uc.credentialRepo.Delete(ctx, credentialID, tutorID)
+}
+
+// ListReviewQueue retrieves a page of the credentials with a status, pending ones by default,
+// with their tutors and links to their proof documents
+func (uc *CredentialUseCase) ListReviewQueue(ctx context.Context, filters *entities.CredentialFilters) ([]entities.TutorCredential, error) {
+	if filters.Status == "" {
+		filters.Status = entities.CredentialPending
+	}
+	if filters.Limit <= 0 {
+		filters.Limit = defaultModerationListLimit
+	}
+	if filters.Limit > maxModerationListLimit {
+		filters.Limit = maxModerationListLimit
+	}
+	if filters.Offset < 0 {
+		filters.Offset = 0
+	}
+
+	credentials, err := uc.credentialRepo.ListForReview(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	tutorIDs := make([]int, len(credentials))
+	for i := range credentials {
+		tutorIDs[i] = credentials[i].TutorID
+	}
+	tutors, err := uc.userRepo.GetByIDs(ctx, tutorIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range credentials {
+		credentials[i].Tutor = tutors[credentials[i].TutorID]
+		if err := uc.uploadUseCase.SignUpload(credentials[i].Document); err != nil {
+			return nil, err
+		}
+	}
+	return credentials, nil
+}
+
+// VerifyCredential marks a credential as verified and tells the tutor
+func (uc *CredentialUseCase) VerifyCredential(ctx context.Context, adminID, credentialID int) error {
+	return uc.review(ctx, adminID, credentialID, entities.CredentialVerified, "")
+}
+
+// RejectCredential rejects a credential for a reason, which is shown to the tutor
+func (uc *CredentialUseCase) RejectCredential(ctx context.Context, adminID, credentialID int, req *entities.RejectCredentialRequest) error {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return entities.ErrRejectionReasonRequired
+	}
+	return uc.review(ctx, adminID, credentialID, entities.CredentialRejected, reason)
+}
+
+// review records an admin's decision on a credential and notifies the tutor of it
+func (uc *CredentialUseCase) review(ctx context.Context, adminID, credentialID int, status entities.CredentialStatus, reason string) error {
+	credential, err := uc.credentialRepo.GetByID(ctx, credentialID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.credentialRepo.Review(ctx, credentialID, adminID, status, &reason); err != nil {
+		return err
+	}
+
+	notification := &entities.Notification{
+		UserID: credential.TutorID,
+		Type:   entities.NotificationCredentialReviewed,
+		Title:  "Your credential was verified",
+		Body:   fmt.Sprintf("\"%s\" is now shown as verified on your profile.", credential.Title),
+		Data: map[string]interface{}{
+			"credential_id": credential.ID,
+			"status":        status,
+		},
+	}
+	if status == entities.CredentialRejected {
+		notification.Title = "Your credential was not verified"
+		notification.Body = fmt.Sprintf("\"%s\" was rejected: %s", credential.Title, reason)
+		notification.Data["reason"] = reason
+	}
+	if err := uc.notifier.Notify(ctx, notification); err != nil {
+		logger.Error("Failed to notify tutor of credential review", "credential_id", credential.ID, "error", err)
+	}
+	return nil
+}
+
+// newCredential validates a credential request, checking that its proof document is one of
+// the tutor's uploads for a credential
+func (uc *CredentialUseCase) newCredential(ctx context.Context, tutorID int, req *entities.CredentialRequest) (*entities.TutorCredential, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	document, err := uc.uploadRepo.GetByID(ctx, req.DocumentID)
+	if err != nil {
+		if errors.Is(err, entities.ErrUploadNotFound) {
+			return nil, entities.ErrCredentialDocument
+		}
+		return nil, err
+	}
+	if document.UserID != tutorID || document.Purpose != entities.UploadCredential {
+		return nil, entities.ErrCredentialDocument
+	}
+
+	return &entities.TutorCredential{
+		TutorID:      tutorID,
+		Kind:         req.Kind,
+		Title:        req.Title,
+		Issuer:       req.Issuer,
+		FieldOfStudy: req.FieldOfStudy,
+		Year:         req.Year,
+		DocumentID:   req.DocumentID,
+	}, nil
+}
+
+// getOwnCredential retrieves a credential of the tutor with a link to its proof document
+func (uc *CredentialUseCase) getOwnCredential(ctx context.Context, tutorID, credentialID int) (*entities.TutorCredential, error) {
+	credential, err := uc.credentialRepo.GetByID(ctx, credentialID)
+	if err != nil {
+		return nil, err
+	}
+	if credential.TutorID != tutorID {
+		return nil, entities.ErrCredentialNotFound
+	}
+
+	if err := uc.uploadUseCase.SignUpload(credential.Document); err != nil {
+		return nil, err
+	}
+	return credential, nil
+}
@@ -0,0 +1,326 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/repositories"
+	"tongly-backend/pkg/pdf"
+)
+
+// EarningsUseCase handles business logic for tutor earnings, payouts and statements
+type EarningsUseCase struct {
+	earningsRepo *repositories.EarningsRepository
+	userRepo     *repositories.UserRepository
+	holdPeriod   time.Duration
+}
+
+// NewEarningsUseCase creates a new EarningsUseCase
+func NewEarningsUseCase(
+	earningsRepo *repositories.EarningsRepository,
+	userRepo *repositories.UserRepository,
+	holdDays int,
+) *EarningsUseCase {
+	return &EarningsUseCase{
+		earningsRepo: earningsRepo,
+		userRepo:     userRepo,
+		holdPeriod:   time.Duration(holdDays) * 24 * time.Hour,
+	}
+}
+
+// GetEarnings retrieves a tutor's earnings in [from, to) with totals by period, language and student
+func (uc *EarningsUseCase) GetEarnings(ctx context.Context, tutorID int, from, to time.Time, period entities.EarningsPeriod) (*entities.TutorEarnings, error) {
+	if !from.Before(to) {
+		return nil, entities.ErrInvalidEarningsRange
+	}
+	switch period {
+	case entities.EarningsPeriodDay, entities.EarningsPeriodWeek, entities.EarningsPeriodMonth:
+	default:
+		return nil, entities.ErrInvalidEarningsRange
+	}
+
+	items, err := uc.getLineItems(ctx, tutorID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	earnings := &entities.TutorEarnings{
+		From:      from,
+		To:        to,
+		Period:    period,
+		LineItems: items,
+	}
+	if earnings.LineItems == nil {
+		earnings.LineItems = []entities.EarningsLineItem{}
+	}
+
+	earnings.ByPeriod = aggregateEarnings(items, func(item entities.EarningsLineItem) (string, string) {
+		key := periodKey(item.OccurredAt, period)
+		return key, key
+	})
+	earnings.ByLanguage = aggregateEarnings(items, func(item entities.EarningsLineItem) (string, string) {
+		return strconv.Itoa(item.LanguageID), item.LanguageName
+	})
+	earnings.ByStudent = aggregateEarnings(items, func(item entities.EarningsLineItem) (string, string) {
+		return strconv.Itoa(item.StudentID), item.StudentName
+	})
+
+	for _, item := range items {
+		earnings.Total += item.Amount
+	}
+	earnings.Total = entities.RoundMoney(earnings.Total)
+
+	return earnings, nil
+}
+
+// GetPayoutLedger retrieves a tutor's balances and the ledger of earnings and payouts
+func (uc *EarningsUseCase) GetPayoutLedger(ctx context.Context, tutorID int) (*entities.PayoutLedger, error) {
+	items, err := uc.getLineItems(ctx, tutorID, time.Time{}, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	payouts, err := uc.earningsRepo.GetPayouts(ctx, tutorID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	ledger := &entities.PayoutLedger{
+		HoldDays: int(uc.holdPeriod.Hours() / 24),
+		Entries:  []entities.PayoutLedgerEntry{},
+	}
+
+	for _, item := range items {
+		lessonID := item.LessonID
+		availableAt := item.AvailableAt
+		status := "available"
+		if availableAt.After(now) {
+			status = "pending"
+			ledger.Balance.Pending += item.Amount
+		} else {
+			ledger.Balance.Available += item.Amount
+		}
+		ledger.Balance.Earned += item.Amount
+
+		ledger.Entries = append(ledger.Entries, entities.PayoutLedgerEntry{
+			Type:        string(item.Type),
+			Amount:      item.Amount,
+			OccurredAt:  item.OccurredAt,
+			AvailableAt: &availableAt,
+			LessonID:    &lessonID,
+			Status:      status,
+		})
+	}
+
+	for _, payout := range payouts {
+		if payout.Status == entities.PayoutStatusRejected {
+			continue
+		}
+		payoutID := payout.ID
+		ledger.Balance.Available -= payout.Amount
+		ledger.Balance.PaidOut += payout.Amount
+
+		ledger.Entries = append(ledger.Entries, entities.PayoutLedgerEntry{
+			Type:       "payout",
+			Amount:     -payout.Amount,
+			OccurredAt: payout.RequestedAt,
+			PayoutID:   &payoutID,
+			Status:     string(payout.Status),
+		})
+	}
+
+	// Newest entries first
+	sort.SliceStable(ledger.Entries, func(i, j int) bool {
+		return ledger.Entries[i].OccurredAt.After(ledger.Entries[j].OccurredAt)
+	})
+
+	ledger.Balance.Pending = entities.RoundMoney(ledger.Balance.Pending)
+	ledger.Balance.Available = entities.RoundMoney(ledger.Balance.Available)
+	ledger.Balance.PaidOut = entities.RoundMoney(ledger.Balance.PaidOut)
+	ledger.Balance.Earned = entities.RoundMoney(ledger.Balance.Earned)
+
+	return ledger, nil
+}
+
+// RequestPayout requests a payout of part of the tutor's available balance
+func (uc *EarningsUseCase) RequestPayout(ctx context.Context, tutorID int, req *entities.PayoutRequest) (*entities.TutorPayout, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	payout := &entities.TutorPayout{
+		TutorID: tutorID,
+		Amount:  entities.RoundMoney(req.Amount),
+		Status:  entities.PayoutStatusRequested,
+	}
+
+	if err := uc.earningsRepo.CreatePayout(ctx, payout, uc.holdPeriod); err != nil {
+		return nil, err
+	}
+
+	return payout, nil
+}
+
+// GetPayouts retrieves all payouts of a tutor
+func (uc *EarningsUseCase) GetPayouts(ctx context.Context, tutorID int) ([]entities.TutorPayout, error) {
+	return uc.earningsRepo.GetPayouts(ctx, tutorID)
+}
+
+// GetMonthlyStatementCSV renders the statement of a tutor for the month starting at monthStart as CSV
+func (uc *EarningsUseCase) GetMonthlyStatementCSV(ctx context.Context, tutorID int, monthStart time.Time) ([]byte, error) {
+	items, err := uc.getLineItems(ctx, tutorID, monthStart, monthStart.AddDate(0, 1, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	rows := [][]string{{"date", "type", "lesson_id", "student", "language", "amount", "available_at"}}
+	var total float64
+	for _, item := range items {
+		rows = append(rows, []string{
+			item.OccurredAt.Format(time.DateOnly),
+			string(item.Type),
+			strconv.Itoa(item.LessonID),
+			item.StudentName,
+			item.LanguageName,
+			formatMoney(item.Amount),
+			item.AvailableAt.Format(time.DateOnly),
+		})
+		total += item.Amount
+	}
+	rows = append(rows, []string{"", "total", "", "", "", formatMoney(total), ""})
+
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// GetMonthlyStatementPDF renders the statement of a tutor for the month starting at monthStart as PDF
+func (uc *EarningsUseCase) GetMonthlyStatementPDF(ctx context.Context, tutorID int, monthStart time.Time) ([]byte, error) {
+	tutor, err := uc.userRepo.GetByID(ctx, tutorID)
+	if err != nil {
+		return nil, err
+	}
+	if tutor == nil {
+		return nil, entities.ErrUserNotFound
+	}
+
+	items, err := uc.getLineItems(ctx, tutorID, monthStart, monthStart.AddDate(0, 1, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	tutorName := tutor.FirstName + " " + tutor.LastName
+	if tutor.FirstName == "" && tutor.LastName == "" {
+		tutorName = tutor.Username
+	}
+
+	doc := pdf.New()
+	doc.AddLines(
+		"Tongly - monthly earnings statement",
+		"",
+		"Tutor:  "+tutorName,
+		"Period: "+monthStart.Format("January 2006"),
+		"",
+		fmt.Sprintf("%-10s  %-21s  %6s  %-22s  %-14s  %9s", "Date", "Type", "Lesson", "Student", "Language", "Amount"),
+		strings.Repeat("-", 92),
+	)
+
+	var total float64
+	subtotals := make(map[entities.EarningsItemType]float64)
+	for _, item := range items {
+		doc.AddLine(fmt.Sprintf("%-10s  %-21s  %6d  %-22.22s  %-14.14s  %9s",
+			item.OccurredAt.Format(time.DateOnly),
+			item.Type,
+			item.LessonID,
+			item.StudentName,
+			item.LanguageName,
+			formatMoney(item.Amount),
+		))
+		total += item.Amount
+		subtotals[item.Type] += item.Amount
+	}
+
+	doc.AddLines(
+		"",
+		fmt.Sprintf("%-30s %9s", "Lessons:", formatMoney(subtotals[entities.EarningsItemLesson])),
+		fmt.Sprintf("%-30s %9s", "Late cancellation fees:", formatMoney(subtotals[entities.EarningsItemLateCancellationFee])),
+		fmt.Sprintf("%-30s %9s", "No-show fees:", formatMoney(subtotals[entities.EarningsItemNoShowFee])),
+		fmt.Sprintf("%-30s %9s", "Total:", formatMoney(total)),
+	)
+
+	return doc.Bytes(), nil
+}
+
+// getLineItems retrieves line items and sets the date at which each becomes available for payout
+func (uc *EarningsUseCase) getLineItems(ctx context.Context, tutorID int, from, to time.Time) ([]entities.EarningsLineItem, error) {
+	items, err := uc.earningsRepo.GetLineItems(ctx, tutorID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range items {
+		items[i].AvailableAt = items[i].OccurredAt.Add(uc.holdPeriod)
+	}
+
+	return items, nil
+}
+
+// aggregateEarnings groups line items by the key returned from keyFn, keeping the first-seen order
+func aggregateEarnings(items []entities.EarningsLineItem, keyFn func(entities.EarningsLineItem) (string, string)) []entities.EarningsTotal {
+	totals := []entities.EarningsTotal{}
+	index := make(map[string]int)
+
+	for _, item := range items {
+		key, label := keyFn(item)
+		i, ok := index[key]
+		if !ok {
+			i = len(totals)
+			index[key] = i
+			totals = append(totals, entities.EarningsTotal{Key: key, Label: label})
+		}
+
+		totals[i].Amount += item.Amount
+		if item.Type == entities.EarningsItemLesson {
+			totals[i].Lessons++
+		} else {
+			totals[i].Fees += item.Amount
+		}
+	}
+
+	for i := range totals {
+		totals[i].Amount = entities.RoundMoney(totals[i].Amount)
+		totals[i].Fees = entities.RoundMoney(totals[i].Fees)
+	}
+
+	return totals
+}
+
+// periodKey returns the key of the period that contains t
+func periodKey(t time.Time, period entities.EarningsPeriod) string {
+	switch period {
+	case entities.EarningsPeriodDay:
+		return t.Format(time.DateOnly)
+	case entities.EarningsPeriodWeek:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	default:
+		return t.Format("2006-01")
+	}
+}
+
+// formatMoney formats an amount with two decimals
+func formatMoney(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
@@ -0,0 +1,217 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"tongly-backend/internal/emails"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/logger"
+	"tongly-backend/internal/repositories"
+	"tongly-backend/pkg/mailer"
+)
+
+const (
+	// emailMaxAttempts is higher than the job default so that a mail server outage of a few hours is survived
+	emailMaxAttempts = 8
+
+	// weeklySummaryHour is the hour (UTC) on Mondays at which weekly summaries are sent
+	weeklySummaryHour = 8
+)
+
+// notificationTemplates maps the notification events that are emailed to their templates
+var notificationTemplates = map[entities.NotificationType]emails.Template{
+	entities.NotificationLessonBooked:      emails.TemplateLessonBooked,
+	entities.NotificationLessonCancelled:   emails.TemplateLessonCancelled,
+	entities.NotificationLessonRescheduled: emails.TemplateLessonRescheduled,
+	entities.NotificationLessonReminder:    emails.TemplateLessonReminder,
+}
+
+// EmailUseCase renders emails and sends them through the job queue, so that sending never
+// blocks a request and failed deliveries are retried
+type EmailUseCase struct {
+	prefsRepo  *repositories.NotificationPreferenceRepository
+	lessonRepo *repositories.LessonRepository
+	jobRepo    *repositories.JobRepository
+	mailer     mailer.Mailer
+	appURL     string
+}
+
+// NewEmailUseCase creates a new EmailUseCase. appURL is the address of the frontend used in links.
+func NewEmailUseCase(
+	prefsRepo *repositories.NotificationPreferenceRepository,
+	lessonRepo *repositories.LessonRepository,
+	jobRepo *repositories.JobRepository,
+	mailer mailer.Mailer,
+	appURL string,
+) *EmailUseCase {
+	return &EmailUseCase{
+		prefsRepo:  prefsRepo,
+		lessonRepo: lessonRepo,
+		jobRepo:    jobRepo,
+		mailer:     mailer,
+		appURL:     strings.TrimRight(appURL, "/"),
+	}
+}
+
+// SendForNotification emails a notification in the recipient's language. Events without an
+// email template are skipped.
+func (uc *EmailUseCase) SendForNotification(ctx context.Context, notification *entities.Notification) error {
+	template, ok := notificationTemplates[notification.Type]
+	if !ok {
+		return nil
+	}
+	lessonID, ok := notification.Data["lesson_id"].(int)
+	if !ok {
+		return nil
+	}
+
+	recipient, err := uc.prefsRepo.GetRecipient(ctx, notification.UserID)
+	if err != nil {
+		return err
+	}
+	lesson, err := uc.lessonRepo.GetByID(ctx, lessonID)
+	if err != nil {
+		return err
+	}
+
+	data := emails.LessonData{
+		Name:      recipient.Name(),
+		Language:  lesson.Language.Name,
+		StartTime: emails.FormatTime(recipient.Locale, lesson.StartTime),
+		IsTutor:   lesson.TutorID == notification.UserID,
+		Trial:     lesson.Type == entities.LessonTypeTrial,
+		Group:     lesson.IsGroup(),
+		LessonURL: uc.appURL + "/lessons",
+	}
+	if data.IsTutor {
+		if lesson.Student != nil {
+			data.OtherName = displayName(lesson.Student)
+		}
+	} else {
+		data.OtherName = displayName(lesson.Tutor)
+	}
+
+	if previous, ok := notification.Data["previous_start_time"].(time.Time); ok {
+		data.PreviousStartTime = emails.FormatTime(recipient.Locale, previous)
+	}
+
+	if kind, ok := notification.Data["kind"].(entities.ReminderKind); ok {
+		data.Soon = kind == entities.Reminder15m
+		data.LessonURL = fmt.Sprintf("%s/lessons/room/%d", uc.appURL, lesson.ID)
+	}
+
+	// Deduplicated notifications, such as reminders, may be sent again when their job is retried
+	var dedupKey string
+	if notification.DedupKey != "" {
+		dedupKey = "email:" + notification.DedupKey
+	}
+
+	msg, err := emails.Render(recipient.Email, recipient.Locale, template, data)
+	if err != nil {
+		return err
+	}
+
+	return uc.enqueue(ctx, msg, dedupKey)
+}
+
+// HandleSendEmail runs a send email job
+func (uc *EmailUseCase) HandleSendEmail(ctx context.Context, job *entities.Job) error {
+	var msg mailer.Message
+	if err := json.Unmarshal(job.Payload, &msg); err != nil {
+		// Retrying would not help, so drop the job
+		logger.Error("Invalid email payload", "job_id", job.ID, "error", err)
+		return nil
+	}
+
+	return uc.mailer.Send(ctx, &msg)
+}
+
+// ScheduleWeeklySummaries enqueues the next summary job, which runs on Monday morning.
+// The job is deduplicated by week, so it runs once however often this is called.
+func (uc *EmailUseCase) ScheduleWeeklySummaries(ctx context.Context) error {
+	monday := nextWeeklySummary(time.Now())
+
+	year, week := monday.ISOWeek()
+	_, _, err := uc.jobRepo.Enqueue(ctx, &entities.JobRequest{
+		Type:     entities.JobTypeWeeklySummaries,
+		Payload:  entities.WeeklySummaryPayload{WeekStart: monday},
+		RunAt:    monday,
+		DedupKey: fmt.Sprintf("weekly_summaries:%d-W%02d", year, week),
+	})
+	return err
+}
+
+// HandleWeeklySummaries runs the weekly summary job, enqueueing one email per user.
+// The emails are deduplicated, so a retry only enqueues the ones that are missing.
+func (uc *EmailUseCase) HandleWeeklySummaries(ctx context.Context, job *entities.Job) error {
+	var payload entities.WeeklySummaryPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		logger.Error("Invalid weekly summary payload", "job_id", job.ID, "error", err)
+		return nil
+	}
+
+	summaries, err := uc.prefsRepo.GetWeeklySummaries(ctx, payload.WeekStart)
+	if err != nil {
+		return err
+	}
+
+	year, week := payload.WeekStart.ISOWeek()
+	for _, summary := range summaries {
+		data := emails.WeeklySummaryData{
+			Name:             summary.Recipient.Name(),
+			LessonsCompleted: summary.LessonsCompleted,
+			UpcomingLessons:  summary.UpcomingLessons,
+			CurrentStreak:    summary.CurrentStreak,
+			AppURL:           uc.appURL,
+		}
+		if summary.NextLessonAt != nil {
+			data.NextLesson = emails.FormatTime(summary.Recipient.Locale, *summary.NextLessonAt)
+		}
+
+		msg, err := emails.Render(summary.Recipient.Email, summary.Recipient.Locale, emails.TemplateWeeklySummary, data)
+		if err != nil {
+			return err
+		}
+
+		dedupKey := fmt.Sprintf("email:weekly_summary:%d:%d-W%02d", summary.Recipient.UserID, year, week)
+		if err := uc.enqueue(ctx, msg, dedupKey); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// nextWeeklySummary returns the first Monday at weeklySummaryHour (UTC) after now. A week whose
+// Monday has passed is not caught up on: a server started on Wednesday sends the next summary.
+func nextWeeklySummary(now time.Time) time.Time {
+	now = now.UTC()
+	daysSinceMonday := (int(now.Weekday()) + 6) % 7
+	monday := time.Date(now.Year(), now.Month(), now.Day()-daysSinceMonday, weeklySummaryHour, 0, 0, 0, time.UTC)
+	if !monday.After(now) {
+		monday = monday.AddDate(0, 0, 7)
+	}
+	return monday
+}
+
+// enqueue stores a rendered email to be sent by the job scheduler
+func (uc *EmailUseCase) enqueue(ctx context.Context, msg *mailer.Message, dedupKey string) error {
+	_, _, err := uc.jobRepo.Enqueue(ctx, &entities.JobRequest{
+		Type:        entities.JobTypeSendEmail,
+		Payload:     msg,
+		DedupKey:    dedupKey,
+		MaxAttempts: emailMaxAttempts,
+	})
+	return err
+}
+
+// displayName returns the full name of a user, or the username if the name is empty
+func displayName(user *entities.User) string {
+	if name := strings.TrimSpace(user.FirstName + " " + user.LastName); name != "" {
+		return name
+	}
+	return user.Username
+}
@@ -0,0 +1,32 @@
+package usecases
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextWeeklySummary(t *testing.T) {
+	monday := time.Date(2024, time.March, 4, weeklySummaryHour, 0, 0, 0, time.UTC)
+	nextMonday := monday.AddDate(0, 0, 7)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{name: "early on Monday", now: monday.Add(-time.Hour), want: monday},
+		{name: "at the send time", now: monday, want: nextMonday},
+		{name: "later on Monday", now: monday.Add(time.Hour), want: nextMonday},
+		{name: "Wednesday", now: monday.AddDate(0, 0, 2), want: nextMonday},
+		{name: "Sunday", now: monday.AddDate(0, 0, 6), want: nextMonday},
+		{name: "other time zone", now: monday.Add(-time.Hour).In(time.FixedZone("UTC+3", 3*60*60)), want: monday},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextWeeklySummary(tt.now); !got.Equal(tt.want) {
+				t.Errorf("nextWeeklySummary(%v) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
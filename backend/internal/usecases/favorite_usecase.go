@@ -0,0 +1,278 @@
+package usecases
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/logger"
+	"tongly-backend/internal/repositories"
+)
+
+// FavoriteUseCase handles business logic for students' favorite tutors and saved searches
+type FavoriteUseCase struct {
+	favoriteRepo *repositories.FavoriteRepository
+	tutorRepo    *repositories.TutorRepository
+	userRepo     *repositories.UserRepository
+	studentRepo  *repositories.StudentRepository
+	notifier     Notifier
+}
+
+// NewFavoriteUseCase creates a new FavoriteUseCase
+func NewFavoriteUseCase(
+	favoriteRepo *repositories.FavoriteRepository,
+	tutorRepo *repositories.TutorRepository,
+	userRepo *repositories.UserRepository,
+	studentRepo *repositories.StudentRepository,
+	notifier Notifier,
+) *FavoriteUseCase {
+	return &FavoriteUseCase{
+		favoriteRepo: favoriteRepo,
+		tutorRepo:    tutorRepo,
+		userRepo:     userRepo,
+		studentRepo:  studentRepo,
+		notifier:     notifier,
+	}
+}
+
+// GetFavorites retrieves a student's favorite tutors with their profiles
+func (uc *FavoriteUseCase) GetFavorites(ctx context.Context, studentID int) ([]entities.FavoriteTutor, error) {
+	favorites, err := uc.favoriteRepo.GetFavorites(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	tutors := make([]entities.TutorProfile, len(favorites))
+	for i := range favorites {
+		tutors[i] = favorites[i].Tutor
+	}
+	if err := populateTutorRelations(ctx, uc.userRepo, uc.studentRepo, tutors); err != nil {
+		return nil, err
+	}
+	for i := range favorites {
+		favorites[i].Tutor = tutors[i]
+	}
+
+	return favorites, nil
+}
+
+// AddFavorite adds a tutor to a student's favorites
+func (uc *FavoriteUseCase) AddFavorite(ctx context.Context, studentID, tutorID int) error {
+	tutor, err := uc.tutorRepo.GetByUserID(ctx, tutorID)
+	if err != nil {
+		return err
+	}
+	if tutor == nil {
+		return entities.ErrNotFound
+	}
+
+	return uc.favoriteRepo.AddFavorite(ctx, studentID, tutorID)
+}
+
+// RemoveFavorite removes a tutor from a student's favorites
+func (uc *FavoriteUseCase) RemoveFavorite(ctx context.Context, studentID, tutorID int) error {
+	return uc.favoriteRepo.RemoveFavorite(ctx, studentID, tutorID)
+}
+
+// GetSavedSearches retrieves a student's saved searches
+func (uc *FavoriteUseCase) GetSavedSearches(ctx context.Context, studentID int) ([]entities.SavedSearch, error) {
+	return uc.favoriteRepo.GetSavedSearches(ctx, studentID)
+}
+
+// CreateSavedSearch saves a student's search. Only tutors matching it from now on are announced.
+func (uc *FavoriteUseCase) CreateSavedSearch(ctx context.Context, studentID int, req *entities.SavedSearchRequest) (*entities.SavedSearch, error) {
+	search, err := newSavedSearch(studentID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.favoriteRepo.CreateSavedSearch(ctx, search); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
+// UpdateSavedSearch replaces the name, filters and alert setting of a student's saved search
+func (uc *FavoriteUseCase) UpdateSavedSearch(ctx context.Context, studentID, searchID int, req *entities.SavedSearchRequest) (*entities.SavedSearch, error) {
+	search, err := newSavedSearch(studentID, req)
+	if err != nil {
+		return nil, err
+	}
+	search.ID = searchID
+
+	if err := uc.favoriteRepo.UpdateSavedSearch(ctx, search); err != nil {
+		return nil, err
+	}
+	return search, nil
+}
+
+// DeleteSavedSearch deletes a student's saved search
+func (uc *FavoriteUseCase) DeleteSavedSearch(ctx context.Context, studentID, searchID int) error {
+	return uc.favoriteRepo.DeleteSavedSearch(ctx, searchID, studentID)
+}
+
+// HandleTutorAlerts runs the hourly tutor alert job, notifying students of new tutors matching their
+// saved searches and of new availability of their favorite tutors. What a student was told
+// about is recorded right after, so an earlier attempt's alerts are not sent again. An attempt
+// that fails between the two finds the same alerts, which carry dedup keys derived from what
+// they are about, so that the retry does not notify twice.
+func (uc *FavoriteUseCase) HandleTutorAlerts(ctx context.Context, job *entities.Job) error {
+	if err := uc.alertSavedSearchMatches(ctx); err != nil {
+		return err
+	}
+	return uc.alertFavoriteAvailability(ctx)
+}
+
+// alertSavedSearchMatches notifies students of the tutors newly matching their saved searches
+func (uc *FavoriteUseCase) alertSavedSearchMatches(ctx context.Context) error {
+	searches, err := uc.favoriteRepo.GetAlertingSavedSearches(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range searches {
+		search := &searches[i]
+		tutorIDs, err := uc.favoriteRepo.GetNewSavedSearchMatches(ctx, search)
+		if err != nil {
+			// A search whose filters no longer work must not hold up everyone else's alerts
+			logger.Error("Failed to match saved search", "saved_search_id", search.ID, "error", err)
+			continue
+		}
+		if len(tutorIDs) == 0 {
+			continue
+		}
+
+		names, err := uc.tutorNames(ctx, tutorIDs)
+		if err != nil {
+			return err
+		}
+
+		body := fmt.Sprintf("%s matches your search \"%s\".", names, search.Name)
+		if len(tutorIDs) > 1 {
+			body = fmt.Sprintf("%d new tutors match your search \"%s\": %s.", len(tutorIDs), search.Name, names)
+		}
+		err = uc.notifier.Notify(ctx, &entities.Notification{
+			UserID: search.StudentID,
+			Type:   entities.NotificationSavedSearchMatch,
+			Title:  "New tutors for your saved search",
+			Body:   body,
+			Data: map[string]interface{}{
+				"saved_search_id": search.ID,
+				"tutor_ids":       tutorIDs,
+			},
+			DedupKey: savedSearchMatchKey(search.ID, tutorIDs),
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := uc.favoriteRepo.AddSavedSearchMatches(ctx, search.ID, tutorIDs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// alertFavoriteAvailability notifies students of favorite tutors who added or changed their
+// availability since the students were last told
+func (uc *FavoriteUseCase) alertFavoriteAvailability(ctx context.Context) error {
+	changes, err := uc.favoriteRepo.GetFavoriteAvailabilityChanges(ctx)
+	if err != nil {
+		return err
+	}
+
+	tutorIDs := make([]int, 0, len(changes))
+	for _, change := range changes {
+		tutorIDs = append(tutorIDs, change.TutorID)
+	}
+	tutors, err := uc.userRepo.GetByIDs(ctx, tutorIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		tutor, ok := tutors[change.TutorID]
+		if !ok {
+			continue
+		}
+
+		err := uc.notifier.Notify(ctx, &entities.Notification{
+			UserID:   change.StudentID,
+			Type:     entities.NotificationFavoriteAvailability,
+			Title:    "A favorite tutor has new availability",
+			Body:     fmt.Sprintf("%s has opened new times for lessons.", displayName(tutor)),
+			Data:     map[string]interface{}{"tutor_id": change.TutorID},
+			DedupKey: fmt.Sprintf("favorite_availability:%d:%d:%d", change.StudentID, change.TutorID, change.CheckedAt.UnixMicro()),
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := uc.favoriteRepo.MarkFavoriteAvailabilityChecked(ctx, change.StudentID, change.TutorID, change.CheckedAt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// savedSearchMatchKey returns the dedup key of an alert about tutors newly matching a saved
+// search. The tutor IDs are hashed, as a long list would not fit the key.
+func savedSearchMatchKey(searchID int, tutorIDs []int) string {
+	ids := make([]string, len(tutorIDs))
+	for i, id := range tutorIDs {
+		ids[i] = fmt.Sprint(id)
+	}
+	sum := sha256.Sum256([]byte(strings.Join(ids, ",")))
+	return fmt.Sprintf("saved_search_match:%d:%s", searchID, hex.EncodeToString(sum[:16]))
+}
+
+// tutorNames lists the names of the first tutors of an alert, noting how many more there are
+func (uc *FavoriteUseCase) tutorNames(ctx context.Context, tutorIDs []int) (string, error) {
+	shown := tutorIDs
+	if len(shown) > entities.MaxSavedSearchAlertTutors {
+		shown = shown[:entities.MaxSavedSearchAlertTutors]
+	}
+
+	users, err := uc.userRepo.GetByIDs(ctx, shown)
+	if err != nil {
+		return "", err
+	}
+
+	names := make([]string, 0, len(shown))
+	for _, id := range shown {
+		if user, ok := users[id]; ok {
+			names = append(names, displayName(user))
+		}
+	}
+	if more := len(tutorIDs) - len(names); more > 0 {
+		names = append(names, fmt.Sprintf("%d more", more))
+	}
+
+	return strings.Join(names, ", "), nil
+}
+
+// newSavedSearch validates a request to save a search and normalizes its filters like a search
+func newSavedSearch(studentID int, req *entities.SavedSearchRequest) (*entities.SavedSearch, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if err := normalizeSearchFilters(&req.Filters); err != nil {
+		return nil, err
+	}
+
+	notify := true
+	if req.Notify != nil {
+		notify = *req.Notify
+	}
+
+	return &entities.SavedSearch{
+		StudentID: studentID,
+		Name:      req.Name,
+		Filters:   req.Filters,
+		Notify:    notify,
+	}, nil
+}
@@ -0,0 +1,45 @@
+package usecases
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSavedSearchMatchKey(t *testing.T) {
+	many := make([]int, 500)
+	for i := range many {
+		many[i] = 100000 + i
+	}
+
+	key := savedSearchMatchKey(7, []int{3, 5})
+	if !strings.HasPrefix(key, "saved_search_match:7:") {
+		t.Errorf("savedSearchMatchKey() = %q, want the saved_search_match:7: prefix", key)
+	}
+	if again := savedSearchMatchKey(7, []int{3, 5}); again != key {
+		t.Errorf("savedSearchMatchKey() = %q on a retry, want %q", again, key)
+	}
+
+	tests := []struct {
+		name     string
+		searchID int
+		tutorIDs []int
+	}{
+		{name: "other tutors", searchID: 7, tutorIDs: []int{3, 6}},
+		{name: "more tutors", searchID: 7, tutorIDs: []int{3, 5, 9}},
+		{name: "other search", searchID: 8, tutorIDs: []int{3, 5}},
+		{name: "many tutors", searchID: 7, tutorIDs: many},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := savedSearchMatchKey(tt.searchID, tt.tutorIDs)
+			if got == key {
+				t.Errorf("savedSearchMatchKey() = %q, the same as for another alert", got)
+			}
+			// Email keys add the "email:" prefix and must fit the 200 character column too
+			if len("email:"+got) > 200 {
+				t.Errorf("savedSearchMatchKey() is %d characters long", len(got))
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package usecases
 
 import (
 	"context"
+	"fmt"
 	"math/rand"
+	"time"
 	"tongly-backend/internal/entities"
 	"tongly-backend/internal/repositories"
 )
@@ -11,13 +13,22 @@ import (
 type GameUseCase struct {
 	gameRepo     *repositories.GameRepository
 	languageRepo *repositories.LanguageRepository
+	jobRepo      *repositories.JobRepository
+	notifier     Notifier
 }
 
 // NewGameUseCase creates a new GameUseCase
-func NewGameUseCase(gameRepo *repositories.GameRepository, languageRepo *repositories.LanguageRepository) *GameUseCase {
+func NewGameUseCase(
+	gameRepo *repositories.GameRepository,
+	languageRepo *repositories.LanguageRepository,
+	jobRepo *repositories.JobRepository,
+	notifier Notifier,
+) *GameUseCase {
 	return &GameUseCase{
 		gameRepo:     gameRepo,
 		languageRepo: languageRepo,
+		jobRepo:      jobRepo,
+		notifier:     notifier,
 	}
 }
 
@@ -140,3 +151,41 @@ func (uc *GameUseCase) GetLeaderboard(ctx context.Context, userID int) ([]entiti
 
 	return leaderboard, userRank, nil
 }
+
+// ScheduleStreakReminders enqueues today's streak reminder job. The job is deduplicated by date,
+// so it runs once a day however often this is called and however many replicas call it.
+func (uc *GameUseCase) ScheduleStreakReminders(ctx context.Context) error {
+	now := time.Now().UTC()
+	runAt := time.Date(now.Year(), now.Month(), now.Day(), entities.StreakReminderHour, 0, 0, 0, time.UTC)
+
+	_, _, err := uc.jobRepo.Enqueue(ctx, &entities.JobRequest{
+		Type:     entities.JobTypeStreakReminders,
+		RunAt:    runAt,
+		DedupKey: "streak_reminders:" + now.Format("2006-01-02"),
+	})
+	return err
+}
+
+// HandleStreakReminders runs the streak reminder job, notifying the students who have not played
+// yet today that their streak is about to end. Students reminded by an earlier attempt are skipped.
+func (uc *GameUseCase) HandleStreakReminders(ctx context.Context, job *entities.Job) error {
+	streaks, err := uc.gameRepo.GetStreaksAtRisk(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, streak := range streaks {
+		err := uc.notifier.Notify(ctx, &entities.Notification{
+			UserID: streak.UserID,
+			Type:   entities.NotificationStreakAtRisk,
+			Title:  "Your streak is at risk",
+			Body:   fmt.Sprintf("Play a game today to keep your %d-day streak.", streak.CurrentStreak),
+			Data:   map[string]interface{}{"current_streak": streak.CurrentStreak},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/repositories"
+)
+
+// GroupClassUseCase handles business logic for the public group class catalog
+type GroupClassUseCase struct {
+	groupClassRepo *repositories.GroupClassRepository
+	lessonUseCase  *LessonUseCase
+}
+
+// NewGroupClassUseCase creates a new GroupClassUseCase
+func NewGroupClassUseCase(
+	groupClassRepo *repositories.GroupClassRepository,
+	lessonUseCase *LessonUseCase,
+) *GroupClassUseCase {
+	return &GroupClassUseCase{
+		groupClassRepo: groupClassRepo,
+		lessonUseCase:  lessonUseCase,
+	}
+}
+
+// CreateGroupClass schedules a group lesson for a tutor and publishes it in the catalog
+func (uc *GroupClassUseCase) CreateGroupClass(ctx context.Context, tutorID int, req *entities.GroupClassRequest) (*entities.GroupClass, error) {
+	// Validate request
+	req.ApplyDefaults()
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", entities.ErrInvalidGroupClass, err)
+	}
+
+	// The class is an ordinary group lesson with a catalog listing
+	lesson, err := uc.lessonUseCase.newGroupLesson(ctx, tutorID, &req.GroupLessonRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.groupClassRepo.Create(ctx, lesson, req); err != nil {
+		return nil, err
+	}
+
+	return uc.groupClassRepo.GetByID(ctx, lesson.ID)
+}
+
+// GetGroupClass retrieves a published group class
+func (uc *GroupClassUseCase) GetGroupClass(ctx context.Context, lessonID int) (*entities.GroupClass, error) {
+	return uc.groupClassRepo.GetByID(ctx, lessonID)
+}
+
+// SearchGroupClasses retrieves upcoming published group classes that match the filters
+func (uc *GroupClassUseCase) SearchGroupClasses(ctx context.Context, filters *entities.GroupClassFilters) ([]entities.GroupClass, error) {
+	if filters.From != nil && filters.To != nil && !filters.From.Before(*filters.To) {
+		return nil, entities.ErrInvalidTimeWindow
+	}
+	return uc.groupClassRepo.Search(ctx, filters)
+}
@@ -0,0 +1,64 @@
+package usecases
+
+import (
+	"context"
+	"time"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/repositories"
+)
+
+const (
+	defaultJobListLimit = 50
+	maxJobListLimit     = 200
+)
+
+// JobUseCase handles business logic for inspecting and retrying background jobs
+type JobUseCase struct {
+	jobRepo *repositories.JobRepository
+}
+
+// NewJobUseCase creates a new JobUseCase
+func NewJobUseCase(jobRepo *repositories.JobRepository) *JobUseCase {
+	return &JobUseCase{
+		jobRepo: jobRepo,
+	}
+}
+
+// ListJobs retrieves a page of jobs matching the filters together with the number of jobs per status
+func (uc *JobUseCase) ListJobs(ctx context.Context, filters *entities.JobFilters) (*entities.JobList, error) {
+	if filters.Limit <= 0 {
+		filters.Limit = defaultJobListLimit
+	}
+	if filters.Limit > maxJobListLimit {
+		filters.Limit = maxJobListLimit
+	}
+	if filters.Offset < 0 {
+		filters.Offset = 0
+	}
+
+	jobs, err := uc.jobRepo.List(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := uc.jobRepo.CountByStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.JobList{
+		Jobs:   jobs,
+		Counts: counts,
+	}, nil
+}
+
+// RetryJob puts a failed job back in the queue
+func (uc *JobUseCase) RetryJob(ctx context.Context, jobID int64) (*entities.Job, error) {
+	return uc.jobRepo.Retry(ctx, jobID)
+}
+
+// PruneJobs deletes the jobs that succeeded more than JobRetention ago. Recurring jobs add one
+// row per interval, so the table would otherwise keep growing.
+func (uc *JobUseCase) PruneJobs(ctx context.Context) (int, error) {
+	return uc.jobRepo.DeleteSucceededBefore(ctx, time.Now().Add(-entities.JobRetention))
+}
@@ -0,0 +1,436 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/logger"
+	"tongly-backend/internal/repositories"
+)
+
+// LessonNotesUseCase handles the notes, homework and private student feedback tutors write
+// for their lessons
+type LessonNotesUseCase struct {
+	notesRepo  *repositories.LessonNotesRepository
+	lessonRepo *repositories.LessonRepository
+	notifier   Notifier
+}
+
+// NewLessonNotesUseCase creates a new LessonNotesUseCase
+func NewLessonNotesUseCase(
+	notesRepo *repositories.LessonNotesRepository,
+	lessonRepo *repositories.LessonRepository,
+	notifier Notifier,
+) *LessonNotesUseCase {
+	return &LessonNotesUseCase{
+		notesRepo:  notesRepo,
+		lessonRepo: lessonRepo,
+		notifier:   notifier,
+	}
+}
+
+// SaveNotes writes the notes of a lesson. The students of the lesson are notified the first time.
+func (uc *LessonNotesUseCase) SaveNotes(ctx context.Context, tutorID, lessonID int, req *entities.LessonNotesRequest) (*entities.LessonNotes, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	lesson, err := uc.getTutorLesson(ctx, tutorID, lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = uc.notesRepo.GetNotes(ctx, lessonID)
+	if err != nil && !errors.Is(err, entities.ErrLessonNotesNotFound) {
+		return nil, err
+	}
+	isNew := err != nil
+
+	notes := &entities.LessonNotes{
+		LessonID:    lessonID,
+		TutorID:     tutorID,
+		Summary:     req.Summary,
+		Topics:      req.Topics,
+		Vocabulary:  req.Vocabulary,
+		Corrections: req.Corrections,
+	}
+	if err := uc.notesRepo.SaveNotes(ctx, notes, req.PrivateNotes); err != nil {
+		return nil, err
+	}
+
+	if notes.PrivateNotes, err = uc.notesRepo.GetPrivateNotes(ctx, lessonID); err != nil {
+		return nil, err
+	}
+
+	if isNew {
+		uc.notifyStudents(ctx, lesson, "Lesson notes available", "Your tutor shared notes from your lesson.")
+	}
+
+	return notes, nil
+}
+
+// GetNotes retrieves the notes of a lesson for one of its attendees. Private notes are only
+// included for the tutor.
+func (uc *LessonNotesUseCase) GetNotes(ctx context.Context, userID, lessonID int) (*entities.LessonNotes, error) {
+	lesson, err := getAttendedLesson(ctx, uc.lessonRepo, userID, lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := uc.notesRepo.GetNotes(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	if lesson.TutorID == userID {
+		if notes.PrivateNotes, err = uc.notesRepo.GetPrivateNotes(ctx, lessonID); err != nil {
+			return nil, err
+		}
+	}
+
+	return notes, nil
+}
+
+// GetHomework retrieves the homework of a lesson for one of its attendees. Students only see
+// whether they have done it themselves.
+func (uc *LessonNotesUseCase) GetHomework(ctx context.Context, userID, lessonID int) ([]entities.Homework, error) {
+	lesson, err := getAttendedLesson(ctx, uc.lessonRepo, userID, lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	homeworkByLesson, err := uc.notesRepo.ListHomework(ctx, []int{lessonID})
+	if err != nil {
+		return nil, err
+	}
+
+	homework := homeworkByLesson[lessonID]
+	if homework == nil {
+		homework = []entities.Homework{}
+	}
+	if lesson.TutorID != userID {
+		for i := range homework {
+			homework[i] = homework[i].ForStudent(userID)
+		}
+	}
+
+	return homework, nil
+}
+
+// AddHomework assigns homework in a lesson and notifies its students
+func (uc *LessonNotesUseCase) AddHomework(ctx context.Context, tutorID, lessonID int, req *entities.HomeworkRequest) (*entities.Homework, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	lesson, err := uc.getTutorLesson(ctx, tutorID, lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	homework := &entities.Homework{
+		LessonID:    lessonID,
+		TutorID:     tutorID,
+		Description: req.Description,
+		DueAt:       req.DueAt,
+	}
+	if err := uc.notesRepo.CreateHomework(ctx, homework, entities.MaxHomeworkPerLesson); err != nil {
+		return nil, err
+	}
+
+	uc.notifyStudents(ctx, lesson, "New homework", preview(homework.Description))
+
+	return homework, nil
+}
+
+// UpdateHomework changes the description and due date of homework the tutor assigned
+func (uc *LessonNotesUseCase) UpdateHomework(ctx context.Context, tutorID, homeworkID int, req *entities.HomeworkRequest) (*entities.Homework, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	homework, err := uc.notesRepo.GetHomework(ctx, homeworkID)
+	if err != nil {
+		return nil, err
+	}
+	if homework.TutorID != tutorID {
+		return nil, entities.ErrNotLessonTutor
+	}
+
+	homework.Description = req.Description
+	homework.DueAt = req.DueAt
+	if err := uc.notesRepo.UpdateHomework(ctx, homework); err != nil {
+		return nil, err
+	}
+
+	return homework, nil
+}
+
+// DeleteHomework removes homework the tutor assigned
+func (uc *LessonNotesUseCase) DeleteHomework(ctx context.Context, tutorID, homeworkID int) error {
+	homework, err := uc.notesRepo.GetHomework(ctx, homeworkID)
+	if err != nil {
+		return err
+	}
+	if homework.TutorID != tutorID {
+		return entities.ErrNotLessonTutor
+	}
+
+	return uc.notesRepo.DeleteHomework(ctx, homeworkID)
+}
+
+// SetHomeworkDone marks homework as done or not done for a student of its lesson
+func (uc *LessonNotesUseCase) SetHomeworkDone(ctx context.Context, studentID, homeworkID int, done bool) (*entities.Homework, error) {
+	homework, err := uc.notesRepo.GetHomework(ctx, homeworkID)
+	if err != nil {
+		return nil, err
+	}
+
+	lesson, err := getAttendedLesson(ctx, uc.lessonRepo, studentID, homework.LessonID)
+	if err != nil {
+		return nil, err
+	}
+	if lesson.TutorID == studentID {
+		return nil, entities.ErrNotLessonAttendee
+	}
+
+	if err := uc.notesRepo.SetHomeworkCompleted(ctx, homeworkID, studentID, done); err != nil {
+		return nil, err
+	}
+
+	homework, err = uc.notesRepo.GetHomework(ctx, homeworkID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := homework.ForStudent(studentID)
+	return &result, nil
+}
+
+// SaveFeedback gives or replaces the tutor's private feedback to a student of a completed
+// lesson. The student is notified the first time.
+func (uc *LessonNotesUseCase) SaveFeedback(ctx context.Context, tutorID, lessonID int, req *entities.StudentFeedbackRequest) (*entities.StudentFeedback, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	lesson, err := uc.getTutorLesson(ctx, tutorID, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	if lesson.GetStatus() != entities.LessonStatusCompleted {
+		return nil, entities.ErrFeedbackNotOpen
+	}
+
+	studentID := req.StudentID
+	if studentID == 0 {
+		studentID = lesson.StudentID
+	}
+	if lesson.IsGroup() {
+		if lesson.Participants, err = uc.lessonRepo.GetParticipants(ctx, lessonID); err != nil {
+			return nil, err
+		}
+	}
+	if studentID == 0 || studentID == tutorID || !lesson.IsAttendee(studentID) {
+		return nil, entities.ErrFeedbackStudent
+	}
+
+	existing, err := uc.notesRepo.ListStudentFeedback(ctx, []int{lessonID}, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	feedback := &entities.StudentFeedback{
+		LessonID:  lessonID,
+		TutorID:   tutorID,
+		StudentID: studentID,
+		Rating:    req.Rating,
+		Comment:   req.Comment,
+	}
+	if err := uc.notesRepo.SaveFeedback(ctx, feedback); err != nil {
+		return nil, err
+	}
+
+	if existing[lessonID] == nil {
+		err := uc.notifier.Notify(ctx, &entities.Notification{
+			UserID: studentID,
+			Type:   entities.NotificationStudentFeedback,
+			Title:  "Feedback from your tutor",
+			Body:   "Your tutor shared feedback on your progress in a lesson.",
+			Data:   map[string]interface{}{"lesson_id": lessonID, "tutor_id": tutorID},
+		})
+		if err != nil {
+			logger.Error("Failed to notify student", "lesson_id", lessonID, "user_id", studentID, "error", err)
+		}
+	}
+
+	return feedback, nil
+}
+
+// GetFeedback retrieves the feedback of a lesson for one of its attendees. The tutor sees the
+// feedback of every student, students only their own.
+func (uc *LessonNotesUseCase) GetFeedback(ctx context.Context, userID, lessonID int) ([]entities.StudentFeedback, error) {
+	lesson, err := getAttendedLesson(ctx, uc.lessonRepo, userID, lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	if lesson.TutorID == userID {
+		return uc.notesRepo.ListLessonFeedback(ctx, lessonID)
+	}
+
+	feedbackByLesson, err := uc.notesRepo.ListStudentFeedback(ctx, []int{lessonID}, userID)
+	if err != nil {
+		return nil, err
+	}
+	feedback := []entities.StudentFeedback{}
+	if own := feedbackByLesson[lessonID]; own != nil {
+		feedback = append(feedback, *own)
+	}
+
+	return feedback, nil
+}
+
+// GetStudentHistory retrieves the lessons a tutor had with a student, with notes including the
+// tutor's private ones
+func (uc *LessonNotesUseCase) GetStudentHistory(ctx context.Context, tutorID, studentID int) (*entities.LessonHistory, error) {
+	return uc.getHistory(ctx, tutorID, studentID, true)
+}
+
+// GetTutorHistory retrieves the lessons a student had with a tutor, with the shared notes
+func (uc *LessonNotesUseCase) GetTutorHistory(ctx context.Context, studentID, tutorID int) (*entities.LessonHistory, error) {
+	return uc.getHistory(ctx, tutorID, studentID, false)
+}
+
+// GetStudentHomework lists the homework of all lessons a student attended, optionally only
+// the homework they have not done yet
+func (uc *LessonNotesUseCase) GetStudentHomework(ctx context.Context, studentID int, openOnly bool) ([]entities.StudentHomework, error) {
+	homework, err := uc.notesRepo.ListStudentHomework(ctx, studentID, openOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range homework {
+		homework[i].Overdue = homework[i].IsOverdue(now)
+	}
+
+	return homework, nil
+}
+
+// getHistory assembles the history of a student with a tutor from the lessons, notes, homework
+// and the feedback the student was given
+func (uc *LessonNotesUseCase) getHistory(ctx context.Context, tutorID, studentID int, withPrivate bool) (*entities.LessonHistory, error) {
+	lessons, err := uc.notesRepo.GetHistoryLessons(ctx, tutorID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &entities.LessonHistory{
+		TutorID:   tutorID,
+		StudentID: studentID,
+		Lessons:   lessons,
+	}
+	if len(lessons) == 0 {
+		return history, nil
+	}
+
+	lessonIDs := make([]int, len(lessons))
+	for i, lesson := range lessons {
+		lessonIDs[i] = lesson.LessonID
+	}
+
+	notes, err := uc.notesRepo.ListNotes(ctx, lessonIDs, withPrivate)
+	if err != nil {
+		return nil, err
+	}
+	homework, err := uc.notesRepo.ListHomework(ctx, lessonIDs)
+	if err != nil {
+		return nil, err
+	}
+	feedback, err := uc.notesRepo.ListStudentFeedback(ctx, lessonIDs, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range history.Lessons {
+		entry := &history.Lessons[i]
+		entry.Notes = notes[entry.LessonID]
+		entry.Feedback = feedback[entry.LessonID]
+		for _, assignment := range homework[entry.LessonID] {
+			entry.Homework = append(entry.Homework, assignment.ForStudent(studentID))
+		}
+	}
+
+	return history, nil
+}
+
+// getTutorLesson retrieves a lesson the tutor can write notes for
+func (uc *LessonNotesUseCase) getTutorLesson(ctx context.Context, tutorID, lessonID int) (*entities.Lesson, error) {
+	lesson, err := uc.lessonRepo.GetByID(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	if lesson.TutorID != tutorID {
+		return nil, entities.ErrNotLessonTutor
+	}
+	if lesson.CancelledAt != nil {
+		return nil, entities.ErrLessonNotesClosed
+	}
+	return lesson, nil
+}
+
+// notifyStudents tells the student or the participants of a lesson about new notes or homework,
+// logging failures instead of failing the request
+func (uc *LessonNotesUseCase) notifyStudents(ctx context.Context, lesson *entities.Lesson, title, body string) {
+	studentIDs := []int{}
+	if lesson.IsGroup() {
+		participants, err := uc.lessonRepo.GetParticipants(ctx, lesson.ID)
+		if err != nil {
+			logger.Error("Failed to load participants", "lesson_id", lesson.ID, "error", err)
+			return
+		}
+		for _, participant := range participants {
+			studentIDs = append(studentIDs, participant.StudentID)
+		}
+	} else if lesson.StudentID != 0 {
+		studentIDs = append(studentIDs, lesson.StudentID)
+	}
+
+	for _, studentID := range studentIDs {
+		err := uc.notifier.Notify(ctx, &entities.Notification{
+			UserID: studentID,
+			Type:   entities.NotificationLessonNotes,
+			Title:  title,
+			Body:   body,
+			Data:   map[string]interface{}{"lesson_id": lesson.ID, "tutor_id": lesson.TutorID},
+		})
+		if err != nil {
+			logger.Error("Failed to notify student", "lesson_id", lesson.ID, "user_id", studentID, "error", err)
+		}
+	}
+}
+
+// getAttendedLesson retrieves a lesson the user is the tutor, student or a participant of
+func getAttendedLesson(ctx context.Context, lessonRepo *repositories.LessonRepository, userID, lessonID int) (*entities.Lesson, error) {
+	lesson, err := lessonRepo.GetByID(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	if lesson.IsAttendee(userID) {
+		return lesson, nil
+	}
+	if !lesson.IsGroup() {
+		return nil, entities.ErrNotLessonAttendee
+	}
+
+	participant, err := lessonRepo.GetParticipant(ctx, lessonID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if participant == nil {
+		return nil, entities.ErrNotLessonAttendee
+	}
+	return lesson, nil
+}
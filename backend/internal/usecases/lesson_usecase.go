@@ -3,8 +3,10 @@ package usecases
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 	"tongly-backend/internal/entities"
+	"tongly-backend/internal/logger"
 	"tongly-backend/internal/repositories"
 )
 
@@ -15,6 +17,8 @@ type LessonUseCase struct {
 	tutorRepo   *repositories.TutorRepository
 	studentRepo *repositories.StudentRepository
 	langRepo    *repositories.LanguageRepository
+	busyRepo    *repositories.BusyTimeRepository
+	notifier    Notifier
 }
 
 // NewLessonUseCase creates a new LessonUseCase
@@ -24,6 +28,8 @@ func NewLessonUseCase(
 	tutorRepo *repositories.TutorRepository,
 	studentRepo *repositories.StudentRepository,
 	langRepo *repositories.LanguageRepository,
+	busyRepo *repositories.BusyTimeRepository,
+	notifier Notifier,
 ) *LessonUseCase {
 	return &LessonUseCase{
 		lessonRepo:  lessonRepo,
@@ -31,12 +37,15 @@ func NewLessonUseCase(
 		tutorRepo:   tutorRepo,
 		studentRepo: studentRepo,
 		langRepo:    langRepo,
+		busyRepo:    busyRepo,
+		notifier:    notifier,
 	}
 }
 
 // BookLesson books a new lesson
 func (uc *LessonUseCase) BookLesson(ctx context.Context, studentID int, req *entities.LessonBookingRequest) (*entities.Lesson, error) {
 	// Validate request
+	req.ApplyDefaults()
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
@@ -65,7 +74,7 @@ func (uc *LessonUseCase) BookLesson(ctx context.Context, studentID int, req *ent
 		return nil, err
 	}
 	if language == nil {
-		return nil, errors.New("language not found")
+		return nil, entities.ErrLanguageNotFound
 	}
 
 	// Check tutor availability
@@ -73,6 +82,24 @@ func (uc *LessonUseCase) BookLesson(ctx context.Context, studentID int, req *ent
 		return nil, errors.New("lesson start time must be in the future")
 	}
 
+	// Busy times imported from the tutor's external calendars are unavailable
+	busy, err := uc.busyRepo.HasBusyTime(ctx, req.TutorID, req.StartTime, req.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	if busy {
+		return nil, entities.ErrTutorBusy
+	}
+
+	// Trial lessons have a fixed price, regular lessons are priced at the tutor's current hourly rate
+	price := entities.CalculateLessonPrice(tutorProfile.HourlyRate, req.StartTime, req.EndTime)
+	if req.Type == entities.LessonTypeTrial {
+		if !tutorProfile.OffersTrial {
+			return nil, entities.ErrTrialNotOffered
+		}
+		price = tutorProfile.TrialPrice
+	}
+
 	// Create the lesson
 	lesson := &entities.Lesson{
 		StudentID:  studentID,
@@ -81,13 +108,34 @@ func (uc *LessonUseCase) BookLesson(ctx context.Context, studentID int, req *ent
 		StartTime:  req.StartTime,
 		EndTime:    req.EndTime,
 		Notes:      req.Notes,
+		Type:       req.Type,
+		Price:      price,
 	}
 
-	// Save to database
+	// Save to database (also enforces the one-trial-per-tutor limit)
 	if err := uc.lessonRepo.Create(ctx, lesson); err != nil {
 		return nil, err
 	}
 
+	lessonKind := language.Name
+	if lesson.Type == entities.LessonTypeTrial {
+		lessonKind = "trial " + language.Name
+	}
+	uc.notify(ctx, &entities.Notification{
+		UserID: lesson.TutorID,
+		Type:   entities.NotificationLessonBooked,
+		Title:  "New lesson booked",
+		Body:   fmt.Sprintf("A student booked a %s lesson with you.", lessonKind),
+		Data:   map[string]interface{}{"lesson_id": lesson.ID, "start_time": lesson.StartTime},
+	})
+	uc.notify(ctx, &entities.Notification{
+		UserID: studentID,
+		Type:   entities.NotificationLessonBooked,
+		Title:  "Lesson booked",
+		Body:   fmt.Sprintf("Your %s lesson is booked.", lessonKind),
+		Data:   map[string]interface{}{"lesson_id": lesson.ID, "start_time": lesson.StartTime},
+	})
+
 	return lesson, nil
 }
 
@@ -103,10 +151,19 @@ func (uc *LessonUseCase) GetLessonByID(ctx context.Context, lessonID int) (*enti
 	}
 
 	// Populate related entities
-	if err := uc.populateLessonRelations(ctx, lesson); err != nil {
+	if err := uc.populateLessonRelations(ctx, []*entities.Lesson{lesson}); err != nil {
 		return nil, err
 	}
 
+	// Get participants of group lessons
+	if lesson.IsGroup() {
+		participants, err := uc.lessonRepo.GetParticipants(ctx, lessonID)
+		if err != nil {
+			return nil, err
+		}
+		lesson.Participants = participants
+	}
+
 	// Get reviews
 	reviews, err := uc.lessonRepo.GetReviewsByLessonID(ctx, lessonID)
 	if err != nil {
@@ -128,6 +185,11 @@ func (uc *LessonUseCase) CancelLesson(ctx context.Context, lessonID int, userID
 		return errors.New("lesson not found")
 	}
 
+	// Participants of a group lesson cancel only their own seat
+	if lesson.IsGroup() && lesson.TutorID != userID {
+		return uc.CancelSeat(ctx, lessonID, userID)
+	}
+
 	// Check if user is associated with this lesson
 	if lesson.StudentID != userID && lesson.TutorID != userID {
 		return errors.New("user not authorized to cancel this lesson")
@@ -138,25 +200,378 @@ func (uc *LessonUseCase) CancelLesson(ctx context.Context, lessonID int, userID
 		return err
 	}
 
+	// Late cancellations are only possible for students and are charged a fee
+	var fee *float64
+	if lesson.IsLateCancellation() {
+		if userID != lesson.StudentID {
+			return entities.ErrLessonNotCancellable
+		}
+		if amount := lesson.LateCancellationFee(); amount > 0 {
+			fee = &amount
+		}
+	}
+
 	// Cancel the lesson
-	return uc.lessonRepo.CancelLesson(ctx, lessonID, userID)
+	if err := uc.lessonRepo.CancelLesson(ctx, lessonID, userID, fee); err != nil {
+		return err
+	}
+
+	if lesson.IsGroup() {
+		uc.notifyGroupLessonCancelled(ctx, lesson, "The tutor cancelled the group lesson.")
+		return nil
+	}
+
+	// Let the other side of a one-on-one lesson know
+	recipientID, cancelledBy := lesson.StudentID, "tutor"
+	if userID == lesson.StudentID {
+		recipientID, cancelledBy = lesson.TutorID, "student"
+	}
+	uc.notify(ctx, &entities.Notification{
+		UserID: recipientID,
+		Type:   entities.NotificationLessonCancelled,
+		Title:  "Lesson cancelled",
+		Body:   fmt.Sprintf("Your %s lesson was cancelled by the %s.", lesson.Language.Name, cancelledBy),
+		Data:   map[string]interface{}{"lesson_id": lesson.ID, "start_time": lesson.StartTime},
+	})
+
+	return nil
 }
 
-// AddReview adds a review for a lesson
-func (uc *LessonUseCase) AddReview(ctx context.Context, lessonID int, userID int, rating int) (*entities.Review, error) {
+// RescheduleLesson moves a one-on-one lesson to another time and notifies the other side
+func (uc *LessonUseCase) RescheduleLesson(ctx context.Context, lessonID int, userID int, req *entities.LessonRescheduleRequest) (*entities.Lesson, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
 	// Get lesson
 	lesson, err := uc.lessonRepo.GetByID(ctx, lessonID)
 	if err != nil {
 		return nil, err
 	}
 	if lesson == nil {
-		return nil, errors.New("lesson not found")
+		return nil, entities.ErrNotFound
 	}
 
 	// Check if user is associated with this lesson
 	if lesson.StudentID != userID && lesson.TutorID != userID {
+		return nil, entities.ErrNotLessonAttendee
+	}
+
+	if err := lesson.CanReschedule(); err != nil {
+		return nil, err
+	}
+
+	// The lesson keeps its duration, and so its price
+	previousStart := lesson.StartTime
+	startTime := req.StartTime
+	endTime := startTime.Add(lesson.EndTime.Sub(lesson.StartTime))
+
+	busy, err := uc.busyRepo.HasBusyTime(ctx, lesson.TutorID, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+	if busy {
+		return nil, entities.ErrTutorBusy
+	}
+
+	if err := uc.lessonRepo.RescheduleLesson(ctx, lessonID, previousStart, startTime, endTime); err != nil {
+		return nil, err
+	}
+	lesson.StartTime, lesson.EndTime = startTime, endTime
+
+	// Let the other side know
+	recipientID, rescheduledBy := lesson.StudentID, "tutor"
+	if userID == lesson.StudentID {
+		recipientID, rescheduledBy = lesson.TutorID, "student"
+	}
+	uc.notify(ctx, &entities.Notification{
+		UserID: recipientID,
+		Type:   entities.NotificationLessonRescheduled,
+		Title:  "Lesson rescheduled",
+		Body:   fmt.Sprintf("Your %s lesson was moved to another time by the %s.", lesson.Language.Name, rescheduledBy),
+		Data:   map[string]interface{}{"lesson_id": lesson.ID, "start_time": startTime, "previous_start_time": previousStart},
+	})
+
+	return lesson, nil
+}
+
+// CreateGroupLesson schedules a new group lesson for a tutor
+func (uc *LessonUseCase) CreateGroupLesson(ctx context.Context, tutorID int, req *entities.GroupLessonRequest) (*entities.Lesson, error) {
+	// Validate request
+	req.ApplyDefaults()
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	lesson, err := uc.newGroupLesson(ctx, tutorID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.lessonRepo.Create(ctx, lesson); err != nil {
+		return nil, err
+	}
+
+	return lesson, nil
+}
+
+// newGroupLesson checks the tutor and language of a validated group lesson request and builds
+// the lesson to be saved. Group classes of the catalog are built the same way.
+func (uc *LessonUseCase) newGroupLesson(ctx context.Context, tutorID int, req *entities.GroupLessonRequest) (*entities.Lesson, error) {
+	// Check if tutor exists
+	tutorProfile, err := uc.tutorRepo.GetByUserID(ctx, tutorID)
+	if err != nil {
+		return nil, err
+	}
+	if tutorProfile == nil {
+		return nil, errors.New("tutor not found")
+	}
+
+	// Check if language exists
+	language, err := uc.langRepo.GetLanguageByID(ctx, req.LanguageID)
+	if err != nil {
+		return nil, err
+	}
+	if language == nil {
+		return nil, entities.ErrLanguageNotFound
+	}
+
+	capacity := req.Capacity
+	minParticipants := req.MinParticipants
+	return &entities.Lesson{
+		TutorID:            tutorID,
+		LanguageID:         req.LanguageID,
+		StartTime:          req.StartTime,
+		EndTime:            req.EndTime,
+		Notes:              req.Notes,
+		Type:               entities.LessonTypeGroup,
+		Price:              entities.RoundMoney(req.Price),
+		Capacity:           &capacity,
+		MinParticipants:    &minParticipants,
+		EnrollmentDeadline: req.EnrollmentDeadline,
+	}, nil
+}
+
+// BookSeat books a seat in a group lesson for a student
+func (uc *LessonUseCase) BookSeat(ctx context.Context, lessonID int, studentID int) (*entities.LessonParticipant, error) {
+	// Check if student exists
+	studentProfile, err := uc.studentRepo.GetByUserID(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if studentProfile == nil {
+		return nil, errors.New("student not found")
+	}
+
+	lesson, err := uc.lessonRepo.GetByID(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	if err := lesson.CanEnroll(); err != nil {
+		return nil, err
+	}
+
+	participant := &entities.LessonParticipant{
+		LessonID:  lessonID,
+		StudentID: studentID,
+		Price:     lesson.Price,
+	}
+
+	// Capacity and duplicate seats are checked again while the lesson is locked
+	if err := uc.lessonRepo.AddParticipant(ctx, participant); err != nil {
+		return nil, err
+	}
+
+	uc.notify(ctx, &entities.Notification{
+		UserID: lesson.TutorID,
+		Type:   entities.NotificationLessonBooked,
+		Title:  "New group lesson participant",
+		Body:   fmt.Sprintf("A student booked a seat in your %s group lesson.", lesson.Language.Name),
+		Data:   map[string]interface{}{"lesson_id": lesson.ID, "start_time": lesson.StartTime},
+	})
+	uc.notify(ctx, &entities.Notification{
+		UserID: studentID,
+		Type:   entities.NotificationLessonBooked,
+		Title:  "Seat booked",
+		Body:   fmt.Sprintf("Your seat in the %s group lesson is booked.", lesson.Language.Name),
+		Data:   map[string]interface{}{"lesson_id": lesson.ID, "start_time": lesson.StartTime},
+	})
+
+	return participant, nil
+}
+
+// CancelSeat cancels a student's seat in a group lesson, charging a fee for late cancellations
+func (uc *LessonUseCase) CancelSeat(ctx context.Context, lessonID int, studentID int) error {
+	lesson, err := uc.lessonRepo.GetByID(ctx, lessonID)
+	if err != nil {
+		return err
+	}
+	if !lesson.IsGroup() {
+		return entities.ErrNotGroupLesson
+	}
+
+	seat, err := uc.lessonRepo.GetParticipant(ctx, lessonID, studentID)
+	if err != nil {
+		return err
+	}
+	if seat == nil {
+		return entities.ErrNotEnrolled
+	}
+
+	if err := lesson.CanCancel(); err != nil {
+		return err
+	}
+
+	return uc.lessonRepo.CancelParticipant(ctx, seat.ID, lesson.SeatCancellationFee(seat))
+}
+
+// CancelUnderfilledGroupLessons cancels group lessons that did not reach their minimum
+// number of participants by the enrollment deadline and notifies everyone involved
+func (uc *LessonUseCase) CancelUnderfilledGroupLessons(ctx context.Context) (int, error) {
+	lessons, err := uc.lessonRepo.CancelUnderfilledGroupLessons(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range lessons {
+		uc.notifyGroupLessonCancelled(ctx, &lessons[i], "Not enough participants enrolled before the deadline.")
+
+		if err := uc.notifier.Notify(ctx, &entities.Notification{
+			UserID: lessons[i].TutorID,
+			Type:   entities.NotificationLessonCancelled,
+			Title:  "Group lesson cancelled",
+			Body:   "Your group lesson was cancelled because not enough participants enrolled before the deadline.",
+			Data:   map[string]interface{}{"lesson_id": lessons[i].ID},
+		}); err != nil {
+			logger.Error("Failed to notify tutor", "lesson_id", lessons[i].ID, "error", err)
+		}
+	}
+
+	return len(lessons), nil
+}
+
+// GetRoomAccess checks if a user may join the video room of a lesson
+func (uc *LessonUseCase) GetRoomAccess(ctx context.Context, lessonID int, userID int) (*entities.LessonRoomAccess, error) {
+	lesson, err := uc.lessonRepo.GetByID(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	if lesson.CancelledAt != nil {
+		return nil, errors.New("lesson is cancelled")
+	}
+
+	access := &entities.LessonRoomAccess{
+		LessonID:        lessonID,
+		UserID:          userID,
+		MaxParticipants: 2,
+	}
+	if lesson.Capacity != nil {
+		access.MaxParticipants = *lesson.Capacity + 1
+	}
+
+	switch {
+	case lesson.TutorID == userID:
+		access.Role = "tutor"
+	case lesson.StudentID == userID:
+		access.Role = "student"
+	case lesson.IsGroup():
+		seat, err := uc.lessonRepo.GetParticipant(ctx, lessonID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if seat == nil {
+			return nil, entities.ErrNotEnrolled
+		}
+		access.Role = "student"
+	default:
+		return nil, errors.New("user is not an attendee of this lesson")
+	}
+
+	return access, nil
+}
+
+// notifyGroupLessonCancelled notifies the participants of a cancelled group lesson
+func (uc *LessonUseCase) notifyGroupLessonCancelled(ctx context.Context, lesson *entities.Lesson, reason string) {
+	participants, err := uc.lessonRepo.GetParticipants(ctx, lesson.ID)
+	if err != nil {
+		logger.Error("Failed to load participants of cancelled lesson", "lesson_id", lesson.ID, "error", err)
+		return
+	}
+
+	for _, participant := range participants {
+		err := uc.notifier.Notify(ctx, &entities.Notification{
+			UserID: participant.StudentID,
+			Type:   entities.NotificationLessonCancelled,
+			Title:  "Group lesson cancelled",
+			Body:   reason,
+			Data:   map[string]interface{}{"lesson_id": lesson.ID},
+		})
+		if err != nil {
+			logger.Error("Failed to notify participant", "lesson_id", lesson.ID, "user_id", participant.StudentID, "error", err)
+		}
+	}
+}
+
+// notify sends a notification, logging failures instead of failing the action that triggered it
+func (uc *LessonUseCase) notify(ctx context.Context, notification *entities.Notification) {
+	if err := uc.notifier.Notify(ctx, notification); err != nil {
+		logger.Error("Failed to send notification", "user_id", notification.UserID, "type", notification.Type, "error", err)
+	}
+}
+
+// ReportNoShow records that the student did not show up for a lesson and charges the no-show fee
+func (uc *LessonUseCase) ReportNoShow(ctx context.Context, lessonID int, tutorID int) error {
+	// Get lesson
+	lesson, err := uc.lessonRepo.GetByID(ctx, lessonID)
+	if err != nil {
+		return err
+	}
+	if lesson == nil {
+		return errors.New("lesson not found")
+	}
+
+	// Only the tutor of the lesson can report a no-show
+	if lesson.TutorID != tutorID {
+		return errors.New("user not authorized to report a no-show for this lesson")
+	}
+
+	if err := lesson.CanReportNoShow(); err != nil {
+		return err
+	}
+
+	return uc.lessonRepo.MarkStudentNoShow(ctx, lessonID, lesson.NoShowFeeAmount())
+}
+
+// AddReview adds a student's public review of a lesson's tutor. Tutors give their students
+// private feedback through LessonNotesUseCase.SaveFeedback instead.
+func (uc *LessonUseCase) AddReview(ctx context.Context, lessonID int, userID int, req *entities.ReviewRequest) (*entities.Review, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Get lesson
+	lesson, err := uc.lessonRepo.GetByID(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+	if lesson == nil {
+		return nil, errors.New("lesson not found")
+	}
+
+	// Check if user is associated with this lesson
+	if lesson.IsGroup() {
+		participants, err := uc.lessonRepo.GetParticipants(ctx, lessonID)
+		if err != nil {
+			return nil, err
+		}
+		lesson.Participants = participants
+	}
+	if !lesson.IsAttendee(userID) {
 		return nil, errors.New("user not authorized to review this lesson")
 	}
+	if userID == lesson.TutorID {
+		return nil, entities.ErrReviewNotAllowed
+	}
 
 	// Check if lesson is completed
 	if lesson.GetStatus() != entities.LessonStatusCompleted {
@@ -170,7 +585,7 @@ func (uc *LessonUseCase) AddReview(ctx context.Context, lessonID int, userID int
 	}
 	for _, review := range reviews {
 		if review.ReviewerID == userID {
-			return nil, errors.New("user has already reviewed this lesson")
+			return nil, entities.ErrAlreadyReviewed
 		}
 	}
 
@@ -178,7 +593,10 @@ func (uc *LessonUseCase) AddReview(ctx context.Context, lessonID int, userID int
 	review := &entities.Review{
 		LessonID:   lessonID,
 		ReviewerID: userID,
-		Rating:     rating,
+		TutorID:    lesson.TutorID,
+		Rating:     req.Rating,
+		Comment:    req.Comment,
+		Aspects:    req.Aspects,
 	}
 
 	// Save review
@@ -186,6 +604,14 @@ func (uc *LessonUseCase) AddReview(ctx context.Context, lessonID int, userID int
 		return nil, err
 	}
 
+	uc.notify(ctx, &entities.Notification{
+		UserID: lesson.TutorID,
+		Type:   entities.NotificationReviewReceived,
+		Title:  "New review",
+		Body:   fmt.Sprintf("You received a %d-star review for your %s lesson.", review.Rating, lesson.Language.Name),
+		Data:   map[string]interface{}{"lesson_id": lesson.ID, "review_id": review.ID, "rating": review.Rating},
+	})
+
 	return review, nil
 }
 
@@ -206,9 +632,8 @@ func (uc *LessonUseCase) GetUpcomingLessonsByUser(ctx context.Context, userID in
 		return nil, err
 	}
 
-	// Populate related entities for each lesson
-	for i := range lessons {
-		uc.populateLessonRelations(ctx, &lessons[i])
+	if err := uc.populateLessonListRelations(ctx, lessons); err != nil {
+		return nil, err
 	}
 
 	return lessons, nil
@@ -221,9 +646,8 @@ func (uc *LessonUseCase) GetPastLessonsByUser(ctx context.Context, userID int, i
 		return nil, err
 	}
 
-	// Populate related entities for each lesson
-	for i := range lessons {
-		uc.populateLessonRelations(ctx, &lessons[i])
+	if err := uc.populateLessonListRelations(ctx, lessons); err != nil {
+		return nil, err
 	}
 
 	return lessons, nil
@@ -244,9 +668,8 @@ func (uc *LessonUseCase) GetAllLessonsByUser(ctx context.Context, userID int, is
 		return nil, err
 	}
 
-	// Populate related entities for each lesson
-	for i := range lessons {
-		uc.populateLessonRelations(ctx, &lessons[i])
+	if err := uc.populateLessonListRelations(ctx, lessons); err != nil {
+		return nil, err
 	}
 
 	return lessons, nil
@@ -259,36 +682,52 @@ func (uc *LessonUseCase) GetCancelledLessonsByUser(ctx context.Context, userID i
 		return nil, err
 	}
 
-	// Populate related entities for each lesson
-	for i := range lessons {
-		uc.populateLessonRelations(ctx, &lessons[i])
+	if err := uc.populateLessonListRelations(ctx, lessons); err != nil {
+		return nil, err
 	}
 
 	return lessons, nil
 }
 
-// Helper method to populate lesson relations
-func (uc *LessonUseCase) populateLessonRelations(ctx context.Context, lesson *entities.Lesson) error {
-	// Get student info
-	student, err := uc.userRepo.GetByID(ctx, lesson.StudentID)
-	if err != nil {
-		return err
+// populateLessonListRelations populates the relations of a list of lessons
+func (uc *LessonUseCase) populateLessonListRelations(ctx context.Context, lessons []entities.Lesson) error {
+	ptrs := make([]*entities.Lesson, len(lessons))
+	for i := range lessons {
+		ptrs[i] = &lessons[i]
+	}
+	return uc.populateLessonRelations(ctx, ptrs)
+}
+
+// populateLessonRelations loads the students, tutors and languages of the lessons with one
+// query each, however many lessons there are. Group lessons have no student.
+func (uc *LessonUseCase) populateLessonRelations(ctx context.Context, lessons []*entities.Lesson) error {
+	if len(lessons) == 0 {
+		return nil
+	}
+
+	userIDs := make([]int, 0, 2*len(lessons))
+	languageIDs := make([]int, 0, len(lessons))
+	for _, lesson := range lessons {
+		if lesson.StudentID != 0 {
+			userIDs = append(userIDs, lesson.StudentID)
+		}
+		userIDs = append(userIDs, lesson.TutorID)
+		languageIDs = append(languageIDs, lesson.LanguageID)
 	}
-	lesson.Student = student
 
-	// Get tutor info
-	tutor, err := uc.userRepo.GetByID(ctx, lesson.TutorID)
+	users, err := uc.userRepo.GetByIDs(ctx, userIDs)
 	if err != nil {
 		return err
 	}
-	lesson.Tutor = tutor
-
-	// Get language info
-	language, err := uc.langRepo.GetLanguageByID(ctx, lesson.LanguageID)
+	languages, err := uc.langRepo.GetLanguagesByIDs(ctx, languageIDs)
 	if err != nil {
 		return err
 	}
-	lesson.Language = language
 
+	for _, lesson := range lessons {
+		lesson.Student = users[lesson.StudentID]
+		lesson.Tutor = users[lesson.TutorID]
+		lesson.Language = languages[lesson.LanguageID]
+	}
 	return nil
 }
@@ -0,0 +1,368 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/logger"
+	"tongly-backend/internal/repositories"
+)
+
+const (
+	defaultConversationListLimit = 20
+	maxConversationListLimit     = 100
+	defaultMessagePageLimit      = 50
+	maxMessagePageLimit          = 100
+
+	defaultReportListLimit = 50
+	maxReportListLimit     = 200
+
+	// messagePreviewLength is the number of characters of a message shown in its notification
+	messagePreviewLength = 100
+)
+
+// MessageUseCase handles the persistent conversations between students and tutors
+type MessageUseCase struct {
+	messageRepo *repositories.MessageRepository
+	userRepo    *repositories.UserRepository
+	notifier    Notifier
+}
+
+// NewMessageUseCase creates a new MessageUseCase
+func NewMessageUseCase(
+	messageRepo *repositories.MessageRepository,
+	userRepo *repositories.UserRepository,
+	notifier Notifier,
+) *MessageUseCase {
+	return &MessageUseCase{
+		messageRepo: messageRepo,
+		userRepo:    userRepo,
+		notifier:    notifier,
+	}
+}
+
+// StartConversation sends a message to a user, starting a conversation with them unless one exists.
+// Students may message any tutor; tutors may only start conversations with students who booked them.
+func (uc *MessageUseCase) StartConversation(ctx context.Context, senderID int, req *entities.StartConversationRequest) (*entities.Conversation, *entities.Message, error) {
+	if err := req.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	sender, err := uc.getUser(ctx, senderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	recipient, err := uc.getUser(ctx, req.RecipientID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var studentID, tutorID int
+	switch {
+	case sender.Role == "student" && recipient.Role == "tutor":
+		studentID, tutorID = sender.ID, recipient.ID
+	case sender.Role == "tutor" && recipient.Role == "student":
+		studentID, tutorID = recipient.ID, sender.ID
+	default:
+		return nil, nil, entities.ErrInvalidRecipient
+	}
+
+	conversation, err := uc.messageRepo.GetConversationBetween(ctx, studentID, tutorID)
+	if err != nil && !errors.Is(err, entities.ErrConversationNotFound) {
+		return nil, nil, err
+	}
+
+	if conversation == nil {
+		if err := uc.checkNotBlocked(ctx, senderID, req.RecipientID); err != nil {
+			return nil, nil, err
+		}
+
+		booked, err := uc.messageRepo.HasBooking(ctx, studentID, tutorID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if senderID == tutorID && !booked {
+			return nil, nil, entities.ErrConversationNotAllowed
+		}
+
+		// Students reaching out to tutors they have not booked are limited to curb spam
+		dailyLimit := 0
+		if !booked {
+			dailyLimit = entities.MaxPreBookingConversationsPerDay
+		}
+
+		created := &entities.Conversation{StudentID: studentID, TutorID: tutorID, StartedBy: senderID}
+		if err := uc.messageRepo.CreateConversation(ctx, created, dailyLimit); err != nil {
+			return nil, nil, err
+		}
+
+		if conversation, err = uc.messageRepo.GetConversation(ctx, created.ID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	message, err := uc.send(ctx, conversation, sender, &req.MessageRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return conversation, message, nil
+}
+
+// SendMessage sends a message in a conversation the user takes part in
+func (uc *MessageUseCase) SendMessage(ctx context.Context, userID int, conversationID int, req *entities.MessageRequest) (*entities.Message, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	conversation, err := uc.GetConversation(ctx, userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	sender, err := uc.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.send(ctx, conversation, sender, req)
+}
+
+// send stores a message from one participant of a conversation and notifies the other
+func (uc *MessageUseCase) send(ctx context.Context, conversation *entities.Conversation, sender *entities.User, req *entities.MessageRequest) (*entities.Message, error) {
+	recipientID := conversation.OtherParticipant(sender.ID)
+	if err := uc.checkNotBlocked(ctx, sender.ID, recipientID); err != nil {
+		return nil, err
+	}
+
+	// Until they book, students may only send a few messages in a row without a reply
+	unansweredLimit := 0
+	if sender.ID == conversation.StudentID {
+		booked, err := uc.messageRepo.HasBooking(ctx, conversation.StudentID, conversation.TutorID)
+		if err != nil {
+			return nil, err
+		}
+		if !booked {
+			unansweredLimit = entities.MaxUnansweredPreBookingMessages
+		}
+	}
+
+	message := &entities.Message{
+		ConversationID: conversation.ID,
+		SenderID:       sender.ID,
+		Body:           req.Body,
+		Attachments:    req.Attachments,
+	}
+	if err := uc.messageRepo.CreateMessage(ctx, message, unansweredLimit); err != nil {
+		return nil, err
+	}
+
+	body := preview(req.Body)
+	if body == "" {
+		body = fmt.Sprintf("%d attachment(s)", len(req.Attachments))
+	}
+	err := uc.notifier.Notify(ctx, &entities.Notification{
+		UserID: recipientID,
+		Type:   entities.NotificationMessageReceived,
+		Title:  fmt.Sprintf("New message from %s", displayName(sender)),
+		Body:   body,
+		Data:   map[string]interface{}{"conversation_id": conversation.ID, "message_id": message.ID},
+	})
+	if err != nil {
+		logger.Error("Failed to notify message recipient", "conversation_id", conversation.ID, "error", err)
+	}
+
+	return message, nil
+}
+
+// GetConversations retrieves a page of a user's conversations, most recently active first
+func (uc *MessageUseCase) GetConversations(ctx context.Context, userID int, filters *entities.ConversationFilters) (*entities.ConversationList, error) {
+	if filters.Limit <= 0 {
+		filters.Limit = defaultConversationListLimit
+	}
+	if filters.Limit > maxConversationListLimit {
+		filters.Limit = maxConversationListLimit
+	}
+	if filters.Offset < 0 {
+		filters.Offset = 0
+	}
+
+	conversations, err := uc.messageRepo.ListConversations(ctx, userID, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := uc.messageRepo.CountConversations(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.ConversationList{
+		Conversations: conversations,
+		Total:         total,
+	}, nil
+}
+
+// GetConversation retrieves a conversation the user takes part in
+func (uc *MessageUseCase) GetConversation(ctx context.Context, userID int, conversationID int) (*entities.Conversation, error) {
+	conversation, err := uc.messageRepo.GetConversation(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Conversations of other users are reported as missing rather than forbidden
+	if !conversation.HasParticipant(userID) {
+		return nil, entities.ErrConversationNotFound
+	}
+
+	return conversation, nil
+}
+
+// GetMessages retrieves a page of a conversation's messages older than beforeID, newest first
+func (uc *MessageUseCase) GetMessages(ctx context.Context, userID int, conversationID int, beforeID int64, limit int) (*entities.MessagePage, error) {
+	if _, err := uc.GetConversation(ctx, userID, conversationID); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = defaultMessagePageLimit
+	}
+	if limit > maxMessagePageLimit {
+		limit = maxMessagePageLimit
+	}
+	if beforeID < 0 {
+		beforeID = 0
+	}
+
+	// One extra message tells whether there is another page
+	messages, err := uc.messageRepo.ListMessages(ctx, conversationID, beforeID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &entities.MessagePage{Messages: messages}
+	if len(messages) > limit {
+		page.Messages = messages[:limit]
+		page.HasMore = true
+	}
+
+	return page, nil
+}
+
+// MarkRead marks the messages the user received in a conversation as read
+func (uc *MessageUseCase) MarkRead(ctx context.Context, userID int, conversationID int) (int, error) {
+	if _, err := uc.GetConversation(ctx, userID, conversationID); err != nil {
+		return 0, err
+	}
+
+	return uc.messageRepo.MarkRead(ctx, conversationID, userID)
+}
+
+// GetUnreadCount counts the messages the user has not read in all conversations
+func (uc *MessageUseCase) GetUnreadCount(ctx context.Context, userID int) (int, error) {
+	return uc.messageRepo.CountUnread(ctx, userID)
+}
+
+// ReportConversation reports a conversation, or one of its messages, to the admins
+func (uc *MessageUseCase) ReportConversation(ctx context.Context, userID int, conversationID int, req *entities.ReportRequest) (*entities.ConversationReport, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.GetConversation(ctx, userID, conversationID); err != nil {
+		return nil, err
+	}
+	if req.MessageID != nil {
+		if _, err := uc.messageRepo.GetMessage(ctx, conversationID, *req.MessageID); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &entities.ConversationReport{
+		ConversationID: conversationID,
+		MessageID:      req.MessageID,
+		ReporterID:     userID,
+		Reason:         req.Reason,
+	}
+	if err := uc.messageRepo.CreateReport(ctx, report); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// BlockUser stops the user and another user from messaging each other
+func (uc *MessageUseCase) BlockUser(ctx context.Context, userID int, blockedID int) error {
+	if userID == blockedID {
+		return entities.ErrCannotBlockSelf
+	}
+	if _, err := uc.getUser(ctx, blockedID); err != nil {
+		return err
+	}
+
+	return uc.messageRepo.Block(ctx, userID, blockedID)
+}
+
+// UnblockUser lifts the block the user placed on another user
+func (uc *MessageUseCase) UnblockUser(ctx context.Context, userID int, blockedID int) error {
+	return uc.messageRepo.Unblock(ctx, userID, blockedID)
+}
+
+// GetBlockedUsers retrieves the users the user has blocked
+func (uc *MessageUseCase) GetBlockedUsers(ctx context.Context, userID int) ([]entities.BlockedUser, error) {
+	return uc.messageRepo.ListBlocked(ctx, userID)
+}
+
+// ListReports retrieves a page of conversation reports for the admins
+func (uc *MessageUseCase) ListReports(ctx context.Context, filters *entities.ReportFilters) ([]entities.ConversationReport, error) {
+	if filters.Limit <= 0 {
+		filters.Limit = defaultReportListLimit
+	}
+	if filters.Limit > maxReportListLimit {
+		filters.Limit = maxReportListLimit
+	}
+	if filters.Offset < 0 {
+		filters.Offset = 0
+	}
+
+	return uc.messageRepo.ListReports(ctx, filters)
+}
+
+// ResolveReport marks a conversation report as handled
+func (uc *MessageUseCase) ResolveReport(ctx context.Context, reportID int) error {
+	return uc.messageRepo.ResolveReport(ctx, reportID)
+}
+
+// getUser retrieves a user, failing with ErrUserNotFound if there is none
+func (uc *MessageUseCase) getUser(ctx context.Context, userID int) (*entities.User, error) {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, entities.ErrUserNotFound
+	}
+	return user, nil
+}
+
+// checkNotBlocked fails if either user has blocked the other
+func (uc *MessageUseCase) checkNotBlocked(ctx context.Context, userID, otherID int) error {
+	blocked, err := uc.messageRepo.IsBlocked(ctx, userID, otherID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return entities.ErrUserBlocked
+	}
+	return nil
+}
+
+// preview shortens a message for display in a notification
+func preview(text string) string {
+	runes := []rune(text)
+	if len(runes) <= messagePreviewLength {
+		return text
+	}
+	return string(runes[:messagePreviewLength-1]) + "…"
+}
@@ -0,0 +1,222 @@
+package usecases
+
+import (
+	"context"
+	"sync"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/logger"
+	"tongly-backend/internal/repositories"
+)
+
+const (
+	defaultNotificationListLimit = 20
+	maxNotificationListLimit     = 100
+
+	// subscriptionBuffer is how many notifications may queue up for a slow stream before they are dropped
+	subscriptionBuffer = 16
+)
+
+// NotificationUseCase delivers notifications through the channels each user chose: in-app
+// notifications are stored and pushed to the users' open streams, emails are queued.
+// It is the Notifier used by the other use cases.
+type NotificationUseCase struct {
+	notificationRepo *repositories.NotificationRepository
+	prefsRepo        *repositories.NotificationPreferenceRepository
+	emailUseCase     *EmailUseCase
+
+	mu          sync.Mutex
+	subscribers map[int]map[chan *entities.Notification]struct{}
+	closed      bool
+}
+
+// NewNotificationUseCase creates a new NotificationUseCase
+func NewNotificationUseCase(
+	notificationRepo *repositories.NotificationRepository,
+	prefsRepo *repositories.NotificationPreferenceRepository,
+	emailUseCase *EmailUseCase,
+) *NotificationUseCase {
+	return &NotificationUseCase{
+		notificationRepo: notificationRepo,
+		prefsRepo:        prefsRepo,
+		emailUseCase:     emailUseCase,
+		subscribers:      make(map[int]map[chan *entities.Notification]struct{}),
+	}
+}
+
+// Notify delivers a notification through the channels the user enabled for its event. In-app
+// notifications reach open streams through Listen, whichever replica they are connected to.
+func (uc *NotificationUseCase) Notify(ctx context.Context, notification *entities.Notification) error {
+	channels, err := uc.prefsRepo.GetChannels(ctx, notification.UserID, notification.Type)
+	if err != nil {
+		return err
+	}
+
+	if channels.InApp {
+		if err := uc.notificationRepo.Create(ctx, notification); err != nil {
+			return err
+		}
+	}
+	if channels.Email {
+		if err := uc.emailUseCase.SendForNotification(ctx, notification); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetPreferences retrieves a user's notification preferences
+func (uc *NotificationUseCase) GetPreferences(ctx context.Context, userID int) (*entities.NotificationPreferences, error) {
+	return uc.prefsRepo.GetPreferences(ctx, userID)
+}
+
+// UpdatePreferences changes a user's email language and the channels of the given events
+func (uc *NotificationUseCase) UpdatePreferences(ctx context.Context, userID int, req *entities.NotificationPreferencesRequest) (*entities.NotificationPreferences, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := uc.prefsRepo.SavePreferences(ctx, userID, req); err != nil {
+		return nil, err
+	}
+
+	return uc.prefsRepo.GetPreferences(ctx, userID)
+}
+
+// GetNotifications retrieves a page of a user's notifications, newest first
+func (uc *NotificationUseCase) GetNotifications(ctx context.Context, userID int, filters *entities.NotificationFilters) (*entities.NotificationList, error) {
+	if filters.Limit <= 0 {
+		filters.Limit = defaultNotificationListLimit
+	}
+	if filters.Limit > maxNotificationListLimit {
+		filters.Limit = maxNotificationListLimit
+	}
+	if filters.Offset < 0 {
+		filters.Offset = 0
+	}
+
+	notifications, err := uc.notificationRepo.List(ctx, userID, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := uc.notificationRepo.Count(ctx, userID, filters.UnreadOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	unread, err := uc.notificationRepo.Count(ctx, userID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.NotificationList{
+		Notifications: notifications,
+		Total:         total,
+		UnreadCount:   unread,
+	}, nil
+}
+
+// GetUnreadCount counts a user's unread notifications
+func (uc *NotificationUseCase) GetUnreadCount(ctx context.Context, userID int) (int, error) {
+	return uc.notificationRepo.Count(ctx, userID, true)
+}
+
+// MarkRead marks a notification of a user as read
+func (uc *NotificationUseCase) MarkRead(ctx context.Context, userID int, notificationID int64) error {
+	return uc.notificationRepo.MarkRead(ctx, notificationID, userID)
+}
+
+// MarkAllRead marks all notifications of a user as read
+func (uc *NotificationUseCase) MarkAllRead(ctx context.Context, userID int) (int, error) {
+	return uc.notificationRepo.MarkAllRead(ctx, userID)
+}
+
+// Subscribe opens a stream of the user's new notifications. The channel is closed when the
+// use case stops listening; unsubscribe must be called once the stream is no longer read.
+func (uc *NotificationUseCase) Subscribe(userID int) (notifications <-chan *entities.Notification, unsubscribe func()) {
+	ch := make(chan *entities.Notification, subscriptionBuffer)
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if uc.closed {
+		close(ch)
+		return ch, func() {}
+	}
+
+	if uc.subscribers[userID] == nil {
+		uc.subscribers[userID] = make(map[chan *entities.Notification]struct{})
+	}
+	uc.subscribers[userID][ch] = struct{}{}
+
+	return ch, func() {
+		uc.mu.Lock()
+		defer uc.mu.Unlock()
+
+		if _, ok := uc.subscribers[userID][ch]; !ok {
+			return
+		}
+		delete(uc.subscribers[userID], ch)
+		if len(uc.subscribers[userID]) == 0 {
+			delete(uc.subscribers, userID)
+		}
+		close(ch)
+	}
+}
+
+// Listen pushes the notifications created by any backend replica to the streams opened on
+// this one, until ctx is cancelled. All streams are closed when it returns.
+func (uc *NotificationUseCase) Listen(ctx context.Context, dsn string) error {
+	defer uc.closeSubscribers()
+
+	return uc.notificationRepo.Listen(ctx, dsn, func(userID int, notificationID int64) {
+		if !uc.hasSubscribers(userID) {
+			return
+		}
+
+		notification, err := uc.notificationRepo.GetByID(ctx, notificationID, userID)
+		if err != nil {
+			logger.Error("Failed to load notification for streaming", "notification_id", notificationID, "error", err)
+			return
+		}
+
+		uc.publish(notification)
+	})
+}
+
+// hasSubscribers checks if the user has a stream open on this replica
+func (uc *NotificationUseCase) hasSubscribers(userID int) bool {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	return len(uc.subscribers[userID]) > 0
+}
+
+// publish sends a notification to the user's streams, dropping it for streams that fall behind
+func (uc *NotificationUseCase) publish(notification *entities.Notification) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	for ch := range uc.subscribers[notification.UserID] {
+		select {
+		case ch <- notification:
+		default:
+			logger.Error("Notification stream is full, dropping notification",
+				"user_id", notification.UserID, "notification_id", notification.ID)
+		}
+	}
+}
+
+// closeSubscribers closes all streams, e.g. when the server shuts down
+func (uc *NotificationUseCase) closeSubscribers() {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	for userID, channels := range uc.subscribers {
+		for ch := range channels {
+			close(ch)
+		}
+		delete(uc.subscribers, userID)
+	}
+	uc.closed = true
+}
@@ -0,0 +1,11 @@
+package usecases
+
+import (
+	"context"
+	"tongly-backend/internal/entities"
+)
+
+// Notifier delivers notifications to users
+type Notifier interface {
+	Notify(ctx context.Context, notification *entities.Notification) error
+}
@@ -0,0 +1,79 @@
+package usecases_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"sync/atomic"
+
+	"github.com/lib/pq"
+)
+
+const countingDriverName = "postgres-counting"
+
+// queries counts the statements sent through the counting driver
+var queries atomic.Int64
+
+// countingDriver wraps the pq driver and counts the statements run on its connections
+type countingDriver struct {
+	pq.Driver
+}
+
+func (d countingDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &countingConn{Conn: conn}, nil
+}
+
+// countingConn counts the statements run on a pq connection. pq implements the context
+// variants of every method, so they are passed straight through.
+type countingConn struct {
+	driver.Conn
+}
+
+func (c *countingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queries.Add(1)
+	return c.Conn.(driver.QueryerContext).QueryContext(ctx, query, args)
+}
+
+func (c *countingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	queries.Add(1)
+	return c.Conn.(driver.ExecerContext).ExecContext(ctx, query, args)
+}
+
+func (c *countingConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	stmt, err := c.Conn.(driver.ConnPrepareContext).PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &countingStmt{Stmt: stmt}, nil
+}
+
+func (c *countingConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return c.Conn.(driver.ConnBeginTx).BeginTx(ctx, opts)
+}
+
+func (c *countingConn) ResetSession(ctx context.Context) error {
+	return c.Conn.(driver.SessionResetter).ResetSession(ctx)
+}
+
+func (c *countingConn) IsValid() bool {
+	return c.Conn.(driver.Validator).IsValid()
+}
+
+// countingStmt counts the executions of a prepared statement. pq statements only implement
+// the methods without a context.
+type countingStmt struct {
+	driver.Stmt
+}
+
+func (s *countingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	queries.Add(1)
+	return s.Stmt.Exec(args)
+}
+
+func (s *countingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	queries.Add(1)
+	return s.Stmt.Query(args)
+}
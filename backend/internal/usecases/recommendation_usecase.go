@@ -0,0 +1,378 @@
+package usecases
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/repositories"
+)
+
+const (
+	defaultRecommendationLimit = 10
+	maxRecommendationLimit     = 50
+
+	// recommendationCandidates is how many of the best rated tutors who speak the student's
+	// languages are scored
+	recommendationCandidates = 500
+)
+
+// RecommendationUseCase recommends tutors to students
+type RecommendationUseCase struct {
+	studentRepo *repositories.StudentRepository
+	tutorRepo   *repositories.TutorRepository
+	userRepo    *repositories.UserRepository
+	langRepo    *repositories.LanguageRepository
+	weights     entities.RecommendationWeights
+}
+
+// NewRecommendationUseCase creates a new RecommendationUseCase scoring with the given weights
+func NewRecommendationUseCase(
+	studentRepo *repositories.StudentRepository,
+	tutorRepo *repositories.TutorRepository,
+	userRepo *repositories.UserRepository,
+	langRepo *repositories.LanguageRepository,
+	weights entities.RecommendationWeights,
+) *RecommendationUseCase {
+	return &RecommendationUseCase{
+		studentRepo: studentRepo,
+		tutorRepo:   tutorRepo,
+		userRepo:    userRepo,
+		langRepo:    langRepo,
+		weights:     weights,
+	}
+}
+
+// RecommendTutors scores tutors by how well they fit the student's interests, goals, languages,
+// preferred times and price range, and by their rating, and returns the best of them with the
+// breakdown of their scores
+func (uc *RecommendationUseCase) RecommendTutors(ctx context.Context, studentID, limit int) (*entities.TutorRecommendations, error) {
+	if limit <= 0 {
+		limit = defaultRecommendationLimit
+	}
+	if limit > maxRecommendationLimit {
+		limit = maxRecommendationLimit
+	}
+
+	prefs, err := uc.studentRepo.GetPreferences(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	languages, err := uc.studentRepo.GetLanguages(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	interests, err := uc.studentRepo.GetInterests(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	goals, err := uc.studentRepo.GetGoals(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	proficiencies, err := uc.langRepo.GetAllProficiencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nobody speaks a language better than at the top level, the student's native languages
+	topProficiency := 0
+	for _, proficiency := range proficiencies {
+		if proficiency.ID > topProficiency {
+			topProficiency = proficiency.ID
+		}
+	}
+
+	student := newStudentFit(prefs, languages, interests, goals, topProficiency, time.Now())
+	candidates, err := uc.tutorRepo.GetRecommendationCandidates(ctx, student.languageIDs(), recommendationCandidates)
+	if err != nil {
+		return nil, err
+	}
+
+	recommendations := make([]entities.TutorRecommendation, 0, len(candidates))
+	for i := range candidates {
+		if candidates[i].Tutor.UserID == studentID {
+			continue
+		}
+		recommendations = append(recommendations, student.score(&candidates[i], uc.weights))
+	}
+
+	// Candidates come best rated first, which breaks ties
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		return recommendations[i].Score > recommendations[j].Score
+	})
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+
+	tutors := make([]entities.TutorProfile, len(recommendations))
+	for i := range recommendations {
+		tutors[i] = recommendations[i].Tutor
+	}
+	if err := populateTutorRelations(ctx, uc.userRepo, uc.studentRepo, tutors); err != nil {
+		return nil, err
+	}
+	for i := range recommendations {
+		recommendations[i].Tutor = tutors[i]
+	}
+
+	return &entities.TutorRecommendations{
+		Recommendations: recommendations,
+		Weights:         uc.weights,
+	}, nil
+}
+
+// studentFit holds what tutors are compared with to recommend them to a student
+type studentFit struct {
+	interestIDs    []int
+	goalIDs        []int
+	languages      map[int]int // Proficiency of each language the student learns; native ones are left out
+	preferredTimes []timeInterval
+	preferredMins  int
+	minPrice       *float64
+	maxPrice       *float64
+	now            time.Time
+}
+
+// timeInterval is a span of absolute time
+type timeInterval struct {
+	start, end time.Time
+}
+
+func newStudentFit(prefs *entities.StudentPreferences, languages []entities.UserLanguage, interests []entities.UserInterest, goals []entities.UserGoal, topProficiency int, now time.Time) *studentFit {
+	fit := &studentFit{
+		languages: make(map[int]int, len(languages)),
+		minPrice:  prefs.MinPrice,
+		maxPrice:  prefs.MaxPrice,
+		now:       now,
+	}
+	for _, interest := range interests {
+		fit.interestIDs = append(fit.interestIDs, interest.InterestID)
+	}
+	for _, goal := range goals {
+		fit.goalIDs = append(fit.goalIDs, goal.GoalID)
+	}
+	for _, language := range languages {
+		if language.ProficiencyID < topProficiency {
+			fit.languages[language.LanguageID] = language.ProficiencyID
+		}
+	}
+
+	// Each preferred time is laid out once over the coming week
+	loc := loadLocation(prefs.TimeZone)
+	for i := range prefs.PreferredTimes {
+		start, length := prefs.PreferredTimes[i].Window()
+		fit.preferredTimes = append(fit.preferredTimes,
+			layOutWeekly(prefs.PreferredTimes[i].DayOfWeek, start, length, loc, now, 0, 6)...)
+	}
+	fit.preferredTimes = mergeIntervals(fit.preferredTimes)
+	for _, interval := range fit.preferredTimes {
+		fit.preferredMins += int(interval.end.Sub(interval.start) / time.Minute)
+	}
+
+	return fit
+}
+
+// languageIDs returns the languages the student learns
+func (f *studentFit) languageIDs() []int {
+	ids := make([]int, 0, len(f.languages))
+	for id := range f.languages {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// score scores a tutor. Factors the student gave nothing to compare with get no weight, and
+// the score is the weighted mean of the matches of the other factors.
+func (f *studentFit) score(candidate *entities.RecommendationCandidate, weights entities.RecommendationWeights) entities.TutorRecommendation {
+	tutor := &candidate.Tutor
+	var b entities.RecommendationBreakdown
+
+	b.SharedInterests = intersect(f.interestIDs, candidate.InterestIDs)
+	if len(f.interestIDs) > 0 {
+		b.Interests = entities.RecommendationFactor{
+			Match:  float64(len(b.SharedInterests)) / float64(len(f.interestIDs)),
+			Weight: weights.Interests,
+		}
+	}
+
+	b.SharedGoals = intersect(f.goalIDs, candidate.GoalIDs)
+	if len(f.goalIDs) > 0 {
+		b.Goals = entities.RecommendationFactor{
+			Match:  float64(len(b.SharedGoals)) / float64(len(f.goalIDs)),
+			Weight: weights.Goals,
+		}
+	}
+
+	// A tutor can teach a language they speak at a higher level than the student
+	b.TaughtLanguages = []int{}
+	for _, language := range candidate.Languages {
+		if proficiency, ok := f.languages[language.LanguageID]; ok && language.ProficiencyID > proficiency {
+			b.TaughtLanguages = append(b.TaughtLanguages, language.LanguageID)
+		}
+	}
+	if len(f.languages) > 0 {
+		b.Language = entities.RecommendationFactor{
+			Match:  float64(len(b.TaughtLanguages)) / float64(len(f.languages)),
+			Weight: weights.Language,
+		}
+	}
+
+	b.PreferredMinutes = f.preferredMins
+	if f.preferredMins > 0 {
+		b.OverlapMinutes = f.overlapMinutes(candidate)
+		b.Availability = entities.RecommendationFactor{
+			Match:  float64(b.OverlapMinutes) / float64(f.preferredMins),
+			Weight: weights.Availability,
+		}
+	}
+
+	// Rating scores run from 1 to 5. Tutors whose stats were not computed yet count as average.
+	b.RatedByReviews = tutor.ReviewsCount > 0
+	ratingMatch := 0.5
+	if tutor.RatingScore > 0 {
+		ratingMatch = clamp01((tutor.RatingScore - 1) / 4)
+	}
+	b.Rating = entities.RecommendationFactor{Match: ratingMatch, Weight: weights.Rating}
+
+	// Outside the range the match falls with the ratio of the rate to the nearest bound
+	b.WithinPriceRange = true
+	if f.minPrice != nil || f.maxPrice != nil {
+		priceMatch := 1.0
+		switch {
+		case f.maxPrice != nil && tutor.HourlyRate > *f.maxPrice:
+			b.WithinPriceRange = false
+			priceMatch = *f.maxPrice / tutor.HourlyRate
+		case f.minPrice != nil && tutor.HourlyRate < *f.minPrice:
+			b.WithinPriceRange = false
+			priceMatch = tutor.HourlyRate / *f.minPrice
+		}
+		b.Price = entities.RecommendationFactor{Match: priceMatch, Weight: weights.Price}
+	}
+
+	factors := []*entities.RecommendationFactor{&b.Interests, &b.Goals, &b.Language, &b.Availability, &b.Rating, &b.Price}
+	var totalWeight float64
+	for _, factor := range factors {
+		if factor.Weight < 0 {
+			factor.Weight = 0
+		}
+		totalWeight += factor.Weight
+	}
+
+	var score float64
+	for _, factor := range factors {
+		factor.Match = roundScore(factor.Match)
+		if totalWeight > 0 {
+			factor.Points = roundScore(factor.Match * factor.Weight / totalWeight)
+		}
+		score += factor.Points
+	}
+
+	return entities.TutorRecommendation{
+		Tutor:     *tutor,
+		Score:     roundScore(score),
+		Breakdown: b,
+	}
+}
+
+// overlapMinutes returns how many minutes of the student's preferred times in the coming week
+// the tutor's weekly availability covers
+func (f *studentFit) overlapMinutes(candidate *entities.RecommendationCandidate) int {
+	// The availability is laid out a day further each way so that times the time zones move
+	// across midnight are covered
+	loc := loadLocation(candidate.Tutor.TimeZone)
+	var available []timeInterval
+	for i := range candidate.Availability {
+		start, length := candidate.Availability[i].Window()
+		available = append(available,
+			layOutWeekly(candidate.Availability[i].DayOfWeek, start, length, loc, f.now, -1, 7)...)
+	}
+	available = mergeIntervals(available)
+
+	var overlap time.Duration
+	for _, preferred := range f.preferredTimes {
+		for _, interval := range available {
+			start, end := interval.start, interval.end
+			if preferred.start.After(start) {
+				start = preferred.start
+			}
+			if preferred.end.Before(end) {
+				end = preferred.end
+			}
+			if end.After(start) {
+				overlap += end.Sub(start)
+			}
+		}
+	}
+	return int(overlap / time.Minute)
+}
+
+// layOutWeekly returns the occurrences of a weekly time on the days from the first to the last
+// day counted from today in the time zone
+func layOutWeekly(day int, start, length time.Duration, loc *time.Location, now time.Time, first, last int) []timeInterval {
+	today := now.In(loc)
+	var intervals []timeInterval
+	for n := first; n <= last; n++ {
+		date := time.Date(today.Year(), today.Month(), today.Day()+n, 0, 0, 0, 0, loc)
+		if int(date.Weekday()) != day {
+			continue
+		}
+		begin := time.Date(date.Year(), date.Month(), date.Day(), 0, int(start/time.Minute), 0, 0, loc)
+		intervals = append(intervals, timeInterval{start: begin, end: begin.Add(length)})
+	}
+	return intervals
+}
+
+// mergeIntervals sorts intervals and merges the ones that overlap
+func mergeIntervals(intervals []timeInterval) []timeInterval {
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].start.Before(intervals[j].start)
+	})
+
+	var merged []timeInterval
+	for _, interval := range intervals {
+		if n := len(merged); n > 0 && !interval.start.After(merged[n-1].end) {
+			if interval.end.After(merged[n-1].end) {
+				merged[n-1].end = interval.end
+			}
+			continue
+		}
+		merged = append(merged, interval)
+	}
+	return merged
+}
+
+// loadLocation loads a time zone, falling back to UTC
+func loadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// intersect returns the values of a that are also in b
+func intersect(a, b []int) []int {
+	in := make(map[int]bool, len(b))
+	for _, value := range b {
+		in[value] = true
+	}
+	shared := []int{}
+	for _, value := range a {
+		if in[value] {
+			shared = append(shared, value)
+		}
+	}
+	return shared
+}
+
+func clamp01(value float64) float64 {
+	return math.Max(0, math.Min(1, value))
+}
+
+// roundScore rounds a score to three decimals
+func roundScore(value float64) float64 {
+	return math.Round(value*1000) / 1000
+}
@@ -0,0 +1,161 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/logger"
+	"tongly-backend/internal/repositories"
+)
+
+// reminderLookahead is how far beyond the reminder offsets lessons are scheduled, so that
+// a missed sweep does not lose reminders
+const reminderLookahead = time.Hour
+
+// ReminderUseCase schedules and sends reminders about upcoming lessons
+type ReminderUseCase struct {
+	lessonRepo *repositories.LessonRepository
+	jobRepo    *repositories.JobRepository
+	notifier   Notifier
+}
+
+// NewReminderUseCase creates a new ReminderUseCase
+func NewReminderUseCase(
+	lessonRepo *repositories.LessonRepository,
+	jobRepo *repositories.JobRepository,
+	notifier Notifier,
+) *ReminderUseCase {
+	return &ReminderUseCase{
+		lessonRepo: lessonRepo,
+		jobRepo:    jobRepo,
+		notifier:   notifier,
+	}
+}
+
+// ScheduleReminders enqueues reminder jobs for the lessons starting soon. Jobs are deduplicated
+// by lesson, kind and start time, so sweeping repeatedly is safe and a rescheduled lesson gets
+// new reminders. It returns the number of jobs enqueued.
+func (uc *ReminderUseCase) ScheduleReminders(ctx context.Context) (int, error) {
+	var maxOffset time.Duration
+	for _, offset := range entities.ReminderOffsets {
+		if offset > maxOffset {
+			maxOffset = offset
+		}
+	}
+
+	lessons, err := uc.lessonRepo.GetLessonsStartingBefore(ctx, time.Now().Add(maxOffset+reminderLookahead))
+	if err != nil {
+		return 0, err
+	}
+
+	enqueued := 0
+	for _, lesson := range lessons {
+		for kind, offset := range entities.ReminderOffsets {
+			runAt := lesson.StartTime.Add(-offset)
+
+			// A lesson booked after its reminder was due does not get that reminder
+			if lesson.CreatedAt.After(runAt) {
+				continue
+			}
+
+			_, created, err := uc.jobRepo.Enqueue(ctx, &entities.JobRequest{
+				Type: entities.JobTypeLessonReminder,
+				Payload: entities.LessonReminderPayload{
+					LessonID:  lesson.ID,
+					Kind:      kind,
+					StartTime: lesson.StartTime,
+				},
+				RunAt:    runAt,
+				DedupKey: fmt.Sprintf("lesson_reminder:%d:%s:%d", lesson.ID, kind, lesson.StartTime.Unix()),
+			})
+			if err != nil {
+				return enqueued, err
+			}
+			if created {
+				enqueued++
+			}
+		}
+	}
+
+	return enqueued, nil
+}
+
+// HandleLessonReminder runs a lesson reminder job, notifying the tutor and the students of the
+// lesson. Reminders of lessons that were cancelled, moved or have already started are dropped.
+func (uc *ReminderUseCase) HandleLessonReminder(ctx context.Context, job *entities.Job) error {
+	var payload entities.LessonReminderPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		// Retrying would not help, so drop the job
+		logger.Error("Invalid lesson reminder payload", "job_id", job.ID, "error", err)
+		return nil
+	}
+
+	lesson, err := uc.lessonRepo.GetByID(ctx, payload.LessonID)
+	if errors.Is(err, entities.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if lesson.CancelledAt != nil || !lesson.StartTime.Equal(payload.StartTime) || !lesson.StartTime.After(time.Now()) {
+		return nil
+	}
+
+	recipients := []int{lesson.TutorID}
+	if lesson.IsGroup() {
+		participants, err := uc.lessonRepo.GetParticipants(ctx, lesson.ID)
+		if err != nil {
+			return err
+		}
+		for _, participant := range participants {
+			recipients = append(recipients, participant.StudentID)
+		}
+	} else if lesson.StudentID != 0 {
+		recipients = append(recipients, lesson.StudentID)
+	}
+
+	languageName := "language"
+	if lesson.Language != nil {
+		languageName = lesson.Language.Name
+	}
+
+	body := fmt.Sprintf("Your %s lesson starts in 24 hours.", languageName)
+	if payload.Kind == entities.Reminder15m {
+		body = fmt.Sprintf("Your %s lesson starts in 15 minutes.", languageName)
+	}
+
+	// Notifications are deduplicated per recipient by the job's dedup key, so a retry after a
+	// partial failure only notifies the recipients and channels that were missed
+	jobKey := fmt.Sprintf("job:%d", job.ID)
+	if job.DedupKey != nil {
+		jobKey = *job.DedupKey
+	}
+
+	// Notify everyone before reporting a failure, so that one failing recipient does not
+	// block the others
+	var notifyErr error
+	for _, userID := range recipients {
+		err := uc.notifier.Notify(ctx, &entities.Notification{
+			UserID: userID,
+			Type:   entities.NotificationLessonReminder,
+			Title:  "Upcoming lesson",
+			Body:   body,
+			Data: map[string]interface{}{
+				"lesson_id":  lesson.ID,
+				"start_time": lesson.StartTime,
+				"kind":       payload.Kind,
+			},
+			DedupKey: fmt.Sprintf("%s:%d", jobKey, userID),
+		})
+		if err != nil {
+			logger.Error("Failed to send lesson reminder", "lesson_id", lesson.ID, "user_id", userID, "error", err)
+			notifyErr = err
+		}
+	}
+
+	return notifyErr
+}
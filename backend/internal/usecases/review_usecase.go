@@ -0,0 +1,208 @@
+package usecases
+
+import (
+	"context"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/logger"
+	"tongly-backend/internal/repositories"
+)
+
+const (
+	defaultReviewPageLimit = 20
+	maxReviewPageLimit     = 50
+
+	defaultModerationListLimit = 50
+	maxModerationListLimit     = 200
+)
+
+// ReviewUseCase handles the public reviews of tutors, their replies, votes and moderation
+type ReviewUseCase struct {
+	reviewRepo *repositories.ReviewRepository
+	notifier   Notifier
+}
+
+// NewReviewUseCase creates a new ReviewUseCase
+func NewReviewUseCase(reviewRepo *repositories.ReviewRepository, notifier Notifier) *ReviewUseCase {
+	return &ReviewUseCase{
+		reviewRepo: reviewRepo,
+		notifier:   notifier,
+	}
+}
+
+// GetTutorReviews retrieves a page of the reviews students wrote about a tutor, with the
+// summary of all of them
+func (uc *ReviewUseCase) GetTutorReviews(ctx context.Context, tutorID int, filters *entities.ReviewFilters) (*entities.ReviewPage, error) {
+	if filters.Sort != entities.ReviewSortHelpful {
+		filters.Sort = entities.ReviewSortRecent
+	}
+	if filters.Limit <= 0 {
+		filters.Limit = defaultReviewPageLimit
+	}
+	if filters.Limit > maxReviewPageLimit {
+		filters.Limit = maxReviewPageLimit
+	}
+	if filters.Offset < 0 {
+		filters.Offset = 0
+	}
+
+	summary, err := uc.reviewRepo.GetTutorSummary(ctx, tutorID)
+	if err != nil {
+		return nil, err
+	}
+	reviews, err := uc.reviewRepo.ListTutorReviews(ctx, tutorID, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.ReviewPage{
+		Reviews: reviews,
+		Summary: *summary,
+		Total:   summary.ReviewsCount,
+		Limit:   filters.Limit,
+		Offset:  filters.Offset,
+	}, nil
+}
+
+// ReplyToReview sets or replaces the tutor's public reply to a review of one of their lessons
+// and tells the reviewer about it
+func (uc *ReviewUseCase) ReplyToReview(ctx context.Context, tutorID, reviewID int, req *entities.ReviewReplyRequest) (*entities.Review, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	review, err := uc.getRepliableReview(ctx, tutorID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	hadReply := review.Reply != nil
+
+	if err := uc.reviewRepo.SetReply(ctx, reviewID, req.Text); err != nil {
+		return nil, err
+	}
+
+	// Editing a reply does not notify the reviewer again
+	if !hadReply {
+		err := uc.notifier.Notify(ctx, &entities.Notification{
+			UserID: review.ReviewerID,
+			Type:   entities.NotificationReviewReply,
+			Title:  "Your tutor replied to your review",
+			Body:   preview(req.Text),
+			Data:   map[string]interface{}{"lesson_id": review.LessonID, "review_id": review.ID, "tutor_id": tutorID},
+		})
+		if err != nil {
+			logger.Error("Failed to notify reviewer", "review_id", review.ID, "error", err)
+		}
+	}
+
+	return uc.reviewRepo.GetByID(ctx, reviewID)
+}
+
+// DeleteReply removes the tutor's reply to a review of one of their lessons
+func (uc *ReviewUseCase) DeleteReply(ctx context.Context, tutorID, reviewID int) error {
+	if _, err := uc.getRepliableReview(ctx, tutorID, reviewID); err != nil {
+		return err
+	}
+	return uc.reviewRepo.DeleteReply(ctx, reviewID)
+}
+
+// MarkHelpful records that the user found a visible review helpful and returns the new number of votes
+func (uc *ReviewUseCase) MarkHelpful(ctx context.Context, userID, reviewID int) (int, error) {
+	if err := uc.checkCanVote(ctx, userID, reviewID); err != nil {
+		return 0, err
+	}
+	return uc.reviewRepo.AddVote(ctx, reviewID, userID)
+}
+
+// UnmarkHelpful withdraws the user's helpful vote and returns the new number of votes
+func (uc *ReviewUseCase) UnmarkHelpful(ctx context.Context, userID, reviewID int) (int, error) {
+	if err := uc.checkCanVote(ctx, userID, reviewID); err != nil {
+		return 0, err
+	}
+	return uc.reviewRepo.RemoveVote(ctx, reviewID, userID)
+}
+
+// ReportReview reports a visible review to the admins
+func (uc *ReviewUseCase) ReportReview(ctx context.Context, userID, reviewID int, req *entities.ReviewReportRequest) (*entities.ReviewReport, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.getVisibleReview(ctx, reviewID); err != nil {
+		return nil, err
+	}
+
+	report := &entities.ReviewReport{
+		ReviewID:   reviewID,
+		ReporterID: userID,
+		Reason:     req.Reason,
+	}
+	if err := uc.reviewRepo.CreateReport(ctx, report); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// ListModerationQueue retrieves a page of reported or hidden reviews for the admins
+func (uc *ReviewUseCase) ListModerationQueue(ctx context.Context, filters *entities.ReviewModerationFilters) ([]entities.ModeratedReview, error) {
+	if filters.Status != entities.ReviewModerationHidden {
+		filters.Status = entities.ReviewModerationReported
+	}
+	if filters.Limit <= 0 {
+		filters.Limit = defaultModerationListLimit
+	}
+	if filters.Limit > maxModerationListLimit {
+		filters.Limit = maxModerationListLimit
+	}
+	if filters.Offset < 0 {
+		filters.Offset = 0
+	}
+
+	return uc.reviewRepo.ListModerationQueue(ctx, filters)
+}
+
+// HideReview takes a review off the tutor's profile and resolves its reports
+func (uc *ReviewUseCase) HideReview(ctx context.Context, adminID, reviewID int, req *entities.HideReviewRequest) error {
+	return uc.reviewRepo.Hide(ctx, reviewID, adminID, req.Reason)
+}
+
+// RestoreReview shows a hidden review again, or dismisses the reports of a visible one
+func (uc *ReviewUseCase) RestoreReview(ctx context.Context, reviewID int) error {
+	return uc.reviewRepo.Restore(ctx, reviewID)
+}
+
+// getRepliableReview retrieves a review of one of the tutor's lessons
+func (uc *ReviewUseCase) getRepliableReview(ctx context.Context, tutorID, reviewID int) (*entities.Review, error) {
+	review, err := uc.reviewRepo.GetByID(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.TutorID != tutorID {
+		return nil, entities.ErrReplyNotAllowed
+	}
+	return review, nil
+}
+
+// getVisibleReview retrieves a review that is shown on a tutor's profile
+func (uc *ReviewUseCase) getVisibleReview(ctx context.Context, reviewID int) (*entities.Review, error) {
+	review, err := uc.reviewRepo.GetByID(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.HiddenAt != nil {
+		return nil, entities.ErrReviewNotFound
+	}
+	return review, nil
+}
+
+// checkCanVote fails unless the review is visible and was written by someone else
+func (uc *ReviewUseCase) checkCanVote(ctx context.Context, userID, reviewID int) error {
+	review, err := uc.getVisibleReview(ctx, reviewID)
+	if err != nil {
+		return err
+	}
+	if review.ReviewerID == userID {
+		return entities.ErrCannotVoteOwnReview
+	}
+	return nil
+}
@@ -231,3 +231,19 @@ func (uc *StudentUseCase) AddGoal(ctx context.Context, studentID int, goalID int
 func (uc *StudentUseCase) RemoveGoal(ctx context.Context, studentID int, goalID int) error {
 	return uc.studentRepo.RemoveGoal(ctx, studentID, goalID)
 }
+
+// GetPreferences retrieves what a student would like from their lessons
+func (uc *StudentUseCase) GetPreferences(ctx context.Context, studentID int) (*entities.StudentPreferences, error) {
+	return uc.studentRepo.GetPreferences(ctx, studentID)
+}
+
+// UpdatePreferences replaces what a student would like from their lessons
+func (uc *StudentUseCase) UpdatePreferences(ctx context.Context, studentID int, prefs *entities.StudentPreferences) (*entities.StudentPreferences, error) {
+	if err := prefs.Validate(); err != nil {
+		return nil, err
+	}
+	if err := uc.studentRepo.SavePreferences(ctx, studentID, prefs); err != nil {
+		return nil, err
+	}
+	return uc.studentRepo.GetPreferences(ctx, studentID)
+}
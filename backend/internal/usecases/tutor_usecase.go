@@ -3,10 +3,19 @@ package usecases
 import (
 	"context"
 	"errors"
+	"strings"
 	"tongly-backend/internal/entities"
 	"tongly-backend/internal/repositories"
 )
 
+const (
+	// maxTutorSearchQueryLength is the longest free-text tutor search, in characters
+	maxTutorSearchQueryLength = 200
+
+	defaultTutorSearchLimit = 20
+	maxTutorSearchLimit     = 100
+)
+
 // TutorUseCase handles business logic for tutors
 type TutorUseCase struct {
 	tutorRepo   *repositories.TutorRepository
@@ -55,18 +64,6 @@ func (uc *TutorUseCase) GetTutorProfile(ctx context.Context, tutorID int) (*enti
 	}
 	tutorProfile.Languages = languages
 
-	// Get average rating
-	rating, err := uc.lessonRepo.GetTutorAverageRating(ctx, tutorID)
-	if err == nil {
-		tutorProfile.Rating = rating
-	}
-
-	// Get reviews count
-	reviewsCount, err := uc.lessonRepo.GetTutorReviewsCount(ctx, tutorID)
-	if err == nil {
-		tutorProfile.ReviewsCount = reviewsCount
-	}
-
 	return tutorProfile, nil
 }
 
@@ -86,6 +83,9 @@ func (uc *TutorUseCase) UpdateTutorProfile(ctx context.Context, tutorID int, req
 		tutorProfile.Bio = req.Bio
 	}
 	if req.Education != nil {
+		if err := entities.ValidateEducation(req.Education); err != nil {
+			return err
+		}
 		tutorProfile.Education = req.Education
 	}
 	if req.IntroVideoURL != "" {
@@ -94,11 +94,59 @@ func (uc *TutorUseCase) UpdateTutorProfile(ctx context.Context, tutorID int, req
 	if req.YearsExperience != nil {
 		tutorProfile.YearsExperience = *req.YearsExperience
 	}
+	if req.HourlyRate != nil {
+		if *req.HourlyRate < 0 {
+			return errors.New("hourly rate cannot be negative")
+		}
+		tutorProfile.HourlyRate = entities.RoundMoney(*req.HourlyRate)
+	}
+	if req.OffersTrial != nil {
+		tutorProfile.OffersTrial = *req.OffersTrial
+	}
+	if req.TrialPrice != nil {
+		if *req.TrialPrice < 0 {
+			return errors.New("trial price cannot be negative")
+		}
+		tutorProfile.TrialPrice = entities.RoundMoney(*req.TrialPrice)
+	}
+	if req.TimeZone != "" {
+		if err := entities.ValidateTimeZone(req.TimeZone); err != nil {
+			return err
+		}
+		tutorProfile.TimeZone = req.TimeZone
+	}
 
 	// Save updated profile
 	return uc.tutorRepo.Update(ctx, tutorProfile)
 }
 
+// AddEducation adds an entry to a tutor's education and returns all entries
+func (uc *TutorUseCase) AddEducation(ctx context.Context, tutorID int, education *entities.Education) ([]entities.Education, error) {
+	if err := education.Validate(); err != nil {
+		return nil, err
+	}
+	return uc.tutorRepo.AddEducation(ctx, tutorID, education)
+}
+
+// UpdateEducation replaces the entry at a position of a tutor's education and returns all entries
+func (uc *TutorUseCase) UpdateEducation(ctx context.Context, tutorID, index int, education *entities.Education) ([]entities.Education, error) {
+	if index < 0 {
+		return nil, entities.ErrEducationNotFound
+	}
+	if err := education.Validate(); err != nil {
+		return nil, err
+	}
+	return uc.tutorRepo.UpdateEducation(ctx, tutorID, index, education)
+}
+
+// RemoveEducation removes the entry at a position of a tutor's education and returns the rest
+func (uc *TutorUseCase) RemoveEducation(ctx context.Context, tutorID, index int) ([]entities.Education, error) {
+	if index < 0 {
+		return nil, entities.ErrEducationNotFound
+	}
+	return uc.tutorRepo.RemoveEducation(ctx, tutorID, index)
+}
+
 // AddTutorAvailability adds a new availability slot for a tutor
 func (uc *TutorUseCase) AddTutorAvailability(ctx context.Context, tutorID int, req *entities.TutorAvailabilityRequest) (*entities.TutorAvailability, error) {
 	// Validate tutor exists
@@ -178,41 +226,109 @@ func (uc *TutorUseCase) GetTutorAvailabilities(ctx context.Context, tutorID int)
 	return uc.tutorRepo.GetAvailabilities(ctx, tutorID)
 }
 
-// SearchTutors searches for tutors based on filters
-func (uc *TutorUseCase) SearchTutors(ctx context.Context, filters *entities.TutorSearchFilters) ([]entities.TutorProfile, error) {
+// SearchTutors retrieves a page of the tutors matching the filters with the total number of
+// matches and the facet counts of the filter options. Results are best rated first or, with
+// a free-text query, best matching first unless another sort is asked for.
+func (uc *TutorUseCase) SearchTutors(ctx context.Context, filters *entities.TutorSearchFilters) (*entities.TutorSearchPage, error) {
+	if err := normalizeSearchFilters(filters); err != nil {
+		return nil, err
+	}
+	if filters.Sort == "" {
+		filters.Sort = entities.TutorSortRating
+		if filters.Query != "" {
+			filters.Sort = entities.TutorSortRelevance
+		}
+	}
+	if filters.Sort == entities.TutorSortRelevance && filters.Query == "" {
+		filters.Sort = entities.TutorSortRating
+	}
+	if filters.Limit <= 0 {
+		filters.Limit = defaultTutorSearchLimit
+	}
+	if filters.Limit > maxTutorSearchLimit {
+		filters.Limit = maxTutorSearchLimit
+	}
+	if filters.Offset < 0 {
+		filters.Offset = 0
+	}
+
 	tutors, err := uc.tutorRepo.SearchTutors(ctx, filters)
 	if err != nil {
 		return nil, err
 	}
+	total, err := uc.tutorRepo.CountTutors(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+	facets, err := uc.tutorRepo.GetSearchFacets(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
 
-	// For each tutor, enrich with additional information
-	for i := range tutors {
-		// Get user information
-		user, err := uc.userRepo.GetByID(ctx, tutors[i].UserID)
-		if err != nil {
-			continue // Skip if can't get user info
-		}
-		tutors[i].User = user
+	if err := populateTutorRelations(ctx, uc.userRepo, uc.studentRepo, tutors); err != nil {
+		return nil, err
+	}
 
-		// Get languages
-		languages, err := uc.studentRepo.GetLanguages(ctx, tutors[i].UserID)
-		if err != nil {
-			continue // Skip if can't get languages
-		}
-		tutors[i].Languages = languages
+	return &entities.TutorSearchPage{
+		Tutors: tutors,
+		Total:  total,
+		Limit:  filters.Limit,
+		Offset: filters.Offset,
+		Sort:   filters.Sort,
+		Facets: *facets,
+	}, nil
+}
 
-		// Get rating
-		rating, err := uc.lessonRepo.GetTutorAverageRating(ctx, tutors[i].UserID)
-		if err == nil {
-			tutors[i].Rating = rating
-		}
+// normalizeSearchFilters trims the free text of tutor search filters, defaults their time
+// zone to UTC and checks their availability filters
+func normalizeSearchFilters(filters *entities.TutorSearchFilters) error {
+	filters.Query = strings.TrimSpace(filters.Query)
+	if len([]rune(filters.Query)) > maxTutorSearchQueryLength {
+		filters.Query = string([]rune(filters.Query)[:maxTutorSearchQueryLength])
+	}
+	if filters.TimeZone == "" {
+		filters.TimeZone = "UTC"
+	}
+	return filters.ValidateAvailability()
+}
 
-		// Get reviews count
-		reviewsCount, err := uc.lessonRepo.GetTutorReviewsCount(ctx, tutors[i].UserID)
-		if err == nil {
-			tutors[i].ReviewsCount = reviewsCount
-		}
+// populateTutorRelations loads the users and languages of a list of tutors with one query
+// each, whatever the size of the list
+func populateTutorRelations(ctx context.Context, userRepo *repositories.UserRepository, studentRepo *repositories.StudentRepository, tutors []entities.TutorProfile) error {
+	if len(tutors) == 0 {
+		return nil
 	}
 
-	return tutors, nil
+	userIDs := make([]int, len(tutors))
+	for i := range tutors {
+		userIDs[i] = tutors[i].UserID
+	}
+
+	users, err := userRepo.GetByIDs(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+	languages, err := studentRepo.GetLanguagesByUserIDs(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range tutors {
+		tutors[i].User = users[tutors[i].UserID]
+		tutors[i].Languages = languages[tutors[i].UserID]
+	}
+	return nil
+}
+
+// GetTrialConversionStats retrieves how many of a tutor's trial lessons were followed by a paid lesson
+func (uc *TutorUseCase) GetTrialConversionStats(ctx context.Context, tutorID int) (*entities.TrialConversionStats, error) {
+	return uc.lessonRepo.GetTrialConversionStats(ctx, tutorID)
+}
+
+// RefreshRatingStats recomputes the cached rating stats of every tutor. Reviews refresh their
+// tutor's stats right away; this moves everyone's scores along with the mean of all reviews
+// and lets old reviews weigh less over time.
+func (uc *TutorUseCase) RefreshRatingStats(ctx context.Context) error {
+	_, err := uc.tutorRepo.RefreshStats(ctx)
+	return err
 }
@@ -0,0 +1,542 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+	"tongly-backend/internal/entities"
+	"tongly-backend/internal/logger"
+	"tongly-backend/internal/repositories"
+	"tongly-backend/pkg/imaging"
+	"tongly-backend/pkg/storage"
+
+	"github.com/google/uuid"
+)
+
+// sniffLength is the number of leading bytes used to detect the type of a file
+const sniffLength = 512
+
+// avatarFormats lists the formats every avatar variant is encoded in. JPEG comes first, as it
+// is served to browsers that don't accept WebP.
+var avatarFormats = []struct {
+	contentType string
+	extension   string
+	encode      func(io.Writer, image.Image) error
+}{
+	{contentType: "image/jpeg", extension: ".jpg", encode: imaging.EncodeJPEG},
+	{contentType: "image/webp", extension: ".webp", encode: imaging.EncodeWebP},
+}
+
+// UploadUseCase handles uploaded files: avatars, intro videos and lesson materials
+type UploadUseCase struct {
+	uploadRepo *repositories.UploadRepository
+	lessonRepo *repositories.LessonRepository
+	jobRepo    *repositories.JobRepository
+	store      storage.Storage
+	apiURL     string
+	quotaBytes int64
+	urlTTL     time.Duration
+}
+
+// NewUploadUseCase creates a new UploadUseCase. Each user may upload quotaBytes in total,
+// and signed download URLs stay valid for urlTTL.
+func NewUploadUseCase(
+	uploadRepo *repositories.UploadRepository,
+	lessonRepo *repositories.LessonRepository,
+	jobRepo *repositories.JobRepository,
+	store storage.Storage,
+	apiURL string,
+	quotaBytes int64,
+	urlTTL time.Duration,
+) *UploadUseCase {
+	return &UploadUseCase{
+		uploadRepo: uploadRepo,
+		lessonRepo: lessonRepo,
+		jobRepo:    jobRepo,
+		store:      store,
+		apiURL:     apiURL,
+		quotaBytes: quotaBytes,
+		urlTTL:     urlTTL,
+	}
+}
+
+// Upload stores a file for a purpose. The type is detected from the content rather than
+// trusted from the client, and the file is buffered to disk to learn its size before it
+// is counted against the user's quota and handed to the storage backend.
+func (uc *UploadUseCase) Upload(ctx context.Context, userID int, purpose entities.UploadPurpose, filename string, body io.Reader) (*entities.Upload, error) {
+	if err := purpose.Validate(); err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(body, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, entities.ErrEmptyFile
+	}
+	head = head[:n]
+
+	contentType := sniffContentType(head)
+	ext, ok := purpose.Extension(contentType)
+	if !ok {
+		return nil, entities.ErrUnsupportedFileType
+	}
+
+	tmp, err := os.CreateTemp("", "tongly-upload-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	size, err := io.Copy(tmp, io.LimitReader(io.MultiReader(bytes.NewReader(head), body), purpose.MaxSize()+1))
+	if err != nil {
+		return nil, err
+	}
+	if size > purpose.MaxSize() {
+		return nil, entities.ErrFileTooLarge
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	// Avatars are decoded later by a background job; reject what it could not decode now
+	if purpose.IsProcessed() {
+		if err := checkImage(tmp); err != nil {
+			return nil, err
+		}
+		if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+
+	upload := &entities.Upload{
+		UserID:      userID,
+		Purpose:     purpose,
+		StorageKey:  fmt.Sprintf("%s/%d/%s%s", purpose, userID, uuid.NewString(), ext),
+		Filename:    entities.CleanFilename(filename),
+		ContentType: contentType,
+		Size:        size,
+	}
+	if err := uc.uploadRepo.Create(ctx, upload, uc.quotaBytes); err != nil {
+		return nil, err
+	}
+
+	if err := uc.store.Put(ctx, upload.StorageKey, tmp, size, contentType); err != nil {
+		// Remove the record so that the failed upload does not count against the quota
+		if _, deleteErr := uc.uploadRepo.Delete(ctx, upload, ""); deleteErr != nil {
+			logger.Error("Failed to remove record of failed upload", "upload_id", upload.ID, "error", deleteErr)
+		}
+		return nil, err
+	}
+
+	if err := uc.sign(upload); err != nil {
+		return nil, err
+	}
+	return upload, nil
+}
+
+// GetUploads lists the user's uploads with signed URLs and their quota
+func (uc *UploadUseCase) GetUploads(ctx context.Context, userID int) (*entities.UploadList, error) {
+	uploads, err := uc.uploadRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &entities.UploadList{
+		Uploads: uploads,
+		Quota:   entities.UploadQuota{LimitBytes: uc.quotaBytes},
+	}
+	for i := range list.Uploads {
+		list.Quota.UsedBytes += list.Uploads[i].Size
+		if err := uc.sign(&list.Uploads[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return list, nil
+}
+
+// GetUpload retrieves an upload with a fresh signed URL. Users can get their own uploads,
+// public profile media that is served unprocessed and the materials of lessons they attend.
+func (uc *UploadUseCase) GetUpload(ctx context.Context, userID, uploadID int) (*entities.Upload, error) {
+	upload, err := uc.uploadRepo.GetByID(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	if upload.UserID != userID && (!upload.Purpose.IsPublic() || upload.Purpose.IsProcessed()) {
+		shared, err := uc.uploadRepo.IsSharedWith(ctx, uploadID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !shared {
+			return nil, entities.ErrUploadAccessDenied
+		}
+	}
+
+	if err := uc.sign(upload); err != nil {
+		return nil, err
+	}
+	return upload, nil
+}
+
+// GetPublicURL returns a signed URL of a public upload, such as an avatar, and its expiry.
+// Avatars are served as the processed variant of the given width, or the largest one if
+// width is 0, and are not found until processed. The WebP variant is served if acceptsWebP
+// and the avatar has one. Other uploads are reported as not found so that their IDs cannot
+// be probed.
+func (uc *UploadUseCase) GetPublicURL(ctx context.Context, uploadID, width int, acceptsWebP bool) (string, time.Time, error) {
+	upload, err := uc.uploadRepo.GetByID(ctx, uploadID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if !upload.Purpose.IsPublic() {
+		return "", time.Time{}, entities.ErrUploadNotFound
+	}
+
+	key := upload.StorageKey
+	if upload.Purpose.IsProcessed() {
+		if width == 0 {
+			width = entities.AvatarSizes[len(entities.AvatarSizes)-1]
+		}
+		variant, err := uc.getAvatarVariant(ctx, upload.ID, width, acceptsWebP)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		key = variant.StorageKey
+	}
+
+	expiresAt := uc.expiry()
+	url, err := uc.store.SignedURL(key, expiresAt)
+	return url, expiresAt, err
+}
+
+// getAvatarVariant retrieves the WebP variant of an avatar if wanted, or else the JPEG one
+func (uc *UploadUseCase) getAvatarVariant(ctx context.Context, uploadID, width int, webp bool) (*entities.UploadVariant, error) {
+	if webp {
+		variant, err := uc.uploadRepo.GetVariant(ctx, uploadID, width, "image/webp")
+		if !errors.Is(err, entities.ErrUploadNotFound) {
+			return variant, err
+		}
+	}
+	return uc.uploadRepo.GetVariant(ctx, uploadID, width, "image/jpeg")
+}
+
+// OpenSignedFile opens a file requested through a signed URL of the local storage backend
+func (uc *UploadUseCase) OpenSignedFile(ctx context.Context, key, expires, signature string) (io.ReadCloser, error) {
+	verifier, ok := uc.store.(storage.Verifier)
+	if !ok {
+		return nil, entities.ErrUploadNotFound
+	}
+	if err := verifier.Verify(key, expires, signature); err != nil {
+		return nil, err
+	}
+
+	return uc.store.Open(ctx, key)
+}
+
+// DeleteUpload removes one of the user's uploads. An avatar or intro video using it is cleared.
+func (uc *UploadUseCase) DeleteUpload(ctx context.Context, userID, uploadID int) error {
+	upload, err := uc.uploadRepo.GetByID(ctx, uploadID)
+	if err != nil {
+		return err
+	}
+	if upload.UserID != userID {
+		return entities.ErrUploadNotFound
+	}
+
+	keys, err := uc.uploadRepo.Delete(ctx, upload, uc.contentURL(upload.ID))
+	if err != nil {
+		return err
+	}
+
+	uc.deleteFiles(ctx, keys...)
+	return nil
+}
+
+// SetAvatar queues one of the user's avatar uploads to become their profile picture. A
+// background job makes its variants and then replaces the previous picture, so the request
+// returns before the image is decoded.
+func (uc *UploadUseCase) SetAvatar(ctx context.Context, userID, uploadID int) (*entities.Upload, error) {
+	upload, err := uc.getOwnUpload(ctx, userID, uploadID, entities.UploadAvatar)
+	if err != nil {
+		return nil, err
+	}
+
+	_, _, err = uc.jobRepo.Enqueue(ctx, &entities.JobRequest{
+		Type:     entities.JobTypeProcessAvatar,
+		Payload:  entities.ProcessAvatarPayload{UploadID: upload.ID},
+		RunAt:    time.Now(),
+		DedupKey: fmt.Sprintf("process_avatar:%d", upload.ID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.sign(upload); err != nil {
+		return nil, err
+	}
+	return upload, nil
+}
+
+// HandleProcessAvatar runs the job that makes the square variants of an avatar and sets it as
+// the user's profile picture. Files that turn out not to be usable images are deleted.
+func (uc *UploadUseCase) HandleProcessAvatar(ctx context.Context, job *entities.Job) error {
+	var payload entities.ProcessAvatarPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		// Retrying would not help, so drop the job
+		logger.Error("Invalid process avatar payload", "job_id", job.ID, "error", err)
+		return nil
+	}
+
+	upload, err := uc.uploadRepo.GetByID(ctx, payload.UploadID)
+	if err != nil {
+		if errors.Is(err, entities.ErrUploadNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	file, err := uc.store.Open(ctx, upload.StorageKey)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(io.LimitReader(file, upload.Purpose.MaxSize()+1))
+	file.Close()
+	if err != nil {
+		return err
+	}
+
+	img, orientation, err := imaging.Decode(data)
+	if err != nil {
+		logger.Error("Deleting avatar that cannot be decoded", "upload_id", upload.ID, "error", err)
+		if keys, deleteErr := uc.uploadRepo.Delete(ctx, upload, uc.contentURL(upload.ID)); deleteErr == nil {
+			uc.deleteFiles(ctx, keys...)
+		} else if !errors.Is(deleteErr, entities.ErrUploadNotFound) {
+			return deleteErr
+		}
+		return nil
+	}
+
+	// Scale down once from the full image and orient the result; smaller sizes start from it
+	largest := entities.AvatarSizes[len(entities.AvatarSizes)-1]
+	base := imaging.Orient(imaging.Thumbnail(img, largest), orientation)
+
+	contentURL := uc.contentURL(upload.ID)
+	variants := make([]entities.UploadVariant, 0, len(entities.AvatarSizes)*len(avatarFormats))
+	urls := make(map[int]string, len(entities.AvatarSizes))
+	for _, width := range entities.AvatarSizes {
+		thumbnail := imaging.Thumbnail(base, width)
+		for _, format := range avatarFormats {
+			var buf bytes.Buffer
+			if err := format.encode(&buf, thumbnail); err != nil {
+				return err
+			}
+
+			variant := entities.UploadVariant{
+				UploadID:    upload.ID,
+				Width:       width,
+				StorageKey:  variantKey(upload.StorageKey, width, format.extension),
+				ContentType: format.contentType,
+				Size:        int64(buf.Len()),
+			}
+			if err := uc.store.Put(ctx, variant.StorageKey, &buf, variant.Size, variant.ContentType); err != nil {
+				return err
+			}
+			variants = append(variants, variant)
+		}
+		urls[width] = fmt.Sprintf("%s?size=%d", contentURL, width)
+	}
+
+	staleKeys, err := uc.uploadRepo.SetAvatar(ctx, upload, variants, contentURL, urls)
+	if err != nil {
+		if errors.Is(err, entities.ErrUploadNotFound) {
+			// Deleted while being processed, so nothing refers to the variants
+			for _, variant := range variants {
+				uc.deleteFiles(ctx, variant.StorageKey)
+			}
+			return nil
+		}
+		return err
+	}
+
+	uc.deleteFiles(ctx, staleKeys...)
+	return nil
+}
+
+// SetIntroVideo makes one of the tutor's video uploads their intro video, replacing the previous one
+func (uc *UploadUseCase) SetIntroVideo(ctx context.Context, tutorID, uploadID int) (*entities.Upload, error) {
+	upload, err := uc.getOwnUpload(ctx, tutorID, uploadID, entities.UploadIntroVideo)
+	if err != nil {
+		return nil, err
+	}
+
+	staleKeys, err := uc.uploadRepo.SetIntroVideo(ctx, upload, uc.contentURL(upload.ID))
+	if err != nil {
+		return nil, err
+	}
+	uc.deleteFiles(ctx, staleKeys...)
+
+	if err := uc.sign(upload); err != nil {
+		return nil, err
+	}
+	return upload, nil
+}
+
+// AddLessonMaterial shares one of the user's lesson material uploads with a lesson they attend
+func (uc *UploadUseCase) AddLessonMaterial(ctx context.Context, userID, lessonID, uploadID int) (*entities.LessonMaterial, error) {
+	if _, err := getAttendedLesson(ctx, uc.lessonRepo, userID, lessonID); err != nil {
+		return nil, err
+	}
+	if _, err := uc.getOwnUpload(ctx, userID, uploadID, entities.UploadLessonMaterial); err != nil {
+		return nil, err
+	}
+
+	material, err := uc.uploadRepo.AttachToLesson(ctx, lessonID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.sign(&material.Upload); err != nil {
+		return nil, err
+	}
+	return material, nil
+}
+
+// GetLessonMaterials lists the files shared with a lesson for one of its attendees
+func (uc *UploadUseCase) GetLessonMaterials(ctx context.Context, userID, lessonID int) ([]entities.LessonMaterial, error) {
+	if _, err := getAttendedLesson(ctx, uc.lessonRepo, userID, lessonID); err != nil {
+		return nil, err
+	}
+
+	materials, err := uc.uploadRepo.ListLessonMaterials(ctx, lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range materials {
+		if err := uc.sign(&materials[i].Upload); err != nil {
+			return nil, err
+		}
+	}
+	return materials, nil
+}
+
+// RemoveLessonMaterial stops sharing a file with a lesson. The user who shared it and the
+// tutor of the lesson may do this; the upload itself is kept.
+func (uc *UploadUseCase) RemoveLessonMaterial(ctx context.Context, userID, lessonID, uploadID int) error {
+	lesson, err := getAttendedLesson(ctx, uc.lessonRepo, userID, lessonID)
+	if err != nil {
+		return err
+	}
+
+	material, err := uc.uploadRepo.GetLessonMaterial(ctx, lessonID, uploadID)
+	if err != nil {
+		return err
+	}
+	if material.UserID != userID && lesson.TutorID != userID {
+		return entities.ErrUploadAccessDenied
+	}
+
+	return uc.uploadRepo.DetachFromLesson(ctx, lessonID, uploadID)
+}
+
+// getOwnUpload retrieves one of the user's uploads, checking that it was uploaded for purpose
+func (uc *UploadUseCase) getOwnUpload(ctx context.Context, userID, uploadID int, purpose entities.UploadPurpose) (*entities.Upload, error) {
+	upload, err := uc.uploadRepo.GetByID(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if upload.UserID != userID {
+		return nil, entities.ErrUploadNotFound
+	}
+	if upload.Purpose != purpose {
+		return nil, entities.ErrWrongUploadPurpose
+	}
+	return upload, nil
+}
+
+// SignUpload fills in the download URLs of an upload loaded along with something else, such
+// as the proof document of a credential. Callers check that the user may see the file.
+func (uc *UploadUseCase) SignUpload(upload *entities.Upload) error {
+	return uc.sign(upload)
+}
+
+// sign fills in the download URLs of an upload
+func (uc *UploadUseCase) sign(upload *entities.Upload) error {
+	expiresAt := uc.expiry()
+	url, err := uc.store.SignedURL(upload.StorageKey, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	upload.URL = url
+	upload.URLExpiresAt = &expiresAt
+	if upload.Purpose.IsPublic() {
+		upload.ContentURL = uc.contentURL(upload.ID)
+	}
+	return nil
+}
+
+// expiry returns the expiry time of signed URLs created now. It is rounded so that URLs
+// signed within the same period are identical and browsers can cache the files; every URL
+// stays valid for at least urlTTL.
+func (uc *UploadUseCase) expiry() time.Time {
+	return time.Now().Truncate(uc.urlTTL).Add(2 * uc.urlTTL)
+}
+
+// contentURL returns the permanent address of an upload, which redirects to a signed URL.
+// Profiles store it so that avatars and intro videos never expire.
+func (uc *UploadUseCase) contentURL(uploadID int) string {
+	return fmt.Sprintf("%s/api/uploads/%d/content", uc.apiURL, uploadID)
+}
+
+// deleteFiles removes files from the storage backend, logging failures since the records are already gone
+func (uc *UploadUseCase) deleteFiles(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if err := uc.store.Delete(ctx, key); err != nil {
+			logger.Error("Failed to delete stored file", "key", key, "error", err)
+		}
+	}
+}
+
+// variantKey returns the storage key of a variant, next to the original file:
+// "avatar/42/0f8fad5b.png" becomes "avatar/42/0f8fad5b-128.jpg" for a JPEG variant
+func variantKey(key string, width int, extension string) string {
+	return fmt.Sprintf("%s-%d%s", strings.TrimSuffix(key, path.Ext(key)), width, extension)
+}
+
+// checkImage reads the header of an image to check that it can be decoded safely
+func checkImage(r io.Reader) error {
+	_, _, err := imaging.CheckConfig(r)
+	switch {
+	case errors.Is(err, imaging.ErrTooLarge):
+		return entities.ErrImageTooLarge
+	case err != nil:
+		return entities.ErrUnsupportedFileType
+	}
+	return nil
+}
+
+// sniffContentType detects the media type of a file from its first bytes, without parameters
+func sniffContentType(head []byte) string {
+	detected := http.DetectContentType(head)
+	mediaType, _, err := mime.ParseMediaType(detected)
+	if err != nil {
+		return detected
+	}
+	return mediaType
+}
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_lessons_tutor_end_time;
+DROP INDEX IF EXISTS idx_tutor_payouts_tutor_id;
+
+DROP TRIGGER IF EXISTS update_tutor_payouts_updated_at ON tutor_payouts;
+DROP TABLE IF EXISTS tutor_payouts CASCADE;
+
+ALTER TABLE lessons DROP COLUMN IF EXISTS no_show_fee;
+ALTER TABLE lessons DROP COLUMN IF EXISTS student_no_show;
+ALTER TABLE lessons DROP COLUMN IF EXISTS cancellation_fee;
+ALTER TABLE lessons DROP COLUMN IF EXISTS price;
+
+ALTER TABLE tutor_profiles DROP COLUMN IF EXISTS hourly_rate;
@@ -0,0 +1,28 @@
+-- Tutor rates and lesson prices
+ALTER TABLE tutor_profiles ADD COLUMN hourly_rate NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (hourly_rate >= 0);
+
+ALTER TABLE lessons ADD COLUMN price NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (price >= 0);
+ALTER TABLE lessons ADD COLUMN cancellation_fee NUMERIC(10, 2) CHECK (cancellation_fee >= 0);
+ALTER TABLE lessons ADD COLUMN student_no_show BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE lessons ADD COLUMN no_show_fee NUMERIC(10, 2) CHECK (no_show_fee >= 0);
+
+-- Table: tutor_payouts
+CREATE TABLE tutor_payouts (
+    id SERIAL PRIMARY KEY,
+    tutor_id INTEGER NOT NULL,
+    amount NUMERIC(10, 2) NOT NULL CHECK (amount > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'requested' CHECK (status IN ('requested', 'paid', 'rejected')),
+    requested_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    processed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (tutor_id) REFERENCES users(id) ON DELETE RESTRICT
+);
+
+CREATE TRIGGER update_tutor_payouts_updated_at
+    BEFORE UPDATE ON tutor_payouts
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE INDEX idx_tutor_payouts_tutor_id ON tutor_payouts(tutor_id);
+CREATE INDEX idx_lessons_tutor_end_time ON lessons(tutor_id, end_time);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_tutor_profiles_offers_trial;
+DROP INDEX IF EXISTS idx_lessons_one_trial_per_tutor;
+
+ALTER TABLE lessons DROP COLUMN IF EXISTS lesson_type;
+
+ALTER TABLE tutor_profiles DROP COLUMN IF EXISTS trial_price;
+ALTER TABLE tutor_profiles DROP COLUMN IF EXISTS offers_trial;
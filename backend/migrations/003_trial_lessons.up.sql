@@ -0,0 +1,11 @@
+-- Trial lessons offered by tutors
+ALTER TABLE tutor_profiles ADD COLUMN offers_trial BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE tutor_profiles ADD COLUMN trial_price NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (trial_price >= 0);
+
+ALTER TABLE lessons ADD COLUMN lesson_type VARCHAR(10) NOT NULL DEFAULT 'regular' CHECK (lesson_type IN ('regular', 'trial'));
+
+-- A student can have at most one trial lesson with each tutor (cancelled trials don't count)
+CREATE UNIQUE INDEX idx_lessons_one_trial_per_tutor ON lessons(student_id, tutor_id)
+    WHERE lesson_type = 'trial' AND cancelled_at IS NULL;
+
+CREATE INDEX idx_tutor_profiles_offers_trial ON tutor_profiles(offers_trial) WHERE offers_trial;
@@ -0,0 +1,18 @@
+DROP INDEX IF EXISTS idx_lessons_group_deadline;
+DROP INDEX IF EXISTS idx_lesson_participants_student_id;
+DROP INDEX IF EXISTS idx_lesson_participants_active_seat;
+
+DROP TRIGGER IF EXISTS update_lesson_participants_updated_at ON lesson_participants;
+DROP TABLE IF EXISTS lesson_participants CASCADE;
+
+DELETE FROM lessons WHERE lesson_type = 'group';
+
+ALTER TABLE lessons DROP CONSTRAINT IF EXISTS lessons_group_check;
+ALTER TABLE lessons DROP COLUMN IF EXISTS enrollment_deadline;
+ALTER TABLE lessons DROP COLUMN IF EXISTS min_participants;
+ALTER TABLE lessons DROP COLUMN IF EXISTS capacity;
+
+ALTER TABLE lessons DROP CONSTRAINT IF EXISTS lessons_lesson_type_check;
+ALTER TABLE lessons ADD CONSTRAINT lessons_lesson_type_check CHECK (lesson_type IN ('regular', 'trial'));
+
+ALTER TABLE lessons ALTER COLUMN student_id SET NOT NULL;
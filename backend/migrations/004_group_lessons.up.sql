@@ -0,0 +1,42 @@
+-- Group lessons have no single student; participants are stored in lesson_participants
+ALTER TABLE lessons ALTER COLUMN student_id DROP NOT NULL;
+
+ALTER TABLE lessons DROP CONSTRAINT IF EXISTS lessons_lesson_type_check;
+ALTER TABLE lessons ADD CONSTRAINT lessons_lesson_type_check CHECK (lesson_type IN ('regular', 'trial', 'group'));
+
+ALTER TABLE lessons ADD COLUMN capacity INTEGER CHECK (capacity > 0);
+ALTER TABLE lessons ADD COLUMN min_participants INTEGER CHECK (min_participants > 0);
+ALTER TABLE lessons ADD COLUMN enrollment_deadline TIMESTAMP;
+
+ALTER TABLE lessons ADD CONSTRAINT lessons_group_check CHECK (
+    (lesson_type = 'group' AND student_id IS NULL AND capacity IS NOT NULL
+        AND min_participants IS NOT NULL AND min_participants <= capacity
+        AND enrollment_deadline IS NOT NULL)
+    OR (lesson_type <> 'group' AND student_id IS NOT NULL)
+);
+
+-- Table: lesson_participants
+CREATE TABLE lesson_participants (
+    id SERIAL PRIMARY KEY,
+    lesson_id INTEGER NOT NULL,
+    student_id INTEGER NOT NULL,
+    price NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (price >= 0),
+    cancelled_at TIMESTAMP,
+    cancellation_fee NUMERIC(10, 2) CHECK (cancellation_fee >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (lesson_id) REFERENCES lessons(id) ON DELETE CASCADE,
+    FOREIGN KEY (student_id) REFERENCES users(id) ON DELETE RESTRICT
+);
+
+CREATE TRIGGER update_lesson_participants_updated_at
+    BEFORE UPDATE ON lesson_participants
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- A student holds at most one active seat per lesson
+CREATE UNIQUE INDEX idx_lesson_participants_active_seat ON lesson_participants(lesson_id, student_id)
+    WHERE cancelled_at IS NULL;
+CREATE INDEX idx_lesson_participants_student_id ON lesson_participants(student_id);
+CREATE INDEX idx_lessons_group_deadline ON lessons(enrollment_deadline)
+    WHERE lesson_type = 'group' AND cancelled_at IS NULL;
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_lessons_published_start_time;
+DROP INDEX IF EXISTS idx_lesson_interests_interest_id;
+
+DROP TABLE IF EXISTS lesson_interests CASCADE;
+
+ALTER TABLE lessons DROP CONSTRAINT IF EXISTS lessons_published_check;
+ALTER TABLE lessons DROP COLUMN IF EXISTS max_proficiency_id;
+ALTER TABLE lessons DROP COLUMN IF EXISTS min_proficiency_id;
+ALTER TABLE lessons DROP COLUMN IF EXISTS description;
+ALTER TABLE lessons DROP COLUMN IF EXISTS topic;
+ALTER TABLE lessons DROP COLUMN IF EXISTS is_published;
@@ -0,0 +1,26 @@
+-- Catalog information of group lessons published as open classes
+ALTER TABLE lessons ADD COLUMN is_published BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE lessons ADD COLUMN topic VARCHAR(200);
+ALTER TABLE lessons ADD COLUMN description TEXT;
+ALTER TABLE lessons ADD COLUMN min_proficiency_id INTEGER REFERENCES language_proficiency(id) ON DELETE RESTRICT;
+ALTER TABLE lessons ADD COLUMN max_proficiency_id INTEGER REFERENCES language_proficiency(id) ON DELETE RESTRICT;
+
+ALTER TABLE lessons ADD CONSTRAINT lessons_published_check CHECK (
+    NOT is_published OR (lesson_type = 'group' AND topic IS NOT NULL
+        AND min_proficiency_id IS NOT NULL AND max_proficiency_id IS NOT NULL
+        AND min_proficiency_id <= max_proficiency_id)
+);
+
+-- Table: lesson_interests
+CREATE TABLE lesson_interests (
+    lesson_id INTEGER NOT NULL,
+    interest_id INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (lesson_id, interest_id),
+    FOREIGN KEY (lesson_id) REFERENCES lessons(id) ON DELETE CASCADE,
+    FOREIGN KEY (interest_id) REFERENCES interests(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_lesson_interests_interest_id ON lesson_interests(interest_id);
+CREATE INDEX idx_lessons_published_start_time ON lessons(start_time)
+    WHERE is_published AND cancelled_at IS NULL;
@@ -0,0 +1,8 @@
+-- Table: calendar_feeds
+-- Secret tokens of the per-user iCalendar subscription URLs
+CREATE TABLE calendar_feeds (
+    user_id INTEGER PRIMARY KEY,
+    token VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
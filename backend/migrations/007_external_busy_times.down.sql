@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_tutor_busy_times_source_id;
+DROP INDEX IF EXISTS idx_tutor_busy_times_tutor_time;
+DROP INDEX IF EXISTS idx_tutor_calendar_sources_tutor_id;
+
+DROP TABLE IF EXISTS tutor_busy_times CASCADE;
+
+DROP TRIGGER IF EXISTS update_tutor_calendar_sources_updated_at ON tutor_calendar_sources;
+DROP TABLE IF EXISTS tutor_calendar_sources CASCADE;
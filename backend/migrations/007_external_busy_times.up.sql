@@ -0,0 +1,38 @@
+-- Table: tutor_calendar_sources
+-- External calendars of tutors, subscribed by URL or uploaded as an .ics file
+CREATE TABLE tutor_calendar_sources (
+    id SERIAL PRIMARY KEY,
+    tutor_id INTEGER NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    source_type VARCHAR(10) NOT NULL CHECK (source_type IN ('url', 'upload')),
+    url TEXT,
+    content TEXT,
+    last_synced_at TIMESTAMP,
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (tutor_id) REFERENCES users(id) ON DELETE CASCADE,
+    CHECK ((source_type = 'url' AND url IS NOT NULL) OR (source_type = 'upload' AND content IS NOT NULL))
+);
+
+CREATE TRIGGER update_tutor_calendar_sources_updated_at
+    BEFORE UPDATE ON tutor_calendar_sources
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Table: tutor_busy_times
+-- Busy intervals expanded from the external calendars, replaced on every sync
+CREATE TABLE tutor_busy_times (
+    id SERIAL PRIMARY KEY,
+    source_id INTEGER NOT NULL,
+    tutor_id INTEGER NOT NULL,
+    start_time TIMESTAMP NOT NULL,
+    end_time TIMESTAMP NOT NULL,
+    FOREIGN KEY (source_id) REFERENCES tutor_calendar_sources(id) ON DELETE CASCADE,
+    FOREIGN KEY (tutor_id) REFERENCES users(id) ON DELETE CASCADE,
+    CHECK (end_time > start_time)
+);
+
+CREATE INDEX idx_tutor_calendar_sources_tutor_id ON tutor_calendar_sources(tutor_id);
+CREATE INDEX idx_tutor_busy_times_tutor_time ON tutor_busy_times(tutor_id, start_time, end_time);
+CREATE INDEX idx_tutor_busy_times_source_id ON tutor_busy_times(source_id);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_jobs_status_type;
+DROP INDEX IF EXISTS idx_jobs_lease;
+DROP INDEX IF EXISTS idx_jobs_due;
+
+DROP TRIGGER IF EXISTS update_jobs_updated_at ON jobs;
+DROP TABLE IF EXISTS jobs CASCADE;
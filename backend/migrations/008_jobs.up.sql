@@ -0,0 +1,30 @@
+-- Table: jobs
+-- Durable background jobs. Workers lease due jobs with FOR UPDATE SKIP LOCKED, so several
+-- backend replicas can poll the same table without running a job twice.
+CREATE TABLE jobs (
+    id BIGSERIAL PRIMARY KEY,
+    job_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'running', 'succeeded', 'failed')),
+    dedup_key VARCHAR(200) UNIQUE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 5 CHECK (max_attempts > 0),
+    run_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    locked_by VARCHAR(100),
+    locked_until TIMESTAMP,
+    last_error TEXT,
+    completed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER update_jobs_updated_at
+    BEFORE UPDATE ON jobs
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE INDEX idx_jobs_due ON jobs(run_at) WHERE status = 'pending';
+CREATE INDEX idx_jobs_lease ON jobs(locked_until) WHERE status = 'running';
+CREATE INDEX idx_jobs_status_type ON jobs(status, job_type);
+
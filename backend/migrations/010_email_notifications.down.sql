@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS notification_preferences CASCADE;
+
+ALTER TABLE users DROP COLUMN IF EXISTS locale;
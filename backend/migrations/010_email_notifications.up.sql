@@ -0,0 +1,16 @@
+-- Language of the emails sent to a user
+ALTER TABLE users ADD COLUMN locale VARCHAR(2) NOT NULL DEFAULT 'en'
+    CHECK (locale IN ('en', 'ru', 'es'));
+
+-- Table: notification_preferences
+-- Channels through which a user wants to be told about an event. Events without a row use
+-- the defaults defined in the backend.
+CREATE TABLE notification_preferences (
+    user_id INTEGER NOT NULL,
+    event VARCHAR(50) NOT NULL,
+    in_app BOOLEAN NOT NULL,
+    email BOOLEAN NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, event),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
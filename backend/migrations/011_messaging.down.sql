@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_conversation_reports_open;
+DROP TABLE IF EXISTS conversation_reports CASCADE;
+
+DROP INDEX IF EXISTS idx_user_blocks_blocked;
+DROP TABLE IF EXISTS user_blocks CASCADE;
+
+DROP INDEX IF EXISTS idx_messages_unread;
+DROP INDEX IF EXISTS idx_messages_conversation;
+DROP TABLE IF EXISTS messages CASCADE;
+
+DROP INDEX IF EXISTS idx_conversations_tutor;
+DROP INDEX IF EXISTS idx_conversations_student;
+DROP TABLE IF EXISTS conversations CASCADE;
@@ -0,0 +1,67 @@
+-- Table: conversations
+-- One-to-one message threads between a student and a tutor, separate from the
+-- ephemeral chat of the lesson rooms
+CREATE TABLE conversations (
+    id SERIAL PRIMARY KEY,
+    student_id INTEGER NOT NULL,
+    tutor_id INTEGER NOT NULL,
+    started_by INTEGER NOT NULL,
+    last_message_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (student_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (tutor_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE (student_id, tutor_id),
+    CHECK (student_id <> tutor_id)
+);
+
+CREATE INDEX idx_conversations_student ON conversations(student_id, last_message_at DESC);
+CREATE INDEX idx_conversations_tutor ON conversations(tutor_id, last_message_at DESC);
+
+-- Table: messages
+-- read_at is the read receipt, set when the recipient opens the conversation.
+-- Attachments are references to files uploaded elsewhere.
+CREATE TABLE messages (
+    id BIGSERIAL PRIMARY KEY,
+    conversation_id INTEGER NOT NULL,
+    sender_id INTEGER NOT NULL,
+    body TEXT NOT NULL,
+    attachments JSONB NOT NULL DEFAULT '[]',
+    read_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE,
+    FOREIGN KEY (sender_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_messages_conversation ON messages(conversation_id, id DESC);
+CREATE INDEX idx_messages_unread ON messages(conversation_id, sender_id) WHERE read_at IS NULL;
+
+-- Table: user_blocks
+-- Users who may not message each other; blocking works in both directions
+CREATE TABLE user_blocks (
+    blocker_id INTEGER NOT NULL,
+    blocked_id INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (blocker_id, blocked_id),
+    FOREIGN KEY (blocker_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (blocked_id) REFERENCES users(id) ON DELETE CASCADE,
+    CHECK (blocker_id <> blocked_id)
+);
+
+CREATE INDEX idx_user_blocks_blocked ON user_blocks(blocked_id);
+
+-- Table: conversation_reports
+-- Reports of abusive conversations or messages, reviewed by admins
+CREATE TABLE conversation_reports (
+    id SERIAL PRIMARY KEY,
+    conversation_id INTEGER NOT NULL,
+    message_id BIGINT,
+    reporter_id INTEGER NOT NULL,
+    reason TEXT NOT NULL,
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE,
+    FOREIGN KEY (message_id) REFERENCES messages(id) ON DELETE SET NULL,
+    FOREIGN KEY (reporter_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_conversation_reports_open ON conversation_reports(created_at) WHERE resolved_at IS NULL;
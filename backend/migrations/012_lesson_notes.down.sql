@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS homework_completions CASCADE;
+
+DROP TRIGGER IF EXISTS update_homework_updated_at ON homework;
+DROP INDEX IF EXISTS idx_homework_lesson;
+DROP TABLE IF EXISTS homework CASCADE;
+
+DROP TABLE IF EXISTS lesson_private_notes CASCADE;
+
+DROP TRIGGER IF EXISTS update_lesson_notes_updated_at ON lesson_notes;
+DROP TABLE IF EXISTS lesson_notes CASCADE;
@@ -0,0 +1,63 @@
+-- Table: lesson_notes
+-- Structured notes a tutor writes after a lesson, shared with its students
+CREATE TABLE lesson_notes (
+    id SERIAL PRIMARY KEY,
+    lesson_id INTEGER NOT NULL UNIQUE,
+    tutor_id INTEGER NOT NULL,
+    summary TEXT NOT NULL DEFAULT '',
+    topics JSONB NOT NULL DEFAULT '[]',
+    vocabulary JSONB NOT NULL DEFAULT '[]',
+    corrections JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (lesson_id) REFERENCES lessons(id) ON DELETE CASCADE,
+    FOREIGN KEY (tutor_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TRIGGER update_lesson_notes_updated_at
+    BEFORE UPDATE ON lesson_notes
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Table: lesson_private_notes
+-- Notes only the tutor can see, kept apart from the shared notes so that
+-- no student-facing query can select them
+CREATE TABLE lesson_private_notes (
+    lesson_id INTEGER PRIMARY KEY,
+    tutor_id INTEGER NOT NULL,
+    body TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (lesson_id) REFERENCES lessons(id) ON DELETE CASCADE,
+    FOREIGN KEY (tutor_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Table: homework
+-- Assignments given in a lesson; every student of the lesson completes them separately
+CREATE TABLE homework (
+    id SERIAL PRIMARY KEY,
+    lesson_id INTEGER NOT NULL,
+    tutor_id INTEGER NOT NULL,
+    description TEXT NOT NULL,
+    due_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (lesson_id) REFERENCES lessons(id) ON DELETE CASCADE,
+    FOREIGN KEY (tutor_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_homework_lesson ON homework(lesson_id);
+
+CREATE TRIGGER update_homework_updated_at
+    BEFORE UPDATE ON homework
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Table: homework_completions
+CREATE TABLE homework_completions (
+    homework_id INTEGER NOT NULL,
+    student_id INTEGER NOT NULL,
+    completed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (homework_id, student_id),
+    FOREIGN KEY (homework_id) REFERENCES homework(id) ON DELETE CASCADE,
+    FOREIGN KEY (student_id) REFERENCES users(id) ON DELETE CASCADE
+);
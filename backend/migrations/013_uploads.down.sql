@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_lesson_materials_upload;
+DROP TABLE IF EXISTS lesson_materials CASCADE;
+
+DROP INDEX IF EXISTS idx_uploads_user;
+DROP TABLE IF EXISTS uploads CASCADE;
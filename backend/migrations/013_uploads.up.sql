@@ -0,0 +1,28 @@
+-- Table: uploads
+-- Files users uploaded. The content lives in the configured storage backend under storage_key.
+CREATE TABLE uploads (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    purpose VARCHAR(20) NOT NULL CHECK (purpose IN ('avatar', 'intro_video', 'lesson_material')),
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL CHECK (size_bytes > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_uploads_user ON uploads(user_id, purpose);
+
+-- Table: lesson_materials
+-- Uploaded files shared with the attendees of a lesson
+CREATE TABLE lesson_materials (
+    lesson_id INTEGER NOT NULL,
+    upload_id INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (lesson_id, upload_id),
+    FOREIGN KEY (lesson_id) REFERENCES lessons(id) ON DELETE CASCADE,
+    FOREIGN KEY (upload_id) REFERENCES uploads(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_lesson_materials_upload ON lesson_materials(upload_id);
@@ -0,0 +1,3 @@
+ALTER TABLE users DROP COLUMN IF EXISTS avatar_urls;
+
+DROP TABLE IF EXISTS upload_variants;
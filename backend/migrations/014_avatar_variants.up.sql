@@ -0,0 +1,17 @@
+-- Table: upload_variants
+-- Processed copies of uploads, such as the square crops made of every avatar. Each width is
+-- stored as a JPEG and a WebP variant, served by what the browser accepts.
+CREATE TABLE upload_variants (
+    upload_id INTEGER NOT NULL,
+    width INTEGER NOT NULL CHECK (width > 0),
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL CHECK (size_bytes > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (upload_id, width, content_type),
+    FOREIGN KEY (upload_id) REFERENCES uploads(id) ON DELETE CASCADE
+);
+
+-- URLs of the avatar variants by width, e.g. {"64": "...", "128": "...", "512": "..."}.
+-- NULL until an uploaded avatar has been processed.
+ALTER TABLE users ADD COLUMN avatar_urls JSONB;
@@ -0,0 +1,16 @@
+DROP INDEX IF EXISTS idx_review_reports_open;
+DROP TABLE IF EXISTS review_reports CASCADE;
+DROP TABLE IF EXISTS review_votes CASCADE;
+
+ALTER TABLE reviews
+    DROP CONSTRAINT IF EXISTS reviews_lesson_reviewer_key,
+    DROP COLUMN IF EXISTS hidden_reason,
+    DROP COLUMN IF EXISTS hidden_by,
+    DROP COLUMN IF EXISTS hidden_at,
+    DROP COLUMN IF EXISTS helpful_count,
+    DROP COLUMN IF EXISTS replied_at,
+    DROP COLUMN IF EXISTS reply,
+    DROP COLUMN IF EXISTS punctuality_rating,
+    DROP COLUMN IF EXISTS preparation_rating,
+    DROP COLUMN IF EXISTS pronunciation_help_rating,
+    DROP COLUMN IF EXISTS comment;
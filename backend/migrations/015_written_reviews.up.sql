@@ -0,0 +1,47 @@
+-- Keep only the first review where a user reviewed the same lesson more than once
+DELETE FROM reviews r
+USING reviews earlier
+WHERE r.lesson_id = earlier.lesson_id
+  AND r.reviewer_id = earlier.reviewer_id
+  AND r.id > earlier.id;
+
+-- Written reviews: text, optional aspect ratings, the tutor's reply and moderation
+ALTER TABLE reviews
+    ADD COLUMN comment TEXT NOT NULL DEFAULT '',
+    ADD COLUMN pronunciation_help_rating INTEGER CHECK (pronunciation_help_rating BETWEEN 1 AND 5),
+    ADD COLUMN preparation_rating INTEGER CHECK (preparation_rating BETWEEN 1 AND 5),
+    ADD COLUMN punctuality_rating INTEGER CHECK (punctuality_rating BETWEEN 1 AND 5),
+    ADD COLUMN reply TEXT,
+    ADD COLUMN replied_at TIMESTAMP,
+    ADD COLUMN helpful_count INTEGER NOT NULL DEFAULT 0 CHECK (helpful_count >= 0),
+    ADD COLUMN hidden_at TIMESTAMP,
+    ADD COLUMN hidden_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN hidden_reason TEXT,
+    ADD CONSTRAINT reviews_lesson_reviewer_key UNIQUE (lesson_id, reviewer_id);
+
+-- Table: review_votes
+-- Users who found a review helpful; reviews.helpful_count counts them
+CREATE TABLE review_votes (
+    review_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (review_id, user_id),
+    FOREIGN KEY (review_id) REFERENCES reviews(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Table: review_reports
+-- Reports of inappropriate reviews, handled by admins in the moderation queue
+CREATE TABLE review_reports (
+    id SERIAL PRIMARY KEY,
+    review_id INTEGER NOT NULL,
+    reporter_id INTEGER NOT NULL,
+    reason TEXT NOT NULL,
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (review_id) REFERENCES reviews(id) ON DELETE CASCADE,
+    FOREIGN KEY (reporter_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- A user has at most one open report per review
+CREATE UNIQUE INDEX idx_review_reports_open ON review_reports(review_id, reporter_id) WHERE resolved_at IS NULL;
@@ -0,0 +1,9 @@
+-- Feedback goes back to being the tutor's review of the lesson; of a group lesson's
+-- feedback only the earliest is kept
+INSERT INTO reviews (lesson_id, reviewer_id, rating, comment, created_at)
+SELECT DISTINCT ON (lesson_id) lesson_id, tutor_id, rating, comment, created_at
+FROM student_feedback
+ORDER BY lesson_id, created_at, id
+ON CONFLICT (lesson_id, reviewer_id) DO NOTHING;
+
+DROP TABLE IF EXISTS student_feedback;
@@ -0,0 +1,38 @@
+-- Table: student_feedback
+-- Private progress feedback a tutor gives a student after a lesson, seen only by the two of
+-- them. Reviews are now only written by students about their tutors.
+CREATE TABLE student_feedback (
+    id SERIAL PRIMARY KEY,
+    lesson_id INTEGER NOT NULL,
+    tutor_id INTEGER NOT NULL,
+    student_id INTEGER NOT NULL,
+    rating INTEGER NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    comment TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (lesson_id, student_id),
+    FOREIGN KEY (lesson_id) REFERENCES lessons(id) ON DELETE CASCADE,
+    FOREIGN KEY (tutor_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (student_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_student_feedback_student ON student_feedback(student_id);
+
+CREATE TRIGGER update_student_feedback_updated_at
+    BEFORE UPDATE ON student_feedback
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Reviews tutors wrote about their own lessons become feedback for the student of the
+-- lesson, or for every student who kept their seat in a group lesson
+INSERT INTO student_feedback (lesson_id, tutor_id, student_id, rating, comment, created_at, updated_at)
+SELECT r.lesson_id, l.tutor_id, COALESCE(l.student_id, p.student_id), r.rating, r.comment, r.created_at, r.created_at
+FROM reviews r
+JOIN lessons l ON r.lesson_id = l.id
+LEFT JOIN lesson_participants p ON l.student_id IS NULL AND p.lesson_id = l.id AND p.cancelled_at IS NULL
+WHERE r.reviewer_id = l.tutor_id AND COALESCE(l.student_id, p.student_id) IS NOT NULL
+ON CONFLICT (lesson_id, student_id) DO NOTHING;
+
+DELETE FROM reviews r
+USING lessons l
+WHERE r.lesson_id = l.id AND r.reviewer_id = l.tutor_id;
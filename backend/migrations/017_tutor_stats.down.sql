@@ -0,0 +1 @@
+DROP TABLE IF EXISTS tutor_stats;
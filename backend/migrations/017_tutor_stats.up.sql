@@ -0,0 +1,43 @@
+-- Table: tutor_stats
+-- Rating aggregates of each tutor's visible reviews, kept up to date when reviews are
+-- written or moderated so that search and profiles don't aggregate reviews on every request.
+-- bayesian_score pulls the average towards the mean of all reviews by 10 reviews' worth of
+-- weight, so a single 5-star review does not outrank hundreds of 4.9s; recent_score does the
+-- same with every review's weight halving every 180 days.
+CREATE TABLE tutor_stats (
+    tutor_id INTEGER PRIMARY KEY,
+    reviews_count INTEGER NOT NULL DEFAULT 0,
+    average_rating DOUBLE PRECISION NOT NULL DEFAULT 0,
+    bayesian_score DOUBLE PRECISION NOT NULL DEFAULT 0,
+    recent_score DOUBLE PRECISION NOT NULL DEFAULT 0,
+    last_review_at TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (tutor_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_tutor_stats_recent_score ON tutor_stats(recent_score DESC);
+
+-- Same computation as TutorRepository.RefreshStats
+WITH prior AS (
+    SELECT COALESCE(AVG(rating)::float8, 4.5) AS mean
+    FROM reviews
+    WHERE hidden_at IS NULL
+),
+visible AS (
+    SELECT l.tutor_id, r.rating, r.created_at,
+           POWER(0.5, EXTRACT(EPOCH FROM NOW() - r.created_at)::float8 / 86400 / 180) AS weight
+    FROM reviews r
+    JOIN lessons l ON l.id = r.lesson_id
+    WHERE r.hidden_at IS NULL
+)
+INSERT INTO tutor_stats (tutor_id, reviews_count, average_rating, bayesian_score, recent_score, last_review_at)
+SELECT tp.user_id,
+       COUNT(v.rating),
+       COALESCE(AVG(v.rating)::float8, 0),
+       (p.mean * 10 + COALESCE(SUM(v.rating), 0)) / (10 + COUNT(v.rating)),
+       (p.mean * 10 + COALESCE(SUM(v.rating * v.weight), 0)) / (10 + COALESCE(SUM(v.weight), 0)),
+       MAX(v.created_at)
+FROM tutor_profiles tp
+CROSS JOIN prior p
+LEFT JOIN visible v ON v.tutor_id = tp.user_id
+GROUP BY tp.user_id, p.mean;
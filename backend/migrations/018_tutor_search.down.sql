@@ -0,0 +1,12 @@
+DROP TRIGGER IF EXISTS update_users_tutor_search_names ON users;
+DROP TRIGGER IF EXISTS update_tutor_profiles_search_columns ON tutor_profiles;
+DROP FUNCTION IF EXISTS update_tutor_search_names();
+DROP FUNCTION IF EXISTS update_tutor_search_columns();
+DROP FUNCTION IF EXISTS tutor_search_text(TEXT, TEXT, TEXT, JSONB);
+DROP FUNCTION IF EXISTS tutor_search_vector(TEXT, TEXT, TEXT, JSONB);
+DROP FUNCTION IF EXISTS education_search_text(JSONB);
+
+DROP INDEX IF EXISTS idx_tutor_profiles_search_text;
+DROP INDEX IF EXISTS idx_tutor_profiles_search_vector;
+ALTER TABLE tutor_profiles DROP COLUMN IF EXISTS search_text;
+ALTER TABLE tutor_profiles DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,80 @@
+-- Full-text search over tutors' names, bios and education. Bios and education are indexed
+-- with both the Russian and the English configuration since tutors write in either; names
+-- are indexed as they are. search_text keeps the same text for trigram matching, which
+-- still finds tutors when the search has a typo.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE tutor_profiles ADD COLUMN search_vector TSVECTOR NOT NULL DEFAULT ''::tsvector;
+ALTER TABLE tutor_profiles ADD COLUMN search_text TEXT NOT NULL DEFAULT '';
+
+-- Every string in a tutor's education JSON, whatever shape it was saved in
+CREATE OR REPLACE FUNCTION education_search_text(education JSONB)
+RETURNS TEXT AS $$
+    SELECT COALESCE(string_agg(value #>> '{}', ' '), '')
+    FROM jsonb_path_query(COALESCE(education, 'null'::jsonb), 'strict $.**') AS value
+    WHERE jsonb_typeof(value) = 'string'
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION tutor_search_vector(first_name TEXT, last_name TEXT, bio TEXT, education JSONB)
+RETURNS TSVECTOR AS $$
+    SELECT setweight(to_tsvector('simple', concat_ws(' ', first_name, last_name)), 'A') ||
+           setweight(to_tsvector('russian', COALESCE(bio, '')), 'B') ||
+           setweight(to_tsvector('english', COALESCE(bio, '')), 'B') ||
+           setweight(to_tsvector('russian', education_search_text(education)), 'C') ||
+           setweight(to_tsvector('english', education_search_text(education)), 'C')
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION tutor_search_text(first_name TEXT, last_name TEXT, bio TEXT, education JSONB)
+RETURNS TEXT AS $$
+    SELECT concat_ws(' ', first_name, last_name, bio, education_search_text(education))
+$$ LANGUAGE sql IMMUTABLE;
+
+-- Keeps the search columns up to date when a tutor edits their profile
+CREATE OR REPLACE FUNCTION update_tutor_search_columns()
+RETURNS TRIGGER AS $$
+DECLARE
+    tutor users%ROWTYPE;
+BEGIN
+    SELECT * INTO tutor FROM users WHERE id = NEW.user_id;
+    NEW.search_vector = tutor_search_vector(tutor.first_name, tutor.last_name, NEW.bio, NEW.education);
+    NEW.search_text = tutor_search_text(tutor.first_name, tutor.last_name, NEW.bio, NEW.education);
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER update_tutor_profiles_search_columns
+    BEFORE INSERT OR UPDATE OF bio, education ON tutor_profiles
+    FOR EACH ROW
+    EXECUTE FUNCTION update_tutor_search_columns();
+
+-- ...and when they change their name
+CREATE OR REPLACE FUNCTION update_tutor_search_names()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE tutor_profiles
+    SET search_vector = tutor_search_vector(NEW.first_name, NEW.last_name, bio, education),
+        search_text = tutor_search_text(NEW.first_name, NEW.last_name, bio, education)
+    WHERE user_id = NEW.id;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER update_users_tutor_search_names
+    AFTER UPDATE OF first_name, last_name ON users
+    FOR EACH ROW
+    WHEN (OLD.first_name IS DISTINCT FROM NEW.first_name OR OLD.last_name IS DISTINCT FROM NEW.last_name)
+    EXECUTE FUNCTION update_tutor_search_names();
+
+-- Fill the search columns of the existing profiles without touching their updated_at
+ALTER TABLE tutor_profiles DISABLE TRIGGER update_tutor_profiles_updated_at;
+
+UPDATE tutor_profiles tp
+SET search_vector = tutor_search_vector(u.first_name, u.last_name, tp.bio, tp.education),
+    search_text = tutor_search_text(u.first_name, u.last_name, tp.bio, tp.education)
+FROM users u
+WHERE u.id = tp.user_id;
+
+ALTER TABLE tutor_profiles ENABLE TRIGGER update_tutor_profiles_updated_at;
+
+CREATE INDEX idx_tutor_profiles_search_vector ON tutor_profiles USING GIN (search_vector);
+CREATE INDEX idx_tutor_profiles_search_text ON tutor_profiles USING GIN (search_text gin_trgm_ops);
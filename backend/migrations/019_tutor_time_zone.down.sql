@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_lessons_tutor_start_time;
+ALTER TABLE tutor_profiles DROP COLUMN IF EXISTS timezone;
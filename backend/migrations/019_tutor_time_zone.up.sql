@@ -0,0 +1,6 @@
+-- Time zone of a tutor's availability times, which are wall-clock times, so that tutor
+-- search can match them against the hours a student asks for in their own time zone
+ALTER TABLE tutor_profiles ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+-- Searching for an open slot looks up a tutor's upcoming lessons
+CREATE INDEX idx_lessons_tutor_start_time ON lessons(tutor_id, start_time) WHERE cancelled_at IS NULL;
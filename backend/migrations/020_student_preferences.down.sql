@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS student_preferred_times;
+ALTER TABLE student_profiles DROP COLUMN IF EXISTS max_price;
+ALTER TABLE student_profiles DROP COLUMN IF EXISTS min_price;
+ALTER TABLE student_profiles DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,19 @@
+-- What a student would like from their lessons, used to recommend tutors. The preferred
+-- times are wall-clock times in the student's time zone.
+ALTER TABLE student_profiles ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+ALTER TABLE student_profiles ADD COLUMN min_price NUMERIC(10, 2) CHECK (min_price >= 0);
+ALTER TABLE student_profiles ADD COLUMN max_price NUMERIC(10, 2) CHECK (max_price >= 0);
+
+-- Weekly times a student would like to have lessons at. An end before the start means the
+-- time runs past midnight.
+CREATE TABLE student_preferred_times (
+    id SERIAL PRIMARY KEY,
+    student_id INTEGER NOT NULL,
+    day_of_week INTEGER NOT NULL CHECK (day_of_week BETWEEN 0 AND 6),
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL CHECK (end_time <> start_time),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (student_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_student_preferred_times_student_id ON student_preferred_times(student_id);
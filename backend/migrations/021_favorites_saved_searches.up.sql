@@ -0,0 +1,43 @@
+-- Tutors a student has shortlisted. availability_checked_at is when the student was last
+-- told about the tutor's availability, so that only slots added or changed later alert them.
+CREATE TABLE favorite_tutors (
+    student_id INTEGER NOT NULL,
+    tutor_id INTEGER NOT NULL,
+    availability_checked_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (student_id, tutor_id),
+    FOREIGN KEY (student_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (tutor_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_favorite_tutors_tutor_id ON favorite_tutors(tutor_id);
+
+-- Tutor search filters a student saved to run again and to be alerted about new matches
+CREATE TABLE saved_searches (
+    id SERIAL PRIMARY KEY,
+    student_id INTEGER NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    filters JSONB NOT NULL DEFAULT '{}',
+    notify BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (student_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_saved_searches_student_id ON saved_searches(student_id);
+
+CREATE TRIGGER update_saved_searches_updated_at
+    BEFORE UPDATE ON saved_searches
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Tutors already known to match a saved search. The tutors matching when the search is saved
+-- are recorded right away, so only tutors matching later are announced.
+CREATE TABLE saved_search_matches (
+    saved_search_id INTEGER NOT NULL,
+    tutor_id INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (saved_search_id, tutor_id),
+    FOREIGN KEY (saved_search_id) REFERENCES saved_searches(id) ON DELETE CASCADE,
+    FOREIGN KEY (tutor_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS tutor_credentials;
+DELETE FROM uploads WHERE purpose = 'credential';
+ALTER TABLE uploads DROP CONSTRAINT uploads_purpose_check;
+ALTER TABLE uploads ADD CONSTRAINT uploads_purpose_check
+    CHECK (purpose IN ('avatar', 'intro_video', 'lesson_material'));
@@ -0,0 +1,37 @@
+-- Proof documents of tutor credentials are private uploads
+ALTER TABLE uploads DROP CONSTRAINT uploads_purpose_check;
+ALTER TABLE uploads ADD CONSTRAINT uploads_purpose_check
+    CHECK (purpose IN ('avatar', 'intro_video', 'lesson_material', 'credential'));
+
+-- Table: tutor_credentials
+-- Degrees and certificates (such as DELE or CELTA) a tutor claims, each with an uploaded proof
+-- document that an admin reviews. Deleting the document deletes the credential. A tutor with
+-- a verified credential is shown as verified.
+CREATE TABLE tutor_credentials (
+    id SERIAL PRIMARY KEY,
+    tutor_id INTEGER NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('education', 'certificate')),
+    title VARCHAR(200) NOT NULL,
+    issuer VARCHAR(200) NOT NULL,
+    field_of_study VARCHAR(200) NOT NULL DEFAULT '',
+    year INTEGER,
+    document_id INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'verified', 'rejected')),
+    rejection_reason TEXT,
+    reviewed_by INTEGER,
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (tutor_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (document_id) REFERENCES uploads(id) ON DELETE CASCADE,
+    FOREIGN KEY (reviewed_by) REFERENCES users(id) ON DELETE SET NULL,
+    CHECK (status <> 'rejected' OR rejection_reason IS NOT NULL)
+);
+
+CREATE INDEX idx_tutor_credentials_tutor ON tutor_credentials(tutor_id, status);
+CREATE INDEX idx_tutor_credentials_status ON tutor_credentials(status, created_at);
+
+CREATE TRIGGER update_tutor_credentials_updated_at
+    BEFORE UPDATE ON tutor_credentials
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,18 @@
+ALTER TABLE tutor_profiles
+    DROP CONSTRAINT IF EXISTS tutor_profiles_education_check,
+    ALTER COLUMN education DROP NOT NULL,
+    ALTER COLUMN education DROP DEFAULT;
+
+ALTER TABLE tutor_profiles DISABLE TRIGGER update_tutor_profiles_updated_at;
+
+-- Years go back to the strings the old clients sent; dropped entries cannot be restored
+UPDATE tutor_profiles
+SET education = COALESCE((
+    SELECT jsonb_agg(entry || jsonb_build_object(
+               'start_year', COALESCE(entry->>'start_year', ''),
+               'end_year', COALESCE(entry->>'end_year', '')
+           ) ORDER BY position)
+    FROM jsonb_array_elements(education) WITH ORDINALITY AS elements(entry, position)
+), '[]'::jsonb);
+
+ALTER TABLE tutor_profiles ENABLE TRIGGER update_tutor_profiles_updated_at;
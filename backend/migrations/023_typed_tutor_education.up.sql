@@ -0,0 +1,50 @@
+-- Tutors' education used to be saved in whatever shape clients sent. It is now an array of
+-- entries with a degree, an institution, a field of study and optional integer years.
+-- Existing rows are normalized to that shape: entries without a degree or an institution
+-- are dropped, years that cannot be read or are not plausible are cleared, and only the
+-- first 20 entries are kept.
+CREATE FUNCTION pg_temp.education_year(value JSONB, max_year INT)
+RETURNS INT AS $$
+    SELECT CASE WHEN year BETWEEN 1950 AND max_year THEN year END
+    FROM (SELECT substring(value #>> '{}' FROM '\d{4}')::INT AS year) AS parsed
+$$ LANGUAGE sql;
+
+-- Normalizing is not an edit by the tutor, so updated_at is left alone
+ALTER TABLE tutor_profiles DISABLE TRIGGER update_tutor_profiles_updated_at;
+
+UPDATE tutor_profiles tp
+SET education = COALESCE((
+    SELECT jsonb_agg(jsonb_strip_nulls(jsonb_build_object(
+               'degree', entry.degree,
+               'institution', entry.institution,
+               'field_of_study', entry.field_of_study,
+               'start_year', entry.start_year,
+               'end_year', CASE WHEN entry.end_year >= COALESCE(entry.start_year, entry.end_year) THEN entry.end_year END
+           )) ORDER BY entry.position)
+    FROM (
+        SELECT *, row_number() OVER (ORDER BY position) AS kept
+        FROM (
+            SELECT position,
+                   left(btrim(COALESCE(element->>'degree', '')), 200) AS degree,
+                   left(btrim(COALESCE(element->>'institution', '')), 200) AS institution,
+                   left(btrim(COALESCE(element->>'field_of_study', '')), 200) AS field_of_study,
+                   pg_temp.education_year(element->'start_year', EXTRACT(YEAR FROM NOW())::INT) AS start_year,
+                   pg_temp.education_year(element->'end_year', EXTRACT(YEAR FROM NOW())::INT + 10) AS end_year
+            FROM jsonb_array_elements(CASE jsonb_typeof(tp.education)
+                     WHEN 'array' THEN tp.education
+                     WHEN 'object' THEN jsonb_build_array(tp.education)
+                     ELSE '[]'::jsonb
+                 END) WITH ORDINALITY AS elements(element, position)
+            WHERE jsonb_typeof(element) = 'object'
+        ) AS parsed
+        WHERE parsed.degree <> '' AND parsed.institution <> ''
+    ) AS entry
+    WHERE entry.kept <= 20
+), '[]'::jsonb);
+
+ALTER TABLE tutor_profiles ENABLE TRIGGER update_tutor_profiles_updated_at;
+
+ALTER TABLE tutor_profiles
+    ALTER COLUMN education SET DEFAULT '[]'::jsonb,
+    ALTER COLUMN education SET NOT NULL,
+    ADD CONSTRAINT tutor_profiles_education_check CHECK (jsonb_typeof(education) = 'array');
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_lessons_tutor_created_at;
+DROP TABLE IF EXISTS tutor_event_counts;
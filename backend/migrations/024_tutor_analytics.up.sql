@@ -0,0 +1,15 @@
+-- Table: tutor_event_counts
+-- Daily counts of how often students saw a tutor, for tutor analytics: profile views and
+-- appearances in search results. Events are counted as they happen rather than stored one
+-- by one, so the table stays small and sums over any period cheaply.
+CREATE TABLE tutor_event_counts (
+    tutor_id INTEGER NOT NULL,
+    event_type VARCHAR(30) NOT NULL CHECK (event_type IN ('profile_view', 'search_impression')),
+    day DATE NOT NULL,
+    count INTEGER NOT NULL DEFAULT 0 CHECK (count >= 0),
+    PRIMARY KEY (tutor_id, event_type, day),
+    FOREIGN KEY (tutor_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Bookings per period are counted by when lessons were created
+CREATE INDEX idx_lessons_tutor_created_at ON lessons(tutor_id, created_at);
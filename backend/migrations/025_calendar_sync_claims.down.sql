@@ -0,0 +1 @@
+ALTER TABLE tutor_calendar_sources DROP COLUMN IF EXISTS sync_claimed_until;
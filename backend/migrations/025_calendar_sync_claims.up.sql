@@ -0,0 +1,3 @@
+-- Every server replica runs the calendar sync, so a calendar is claimed until the given time
+-- before it is fetched, and the other replicas skip it meanwhile
+ALTER TABLE tutor_calendar_sources ADD COLUMN sync_claimed_until TIMESTAMP;
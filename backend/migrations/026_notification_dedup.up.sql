@@ -0,0 +1,3 @@
+-- Notifications sent by jobs carry a key derived from the job, so that a retried job does
+-- not store the same notification twice. NULL for notifications that are not deduplicated.
+ALTER TABLE notifications ADD COLUMN dedup_key VARCHAR(200) UNIQUE;
@@ -0,0 +1,155 @@
+// Package ical reads and writes iCalendar (RFC 5545) files with VEVENT components.
+//
+// Only the subset needed to publish lessons and to import busy times is supported. Written events
+// have UTC start and end times, a status and a sequence number; text values are escaped and long
+// lines are folded at 75 octets. Parsed events may recur with the common RRULE parts.
+package ical
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	dateTimeFormat = "20060102T150405Z"
+	maxLineOctets  = 75
+)
+
+// Status is the status of an event
+type Status string
+
+const (
+	StatusConfirmed Status = "CONFIRMED"
+	StatusTentative Status = "TENTATIVE"
+	StatusCancelled Status = "CANCELLED"
+)
+
+// Event is a VEVENT component
+type Event struct {
+	// UID must stay the same for the lifetime of the event so that calendar clients
+	// replace the existing entry instead of adding a new one
+	UID string
+	// Sequence must increase every time the event is modified
+	Sequence     int
+	Start        time.Time
+	End          time.Time
+	Summary      string
+	Description  string
+	Location     string
+	URL          string
+	Status       Status
+	Created      time.Time
+	LastModified time.Time
+
+	// Only set by Parse
+	AllDay         bool
+	Transparent    bool // The event does not block time
+	RecurrenceRule string
+	ExceptionDates []time.Time
+	RecurrenceID   *time.Time // Set on overrides of a single occurrence of a recurring event
+	duration       time.Duration
+}
+
+// Calendar is a VCALENDAR object
+type Calendar struct {
+	ProductID string
+	Name      string
+	// RefreshInterval is the suggested polling interval for subscriptions, zero to omit it
+	RefreshInterval time.Duration
+	Events          []Event
+}
+
+// Encode writes the calendar in iCalendar format
+func (c *Calendar) Encode() []byte {
+	var buf bytes.Buffer
+	stamp := time.Now()
+
+	writeLine(&buf, "BEGIN:VCALENDAR")
+	writeLine(&buf, "VERSION:2.0")
+	writeLine(&buf, "PRODID:"+c.ProductID)
+	writeLine(&buf, "CALSCALE:GREGORIAN")
+	writeLine(&buf, "METHOD:PUBLISH")
+	if c.Name != "" {
+		writeLine(&buf, "X-WR-CALNAME:"+escapeText(c.Name))
+	}
+	if c.RefreshInterval > 0 {
+		minutes := strconv.Itoa(int(c.RefreshInterval.Minutes()))
+		writeLine(&buf, "REFRESH-INTERVAL;VALUE=DURATION:PT"+minutes+"M")
+		writeLine(&buf, "X-PUBLISHED-TTL:PT"+minutes+"M")
+	}
+
+	for i := range c.Events {
+		c.Events[i].encode(&buf, stamp)
+	}
+
+	writeLine(&buf, "END:VCALENDAR")
+	return buf.Bytes()
+}
+
+func (e *Event) encode(buf *bytes.Buffer, stamp time.Time) {
+	writeLine(buf, "BEGIN:VEVENT")
+	writeLine(buf, "UID:"+e.UID)
+	writeLine(buf, "DTSTAMP:"+formatTime(stamp))
+	writeLine(buf, "SEQUENCE:"+strconv.Itoa(e.Sequence))
+	writeLine(buf, "DTSTART:"+formatTime(e.Start))
+	writeLine(buf, "DTEND:"+formatTime(e.End))
+	writeLine(buf, "SUMMARY:"+escapeText(e.Summary))
+	if e.Description != "" {
+		writeLine(buf, "DESCRIPTION:"+escapeText(e.Description))
+	}
+	if e.Location != "" {
+		writeLine(buf, "LOCATION:"+escapeText(e.Location))
+	}
+	if e.URL != "" {
+		writeLine(buf, "URL:"+e.URL)
+	}
+	if e.Status != "" {
+		writeLine(buf, "STATUS:"+string(e.Status))
+	}
+	if !e.Created.IsZero() {
+		writeLine(buf, "CREATED:"+formatTime(e.Created))
+	}
+	if !e.LastModified.IsZero() {
+		writeLine(buf, "LAST-MODIFIED:"+formatTime(e.LastModified))
+	}
+	writeLine(buf, "END:VEVENT")
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(dateTimeFormat)
+}
+
+// escapeText escapes a TEXT value as described in RFC 5545 section 3.3.11
+func escapeText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\n", `\n`,
+		"\r", `\n`,
+	)
+	return replacer.Replace(s)
+}
+
+// writeLine writes a content line terminated by CRLF, folding it so that no line
+// is longer than 75 octets. Lines are never split inside a UTF-8 sequence.
+func writeLine(buf *bytes.Buffer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		// Step back to the start of a UTF-8 sequence
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space that counts towards the limit
+		limit = maxLineOctets - 1
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
@@ -0,0 +1,353 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	// Calendars reference IANA time zones by name, the tzdata may be missing in containers
+	_ "time/tzdata"
+)
+
+const (
+	dateFormat          = "20060102"
+	localDateTimeFormat = "20060102T150405"
+	maxLineLength       = 1 << 20
+)
+
+var (
+	ErrInvalidCalendar = errors.New("invalid iCalendar data")
+)
+
+// windowsZones maps the Windows time zone names used by Outlook and Exchange to IANA names
+var windowsZones = map[string]string{
+	"UTC":                            "UTC",
+	"GMT Standard Time":              "Europe/London",
+	"W. Europe Standard Time":        "Europe/Berlin",
+	"Romance Standard Time":          "Europe/Paris",
+	"Central Europe Standard Time":   "Europe/Budapest",
+	"Central European Standard Time": "Europe/Warsaw",
+	"E. Europe Standard Time":        "Europe/Chisinau",
+	"FLE Standard Time":              "Europe/Kiev",
+	"GTB Standard Time":              "Europe/Bucharest",
+	"Russian Standard Time":          "Europe/Moscow",
+	"Kaliningrad Standard Time":      "Europe/Kaliningrad",
+	"Ekaterinburg Standard Time":     "Asia/Yekaterinburg",
+	"N. Central Asia Standard Time":  "Asia/Novosibirsk",
+	"North Asia Standard Time":       "Asia/Krasnoyarsk",
+	"Vladivostok Standard Time":      "Asia/Vladivostok",
+	"Turkey Standard Time":           "Europe/Istanbul",
+	"Eastern Standard Time":          "America/New_York",
+	"Central Standard Time":          "America/Chicago",
+	"Mountain Standard Time":         "America/Denver",
+	"Pacific Standard Time":          "America/Los_Angeles",
+	"Central Standard Time (Mexico)": "America/Mexico_City",
+	"SA Pacific Standard Time":       "America/Bogota",
+	"Argentina Standard Time":        "America/Buenos_Aires",
+	"E. South America Standard Time": "America/Sao_Paulo",
+	"India Standard Time":            "Asia/Kolkata",
+	"China Standard Time":            "Asia/Shanghai",
+	"Tokyo Standard Time":            "Asia/Tokyo",
+	"AUS Eastern Standard Time":      "Australia/Sydney",
+}
+
+// property is a parsed content line
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Parse reads the VEVENT components of an iCalendar stream. Recurring events are returned
+// once with their recurrence rule; use BusyPeriods to expand them.
+func Parse(r io.Reader) ([]Event, error) {
+	lines, err := unfoldLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		events     []Event
+		current    *Event
+		depth      int // Nesting below the current VEVENT, e.g. VALARM
+		inCalendar bool
+		defaultLoc = time.UTC
+	)
+
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+
+		prop, err := parseProperty(line)
+		if err != nil {
+			return nil, err
+		}
+
+		switch prop.name {
+		case "BEGIN":
+			value := strings.ToUpper(prop.value)
+			switch {
+			case value == "VCALENDAR":
+				inCalendar = true
+			case value == "VEVENT" && current == nil:
+				current = &Event{}
+			case current != nil:
+				depth++
+			}
+			continue
+		case "END":
+			value := strings.ToUpper(prop.value)
+			switch {
+			case current != nil && depth > 0:
+				depth--
+			case current != nil && value == "VEVENT":
+				if err := current.finish(); err != nil {
+					return nil, err
+				}
+				events = append(events, *current)
+				current = nil
+			case value == "VCALENDAR":
+				inCalendar = false
+			}
+			continue
+		}
+
+		if !inCalendar {
+			return nil, fmt.Errorf("%w: content outside of VCALENDAR", ErrInvalidCalendar)
+		}
+
+		if current == nil {
+			// Calendar-wide default time zone used by Google Calendar exports
+			if prop.name == "X-WR-TIMEZONE" {
+				if loc, err := loadLocation(prop.value); err == nil {
+					defaultLoc = loc
+				}
+			}
+			continue
+		}
+		if depth > 0 {
+			continue
+		}
+
+		if err := current.setProperty(prop, defaultLoc); err != nil {
+			return nil, err
+		}
+	}
+
+	if current != nil {
+		return nil, fmt.Errorf("%w: unterminated VEVENT", ErrInvalidCalendar)
+	}
+
+	return events, nil
+}
+
+// setProperty applies a content line of a VEVENT to the event
+func (e *Event) setProperty(prop property, defaultLoc *time.Location) error {
+	var err error
+
+	switch prop.name {
+	case "UID":
+		e.UID = prop.value
+	case "SUMMARY":
+		e.Summary = unescapeText(prop.value)
+	case "DESCRIPTION":
+		e.Description = unescapeText(prop.value)
+	case "LOCATION":
+		e.Location = unescapeText(prop.value)
+	case "STATUS":
+		e.Status = Status(strings.ToUpper(prop.value))
+	case "TRANSP":
+		e.Transparent = strings.EqualFold(prop.value, "TRANSPARENT")
+	case "SEQUENCE":
+		fmt.Sscanf(prop.value, "%d", &e.Sequence)
+	case "DTSTART":
+		e.Start, e.AllDay, err = parseDateTime(prop, defaultLoc)
+	case "DTEND":
+		e.End, _, err = parseDateTime(prop, defaultLoc)
+	case "DURATION":
+		e.duration, err = parseDuration(prop.value)
+	case "RRULE":
+		e.RecurrenceRule = prop.value
+	case "EXDATE":
+		for _, value := range strings.Split(prop.value, ",") {
+			var exdate time.Time
+			exdate, _, err = parseDateTime(property{name: prop.name, params: prop.params, value: value}, defaultLoc)
+			if err != nil {
+				break
+			}
+			e.ExceptionDates = append(e.ExceptionDates, exdate)
+		}
+	case "RECURRENCE-ID":
+		var recurrenceID time.Time
+		recurrenceID, _, err = parseDateTime(prop, defaultLoc)
+		e.RecurrenceID = &recurrenceID
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidCalendar, prop.name, err)
+	}
+	return nil
+}
+
+// finish checks a parsed VEVENT and derives its end time if it is missing
+func (e *Event) finish() error {
+	if e.Start.IsZero() {
+		return fmt.Errorf("%w: VEVENT without DTSTART", ErrInvalidCalendar)
+	}
+
+	if e.End.IsZero() {
+		switch {
+		case e.duration > 0:
+			e.End = e.Start.Add(e.duration)
+		case e.AllDay:
+			e.End = e.Start.AddDate(0, 0, 1)
+		default:
+			e.End = e.Start
+		}
+	}
+
+	if e.End.Before(e.Start) {
+		return fmt.Errorf("%w: VEVENT ends before it starts", ErrInvalidCalendar)
+	}
+	return nil
+}
+
+// unfoldLines splits the stream into content lines, joining folded continuation lines
+func unfoldLines(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineLength)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCalendar, err)
+	}
+	return lines, nil
+}
+
+// parseProperty splits a content line into its name, parameters and value
+func parseProperty(line string) (property, error) {
+	prop := property{params: map[string]string{}}
+
+	// Find the colon that separates the value, skipping quoted parameter values
+	inQuotes := false
+	colon := -1
+	for i := 0; i < len(line) && colon < 0; i++ {
+		switch line[i] {
+		case '"':
+			inQuotes = !inQuotes
+		case ':':
+			if !inQuotes {
+				colon = i
+			}
+		}
+	}
+	if colon < 0 {
+		return prop, fmt.Errorf("%w: malformed line %q", ErrInvalidCalendar, truncate(line, 40))
+	}
+
+	prop.value = line[colon+1:]
+	parts := strings.Split(line[:colon], ";")
+	prop.name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		prop.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+
+	return prop, nil
+}
+
+// parseDateTime parses a DATE or DATE-TIME value, reporting whether it is a date
+func parseDateTime(prop property, defaultLoc *time.Location) (time.Time, bool, error) {
+	value := strings.TrimSpace(prop.value)
+
+	if prop.params["VALUE"] == "DATE" || len(value) == len(dateFormat) {
+		t, err := time.ParseInLocation(dateFormat, value, defaultLoc)
+		return t, true, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(dateTimeFormat, value)
+		return t, false, err
+	}
+
+	loc := defaultLoc
+	if tzid := prop.params["TZID"]; tzid != "" {
+		if l, err := loadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+
+	t, err := time.ParseInLocation(localDateTimeFormat, value, loc)
+	return t, false, err
+}
+
+// loadLocation resolves an IANA or Windows time zone name
+func loadLocation(name string) (*time.Location, error) {
+	name = strings.TrimPrefix(name, "/")
+	if iana, ok := windowsZones[name]; ok {
+		name = iana
+	}
+	return time.LoadLocation(name)
+}
+
+// parseDuration parses a DURATION value such as PT1H30M or P1D
+func parseDuration(value string) (time.Duration, error) {
+	value = strings.TrimPrefix(value, "+")
+	if !strings.HasPrefix(value, "P") || strings.HasPrefix(value, "-") {
+		return 0, fmt.Errorf("unsupported duration %q", value)
+	}
+
+	var d time.Duration
+	inTime := false
+	number := 0
+	for _, r := range value[1:] {
+		switch {
+		case r >= '0' && r <= '9':
+			number = number*10 + int(r-'0')
+			continue
+		case r == 'T':
+			inTime = true
+		case r == 'W':
+			d += time.Duration(number) * 7 * 24 * time.Hour
+		case r == 'D':
+			d += time.Duration(number) * 24 * time.Hour
+		case r == 'H' && inTime:
+			d += time.Duration(number) * time.Hour
+		case r == 'M' && inTime:
+			d += time.Duration(number) * time.Minute
+		case r == 'S' && inTime:
+			d += time.Duration(number) * time.Second
+		default:
+			return 0, fmt.Errorf("unsupported duration %q", value)
+		}
+		number = 0
+	}
+
+	return d, nil
+}
+
+// unescapeText reverses escapeText
+func unescapeText(s string) string {
+	replacer := strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+	return replacer.Replace(s)
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
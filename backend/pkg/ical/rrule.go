@@ -0,0 +1,418 @@
+package ical
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maxRecurrencePeriods bounds the expansion of a single rule, e.g. a daily rule expands over ~135 years
+	maxRecurrencePeriods = 50000
+)
+
+// Period is a time interval in which the calendar owner is busy
+type Period struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// weekdayNum is a BYDAY value such as MO, 2TU or -1FR
+type weekdayNum struct {
+	n   int
+	day time.Weekday
+}
+
+// recurrenceRule is a parsed RRULE
+type recurrenceRule struct {
+	freq       string
+	interval   int
+	count      int
+	until      *time.Time
+	byDay      []weekdayNum
+	byMonthDay []int
+	byMonth    []int
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// BusyPeriods expands the events into the periods that overlap [from, to), merging overlapping periods.
+// Cancelled and transparent (free) events are ignored, as are occurrences removed with EXDATE or
+// replaced by a RECURRENCE-ID override. Recurring events with an unsupported rule only block
+// their first occurrence.
+func BusyPeriods(events []Event, from, to time.Time) []Period {
+	// Occurrences moved or cancelled individually, by UID
+	overridden := map[string]map[int64]bool{}
+	for _, e := range events {
+		if e.RecurrenceID != nil {
+			if overridden[e.UID] == nil {
+				overridden[e.UID] = map[int64]bool{}
+			}
+			overridden[e.UID][e.RecurrenceID.Unix()] = true
+		}
+	}
+
+	var periods []Period
+	add := func(start, end time.Time) {
+		if start.Before(to) && end.After(from) && end.After(start) {
+			periods = append(periods, Period{Start: start.UTC(), End: end.UTC()})
+		}
+	}
+
+	for _, e := range events {
+		if e.Status == StatusCancelled || e.Transparent {
+			continue
+		}
+
+		rule, err := parseRecurrenceRule(e.RecurrenceRule, e.Start.Location())
+		if e.RecurrenceID != nil || e.RecurrenceRule == "" || err != nil {
+			add(e.Start, e.End)
+			continue
+		}
+
+		excluded := map[int64]bool{}
+		for _, exdate := range e.ExceptionDates {
+			excluded[exdate.Unix()] = true
+		}
+		for unix := range overridden[e.UID] {
+			excluded[unix] = true
+		}
+
+		duration := e.End.Sub(e.Start)
+		// All-day occurrences last whole days, which are 23 or 25 hours long across a daylight saving change
+		days := int(math.Round(duration.Hours() / 24))
+		for _, start := range rule.expand(e.Start, to) {
+			if excluded[start.Unix()] {
+				continue
+			}
+			if e.AllDay {
+				add(start, start.AddDate(0, 0, days))
+			} else {
+				add(start, start.Add(duration))
+			}
+		}
+	}
+
+	return mergePeriods(periods)
+}
+
+// mergePeriods sorts the periods and joins the ones that overlap or touch
+func mergePeriods(periods []Period) []Period {
+	if len(periods) == 0 {
+		return []Period{}
+	}
+
+	sort.Slice(periods, func(i, j int) bool {
+		return periods[i].Start.Before(periods[j].Start)
+	})
+
+	merged := []Period{periods[0]}
+	for _, p := range periods[1:] {
+		last := &merged[len(merged)-1]
+		if !p.Start.After(last.End) {
+			if p.End.After(last.End) {
+				last.End = p.End
+			}
+			continue
+		}
+		merged = append(merged, p)
+	}
+
+	return merged
+}
+
+// parseRecurrenceRule parses the supported subset of RRULE: FREQ (DAILY, WEEKLY, MONTHLY, YEARLY),
+// INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY and BYMONTH. Combinations that RFC 5545 forbids or
+// that are not supported are rejected rather than expanded wrongly.
+func parseRecurrenceRule(value string, loc *time.Location) (*recurrenceRule, error) {
+	rule := &recurrenceRule{interval: 1}
+
+	for _, part := range strings.Split(value, ";") {
+		key, val, _ := strings.Cut(part, "=")
+		key = strings.ToUpper(key)
+
+		switch key {
+		case "FREQ":
+			rule.freq = strings.ToUpper(val)
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid INTERVAL %q", val)
+			}
+			rule.interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid COUNT %q", val)
+			}
+			rule.count = n
+		case "UNTIL":
+			until, _, err := parseDateTime(property{value: val, params: map[string]string{}}, loc)
+			if err != nil {
+				return nil, fmt.Errorf("invalid UNTIL %q", val)
+			}
+			if len(val) == len(dateFormat) {
+				// A date includes the whole day
+				until = until.AddDate(0, 0, 1).Add(-time.Second)
+			}
+			rule.until = &until
+		case "BYDAY":
+			for _, day := range strings.Split(val, ",") {
+				day = strings.ToUpper(strings.TrimSpace(day))
+				if len(day) < 2 {
+					return nil, fmt.Errorf("invalid BYDAY %q", val)
+				}
+				weekday, ok := weekdays[day[len(day)-2:]]
+				if !ok {
+					return nil, fmt.Errorf("invalid BYDAY %q", val)
+				}
+				n := 0
+				if prefix := strings.TrimPrefix(day[:len(day)-2], "+"); prefix != "" {
+					var err error
+					if n, err = strconv.Atoi(prefix); err != nil {
+						return nil, fmt.Errorf("invalid BYDAY %q", val)
+					}
+				}
+				rule.byDay = append(rule.byDay, weekdayNum{n: n, day: weekday})
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(val, ",") {
+				n, err := strconv.Atoi(day)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, fmt.Errorf("invalid BYMONTHDAY %q", val)
+				}
+				rule.byMonthDay = append(rule.byMonthDay, n)
+			}
+		case "BYMONTH":
+			for _, month := range strings.Split(val, ",") {
+				n, err := strconv.Atoi(month)
+				if err != nil || n < 1 || n > 12 {
+					return nil, fmt.Errorf("invalid BYMONTH %q", val)
+				}
+				rule.byMonth = append(rule.byMonth, n)
+			}
+		case "WKST", "":
+			// Weeks always start on Monday
+		default:
+			return nil, fmt.Errorf("unsupported rule part %s", key)
+		}
+	}
+
+	switch rule.freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	default:
+		return nil, fmt.Errorf("unsupported FREQ %q", rule.freq)
+	}
+
+	if rule.freq == "DAILY" || rule.freq == "WEEKLY" {
+		for _, wd := range rule.byDay {
+			if wd.n != 0 {
+				return nil, fmt.Errorf("BYDAY with an ordinal is not allowed with FREQ=%s", rule.freq)
+			}
+		}
+	}
+	if rule.freq == "WEEKLY" && len(rule.byMonthDay) > 0 {
+		return nil, fmt.Errorf("BYMONTHDAY is not allowed with FREQ=WEEKLY")
+	}
+	if rule.freq == "YEARLY" && len(rule.byDay) > 0 && len(rule.byMonth) == 0 {
+		// Weekdays counted across the whole year
+		return nil, fmt.Errorf("unsupported BYDAY without BYMONTH with FREQ=YEARLY")
+	}
+
+	return rule, nil
+}
+
+// expand returns the start times of the occurrences that begin before `to`, the first being dtstart.
+// Occurrences keep the wall clock time of dtstart across daylight saving changes.
+func (r *recurrenceRule) expand(dtstart, to time.Time) []time.Time {
+	occurrences := []time.Time{dtstart}
+	if r.until != nil && dtstart.After(*r.until) {
+		return nil
+	}
+
+	for k := 0; k < maxRecurrencePeriods; k++ {
+		candidates, periodStart := r.period(dtstart, k*r.interval)
+		if !periodStart.Before(to) || (r.until != nil && periodStart.After(*r.until)) {
+			break
+		}
+
+		for _, c := range candidates {
+			if !c.After(dtstart) {
+				continue
+			}
+			if r.count > 0 && len(occurrences) >= r.count {
+				return occurrences
+			}
+			if (r.until != nil && c.After(*r.until)) || !c.Before(to) {
+				return occurrences
+			}
+			occurrences = append(occurrences, c)
+		}
+	}
+
+	return occurrences
+}
+
+// period returns the sorted candidate occurrences of the n-th period after dtstart and the start of that period
+func (r *recurrenceRule) period(dtstart time.Time, n int) ([]time.Time, time.Time) {
+	loc := dtstart.Location()
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, dtstart.Hour(), dtstart.Minute(), dtstart.Second(), 0, loc)
+	}
+
+	var candidates []time.Time
+	var periodStart time.Time
+
+	switch r.freq {
+	case "DAILY":
+		day := dtstart.AddDate(0, 0, n)
+		periodStart = at(day.Year(), day.Month(), day.Day())
+		if r.matchesWeekday(periodStart) && r.matchesMonth(periodStart) && r.matchesMonthDay(periodStart) {
+			candidates = append(candidates, periodStart)
+		}
+	case "WEEKLY":
+		// Weeks start on Monday
+		offset := (int(dtstart.Weekday()) + 6) % 7
+		monday := dtstart.AddDate(0, 0, n*7-offset)
+		periodStart = at(monday.Year(), monday.Month(), monday.Day())
+		var days []time.Time
+		if len(r.byDay) == 0 {
+			day := dtstart.AddDate(0, 0, n*7)
+			days = append(days, at(day.Year(), day.Month(), day.Day()))
+		}
+		for _, wd := range r.byDay {
+			day := monday.AddDate(0, 0, (int(wd.day)+6)%7)
+			days = append(days, at(day.Year(), day.Month(), day.Day()))
+		}
+		for _, day := range days {
+			if r.matchesMonth(day) {
+				candidates = append(candidates, day)
+			}
+		}
+	case "MONTHLY":
+		first := time.Date(dtstart.Year(), dtstart.Month()+time.Month(n), 1, 0, 0, 0, 0, loc)
+		periodStart = at(first.Year(), first.Month(), 1)
+		if len(r.byMonth) == 0 || r.matchesMonth(periodStart) {
+			candidates = r.daysOfMonth(dtstart, first.Year(), first.Month(), at)
+		}
+	case "YEARLY":
+		year := dtstart.Year() + n
+		periodStart = at(year, time.January, 1)
+		months := r.byMonth
+		switch {
+		case len(months) == 0 && len(r.byMonthDay) > 0:
+			months = []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+		case len(months) == 0:
+			months = []int{int(dtstart.Month())}
+		}
+		for _, month := range months {
+			candidates = append(candidates, r.daysOfMonth(dtstart, year, time.Month(month), at)...)
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Before(candidates[j])
+	})
+
+	// The same day may be selected twice, e.g. by BYDAY=MO,1MO
+	unique := candidates[:0]
+	for i, c := range candidates {
+		if i == 0 || !c.Equal(candidates[i-1]) {
+			unique = append(unique, c)
+		}
+	}
+
+	return unique, periodStart
+}
+
+// daysOfMonth returns the days of a month selected by BYDAY and BYMONTHDAY, or the day of dtstart.
+// When both are set, only the days matching both are selected, e.g. BYDAY=FR;BYMONTHDAY=13.
+func (r *recurrenceRule) daysOfMonth(dtstart time.Time, year int, month time.Month, at func(int, time.Month, int) time.Time) []time.Time {
+	daysInMonth := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	var days []time.Time
+
+	switch {
+	case len(r.byDay) > 0:
+		for _, wd := range r.byDay {
+			var matching []int
+			for d := 1; d <= daysInMonth; d++ {
+				if time.Date(year, month, d, 0, 0, 0, 0, time.UTC).Weekday() == wd.day {
+					matching = append(matching, d)
+				}
+			}
+			var selected []int
+			switch {
+			case wd.n == 0:
+				selected = matching
+			case wd.n > 0 && wd.n <= len(matching):
+				selected = []int{matching[wd.n-1]}
+			case wd.n < 0 && -wd.n <= len(matching):
+				selected = []int{matching[len(matching)+wd.n]}
+			}
+			for _, d := range selected {
+				if day := at(year, month, d); r.matchesMonthDay(day) {
+					days = append(days, day)
+				}
+			}
+		}
+	case len(r.byMonthDay) > 0:
+		for _, d := range r.byMonthDay {
+			if d < 0 {
+				d = daysInMonth + d + 1
+			}
+			if d >= 1 && d <= daysInMonth {
+				days = append(days, at(year, month, d))
+			}
+		}
+	default:
+		// Months without the day of dtstart (e.g. the 31st) are skipped
+		if dtstart.Day() <= daysInMonth {
+			days = append(days, at(year, month, dtstart.Day()))
+		}
+	}
+
+	return days
+}
+
+func (r *recurrenceRule) matchesWeekday(t time.Time) bool {
+	if len(r.byDay) == 0 {
+		return true
+	}
+	for _, wd := range r.byDay {
+		if wd.day == t.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *recurrenceRule) matchesMonth(t time.Time) bool {
+	if len(r.byMonth) == 0 {
+		return true
+	}
+	for _, m := range r.byMonth {
+		if time.Month(m) == t.Month() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *recurrenceRule) matchesMonthDay(t time.Time) bool {
+	if len(r.byMonthDay) == 0 {
+		return true
+	}
+	daysInMonth := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, d := range r.byMonthDay {
+		if d == t.Day() || (d < 0 && daysInMonth+d+1 == t.Day()) {
+			return true
+		}
+	}
+	return false
+}
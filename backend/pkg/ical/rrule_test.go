@@ -0,0 +1,380 @@
+package ical
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// periodLayout formats the expected periods in UTC
+const periodLayout = "2006-01-02T15:04Z"
+
+func TestBusyPeriods(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		uid     string
+		from    string
+		to      string
+		want    []string // start/end
+	}{
+		{
+			name: "daily with COUNT", fixture: "daily.ics", uid: "daily-count-datetime",
+			from: "2024-01-01T00:00Z", to: "2024-02-01T00:00Z",
+			want: []string{
+				"2024-01-01T09:00Z/2024-01-01T10:00Z",
+				"2024-01-02T09:00Z/2024-01-02T10:00Z",
+				"2024-01-03T09:00Z/2024-01-03T10:00Z",
+			},
+		},
+		{
+			name: "all-day daily with COUNT", fixture: "daily.ics", uid: "daily-count-date",
+			from: "2024-01-01T00:00Z", to: "2024-02-01T00:00Z",
+			want: []string{
+				"2024-01-01T00:00Z/2024-01-02T00:00Z",
+				"2024-01-03T00:00Z/2024-01-04T00:00Z",
+				"2024-01-05T00:00Z/2024-01-06T00:00Z",
+			},
+		},
+		{
+			name: "daily with UNTIL", fixture: "daily.ics", uid: "daily-until-datetime",
+			from: "2024-01-01T00:00Z", to: "2024-02-01T00:00Z",
+			want: []string{
+				"2024-01-10T09:00Z/2024-01-10T09:30Z",
+				"2024-01-11T09:00Z/2024-01-11T09:30Z",
+				"2024-01-12T09:00Z/2024-01-12T09:30Z",
+			},
+		},
+		{
+			name: "all-day daily with UNTIL", fixture: "daily.ics", uid: "daily-until-date",
+			from: "2024-01-01T00:00Z", to: "2024-02-01T00:00Z",
+			want: []string{
+				"2024-01-20T00:00Z/2024-01-21T00:00Z",
+				"2024-01-22T00:00Z/2024-01-23T00:00Z",
+				"2024-01-24T00:00Z/2024-01-25T00:00Z",
+			},
+		},
+		{
+			name: "daily clipped to the range", fixture: "daily.ics", uid: "daily-count-datetime",
+			from: "2024-01-02T00:00Z", to: "2024-01-03T00:00Z",
+			want: []string{
+				"2024-01-02T09:00Z/2024-01-02T10:00Z",
+			},
+		},
+		{
+			name: "weekly with BYDAY and COUNT", fixture: "weekly.ics", uid: "weekly-count-datetime",
+			from: "2024-01-01T00:00Z", to: "2024-04-01T00:00Z",
+			want: []string{
+				"2024-01-02T14:00Z/2024-01-02T15:00Z",
+				"2024-01-04T14:00Z/2024-01-04T15:00Z",
+				"2024-01-09T14:00Z/2024-01-09T15:00Z",
+				"2024-01-11T14:00Z/2024-01-11T15:00Z",
+			},
+		},
+		{
+			name: "all-day weekly with COUNT", fixture: "weekly.ics", uid: "weekly-count-date",
+			from: "2024-01-01T00:00Z", to: "2024-04-01T00:00Z",
+			want: []string{
+				"2024-01-06T00:00Z/2024-01-07T00:00Z",
+				"2024-01-13T00:00Z/2024-01-14T00:00Z",
+				"2024-01-20T00:00Z/2024-01-21T00:00Z",
+			},
+		},
+		{
+			name: "fortnightly with UNTIL", fixture: "weekly.ics", uid: "weekly-until-datetime",
+			from: "2024-01-01T00:00Z", to: "2024-04-01T00:00Z",
+			want: []string{
+				"2024-01-01T08:00Z/2024-01-01T09:00Z",
+				"2024-01-15T08:00Z/2024-01-15T09:00Z",
+				"2024-01-29T08:00Z/2024-01-29T09:00Z",
+			},
+		},
+		{
+			name: "all-day weekly with UNTIL", fixture: "weekly.ics", uid: "weekly-until-date",
+			from: "2024-01-01T00:00Z", to: "2024-04-01T00:00Z",
+			want: []string{
+				"2024-01-03T00:00Z/2024-01-04T00:00Z",
+				"2024-01-10T00:00Z/2024-01-11T00:00Z",
+				"2024-01-17T00:00Z/2024-01-18T00:00Z",
+			},
+		},
+		{
+			name: "weekly with BYMONTH", fixture: "weekly.ics", uid: "weekly-bymonth",
+			from: "2024-01-01T00:00Z", to: "2024-04-01T00:00Z",
+			want: []string{
+				"2024-01-25T10:00Z/2024-01-25T11:00Z",
+				"2024-03-07T10:00Z/2024-03-07T11:00Z",
+				"2024-03-14T10:00Z/2024-03-14T11:00Z",
+				"2024-03-21T10:00Z/2024-03-21T11:00Z",
+			},
+		},
+		{
+			name: "monthly on the 31st skips shorter months", fixture: "monthly.ics", uid: "monthly-count-datetime",
+			from: "2024-01-01T00:00Z", to: "2025-01-01T00:00Z",
+			want: []string{
+				"2024-01-31T12:00Z/2024-01-31T13:00Z",
+				"2024-03-31T12:00Z/2024-03-31T13:00Z",
+				"2024-05-31T12:00Z/2024-05-31T13:00Z",
+				"2024-07-31T12:00Z/2024-07-31T13:00Z",
+			},
+		},
+		{
+			name: "all-day monthly on the last day", fixture: "monthly.ics", uid: "monthly-count-date",
+			from: "2024-01-01T00:00Z", to: "2025-01-01T00:00Z",
+			want: []string{
+				"2024-01-31T00:00Z/2024-02-01T00:00Z",
+				"2024-02-29T00:00Z/2024-03-01T00:00Z",
+				"2024-03-31T00:00Z/2024-04-01T00:00Z",
+			},
+		},
+		{
+			name: "bimonthly with UNTIL", fixture: "monthly.ics", uid: "monthly-until-datetime",
+			from: "2024-01-01T00:00Z", to: "2025-01-01T00:00Z",
+			want: []string{
+				"2024-01-10T09:00Z/2024-01-10T10:00Z",
+				"2024-03-10T09:00Z/2024-03-10T10:00Z",
+				"2024-05-10T09:00Z/2024-05-10T10:00Z",
+				"2024-07-10T09:00Z/2024-07-10T10:00Z",
+			},
+		},
+		{
+			name: "all-day monthly with UNTIL", fixture: "monthly.ics", uid: "monthly-until-date",
+			from: "2024-01-01T00:00Z", to: "2025-01-01T00:00Z",
+			want: []string{
+				"2024-01-05T00:00Z/2024-01-06T00:00Z",
+				"2024-02-05T00:00Z/2024-02-06T00:00Z",
+				"2024-03-05T00:00Z/2024-03-06T00:00Z",
+				"2024-04-05T00:00Z/2024-04-06T00:00Z",
+			},
+		},
+		{
+			name: "monthly with BYDAY and BYMONTHDAY", fixture: "monthly.ics", uid: "monthly-byday-bymonthday",
+			from: "2024-01-01T00:00Z", to: "2026-01-01T00:00Z",
+			want: []string{
+				"2024-09-13T18:00Z/2024-09-13T19:00Z",
+				"2024-12-13T18:00Z/2024-12-13T19:00Z",
+				"2025-06-13T18:00Z/2025-06-13T19:00Z",
+			},
+		},
+		{
+			name: "yearly on a leap day", fixture: "yearly.ics", uid: "yearly-count-datetime",
+			from: "2024-01-01T00:00Z", to: "2030-01-01T00:00Z",
+			want: []string{
+				"2024-02-29T09:00Z/2024-02-29T10:00Z",
+				"2028-02-29T09:00Z/2028-02-29T10:00Z",
+			},
+		},
+		{
+			name: "all-day yearly with COUNT", fixture: "yearly.ics", uid: "yearly-count-date",
+			from: "2024-01-01T00:00Z", to: "2030-01-01T00:00Z",
+			want: []string{
+				"2024-07-04T00:00Z/2024-07-05T00:00Z",
+				"2025-07-04T00:00Z/2025-07-05T00:00Z",
+				"2026-07-04T00:00Z/2026-07-05T00:00Z",
+			},
+		},
+		{
+			name: "yearly with BYMONTH, a BYDAY ordinal and UNTIL", fixture: "yearly.ics", uid: "yearly-until-datetime",
+			from: "2024-01-01T00:00Z", to: "2030-01-01T00:00Z",
+			want: []string{
+				"2024-11-28T17:00Z/2024-11-28T20:00Z",
+				"2025-11-27T17:00Z/2025-11-27T20:00Z",
+				"2026-11-26T17:00Z/2026-11-26T20:00Z",
+			},
+		},
+		{
+			name: "all-day biennial with UNTIL", fixture: "yearly.ics", uid: "yearly-until-date",
+			from: "2024-01-01T00:00Z", to: "2030-01-01T00:00Z",
+			want: []string{
+				"2024-01-01T00:00Z/2024-01-02T00:00Z",
+				"2026-01-01T00:00Z/2026-01-02T00:00Z",
+				"2028-01-01T00:00Z/2028-01-02T00:00Z",
+			},
+		},
+		{
+			name: "yearly with BYMONTHDAY covers every month", fixture: "yearly.ics", uid: "yearly-bymonthday",
+			from: "2024-01-01T00:00Z", to: "2030-01-01T00:00Z",
+			want: []string{
+				"2024-01-01T07:00Z/2024-01-01T08:00Z",
+				"2024-02-01T07:00Z/2024-02-01T08:00Z",
+				"2024-03-01T07:00Z/2024-03-01T08:00Z",
+			},
+		},
+		{
+			name: "last Friday of the month", fixture: "byday.ics", uid: "last-friday",
+			from: "2024-01-01T00:00Z", to: "2024-07-01T00:00Z",
+			want: []string{
+				"2024-01-26T16:00Z/2024-01-26T17:00Z",
+				"2024-02-23T16:00Z/2024-02-23T17:00Z",
+				"2024-03-29T16:00Z/2024-03-29T17:00Z",
+				"2024-04-26T16:00Z/2024-04-26T17:00Z",
+			},
+		},
+		{
+			name: "second Tuesday of the month", fixture: "byday.ics", uid: "second-tuesday",
+			from: "2024-01-01T00:00Z", to: "2024-07-01T00:00Z",
+			want: []string{
+				"2024-01-09T10:00Z/2024-01-09T11:00Z",
+				"2024-02-13T10:00Z/2024-02-13T11:00Z",
+				"2024-03-12T10:00Z/2024-03-12T11:00Z",
+				"2024-04-09T10:00Z/2024-04-09T11:00Z",
+			},
+		},
+		{
+			name: "EXDATE with TZID and in UTC", fixture: "exdate.ics", uid: "exdate-timed",
+			from: "2024-03-01T00:00Z", to: "2024-04-01T00:00Z",
+			want: []string{
+				"2024-03-04T08:00Z/2024-03-04T09:00Z",
+				"2024-03-08T08:00Z/2024-03-08T09:00Z",
+			},
+		},
+		{
+			name: "all-day EXDATE", fixture: "exdate.ics", uid: "exdate-date",
+			from: "2024-03-01T00:00Z", to: "2024-04-01T00:00Z",
+			want: []string{
+				"2024-03-11T00:00Z/2024-03-12T00:00Z",
+				"2024-03-25T00:00Z/2024-03-26T00:00Z",
+			},
+		},
+		{
+			name: "RECURRENCE-ID moves and cancels occurrences", fixture: "overrides.ics", uid: "standup",
+			from: "2024-04-01T00:00Z", to: "2024-05-01T00:00Z",
+			want: []string{
+				"2024-04-01T09:00Z/2024-04-01T09:30Z",
+				"2024-04-08T14:00Z/2024-04-08T14:30Z",
+				"2024-04-22T09:00Z/2024-04-22T09:30Z",
+			},
+		},
+		{
+			name: "TZID keeps the wall clock time across DST", fixture: "timezones.ics", uid: "berlin-dst",
+			from: "2024-03-01T00:00Z", to: "2024-04-01T00:00Z",
+			want: []string{
+				"2024-03-29T08:00Z/2024-03-29T09:00Z",
+				"2024-03-30T08:00Z/2024-03-30T09:00Z",
+				"2024-03-31T07:00Z/2024-03-31T08:00Z",
+			},
+		},
+		{
+			name: "Windows time zone name across DST", fixture: "timezones.ics", uid: "windows-zone-dst",
+			from: "2024-11-01T00:00Z", to: "2024-12-01T00:00Z",
+			want: []string{
+				"2024-11-02T13:00Z/2024-11-02T14:00Z",
+				"2024-11-03T14:00Z/2024-11-03T15:00Z",
+			},
+		},
+		{
+			name: "all-day event in the calendar time zone across DST", fixture: "timezones.ics", uid: "all-day-dst",
+			from: "2024-10-01T00:00Z", to: "2024-11-01T00:00Z",
+			want: []string{
+				"2024-10-24T22:00Z/2024-10-25T22:00Z",
+				"2024-10-26T22:00Z/2024-10-27T23:00Z",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var events []Event
+			for _, e := range parseFixture(t, tt.fixture) {
+				if e.UID == tt.uid {
+					events = append(events, e)
+				}
+			}
+			if len(events) == 0 {
+				t.Fatalf("no event %q in %s", tt.uid, tt.fixture)
+			}
+
+			got := formatPeriods(BusyPeriods(events, mustParseTime(t, tt.from), mustParseTime(t, tt.to)))
+			if !equalStrings(got, tt.want) {
+				t.Errorf("BusyPeriods() =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRecurrenceRuleRejectsUnsupportedRules(t *testing.T) {
+	tests := []struct {
+		name string
+		rule string
+	}{
+		{name: "BYDAY ordinal with DAILY", rule: "FREQ=DAILY;BYDAY=1MO"},
+		{name: "BYDAY ordinal with WEEKLY", rule: "FREQ=WEEKLY;BYDAY=2TU"},
+		{name: "BYMONTHDAY with WEEKLY", rule: "FREQ=WEEKLY;BYMONTHDAY=13"},
+		{name: "yearly BYDAY without BYMONTH", rule: "FREQ=YEARLY;BYDAY=20MO"},
+		{name: "unsupported FREQ", rule: "FREQ=HOURLY"},
+		{name: "unsupported rule part", rule: "FREQ=MONTHLY;BYSETPOS=-1;BYDAY=MO,TU,WE,TH,FR"},
+		{name: "invalid BYDAY", rule: "FREQ=WEEKLY;BYDAY=XX"},
+		{name: "invalid COUNT", rule: "FREQ=DAILY;COUNT=0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseRecurrenceRule(tt.rule, time.UTC); err == nil {
+				t.Errorf("parseRecurrenceRule(%q) succeeded, want an error", tt.rule)
+			}
+		})
+	}
+}
+
+func TestBusyPeriodsBlocksFirstOccurrenceOfUnsupportedRule(t *testing.T) {
+	start := time.Date(2024, time.January, 2, 10, 0, 0, 0, time.UTC)
+	events := []Event{{
+		UID:            "unsupported",
+		Start:          start,
+		End:            start.Add(time.Hour),
+		RecurrenceRule: "FREQ=WEEKLY;BYDAY=1TU",
+	}}
+
+	got := formatPeriods(BusyPeriods(events, start.AddDate(0, 0, -1), start.AddDate(0, 1, 0)))
+	want := []string{"2024-01-02T10:00Z/2024-01-02T11:00Z"}
+	if !equalStrings(got, want) {
+		t.Errorf("BusyPeriods() = %v, want %v", got, want)
+	}
+}
+
+func parseFixture(t *testing.T, name string) []Event {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	events, err := Parse(f)
+	if err != nil {
+		t.Fatalf("Parse(%s): %v", name, err)
+	}
+	return events
+}
+
+func mustParseTime(t *testing.T, value string) time.Time {
+	t.Helper()
+
+	parsed, err := time.Parse(periodLayout, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func formatPeriods(periods []Period) []string {
+	formatted := make([]string, len(periods))
+	for i, p := range periods {
+		formatted[i] = p.Start.UTC().Format(periodLayout) + "/" + p.End.UTC().Format(periodLayout)
+	}
+	return formatted
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,173 @@
+// Package imaging decodes untrusted images and renders normalized thumbnails.
+//
+// Dimensions are read from the header before any pixels are decoded, so small files that
+// expand to huge images (decompression bombs) are rejected cheaply. JPEG orientation from
+// EXIF is read so that thumbnails can be turned upright, and thumbnails are re-encoded as
+// JPEG or WebP, which drops all metadata. WebP is read with golang.org/x/image and written
+// with libwebp, so building the package requires cgo.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+
+	"github.com/chai2010/webp"
+
+	// Register the GIF, PNG and WebP decoders with image.Decode
+	_ "image/gif"
+	_ "image/png"
+
+	_ "golang.org/x/image/webp"
+)
+
+var (
+	ErrNotImage = errors.New("imaging: not a JPEG, PNG, GIF or WebP image")
+	ErrTooLarge = errors.New("imaging: image dimensions are too large")
+)
+
+const (
+	// MaxSide is the largest width or height accepted
+	MaxSide = 12000
+	// MaxPixels is the largest area accepted, about 160 MB once decoded to RGBA
+	MaxPixels = 40_000_000
+
+	// JPEGQuality is the quality JPEG thumbnails are encoded with
+	JPEGQuality = 85
+	// WebPQuality is the quality WebP thumbnails are encoded with
+	WebPQuality = 80
+)
+
+// CheckConfig reads the format and dimensions of an image from its header and checks that
+// it is safe to decode
+func CheckConfig(r io.Reader) (image.Config, string, error) {
+	config, format, err := image.DecodeConfig(r)
+	if err != nil {
+		return image.Config{}, "", ErrNotImage
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return image.Config{}, "", ErrNotImage
+	}
+	if config.Width > MaxSide || config.Height > MaxSide || config.Width*config.Height > MaxPixels {
+		return image.Config{}, "", ErrTooLarge
+	}
+	return config, format, nil
+}
+
+// Decode decodes an image after checking its dimensions. For JPEG images it also returns
+// the EXIF orientation, to be applied with Orient; other formats are always upright (1).
+// Only the first frame of an animated GIF is kept.
+func Decode(data []byte) (image.Image, int, error) {
+	if _, _, err := CheckConfig(bytes.NewReader(data)); err != nil {
+		return nil, 0, err
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, 0, ErrNotImage
+	}
+
+	if format == "jpeg" {
+		return img, jpegOrientation(data), nil
+	}
+	return img, 1, nil
+}
+
+// Thumbnail crops the center square of an image and scales it to size x size pixels.
+// Every target pixel averages the source pixels it covers (a box filter), which keeps detail
+// when shrinking; when growing, the nearest source pixel is used. Transparent areas are
+// flattened onto white, since JPEG has no alpha channel.
+//
+// The source is read in place, so large photos are not copied at full resolution. Cropping
+// the center commutes with Orient, which is best applied to the small result.
+func Thumbnail(img image.Image, size int) *image.RGBA {
+	bounds := img.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	left := bounds.Min.X + (bounds.Dx()-side)/2
+	top := bounds.Min.Y + (bounds.Dy()-side)/2
+	pixel := pixelReader(img)
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	for dy := 0; dy < size; dy++ {
+		y0, y1 := span(dy, side, size)
+		for dx := 0; dx < size; dx++ {
+			x0, x1 := span(dx, side, size)
+
+			var r, g, b, n uint64
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					// The components are premultiplied by alpha, so adding the missing
+					// coverage composites the pixel onto white
+					pr, pg, pb, pa := pixel(left+x, top+y)
+					r += uint64(pr + 0xffff - pa)
+					g += uint64(pg + 0xffff - pa)
+					b += uint64(pb + 0xffff - pa)
+					n++
+				}
+			}
+
+			i := dy*dst.Stride + dx*4
+			dst.Pix[i] = uint8((r / n) >> 8)
+			dst.Pix[i+1] = uint8((g / n) >> 8)
+			dst.Pix[i+2] = uint8((b / n) >> 8)
+			dst.Pix[i+3] = 0xff
+		}
+	}
+	return dst
+}
+
+// EncodeJPEG writes an image as a baseline JPEG without metadata
+func EncodeJPEG(w io.Writer, img image.Image) error {
+	return jpeg.Encode(w, img, &jpeg.Options{Quality: JPEGQuality})
+}
+
+// EncodeWebP writes an image as a lossy WebP without metadata
+func EncodeWebP(w io.Writer, img image.Image) error {
+	return webp.Encode(w, img, &webp.Options{Quality: WebPQuality})
+}
+
+// pixelReader returns a function that reads a pixel like img.At(x, y).RGBA(). The image types
+// that decoders return are read straight from their pixel buffers, since going through At
+// boxes a color for every pixel, which dominates the time it takes to shrink a large photo.
+func pixelReader(img image.Image) func(x, y int) (r, g, b, a uint32) {
+	switch img := img.(type) {
+	case *image.YCbCr:
+		return func(x, y int) (uint32, uint32, uint32, uint32) {
+			ci := img.COffset(x, y)
+			return color.YCbCr{Y: img.Y[img.YOffset(x, y)], Cb: img.Cb[ci], Cr: img.Cr[ci]}.RGBA()
+		}
+	case *image.RGBA:
+		return func(x, y int) (uint32, uint32, uint32, uint32) {
+			p := img.Pix[img.PixOffset(x, y):]
+			return uint32(p[0]) * 0x101, uint32(p[1]) * 0x101, uint32(p[2]) * 0x101, uint32(p[3]) * 0x101
+		}
+	case *image.NRGBA:
+		return func(x, y int) (uint32, uint32, uint32, uint32) {
+			p := img.Pix[img.PixOffset(x, y):]
+			a := uint32(p[3]) * 0x101
+			return uint32(p[0]) * 0x101 * a / 0xffff, uint32(p[1]) * 0x101 * a / 0xffff, uint32(p[2]) * 0x101 * a / 0xffff, a
+		}
+	case *image.Gray:
+		return func(x, y int) (uint32, uint32, uint32, uint32) {
+			v := uint32(img.Pix[img.PixOffset(x, y)]) * 0x101
+			return v, v, v, 0xffff
+		}
+	default:
+		return func(x, y int) (uint32, uint32, uint32, uint32) {
+			return img.At(x, y).RGBA()
+		}
+	}
+}
+
+// span returns the source pixels [from, to) covered by target pixel i, at least one wide
+func span(i, side, size int) (int, int) {
+	from := i * side / size
+	to := (i + 1) * side / size
+	if to <= from {
+		to = from + 1
+	}
+	return from, to
+}
@@ -0,0 +1,185 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"testing"
+)
+
+func TestCheckConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    error
+		wantFmt string
+	}{
+		{name: "small PNG", data: pngWithSize(t, 640, 480), wantFmt: "png"},
+		{name: "largest side", data: pngWithSize(t, MaxSide, 1), wantFmt: "png"},
+		{name: "side too large", data: pngWithSize(t, MaxSide+1, 1), want: ErrTooLarge},
+		{name: "area too large", data: pngWithSize(t, 8000, 8000), want: ErrTooLarge},
+		{name: "JPEG", data: readTestdata(t, "orientation-1.jpg"), wantFmt: "jpeg"},
+		{name: "JPEG header with huge dimensions", data: readTestdata(t, "oversized.jpg"), want: ErrTooLarge},
+		{name: "not an image", data: []byte("GIF? no, plain text"), want: ErrNotImage},
+		{name: "empty", data: nil, want: ErrNotImage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, format, err := CheckConfig(bytes.NewReader(tt.data))
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("CheckConfig() = %v, want %v", err, tt.want)
+			}
+			if format != tt.wantFmt {
+				t.Errorf("CheckConfig() format = %q, want %q", format, tt.wantFmt)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name            string
+		file            string
+		want            error
+		wantOrientation int
+	}{
+		{name: "upright", file: "orientation-1.jpg", wantOrientation: 1},
+		{name: "rotated", file: "orientation-6.jpg", wantOrientation: 6},
+		{name: "garbage EXIF", file: "garbage-app1.jpg", wantOrientation: 1},
+		{name: "truncated EXIF", file: "truncated-app1.jpg", want: ErrNotImage},
+		{name: "oversized", file: "oversized.jpg", want: ErrTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, orientation, err := Decode(readTestdata(t, tt.file))
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Decode() = %v, want %v", err, tt.want)
+			}
+			if err != nil {
+				return
+			}
+			if orientation != tt.wantOrientation {
+				t.Errorf("Decode() orientation = %d, want %d", orientation, tt.wantOrientation)
+			}
+			if got := img.Bounds().Size(); got != image.Pt(4, 2) {
+				t.Errorf("Decode() size = %v, want 4x2", got)
+			}
+		})
+	}
+}
+
+func TestThumbnail(t *testing.T) {
+	red := color.NRGBA{0xff, 0, 0, 0xff}
+	green := color.NRGBA{0, 0xff, 0, 0xff}
+	blue := color.NRGBA{0, 0, 0xff, 0xff}
+	white := color.NRGBA{0xff, 0xff, 0xff, 0xff}
+
+	// Three bands of two pixels each, across a wide image or down a tall one
+	bands := func(w, h int, vertical bool) image.Image {
+		img := image.NewNRGBA(image.Rect(0, 0, w, h))
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				i := x
+				if vertical {
+					i = y
+				}
+				img.Set(x, y, []color.NRGBA{red, green, blue}[i/2])
+			}
+		}
+		return img
+	}
+
+	transparent := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	halfTransparent := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+	halfTransparent.Set(0, 0, color.NRGBA{0, 0, 0, 0x80})
+
+	offset := image.NewRGBA(image.Rect(10, 10, 16, 12))
+	for y := 10; y < 12; y++ {
+		for x := 10; x < 16; x++ {
+			offset.Set(x, y, []color.NRGBA{red, green, blue}[(x-10)/2])
+		}
+	}
+
+	tests := []struct {
+		name string
+		img  image.Image
+		size int
+		want color.NRGBA // Every pixel of the thumbnail
+	}{
+		{name: "wide image keeps the center", img: bands(6, 2, false), size: 2, want: green},
+		{name: "tall image keeps the center", img: bands(2, 6, true), size: 2, want: green},
+		{name: "shrinking averages", img: bands(6, 2, false), size: 1, want: green},
+		{name: "growing repeats pixels", img: bands(6, 2, false), size: 5, want: green},
+		{name: "bounds not at the origin", img: offset, size: 2, want: green},
+		{name: "transparent becomes white", img: transparent, size: 2, want: white},
+		{name: "half transparent is blended onto white", img: halfTransparent, size: 1, want: color.NRGBA{0x7f, 0x7f, 0x7f, 0xff}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			thumb := Thumbnail(tt.img, tt.size)
+			if got := thumb.Bounds(); got != image.Rect(0, 0, tt.size, tt.size) {
+				t.Fatalf("Thumbnail() bounds = %v, want %dx%d", got, tt.size, tt.size)
+			}
+			for y := 0; y < tt.size; y++ {
+				for x := 0; x < tt.size; x++ {
+					if got := color.NRGBAModel.Convert(thumb.At(x, y)); got != tt.want {
+						t.Fatalf("Thumbnail() pixel (%d, %d) = %v, want %v", x, y, got, tt.want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestThumbnailReadsDecodedPhotos(t *testing.T) {
+	// Decoded JPEGs are YCbCr, which is read from the pixel buffers directly
+	img, _, err := Decode(readTestdata(t, "orientation-1.jpg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := img.(*image.YCbCr); !ok {
+		t.Fatalf("decoded %T, want *image.YCbCr", img)
+	}
+
+	// The center square of the 4x2 photo spans its dark left and light right half
+	thumb := Thumbnail(img, 2)
+	left, right := thumb.RGBAAt(0, 0), thumb.RGBAAt(1, 0)
+	if left.R >= 0x80 || right.R < 0x80 {
+		t.Errorf("Thumbnail() = %v, %v, want a dark left and a light right pixel", left, right)
+	}
+}
+
+func readTestdata(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// pngWithSize returns a 1x1 PNG whose header claims the given dimensions, which is all
+// CheckConfig reads
+func pngWithSize(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	// The IHDR chunk follows the 8 byte signature: length, type, then width and height
+	ihdr := data[8+8 : 8+8+13]
+	binary.BigEndian.PutUint32(ihdr[0:], uint32(width))
+	binary.BigEndian.PutUint32(ihdr[4:], uint32(height))
+	binary.BigEndian.PutUint32(data[8+8+13:], crc32.ChecksumIEEE(data[8+4:8+8+13]))
+	return data
+}
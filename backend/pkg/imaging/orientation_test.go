@@ -0,0 +1,138 @@
+package imaging
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestOrient(t *testing.T) {
+	// A 3x2 image whose pixels are labelled a to f:
+	//
+	//	a b c
+	//	d e f
+	src := image.NewGray(image.Rect(0, 0, 3, 2))
+	copy(src.Pix, "abcdef")
+
+	tests := []struct {
+		orientation int
+		want        []string // Rows of the upright image
+	}{
+		{orientation: 0, want: []string{"abc", "def"}},
+		{orientation: 1, want: []string{"abc", "def"}},
+		{orientation: 2, want: []string{"cba", "fed"}},
+		{orientation: 3, want: []string{"fed", "cba"}},
+		{orientation: 4, want: []string{"def", "abc"}},
+		{orientation: 5, want: []string{"ad", "be", "cf"}},
+		{orientation: 6, want: []string{"da", "eb", "fc"}},
+		{orientation: 7, want: []string{"fc", "eb", "da"}},
+		{orientation: 8, want: []string{"cf", "be", "ad"}},
+		{orientation: 9, want: []string{"abc", "def"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.orientation), func(t *testing.T) {
+			got := Orient(src, tt.orientation)
+
+			bounds := got.Bounds()
+			if bounds.Dx() != len(tt.want[0]) || bounds.Dy() != len(tt.want) {
+				t.Fatalf("Orient() size = %dx%d, want %dx%d", bounds.Dx(), bounds.Dy(), len(tt.want[0]), len(tt.want))
+			}
+			for y, row := range tt.want {
+				for x := range row {
+					gray := color.GrayModel.Convert(got.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.Gray)
+					if gray.Y != row[x] {
+						t.Errorf("Orient() pixel (%d, %d) = %c, want %c", x, y, gray.Y, row[x])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{name: "garbage EXIF", data: readTestdata(t, "garbage-app1.jpg"), want: 1},
+		{name: "truncated EXIF", data: readTestdata(t, "truncated-app1.jpg"), want: 1},
+		{name: "without EXIF", data: readTestdata(t, "oversized.jpg"), want: 1},
+		{name: "not a JPEG", data: []byte("\x89PNG\r\n\x1a\n"), want: 1},
+		{name: "empty", data: nil, want: 1},
+		{name: "missing marker", data: []byte{0xff, 0xd8, 0x00, 0xe1, 0x00, 0x08}, want: 1},
+	}
+	for orientation := 1; orientation <= 8; orientation++ {
+		file := fmt.Sprintf("orientation-%d.jpg", orientation)
+		tests = append(tests, struct {
+			name string
+			data []byte
+			want int
+		}{name: file, data: readTestdata(t, file), want: orientation})
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jpegOrientation(tt.data); got != tt.want {
+				t.Errorf("jpegOrientation() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExifOrientation(t *testing.T) {
+	tests := []struct {
+		name string
+		tiff []byte
+		want int
+	}{
+		{name: "little endian", tiff: tiffBlock(binary.LittleEndian, 42, 8, exifTypeShort, 6), want: 6},
+		{name: "big endian", tiff: tiffBlock(binary.BigEndian, 42, 8, exifTypeShort, 8), want: 8},
+		{name: "value out of range", tiff: tiffBlock(binary.LittleEndian, 42, 8, exifTypeShort, 9), want: 1},
+		{name: "wrong type", tiff: tiffBlock(binary.LittleEndian, 42, 8, 4, 6), want: 1},
+		{name: "wrong magic number", tiff: tiffBlock(binary.LittleEndian, 43, 8, exifTypeShort, 6), want: 1},
+		{name: "IFD inside the header", tiff: tiffBlock(binary.LittleEndian, 42, 4, exifTypeShort, 6), want: 1},
+		{name: "IFD past the end", tiff: tiffBlock(binary.LittleEndian, 42, 1000, exifTypeShort, 6), want: 1},
+		{name: "entries past the end", tiff: tiffBlock(binary.LittleEndian, 42, 8, exifTypeShort, 6)[:20], want: 1},
+		{name: "unknown byte order", tiff: []byte("XX\x00\x2a\x00\x00\x00\x08"), want: 1},
+		{name: "too short", tiff: []byte("II\x2a\x00"), want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exifOrientation(tt.tiff); got != tt.want {
+				t.Errorf("exifOrientation() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// tiffBlock builds a TIFF header and an IFD at offset 8 with an image width entry followed
+// by an orientation entry of the given type and value
+func tiffBlock(order binary.ByteOrder, magic uint16, ifd uint32, typ uint16, orientation uint16) []byte {
+	tiff := make([]byte, 8+2+2*12+4)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], magic)
+	order.PutUint32(tiff[4:], ifd)
+	order.PutUint16(tiff[8:], 2)
+
+	entries := []struct{ tag, typ, value uint16 }{
+		{tag: 0x0100, typ: exifTypeShort, value: 640},
+		{tag: exifOrientationTag, typ: typ, value: orientation},
+	}
+	for i, e := range entries {
+		entry := tiff[10+i*12:]
+		order.PutUint16(entry, e.tag)
+		order.PutUint16(entry[2:], e.typ)
+		order.PutUint32(entry[4:], 1)
+		order.PutUint16(entry[8:], e.value)
+	}
+	return tiff
+}
@@ -8,6 +8,12 @@ import (
 	"github.com/golang-jwt/jwt"
 )
 
+// RoomTicketTTL is how long a room ticket can be used to join a lesson's video room
+const RoomTicketTTL = 2 * time.Minute
+
+// roomTicketPurpose marks room tickets so that they are never accepted as login tokens and vice versa
+const roomTicketPurpose = "room"
+
 var jwtSecret = []byte(getJWTSecret())
 
 func getJWTSecret() string {
@@ -37,6 +43,73 @@ func GenerateToken(userID int, role string) (string, error) {
 
 // ValidateToken checks if a token is valid and returns the user ID and role
 func ValidateToken(tokenString string) (int, string, error) {
+	claims, err := parseToken(tokenString)
+	if err != nil {
+		return 0, "", err
+	}
+
+	if _, ok := claims["purpose"]; ok {
+		return 0, "", fmt.Errorf("invalid token")
+	}
+
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return 0, "", fmt.Errorf("invalid user_id in token")
+	}
+
+	role, ok := claims["role"].(string)
+	if !ok {
+		return 0, "", fmt.Errorf("invalid role in token")
+	}
+
+	return int(userID), role, nil
+}
+
+// GenerateRoomTicket creates a token that admits a user to the video room of one lesson for
+// RoomTicketTTL. Unlike the login token it may be put in URLs, since it opens nothing else.
+func GenerateRoomTicket(lessonID, userID int) (string, time.Time, error) {
+	expiresAt := time.Now().Add(RoomTicketTTL)
+
+	token := jwt.New(jwt.SigningMethodHS256)
+
+	claims := token.Claims.(jwt.MapClaims)
+	claims["purpose"] = roomTicketPurpose
+	claims["lesson_id"] = lessonID
+	claims["user_id"] = userID
+	claims["exp"] = expiresAt.Unix()
+
+	tokenString, err := token.SignedString(jwtSecret)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign room ticket: %w", err)
+	}
+
+	return tokenString, expiresAt, nil
+}
+
+// ValidateRoomTicket checks that a room ticket is valid for the lesson and returns the user ID
+func ValidateRoomTicket(ticket string, lessonID int) (int, error) {
+	claims, err := parseToken(ticket)
+	if err != nil {
+		return 0, err
+	}
+
+	if purpose, _ := claims["purpose"].(string); purpose != roomTicketPurpose {
+		return 0, fmt.Errorf("not a room ticket")
+	}
+	if ticketLessonID, _ := claims["lesson_id"].(float64); int(ticketLessonID) != lessonID {
+		return 0, fmt.Errorf("room ticket is for another lesson")
+	}
+
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return 0, fmt.Errorf("invalid user_id in room ticket")
+	}
+
+	return int(userID), nil
+}
+
+// parseToken checks the signature and expiry of a token and returns its claims
+func parseToken(tokenString string) (jwt.MapClaims, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
@@ -45,22 +118,13 @@ func ValidateToken(tokenString string) (int, string, error) {
 	})
 
 	if err != nil {
-		return 0, "", fmt.Errorf("failed to parse token: %w", err)
+		return nil, fmt.Errorf("failed to parse token: %w", err)
 	}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		userID, ok := claims["user_id"].(float64)
-		if !ok {
-			return 0, "", fmt.Errorf("invalid user_id in token")
-		}
-
-		role, ok := claims["role"].(string)
-		if !ok {
-			return 0, "", fmt.Errorf("invalid role in token")
-		}
-
-		return int(userID), role, nil
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid token")
 	}
 
-	return 0, "", fmt.Errorf("invalid token")
+	return claims, nil
 }
@@ -69,3 +69,18 @@ func AuthMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// OptionalAuthMiddleware sets the user info like AuthMiddleware when the request carries a
+// valid token, and lets the request through as anonymous otherwise
+func OptionalAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parts := strings.Split(c.GetHeader("Authorization"), " ")
+		if len(parts) == 2 && parts[0] == "Bearer" && parts[1] != "" {
+			if claims, err := verifyToken(parts[1]); err == nil {
+				c.Set("user_id", claims.UserID)
+				c.Set("user_role", claims.Role)
+			}
+		}
+		c.Next()
+	}
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"strings"
 	"time"
 	"tongly-backend/internal/logger"
 
@@ -14,6 +15,9 @@ type LoggerConfig struct {
 	SkipPaths []string
 	// Skip logging for status codes
 	SkipStatusCodes []int
+	// Path parameters whose values are secret, such as calendar feed tokens. They are logged
+	// as the parameter name instead, e.g. "/api/calendar/:token".
+	RedactParams []string
 }
 
 // Logger returns a gin middleware for logging requests
@@ -34,7 +38,7 @@ func Logger(config ...LoggerConfig) gin.HandlerFunc {
 
 		start := time.Now()
 		requestID := uuid.New().String()
-		path := c.Request.URL.Path
+		path := redactParams(c, c.Request.URL.Path, cfg.RedactParams)
 		raw := c.Request.URL.RawQuery
 		if raw != "" {
 			path = path + "?" + raw
@@ -103,3 +107,13 @@ func Logger(config ...LoggerConfig) gin.HandlerFunc {
 		}
 	}
 }
+
+// redactParams replaces the values of the given path parameters in path with their names
+func redactParams(c *gin.Context, path string, names []string) string {
+	for _, name := range names {
+		if value := c.Param(name); value != "" {
+			path = strings.Replace(path, "/"+value, "/:"+name, 1)
+		}
+	}
+	return path
+}
@@ -0,0 +1,151 @@
+// Package pdf renders simple text documents as PDF files.
+//
+// Only the standard Courier font is used, so the output needs no embedded fonts.
+// Characters outside of Latin-1 are transliterated (Cyrillic) or replaced with "?".
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	pageWidth    = 595 // A4 in points
+	pageHeight   = 842
+	marginLeft   = 40
+	marginTop    = 50
+	fontSize     = 9
+	lineHeight   = 12
+	linesPerPage = (pageHeight - 2*marginTop) / lineHeight
+)
+
+// Document is a text document made of lines that are laid out into A4 pages
+type Document struct {
+	lines []string
+}
+
+// New creates an empty document
+func New() *Document {
+	return &Document{}
+}
+
+// AddLine appends a line of text to the document
+func (d *Document) AddLine(text string) {
+	d.lines = append(d.lines, text)
+}
+
+// AddLines appends several lines of text to the document
+func (d *Document) AddLines(lines ...string) {
+	d.lines = append(d.lines, lines...)
+}
+
+// Bytes renders the document as a PDF file
+func (d *Document) Bytes() []byte {
+	pages := d.paginate()
+
+	// Object layout: 1 catalog, 2 page tree, 3 font, then a page and a content stream per page
+	var objects []string
+	objects = append(objects, "<< /Type /Catalog /Pages 2 0 R >>")
+
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	objects = append(objects, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	objects = append(objects, "<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range pages {
+		content := renderPage(page)
+		objects = append(objects, fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 5+2*i,
+		))
+		objects = append(objects, fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xrefOffset := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n", len(objects)+1)
+	buf.WriteString("0000000000 65535 f \n")
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xrefOffset)
+
+	return buf.Bytes()
+}
+
+// paginate splits the document lines into pages
+func (d *Document) paginate() [][]string {
+	if len(d.lines) == 0 {
+		return [][]string{{}}
+	}
+
+	var pages [][]string
+	for start := 0; start < len(d.lines); start += linesPerPage {
+		end := start + linesPerPage
+		if end > len(d.lines) {
+			end = len(d.lines)
+		}
+		pages = append(pages, d.lines[start:end])
+	}
+	return pages
+}
+
+// renderPage builds the content stream of a single page
+func renderPage(lines []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "BT\n/F1 %d Tf\n%d TL\n%d %d Td\n", fontSize, lineHeight, marginLeft, pageHeight-marginTop)
+	for _, line := range lines {
+		fmt.Fprintf(&b, "(%s) '\n", escape(line))
+	}
+	b.WriteString("ET")
+	return b.String()
+}
+
+// escape converts text into a PDF string literal body in WinAnsi encoding
+func escape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if translit, ok := cyrillic[r]; ok {
+			b.WriteString(translit)
+			continue
+		}
+
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// cyrillic maps Russian letters to their Latin transliteration
+var cyrillic = map[rune]string{
+	'А': "A", 'Б': "B", 'В': "V", 'Г': "G", 'Д': "D", 'Е': "E", 'Ё': "Yo", 'Ж': "Zh",
+	'З': "Z", 'И': "I", 'Й': "Y", 'К': "K", 'Л': "L", 'М': "M", 'Н': "N", 'О': "O",
+	'П': "P", 'Р': "R", 'С': "S", 'Т': "T", 'У': "U", 'Ф': "F", 'Х': "Kh", 'Ц': "Ts",
+	'Ч': "Ch", 'Ш': "Sh", 'Щ': "Shch", 'Ъ': "", 'Ы': "Y", 'Ь': "", 'Э': "E", 'Ю': "Yu",
+	'Я': "Ya",
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya",
+}
@@ -0,0 +1,110 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "plain ASCII", text: "Lesson 1: 30.00 EUR", want: "Lesson 1: 30.00 EUR"},
+		{name: "parentheses and backslash", text: `a (b) \c`, want: `a \(b\) \\c`},
+		{name: "Latin-1", text: "Café", want: `Caf\351`},
+		{name: "Cyrillic", text: "Щука и ёж", want: "Shchuka i yozh"},
+		{name: "hard sign is dropped", text: "объект", want: "obekt"},
+		{name: "other scripts", text: "日本", want: "??"},
+		{name: "control characters", text: "a\tb", want: "a?b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escape(tt.text); got != tt.want {
+				t.Errorf("escape(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines int
+		want  []int // lines per page
+	}{
+		{name: "empty document", lines: 0, want: []int{0}},
+		{name: "single line", lines: 1, want: []int{1}},
+		{name: "exactly one page", lines: linesPerPage, want: []int{linesPerPage}},
+		{name: "one line over", lines: linesPerPage + 1, want: []int{linesPerPage, 1}},
+		{name: "three pages", lines: 2*linesPerPage + 5, want: []int{linesPerPage, linesPerPage, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := New()
+			for i := 0; i < tt.lines; i++ {
+				doc.AddLine(fmt.Sprintf("line %d", i))
+			}
+
+			pages := doc.paginate()
+			if len(pages) != len(tt.want) {
+				t.Fatalf("paginate() returned %d pages, want %d", len(pages), len(tt.want))
+			}
+			for i, page := range pages {
+				if len(page) != tt.want[i] {
+					t.Errorf("page %d has %d lines, want %d", i+1, len(page), tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBytes(t *testing.T) {
+	doc := New()
+	doc.AddLines("Monthly statement", "Total (net): 120.00")
+	for i := 0; i < linesPerPage; i++ {
+		doc.AddLine("lesson")
+	}
+	out := doc.Bytes()
+
+	if !bytes.HasPrefix(out, []byte("%PDF-1.4\n")) {
+		t.Errorf("output does not start with the PDF header")
+	}
+	if !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+		t.Errorf("output does not end with the EOF marker")
+	}
+	if !bytes.Contains(out, []byte("/Count 2")) {
+		t.Errorf("page tree does not count 2 pages")
+	}
+	if !bytes.Contains(out, []byte(`(Total \(net\): 120.00) '`)) {
+		t.Errorf("escaped line not found in the content stream")
+	}
+
+	// startxref must point at the cross-reference table, and every entry at its object
+	match := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	if match == nil {
+		t.Fatal("startxref not found")
+	}
+	xref, _ := strconv.Atoi(string(match[1]))
+	if !bytes.HasPrefix(out[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %d does not point at the xref table", xref)
+	}
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out[xref:], -1)
+	if len(entries) != 3+2*2 {
+		t.Fatalf("xref table has %d objects, want %d", len(entries), 3+2*2)
+	}
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		want := fmt.Sprintf("%d 0 obj\n", i+1)
+		if !bytes.HasPrefix(out[offset:], []byte(want)) {
+			t.Errorf("xref entry %d points at offset %d, which is not %q", i+1, offset, want)
+		}
+	}
+}
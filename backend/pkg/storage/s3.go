@@ -0,0 +1,295 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	s3Algorithm      = "AWS4-HMAC-SHA256"
+	s3Service        = "s3"
+	s3UnsignedBody   = "UNSIGNED-PAYLOAD"
+	s3RequestTimeout = 10 * time.Minute
+
+	// s3MaxPresignExpiry is the longest lifetime S3 accepts for a presigned URL
+	s3MaxPresignExpiry = 7 * 24 * time.Hour
+)
+
+// S3Config holds the location and credentials of an S3-compatible bucket
+type S3Config struct {
+	// Endpoint is the base URL of the service, such as https://s3.eu-central-1.amazonaws.com
+	// or http://minio:9000
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PathStyle addresses the bucket as a path (endpoint/bucket/key) instead of a subdomain
+	// (bucket.endpoint/key). Most self-hosted services need it.
+	PathStyle bool
+}
+
+// S3Storage keeps objects in an S3-compatible bucket. Requests are signed with AWS Signature
+// Version 4; the body is sent unsigned, which S3 accepts over both HTTP and HTTPS.
+type S3Storage struct {
+	config S3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewS3Storage creates a new S3Storage
+func NewS3Storage(config S3Config) *S3Storage {
+	config.Endpoint = strings.TrimRight(config.Endpoint, "/")
+	return &S3Storage{
+		config: config,
+		client: &http.Client{Timeout: s3RequestTimeout},
+		now:    time.Now,
+	}
+}
+
+// Put uploads an object with a single PUT request
+func (s *S3Storage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	req, err := s.newRequest(ctx, http.MethodPut, key, io.LimitReader(body, size))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Open downloads an object. The caller must close the returned body.
+func (s *S3Storage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Delete removes an object. S3 reports success for missing objects too.
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		if err == ErrNotFound {
+			return nil
+		}
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// SignedURL returns a presigned GET URL for an object
+func (s *S3Storage) SignedURL(key string, expiresAt time.Time) (string, error) {
+	objectURL, err := s.objectURL(key)
+	if err != nil {
+		return "", err
+	}
+
+	now := s.now().UTC()
+	expiry := expiresAt.Sub(now).Round(time.Second)
+	if expiry < time.Second {
+		expiry = time.Second
+	}
+	if expiry > s3MaxPresignExpiry {
+		expiry = s3MaxPresignExpiry
+	}
+
+	amzDate := now.Format("20060102T150405Z")
+	scope := s.scope(now)
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", s3Algorithm)
+	query.Set("X-Amz-Credential", s.config.AccessKey+"/"+scope)
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expiry.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalQuery := canonicalQueryString(query)
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		objectURL.EscapedPath(),
+		canonicalQuery,
+		"host:" + objectURL.Host + "\n",
+		"host",
+		s3UnsignedBody,
+	}, "\n")
+
+	signature := s.signature(now, amzDate, scope, canonicalRequest)
+	objectURL.RawQuery = canonicalQuery + "&X-Amz-Signature=" + signature
+	return objectURL.String(), nil
+}
+
+// newRequest builds a request for an object, signed in the Authorization header
+func (s *S3Storage) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	objectURL, err := s.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, objectURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	scope := s.scope(now)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", s3UnsignedBody)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "host:" + objectURL.Host + "\n" +
+		"x-amz-content-sha256:" + s3UnsignedBody + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		objectURL.EscapedPath(),
+		"",
+		canonicalHeaders,
+		signedHeaders,
+		s3UnsignedBody,
+	}, "\n")
+
+	signature := s.signature(now, amzDate, scope, canonicalRequest)
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.config.AccessKey, scope, signedHeaders, signature,
+	))
+
+	return req, nil
+}
+
+// do sends a request and turns error responses into errors
+func (s *S3Storage) do(req *http.Request) (*http.Response, error) {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return nil, fmt.Errorf("storage: %s %s failed with status %d: %s",
+		req.Method, req.URL.Path, resp.StatusCode, strings.TrimSpace(string(message)))
+}
+
+// objectURL returns the URL of an object with path-style or virtual-hosted addressing
+func (s *S3Storage) objectURL(key string) (*url.URL, error) {
+	if !validKey(key) {
+		return nil, ErrInvalidKey
+	}
+
+	endpoint, err := url.Parse(s.config.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("storage: invalid endpoint: %w", err)
+	}
+
+	path := "/" + key
+	if s.config.PathStyle {
+		path = "/" + s.config.Bucket + path
+	} else {
+		endpoint.Host = s.config.Bucket + "." + endpoint.Host
+	}
+
+	// RawPath keeps the AWS encoding, which is stricter than the one url.URL would choose
+	endpoint.Path = path
+	endpoint.RawPath = "/" + escapeKey(strings.TrimPrefix(path, "/"))
+	return endpoint, nil
+}
+
+func (s *S3Storage) scope(now time.Time) string {
+	return now.Format("20060102") + "/" + s.config.Region + "/" + s3Service + "/aws4_request"
+}
+
+// signature signs a canonical request with a key derived from the secret key, date and region
+func (s *S3Storage) signature(now time.Time, amzDate, scope, canonicalRequest string) string {
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{s3Algorithm, amzDate, scope, hex.EncodeToString(hash[:])}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.config.SecretKey), now.Format("20060102"))
+	key = hmacSHA256(key, s.config.Region)
+	key = hmacSHA256(key, s3Service)
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalQueryString sorts and encodes query parameters as Signature Version 4 requires
+func canonicalQueryString(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		for _, value := range query[key] {
+			parts = append(parts, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// escapeKey encodes every segment of a key, keeping the slashes between them
+func escapeKey(key string) string {
+	return uriEncode(key, false)
+}
+
+// uriEncode percent-encodes everything but unreserved characters (and slashes unless
+// encodeSlash is set), using upper case hex digits
+func uriEncode(value string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,51 @@
+// Package storage keeps uploaded files in a blob store.
+//
+// LocalStorage writes files to a directory and signs download URLs with an HMAC that the
+// application checks itself before serving the file. S3Storage talks to Amazon S3 or any
+// S3-compatible service (MinIO, Ceph, R2) and hands out presigned URLs.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound         = errors.New("storage: object not found")
+	ErrInvalidKey       = errors.New("storage: invalid object key")
+	ErrInvalidSignature = errors.New("storage: invalid signature")
+	ErrURLExpired       = errors.New("storage: signed URL has expired")
+)
+
+// Storage stores objects under keys such as "avatar/42/0f8fad5b.jpg"
+type Storage interface {
+	// Put stores size bytes read from body under key, replacing any existing object
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Open reads the object stored under key
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object stored under key. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a URL anyone can download the object from until expiresAt
+	SignedURL(key string, expiresAt time.Time) (string, error)
+}
+
+// Verifier is implemented by backends whose signed URLs are served by the application itself
+type Verifier interface {
+	Verify(key, expires, signature string) error
+}
+
+// validKey checks that a key is a relative slash-separated path without empty, "." or ".." segments
+func validKey(key string) bool {
+	if key == "" || len(key) > 1024 || strings.HasPrefix(key, "/") {
+		return false
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." || strings.ContainsAny(segment, "\\\x00") {
+			return false
+		}
+	}
+	return true
+}
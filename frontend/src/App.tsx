@@ -18,6 +18,8 @@ import { SearchTutor } from './pages/SearchTutor';
 import { ScheduleLesson } from './pages/ScheduleLesson';
 import MyLessons from './pages/MyLessons';
 import LessonRoom from './pages/LessonRoom';
+import { Messages } from './pages/Messages';
+import { LessonHistory } from './pages/LessonHistory';
 import GamesHub from './pages/Games/GamesHub';
 import GamePlay from './pages/Games/GamePlay';
 import Leaderboard from './pages/Games/Leaderboard';
@@ -102,6 +104,30 @@ const AppRoutes = () => {
                             </PrivateRoute>
                         } 
                     />
+                    <Route 
+                        path="/messages" 
+                        element={
+                            <PrivateRoute>
+                                <Messages />
+                            </PrivateRoute>
+                        } 
+                    />
+                    <Route 
+                        path="/messages/:conversationId" 
+                        element={
+                            <PrivateRoute>
+                                <Messages />
+                            </PrivateRoute>
+                        } 
+                    />
+                    <Route 
+                        path="/history/:userId" 
+                        element={
+                            <PrivateRoute>
+                                <LessonHistory />
+                            </PrivateRoute>
+                        } 
+                    />
                     <Route 
                         path="/lessons/room/:lessonId" 
                         element={
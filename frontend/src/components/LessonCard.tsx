@@ -1,6 +1,6 @@
 import React, { useState } from 'react';
 import { useNavigate } from 'react-router-dom';
-import { Lesson, LessonStatus } from '../types/lesson';
+import { AspectRatings, Lesson, LessonStatus } from '../types/lesson';
 import { format } from 'date-fns';
 import { cancelLesson, addReview } from '../services/lesson.service';
 import { toast } from 'react-hot-toast';
@@ -8,6 +8,8 @@ import { useTranslation } from '../contexts/I18nContext';
 import { envConfig } from '../config/env';
 
 const DEFAULT_AVATAR = envConfig.defaultAvatar;
+const MAX_REVIEW_LENGTH = 2000;
+const ASPECTS: (keyof AspectRatings)[] = ['pronunciation_help', 'preparation', 'punctuality'];
 
 interface LessonCardProps {
   lesson: Lesson;
@@ -19,6 +21,8 @@ const LessonCard: React.FC<LessonCardProps> = ({ lesson, currentUserId, onCancel
   const { t } = useTranslation();
   const navigate = useNavigate();
   const [rating, setRating] = useState<number | null>(null);
+  const [comment, setComment] = useState('');
+  const [aspects, setAspects] = useState<AspectRatings>({});
   const [isSubmittingReview, setIsSubmittingReview] = useState(false);
 
   // Determine if the current user is the student or tutor
@@ -83,7 +87,7 @@ const LessonCard: React.FC<LessonCardProps> = ({ lesson, currentUserId, onCancel
     
     try {
       setIsSubmittingReview(true);
-      await addReview(lesson.id, rating);
+      await addReview(lesson.id, { rating, comment: comment.trim(), aspects });
       toast.success(t('components.lesson_card.review_success'));
       if (onCancelSuccess) {
         // Use the same callback to refresh the lessons
@@ -198,6 +202,15 @@ const LessonCard: React.FC<LessonCardProps> = ({ lesson, currentUserId, onCancel
         )}
         
         <div className="flex justify-end space-x-2 mt-4">
+          {otherParticipant && (
+            <button
+              onClick={() => navigate(`/history/${isStudent ? lesson.tutor_id : lesson.student_id}`)}
+              className="px-4 py-2 text-sm font-medium text-orange-600 bg-orange-50 rounded-md hover:bg-orange-100 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-orange-500 transition-colors"
+            >
+              {t('components.lesson_card.view_history')}
+            </button>
+          )}
+
           {canCancel && (
             <button 
               onClick={handleCancelLesson}
@@ -241,6 +254,41 @@ const LessonCard: React.FC<LessonCardProps> = ({ lesson, currentUserId, onCancel
                 </button>
               ))}
             </div>
+            <textarea
+              value={comment}
+              onChange={(e) => setComment(e.target.value)}
+              maxLength={MAX_REVIEW_LENGTH}
+              rows={3}
+              placeholder={t('components.lesson_card.review_comment_placeholder')}
+              className="w-full mb-3 px-3 py-2 text-sm border border-gray-300 rounded-md focus:outline-none focus:ring-2 focus:ring-orange-500"
+            />
+            <p className="text-xs text-gray-500 mb-2">{t('components.lesson_card.aspects_optional')}</p>
+            <div className="space-y-1 mb-3">
+              {ASPECTS.map((aspect) => (
+                <div key={aspect} className="flex items-center justify-between">
+                  <span className="text-sm text-gray-600">{t(`components.lesson_card.aspects.${aspect}`)}</span>
+                  <div className="flex items-center">
+                    {[1, 2, 3, 4, 5].map((star) => (
+                      <button
+                        key={star}
+                        type="button"
+                        onClick={() => setAspects({ ...aspects, [aspect]: aspects[aspect] === star ? undefined : star })}
+                        className="focus:outline-none"
+                      >
+                        <svg
+                          className={`w-5 h-5 ${(aspects[aspect] || 0) >= star ? 'text-yellow-500' : 'text-gray-300'}`}
+                          fill="currentColor"
+                          viewBox="0 0 20 20"
+                          xmlns="http://www.w3.org/2000/svg"
+                        >
+                          <path d="M9.049 2.927c.3-.921 1.603-.921 1.902 0l1.07 3.292a1 1 0 00.95.69h3.462c.969 0 1.371 1.24.588 1.81l-2.8 2.034a1 1 0 00-.364 1.118l1.07 3.292c.3.921-.755 1.688-1.54 1.118l-2.8-2.034a1 1 0 00-1.175 0l-2.8 2.034c-.784.57-1.838-.197-1.539-1.118l1.07-3.292a1 1 0 00-.364-1.118L2.98 8.72c-.783-.57-.38-1.81.588-1.81h3.461a1 1 0 00.951-.69l1.07-3.292z" />
+                        </svg>
+                      </button>
+                    ))}
+                  </div>
+                </div>
+              ))}
+            </div>
             <button
               onClick={handleSubmitReview}
               disabled={!rating || isSubmittingReview}
@@ -0,0 +1,178 @@
+import React, { useEffect, useState } from 'react';
+import { toast } from 'react-hot-toast';
+import { useTranslation } from '../contexts/I18nContext';
+import { getErrorMessage, studentService } from '../services/api';
+import { PreferredTime, StudentPreferences } from '../types/student';
+
+// Days of week as the API expects them, 0 is Sunday, listed from Monday
+const WEEK_DAYS = [
+  { day: 1, key: 'monday' },
+  { day: 2, key: 'tuesday' },
+  { day: 3, key: 'wednesday' },
+  { day: 4, key: 'thursday' },
+  { day: 5, key: 'friday' },
+  { day: 6, key: 'saturday' },
+  { day: 0, key: 'sunday' },
+];
+
+const browserTimeZone = () => Intl.DateTimeFormat().resolvedOptions().timeZone || 'UTC';
+
+// The student's time zone, price range and preferred lesson times, which tutor
+// recommendations are based on
+export const LessonPreferencesForm: React.FC = () => {
+  const { t } = useTranslation();
+  const [timezone, setTimezone] = useState(browserTimeZone());
+  const [minPrice, setMinPrice] = useState('');
+  const [maxPrice, setMaxPrice] = useState('');
+  const [times, setTimes] = useState<PreferredTime[]>([]);
+  const [isSaving, setIsSaving] = useState(false);
+
+  useEffect(() => {
+    const loadPreferences = async () => {
+      try {
+        const prefs = await studentService.getPreferences();
+        // Preferences saved before the student picked a time zone are in UTC
+        if (prefs.timezone !== 'UTC' || prefs.preferred_times.length > 0) {
+          setTimezone(prefs.timezone);
+        }
+        setMinPrice(prefs.min_price?.toString() ?? '');
+        setMaxPrice(prefs.max_price?.toString() ?? '');
+        setTimes(prefs.preferred_times);
+      } catch (error) {
+        toast.error(getErrorMessage(error));
+      }
+    };
+
+    loadPreferences();
+  }, []);
+
+  const updateTime = (index: number, changes: Partial<PreferredTime>) => {
+    setTimes(times.map((time, i) => (i === index ? { ...time, ...changes } : time)));
+  };
+
+  const handleSave = async () => {
+    const prefs: StudentPreferences = {
+      timezone: timezone.trim(),
+      min_price: minPrice === '' ? undefined : Number(minPrice),
+      max_price: maxPrice === '' ? undefined : Number(maxPrice),
+      preferred_times: times,
+    };
+
+    setIsSaving(true);
+    try {
+      const saved = await studentService.updatePreferences(prefs);
+      setTimes(saved.preferred_times);
+      toast.success(t('common.update_success'));
+    } catch (error) {
+      toast.error(getErrorMessage(error));
+    } finally {
+      setIsSaving(false);
+    }
+  };
+
+  return (
+    <div className="bg-white shadow rounded-lg p-6">
+      <h2 className="text-xl font-semibold mb-2">{t('pages.user_preferences.lessons_title')}</h2>
+      <p className="text-sm text-gray-500 mb-6">{t('pages.user_preferences.lessons_hint')}</p>
+
+      <div className="grid grid-cols-1 md:grid-cols-3 gap-4 mb-6">
+        <div>
+          <label htmlFor="timezone" className="block text-sm font-medium text-gray-700 mb-1">
+            {t('pages.user_preferences.timezone')}
+          </label>
+          <input
+            id="timezone"
+            type="text"
+            value={timezone}
+            onChange={(e) => setTimezone(e.target.value)}
+            className="block w-full rounded-md border-gray-300 shadow-sm focus:border-orange-500 focus:ring-orange-500 sm:text-sm"
+          />
+        </div>
+        <div>
+          <label htmlFor="min_price" className="block text-sm font-medium text-gray-700 mb-1">
+            {t('pages.user_preferences.min_price')}
+          </label>
+          <input
+            id="min_price"
+            type="number"
+            min="0"
+            value={minPrice}
+            onChange={(e) => setMinPrice(e.target.value)}
+            className="block w-full rounded-md border-gray-300 shadow-sm focus:border-orange-500 focus:ring-orange-500 sm:text-sm"
+          />
+        </div>
+        <div>
+          <label htmlFor="max_price" className="block text-sm font-medium text-gray-700 mb-1">
+            {t('pages.user_preferences.max_price')}
+          </label>
+          <input
+            id="max_price"
+            type="number"
+            min="0"
+            value={maxPrice}
+            onChange={(e) => setMaxPrice(e.target.value)}
+            className="block w-full rounded-md border-gray-300 shadow-sm focus:border-orange-500 focus:ring-orange-500 sm:text-sm"
+          />
+        </div>
+      </div>
+
+      <h3 className="text-lg font-medium mb-4">{t('pages.user_preferences.preferred_times')}</h3>
+      {times.length === 0 && (
+        <p className="text-gray-500 mb-4">{t('pages.user_preferences.no_preferred_times')}</p>
+      )}
+      <div className="space-y-2 mb-4">
+        {times.map((time, index) => (
+          <div key={index} className="flex items-center gap-2">
+            <select
+              value={time.day_of_week}
+              onChange={(e) => updateTime(index, { day_of_week: Number(e.target.value) })}
+              className="rounded-md border-gray-300 shadow-sm focus:border-orange-500 focus:ring-orange-500 sm:text-sm"
+            >
+              {WEEK_DAYS.map(({ day, key }) => (
+                <option key={day} value={day}>{t(`common.days.${key}`)}</option>
+              ))}
+            </select>
+            <input
+              type="time"
+              value={time.start_time}
+              onChange={(e) => updateTime(index, { start_time: e.target.value })}
+              className="rounded-md border-gray-300 shadow-sm focus:border-orange-500 focus:ring-orange-500 sm:text-sm"
+            />
+            <span className="text-gray-500">—</span>
+            <input
+              type="time"
+              value={time.end_time}
+              onChange={(e) => updateTime(index, { end_time: e.target.value })}
+              className="rounded-md border-gray-300 shadow-sm focus:border-orange-500 focus:ring-orange-500 sm:text-sm"
+            />
+            <button
+              type="button"
+              onClick={() => setTimes(times.filter((_, i) => i !== index))}
+              className="text-red-500 hover:text-red-700 text-sm"
+            >
+              {t('common.remove')}
+            </button>
+          </div>
+        ))}
+      </div>
+
+      <div className="flex justify-between">
+        <button
+          type="button"
+          onClick={() => setTimes([...times, { day_of_week: 1, start_time: '18:00', end_time: '20:00' }])}
+          className="text-sm text-orange-600 hover:text-orange-700"
+        >
+          {t('pages.user_preferences.add_preferred_time')}
+        </button>
+        <button
+          type="button"
+          onClick={handleSave}
+          disabled={isSaving}
+          className="inline-flex justify-center py-2 px-4 border border-transparent shadow-sm text-sm font-medium rounded-md text-white bg-orange-600 hover:bg-orange-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-orange-500 disabled:opacity-50"
+        >
+          {t('common.save')}
+        </button>
+      </div>
+    </div>
+  );
+};
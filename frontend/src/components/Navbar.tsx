@@ -3,6 +3,7 @@ import { Link, useLocation } from 'react-router-dom';
 import { useAuth } from '../contexts/AuthContext';
 import { useTranslation } from '../contexts/I18nContext';
 import { LanguageSwitcher } from './LanguageSwitcher';
+import { NotificationBell } from './NotificationBell';
 import { envConfig } from '../config/env';
 
 const DEFAULT_AVATAR = envConfig.defaultAvatar;
@@ -23,6 +24,7 @@ export const Navbar = () => {
         const navItems = [
             { to: '/search-tutors', label: t('pages.search_tutor.title') || 'Find Tutors', visibleTo: 'student' },
             { to: '/lessons', label: t('pages.my_lessons.title') || 'My Lessons', visibleTo: 'all' },
+            { to: '/messages', label: t('navbar.messages') || 'Messages', visibleTo: 'all' },
             { to: '/tutor-schedule', label: t('pages.tutor_schedule.title') || 'Schedule', visibleTo: 'tutor' },
             { to: '/games', label: t('navbar.games.title') || 'Language Games', visibleTo: 'student' },
             { to: '/tutor-settings', label: t('navbar.tutor_settings') || 'Tutor Settings', visibleTo: 'tutor' },
@@ -86,10 +88,11 @@ export const Navbar = () => {
 
                         {user ? (
                             <div className="flex items-center space-x-4">
+                                <NotificationBell />
                                 <div className="relative group">
                                     <button className="flex items-center space-x-2 p-2 rounded-lg hover:bg-overlay-light transition-colors">
                                         <img
-                                            src={user.profile_picture_url || DEFAULT_AVATAR}
+                                            src={user.avatar_urls?.[64] || user.profile_picture_url || DEFAULT_AVATAR}
                                             alt={user.username}
                                             className="h-8 w-8 rounded-full object-cover border-2 border-border"
                                             onError={(e) => {
@@ -0,0 +1,124 @@
+import React, { useEffect, useState } from 'react';
+import { toast } from 'react-hot-toast';
+import { useTranslation } from '../contexts/I18nContext';
+import { AppNotification, NOTIFICATION_EVENT } from '../types/notification';
+import {
+    getNotifications,
+    markAllNotificationsRead,
+    markNotificationRead,
+    subscribeToNotifications,
+} from '../services/notification.service';
+
+export const NotificationBell = () => {
+    const { t } = useTranslation();
+    const [open, setOpen] = useState(false);
+    const [unreadCount, setUnreadCount] = useState(0);
+    const [notifications, setNotifications] = useState<AppNotification[]>([]);
+
+    useEffect(() => {
+        return subscribeToNotifications({
+            onUnreadCount: setUnreadCount,
+            onNotification: (notification) => {
+                setUnreadCount(count => count + 1);
+                setNotifications(current => [notification, ...current]);
+                toast(notification.title);
+                // Let open pages refresh the data the notification is about
+                window.dispatchEvent(new CustomEvent(NOTIFICATION_EVENT, { detail: notification }));
+            },
+        });
+    }, []);
+
+    const toggle = async () => {
+        const nextOpen = !open;
+        setOpen(nextOpen);
+        if (nextOpen) {
+            try {
+                const list = await getNotifications({ limit: 20 });
+                setNotifications(list.notifications);
+                setUnreadCount(list.unread_count);
+            } catch (error) {
+                console.error('Error loading notifications:', error);
+            }
+        }
+    };
+
+    const handleRead = async (notification: AppNotification) => {
+        if (notification.read_at) return;
+        try {
+            await markNotificationRead(notification.id);
+            setNotifications(current => current.map(n =>
+                n.id === notification.id ? { ...n, read_at: new Date().toISOString() } : n
+            ));
+            setUnreadCount(count => Math.max(0, count - 1));
+        } catch (error) {
+            console.error('Error marking notification as read:', error);
+        }
+    };
+
+    const handleReadAll = async () => {
+        try {
+            await markAllNotificationsRead();
+            const now = new Date().toISOString();
+            setNotifications(current => current.map(n => ({ ...n, read_at: n.read_at || now })));
+            setUnreadCount(0);
+        } catch (error) {
+            console.error('Error marking notifications as read:', error);
+        }
+    };
+
+    return (
+        <div className="relative">
+            <button
+                onClick={toggle}
+                className="relative p-2 rounded-lg hover:bg-overlay-light transition-colors"
+                aria-label={t('navbar.notifications.title') || 'Notifications'}
+            >
+                <svg className="h-6 w-6 text-text-primary" xmlns="http://www.w3.org/2000/svg" fill="none" viewBox="0 0 24 24" stroke="currentColor">
+                    <path strokeLinecap="round" strokeLinejoin="round" strokeWidth={2} d="M15 17h5l-1.405-1.405A2.032 2.032 0 0118 14.158V11a6 6 0 10-12 0v3.159c0 .538-.214 1.055-.595 1.436L4 17h5m6 0v1a3 3 0 11-6 0v-1m6 0H9" />
+                </svg>
+                {unreadCount > 0 && (
+                    <span className="absolute -top-0.5 -right-0.5 min-w-[1.25rem] h-5 px-1 rounded-full bg-error text-white text-xs flex items-center justify-center">
+                        {unreadCount > 99 ? '99+' : unreadCount}
+                    </span>
+                )}
+            </button>
+
+            {open && (
+                <div className="absolute right-0 w-80 mt-2 bg-surface rounded-lg shadow-lg border border-border z-dropdown">
+                    <div className="flex items-center justify-between px-4 py-2 border-b border-border">
+                        <span className="font-medium text-text-primary">
+                            {t('navbar.notifications.title') || 'Notifications'}
+                        </span>
+                        {unreadCount > 0 && (
+                            <button onClick={handleReadAll} className="text-sm text-accent-primary hover:text-accent-primary-hover">
+                                {t('navbar.notifications.mark_all_read') || 'Mark all as read'}
+                            </button>
+                        )}
+                    </div>
+                    <div className="max-h-96 overflow-y-auto">
+                        {notifications.length === 0 ? (
+                            <p className="px-4 py-6 text-sm text-text-secondary text-center">
+                                {t('navbar.notifications.empty') || 'No notifications yet'}
+                            </p>
+                        ) : (
+                            notifications.map(notification => (
+                                <button
+                                    key={notification.id}
+                                    onClick={() => handleRead(notification)}
+                                    className={`block w-full text-left px-4 py-3 border-b border-border last:border-b-0 hover:bg-overlay-light transition-colors
+                                        ${notification.read_at ? '' : 'bg-overlay-light'}`}
+                                >
+                                    <div className="text-sm font-medium text-text-primary">{notification.title}</div>
+                                    <div className="text-sm text-text-secondary">{notification.body}</div>
+                                    <div className="text-xs text-text-secondary mt-1">
+                                        {new Date(notification.created_at).toLocaleString()}
+                                    </div>
+                                </button>
+                            ))
+                        )}
+                    </div>
+                </div>
+            )}
+        </div>
+    );
+};
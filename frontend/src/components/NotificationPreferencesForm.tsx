@@ -0,0 +1,142 @@
+import React, { useEffect, useState } from 'react';
+import { useTranslation } from '../contexts/I18nContext';
+import { getErrorMessage } from '../services/api';
+import {
+    getNotificationPreferences,
+    updateNotificationPreferences,
+} from '../services/notification.service';
+import {
+    EmailLocale,
+    NotificationChannels,
+    NotificationPreferences,
+    NotificationType,
+} from '../types/notification';
+
+const EVENTS: NotificationType[] = [
+    'lesson_booked',
+    'lesson_cancelled',
+    'lesson_rescheduled',
+    'lesson_reminder',
+    'review_received',
+    'review_reply',
+    'streak_at_risk',
+    'message_received',
+    'lesson_notes',
+    'student_feedback',
+    'weekly_summary',
+    'saved_search_match',
+    'favorite_availability',
+    'credential_reviewed',
+];
+
+const LOCALES: EmailLocale[] = ['en', 'ru', 'es'];
+
+// Lets the user choose the language of emails and the channels of every notification event
+export const NotificationPreferencesForm = () => {
+    const { t } = useTranslation();
+    const [preferences, setPreferences] = useState<NotificationPreferences | null>(null);
+    const [saving, setSaving] = useState(false);
+    const [saved, setSaved] = useState(false);
+    const [error, setError] = useState<string | null>(null);
+
+    useEffect(() => {
+        getNotificationPreferences()
+            .then(setPreferences)
+            .catch(err => setError(getErrorMessage(err)));
+    }, []);
+
+    if (!preferences) {
+        return error ? <p className="text-sm text-red-600">{error}</p> : null;
+    }
+
+    const setChannel = (event: NotificationType, channel: keyof NotificationChannels, value: boolean) => {
+        setSaved(false);
+        setPreferences({
+            ...preferences,
+            events: {
+                ...preferences.events,
+                [event]: { ...preferences.events[event], [channel]: value },
+            },
+        });
+    };
+
+    const handleSubmit = async (e: React.FormEvent) => {
+        e.preventDefault();
+        try {
+            setSaving(true);
+            setError(null);
+            setSaved(false);
+            setPreferences(await updateNotificationPreferences(preferences));
+            setSaved(true);
+        } catch (err) {
+            setError(getErrorMessage(err));
+        } finally {
+            setSaving(false);
+        }
+    };
+
+    return (
+        <form onSubmit={handleSubmit} className="space-y-6">
+            <div>
+                <label htmlFor="email_locale" className="block text-sm font-medium text-gray-700">
+                    {t('pages.user_settings.notifications.email_language')}
+                </label>
+                <select
+                    id="email_locale"
+                    value={preferences.locale}
+                    onChange={e => {
+                        setSaved(false);
+                        setPreferences({ ...preferences, locale: e.target.value as EmailLocale });
+                    }}
+                    className="mt-1 block w-full md:w-1/2 rounded-md border-gray-300 shadow-sm focus:border-orange-500 focus:ring-orange-500 sm:text-sm"
+                >
+                    {LOCALES.map(locale => (
+                        <option key={locale} value={locale}>
+                            {t(`pages.user_settings.notifications.locales.${locale}`)}
+                        </option>
+                    ))}
+                </select>
+            </div>
+
+            <table className="min-w-full text-sm">
+                <thead>
+                    <tr className="text-left text-gray-500">
+                        <th className="py-2 font-medium">{t('pages.user_settings.notifications.event')}</th>
+                        <th className="py-2 font-medium text-center">{t('pages.user_settings.notifications.in_app')}</th>
+                        <th className="py-2 font-medium text-center">{t('pages.user_settings.notifications.email')}</th>
+                    </tr>
+                </thead>
+                <tbody className="divide-y divide-gray-100">
+                    {EVENTS.map(event => (
+                        <tr key={event}>
+                            <td className="py-2 text-gray-700">{t(`pages.user_settings.notifications.events.${event}`)}</td>
+                            {(['in_app', 'email'] as const).map(channel => (
+                                <td key={channel} className="py-2 text-center">
+                                    <input
+                                        type="checkbox"
+                                        checked={preferences.events[event]?.[channel] ?? false}
+                                        onChange={e => setChannel(event, channel, e.target.checked)}
+                                        className="h-4 w-4 rounded border-gray-300 text-orange-600 focus:ring-orange-500"
+                                    />
+                                </td>
+                            ))}
+                        </tr>
+                    ))}
+                </tbody>
+            </table>
+
+            {error && <p className="text-sm text-red-600">{error}</p>}
+            {saved && <p className="text-sm text-green-600">{t('pages.user_settings.notifications.saved')}</p>}
+
+            <div className="flex justify-end">
+                <button
+                    type="submit"
+                    disabled={saving}
+                    className="inline-flex justify-center py-2 px-4 border border-transparent shadow-sm text-sm font-medium rounded-md text-white bg-orange-600 hover:bg-orange-700 focus:outline-none focus:ring-2 focus:ring-offset-2 focus:ring-orange-500 disabled:opacity-50"
+                >
+                    {saving ? t('common.saving') : t('common.save')}
+                </button>
+            </div>
+        </form>
+    );
+};
@@ -0,0 +1,109 @@
+import React, { useEffect, useState } from 'react';
+import { Link } from 'react-router-dom';
+import { useTranslation } from '../contexts/I18nContext';
+import { studentService } from '../services/api';
+import { RecommendationFactorName, TutorRecommendation } from '../types/tutor';
+import { TutorCard } from './TutorCard';
+
+const RECOMMENDATION_COUNT = 4;
+const FACTORS: RecommendationFactorName[] = ['language', 'availability', 'goals', 'interests', 'rating', 'price'];
+
+const percent = (value: number) => Math.round(value * 100);
+
+// Tutors recommended to the student, each with what their score is made of
+export const RecommendedTutors: React.FC = () => {
+  const { t } = useTranslation();
+  const [recommendations, setRecommendations] = useState<TutorRecommendation[]>([]);
+  const [expanded, setExpanded] = useState<number | null>(null);
+
+  useEffect(() => {
+    const loadRecommendations = async () => {
+      try {
+        const result = await studentService.getRecommendedTutors(RECOMMENDATION_COUNT);
+        setRecommendations(result.recommendations);
+      } catch (error) {
+        console.error('Error loading recommended tutors:', error);
+      }
+    };
+
+    loadRecommendations();
+  }, []);
+
+  if (recommendations.length === 0) {
+    return null;
+  }
+
+  // Explains the match of a factor with what it was made of
+  const factorDetail = (recommendation: TutorRecommendation, factor: RecommendationFactorName) => {
+    const { breakdown } = recommendation;
+    switch (factor) {
+      case 'interests':
+        return t('components.recommended_tutors.detail_interests', { count: breakdown.shared_interests.length });
+      case 'goals':
+        return t('components.recommended_tutors.detail_goals', { count: breakdown.shared_goals.length });
+      case 'language':
+        return t('components.recommended_tutors.detail_language', { count: breakdown.taught_languages.length });
+      case 'availability':
+        return t('components.recommended_tutors.detail_availability', {
+          overlap: Math.round(breakdown.overlap_minutes / 60 * 10) / 10,
+          preferred: Math.round(breakdown.preferred_minutes / 60 * 10) / 10,
+        });
+      case 'rating':
+        return breakdown.rated_by_reviews
+          ? t('components.recommended_tutors.detail_rating', { rating: recommendation.tutor.rating_score?.toFixed(1) ?? '' })
+          : t('components.recommended_tutors.detail_no_reviews');
+      case 'price':
+        return breakdown.within_price_range
+          ? t('components.recommended_tutors.detail_price_within')
+          : t('components.recommended_tutors.detail_price_outside');
+    }
+  };
+
+  return (
+    <section className="mb-8">
+      <div className="flex items-center justify-between mb-4">
+        <h2 className="text-xl font-bold text-gray-800">{t('components.recommended_tutors.title')}</h2>
+        <Link to="/preferences" className="text-sm text-orange-600 hover:text-orange-700">
+          {t('components.recommended_tutors.improve')}
+        </Link>
+      </div>
+
+      <div className="grid grid-cols-1 md:grid-cols-2 gap-6">
+        {recommendations.map(recommendation => (
+          <div key={recommendation.tutor.user_id}>
+            <TutorCard tutor={recommendation.tutor} />
+            <div className="mt-2 px-2">
+              <button
+                type="button"
+                onClick={() => setExpanded(expanded === recommendation.tutor.user_id ? null : recommendation.tutor.user_id)}
+                className="text-sm text-gray-600 hover:text-orange-600"
+              >
+                {t('components.recommended_tutors.match', { score: percent(recommendation.score) })}
+                {' · '}
+                {expanded === recommendation.tutor.user_id
+                  ? t('components.recommended_tutors.hide_reasons')
+                  : t('components.recommended_tutors.show_reasons')}
+              </button>
+
+              {expanded === recommendation.tutor.user_id && (
+                <ul className="mt-2 space-y-1 text-sm">
+                  {FACTORS.filter(factor => recommendation.breakdown[factor].weight > 0).map(factor => (
+                    <li key={factor} className="flex items-center justify-between gap-4">
+                      <span className="text-gray-700">
+                        {t(`components.recommended_tutors.factors.${factor}`)}
+                        <span className="text-gray-500"> — {factorDetail(recommendation, factor)}</span>
+                      </span>
+                      <span className="text-gray-500 whitespace-nowrap">
+                        +{percent(recommendation.breakdown[factor].points)}
+                      </span>
+                    </li>
+                  ))}
+                </ul>
+              )}
+            </div>
+          </div>
+        ))}
+      </div>
+    </section>
+  );
+};
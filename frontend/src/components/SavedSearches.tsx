@@ -0,0 +1,148 @@
+import React, { useEffect, useState } from 'react';
+import { toast } from 'react-hot-toast';
+import { useTranslation } from '../contexts/I18nContext';
+import { getErrorMessage, studentService } from '../services/api';
+import { SavedSearch } from '../types/student';
+import { TutorSearchFilters, TutorSort } from '../types/tutor';
+
+interface SavedSearchesProps {
+  filters: TutorSearchFilters;
+  sort: TutorSort | '';
+  canSave: boolean;
+  onApply: (filters: TutorSearchFilters, sort: TutorSort | '') => void;
+}
+
+// The student's saved tutor searches, which can be run again and alert the student about
+// new matching tutors, and a form to save the current search
+export const SavedSearches: React.FC<SavedSearchesProps> = ({ filters, sort, canSave, onApply }) => {
+  const { t } = useTranslation();
+  const [searches, setSearches] = useState<SavedSearch[]>([]);
+  const [name, setName] = useState('');
+  const [isSaving, setIsSaving] = useState(false);
+
+  useEffect(() => {
+    const loadSearches = async () => {
+      try {
+        setSearches(await studentService.getSavedSearches());
+      } catch (error) {
+        console.error('Error loading saved searches:', error);
+      }
+    };
+
+    loadSearches();
+  }, []);
+
+  const handleSave = async () => {
+    setIsSaving(true);
+    try {
+      const search = await studentService.createSavedSearch({
+        name: name.trim(),
+        filters: {
+          ...filters,
+          tz: Intl.DateTimeFormat().resolvedOptions().timeZone,
+          sort: sort || undefined,
+        },
+      });
+      setSearches([...searches, search]);
+      setName('');
+      toast.success(t('components.saved_searches.saved'));
+    } catch (error) {
+      toast.error(getErrorMessage(error));
+    } finally {
+      setIsSaving(false);
+    }
+  };
+
+  const handleToggleNotify = async (search: SavedSearch) => {
+    try {
+      const updated = await studentService.updateSavedSearch(search.id, {
+        name: search.name,
+        filters: search.filters,
+        notify: !search.notify,
+      });
+      setSearches(searches.map(s => (s.id === updated.id ? updated : s)));
+    } catch (error) {
+      toast.error(getErrorMessage(error));
+    }
+  };
+
+  const handleDelete = async (search: SavedSearch) => {
+    try {
+      await studentService.deleteSavedSearch(search.id);
+      setSearches(searches.filter(s => s.id !== search.id));
+    } catch (error) {
+      toast.error(getErrorMessage(error));
+    }
+  };
+
+  const handleApply = (search: SavedSearch) => {
+    // The time zone is the browser's at the time the search runs
+    const { sort: savedSort, ...savedFilters } = search.filters;
+    onApply({ ...savedFilters, tz: undefined }, savedSort || '');
+  };
+
+  if (searches.length === 0 && !canSave) {
+    return null;
+  }
+
+  return (
+    <section className="mb-6 bg-white rounded-xl shadow-md p-4">
+      <h2 className="text-lg font-semibold text-gray-800 mb-3">{t('components.saved_searches.title')}</h2>
+
+      {searches.length > 0 && (
+        <ul className="space-y-2 mb-3">
+          {searches.map(search => (
+            <li key={search.id} className="flex items-center justify-between gap-2 text-sm">
+              <button
+                type="button"
+                onClick={() => handleApply(search)}
+                className="text-orange-600 hover:text-orange-700 font-medium truncate"
+              >
+                {search.name}
+              </button>
+              <div className="flex items-center gap-3 whitespace-nowrap">
+                <label className="flex items-center gap-1 text-gray-600">
+                  <input
+                    type="checkbox"
+                    checked={search.notify}
+                    onChange={() => handleToggleNotify(search)}
+                    className="h-4 w-4 text-orange-500 rounded border-gray-300 focus:ring-orange-500"
+                  />
+                  {t('components.saved_searches.notify')}
+                </label>
+                <button
+                  type="button"
+                  onClick={() => handleDelete(search)}
+                  className="text-red-500 hover:text-red-700"
+                >
+                  {t('common.remove')}
+                </button>
+              </div>
+            </li>
+          ))}
+        </ul>
+      )}
+
+      {canSave && (
+        <div className="flex gap-2">
+          <input
+            type="text"
+            value={name}
+            maxLength={100}
+            onChange={(e) => setName(e.target.value)}
+            placeholder={t('components.saved_searches.name_placeholder')}
+            className="flex-1 rounded-lg border border-gray-300 py-1.5 px-3 text-sm focus:ring-2 focus:ring-orange-500 focus:border-orange-500"
+          />
+          <button
+            type="button"
+            onClick={handleSave}
+            disabled={isSaving || name.trim() === ''}
+            className="bg-orange-500 hover:bg-orange-600 text-white text-sm font-medium py-1.5 px-4 rounded-lg disabled:opacity-50"
+          >
+            {t('components.saved_searches.save')}
+          </button>
+        </div>
+      )}
+    </section>
+  );
+};
@@ -6,9 +6,12 @@ import { useTranslation } from '../contexts/I18nContext';
 
 interface TutorCardProps {
   tutor: TutorProfile;
+  // Shows a favorite toggle when given
+  isFavorite?: boolean;
+  onToggleFavorite?: (tutor: TutorProfile) => void;
 }
 
-export const TutorCard: React.FC<TutorCardProps> = ({ tutor }) => {
+export const TutorCard: React.FC<TutorCardProps> = ({ tutor, isFavorite = false, onToggleFavorite }) => {
   const navigate = useNavigate();
   const { t } = useTranslation();
   
@@ -17,6 +20,11 @@ export const TutorCard: React.FC<TutorCardProps> = ({ tutor }) => {
     navigate(`/schedule-lesson/${tutor.user_id}`);
   };
 
+  // Ask the tutor a question before booking
+  const handleMessageTutor = () => {
+    navigate(`/messages?to=${tutor.user_id}`);
+  };
+
   // Format the education array into a readable string
   const formatEducation = () => {
     if (!tutor.education || !Array.isArray(tutor.education) || tutor.education.length === 0) {
@@ -64,8 +72,19 @@ export const TutorCard: React.FC<TutorCardProps> = ({ tutor }) => {
           
           {/* Tutor basic info */}
           <div className="flex-1 min-w-0">
-            <h2 className="text-xl font-bold text-gray-800 truncate mb-1">
-              {tutor.user?.first_name} {tutor.user?.last_name}
+            <h2 className="text-xl font-bold text-gray-800 truncate mb-1 flex items-center gap-2">
+              <span className="truncate">{tutor.user?.first_name} {tutor.user?.last_name}</span>
+              {tutor.is_verified && (
+                <span
+                  title={t('tutor.verified_hint')}
+                  className="flex-shrink-0 inline-flex items-center gap-1 bg-green-100 text-green-700 text-xs font-medium px-2 py-0.5 rounded-full"
+                >
+                  <svg xmlns="http://www.w3.org/2000/svg" className="h-3.5 w-3.5" viewBox="0 0 20 20" fill="currentColor">
+                    <path fillRule="evenodd" d="M16.707 5.293a1 1 0 010 1.414l-8 8a1 1 0 01-1.414 0l-4-4a1 1 0 011.414-1.414L8 12.586l7.293-7.293a1 1 0 011.414 0z" clipRule="evenodd" />
+                  </svg>
+                  {t('tutor.verified')}
+                </span>
+              )}
             </h2>
             <div className="flex flex-wrap items-center text-sm">
               <div className="flex items-center mr-3">
@@ -89,6 +108,20 @@ export const TutorCard: React.FC<TutorCardProps> = ({ tutor }) => {
               </span>
             </div>
           </div>
+
+          {onToggleFavorite && (
+            <button
+              type="button"
+              onClick={() => onToggleFavorite(tutor)}
+              title={isFavorite ? t('tutor.remove_favorite') : t('tutor.add_favorite')}
+              aria-label={isFavorite ? t('tutor.remove_favorite') : t('tutor.add_favorite')}
+              className="flex-shrink-0 self-start text-orange-500 hover:text-orange-600"
+            >
+              <svg xmlns="http://www.w3.org/2000/svg" className="h-6 w-6" fill={isFavorite ? 'currentColor' : 'none'} viewBox="0 0 24 24" stroke="currentColor">
+                <path strokeLinecap="round" strokeLinejoin="round" strokeWidth={2} d="M4.318 6.318a4.5 4.5 0 000 6.364L12 20.364l7.682-7.682a4.5 4.5 0 00-6.364-6.364L12 7.636l-1.318-1.318a4.5 4.5 0 00-6.364 0z" />
+              </svg>
+            </button>
+          )}
         </div>
         
         {/* Additional tutor details */}
@@ -145,6 +178,12 @@ export const TutorCard: React.FC<TutorCardProps> = ({ tutor }) => {
           >
             {t('tutor.schedule_lesson')}
           </button>
+          <button
+            onClick={handleMessageTutor}
+            className="mt-2 w-full text-sm text-orange-600 hover:text-orange-700 font-medium py-1.5"
+          >
+            {t('tutor.message_tutor')}
+          </button>
         </div>
       </div>
     </div>
@@ -0,0 +1,245 @@
+import React, { useEffect, useState } from 'react';
+import { toast } from 'react-hot-toast';
+import { useTranslation } from '../contexts/I18nContext';
+import { getErrorMessage, tutorService } from '../services/api';
+import { uploadFile } from '../services/upload.service';
+import { CredentialKind, CredentialStatus, TutorCredential } from '../types/tutor';
+
+const STATUS_CLASSES: Record<CredentialStatus, string> = {
+  pending: 'bg-yellow-100 text-yellow-800',
+  verified: 'bg-green-100 text-green-700',
+  rejected: 'bg-red-100 text-red-700',
+};
+
+interface CredentialForm {
+  kind: CredentialKind;
+  title: string;
+  issuer: string;
+  field_of_study: string;
+  year: string;
+}
+
+const emptyForm: CredentialForm = {
+  kind: 'certificate',
+  title: '',
+  issuer: '',
+  field_of_study: '',
+  year: '',
+};
+
+// The tutor's degrees and certificates with their proof documents. An admin reviews each of
+// them, and a verified one earns the tutor the verified badge.
+export const TutorCredentials: React.FC = () => {
+  const { t } = useTranslation();
+  const [credentials, setCredentials] = useState<TutorCredential[]>([]);
+  const [form, setForm] = useState<CredentialForm>(emptyForm);
+  const [document, setDocument] = useState<File | null>(null);
+  const [isSaving, setIsSaving] = useState(false);
+
+  useEffect(() => {
+    const loadCredentials = async () => {
+      try {
+        setCredentials(await tutorService.getCredentials());
+      } catch (error) {
+        console.error('Error loading credentials:', error);
+      }
+    };
+
+    loadCredentials();
+  }, []);
+
+  const handleChange = (field: keyof CredentialForm, value: string) => {
+    setForm(prev => ({ ...prev, [field]: value }));
+  };
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault();
+    if (!document) {
+      return;
+    }
+
+    setIsSaving(true);
+    try {
+      const upload = await uploadFile(document, 'credential');
+      const credential = await tutorService.addCredential({
+        kind: form.kind,
+        title: form.title.trim(),
+        issuer: form.issuer.trim(),
+        field_of_study: form.field_of_study.trim() || undefined,
+        year: form.year ? parseInt(form.year) : undefined,
+        document_id: upload.id,
+      });
+      setCredentials([...credentials, credential]);
+      setForm(emptyForm);
+      setDocument(null);
+      toast.success(t('components.tutor_credentials.submitted'));
+    } catch (error) {
+      toast.error(getErrorMessage(error));
+    } finally {
+      setIsSaving(false);
+    }
+  };
+
+  const handleDelete = async (credential: TutorCredential) => {
+    if (!window.confirm(t('components.tutor_credentials.confirm_delete'))) {
+      return;
+    }
+
+    try {
+      await tutorService.deleteCredential(credential.id);
+      setCredentials(credentials.filter(c => c.id !== credential.id));
+    } catch (error) {
+      toast.error(getErrorMessage(error));
+    }
+  };
+
+  const inputClass = 'mt-1 block w-full rounded-md border-gray-300 shadow-sm focus:border-orange-500 focus:ring-orange-500 sm:text-sm';
+
+  return (
+    <div className="bg-white shadow rounded-lg p-6 mt-6">
+      <h2 className="text-lg font-semibold text-gray-800 mb-1">{t('components.tutor_credentials.title')}</h2>
+      <p className="text-sm text-gray-500 mb-4">{t('components.tutor_credentials.description')}</p>
+
+      {credentials.length > 0 && (
+        <ul className="divide-y divide-gray-100 mb-6">
+          {credentials.map(credential => (
+            <li key={credential.id} className="py-3 flex items-start justify-between gap-4 text-sm">
+              <div className="min-w-0">
+                <p className="font-medium text-gray-800">
+                  {credential.title}
+                  {credential.year && <span className="text-gray-500 font-normal"> · {credential.year}</span>}
+                </p>
+                <p className="text-gray-600">
+                  {credential.issuer}
+                  {credential.field_of_study && `, ${credential.field_of_study}`}
+                </p>
+                {credential.document?.url && (
+                  <a
+                    href={credential.document.url}
+                    target="_blank"
+                    rel="noopener noreferrer"
+                    className="text-orange-600 hover:text-orange-700"
+                  >
+                    {credential.document.filename}
+                  </a>
+                )}
+                {credential.status === 'rejected' && credential.rejection_reason && (
+                  <p className="mt-1 text-red-600">
+                    {t('components.tutor_credentials.rejection_reason', { reason: credential.rejection_reason })}
+                  </p>
+                )}
+              </div>
+              <div className="flex items-center gap-3 whitespace-nowrap">
+                <span className={`px-2 py-0.5 rounded-full text-xs font-medium ${STATUS_CLASSES[credential.status]}`}>
+                  {t(`components.tutor_credentials.status.${credential.status}`)}
+                </span>
+                <button
+                  type="button"
+                  onClick={() => handleDelete(credential)}
+                  className="text-red-500 hover:text-red-700"
+                >
+                  {t('common.remove')}
+                </button>
+              </div>
+            </li>
+          ))}
+        </ul>
+      )}
+
+      <form onSubmit={handleSubmit} className="grid grid-cols-1 md:grid-cols-2 gap-4">
+        <div>
+          <label htmlFor="credential-kind" className="block text-sm font-medium text-gray-700">
+            {t('components.tutor_credentials.kind')}
+          </label>
+          <select
+            id="credential-kind"
+            value={form.kind}
+            onChange={(e) => handleChange('kind', e.target.value)}
+            className={inputClass}
+          >
+            <option value="certificate">{t('components.tutor_credentials.kinds.certificate')}</option>
+            <option value="education">{t('components.tutor_credentials.kinds.education')}</option>
+          </select>
+        </div>
+        <div>
+          <label htmlFor="credential-title" className="block text-sm font-medium text-gray-700">
+            {t('components.tutor_credentials.credential_title')}
+          </label>
+          <input
+            id="credential-title"
+            type="text"
+            required
+            maxLength={200}
+            value={form.title}
+            onChange={(e) => handleChange('title', e.target.value)}
+            placeholder={t('components.tutor_credentials.title_placeholder')}
+            className={inputClass}
+          />
+        </div>
+        <div>
+          <label htmlFor="credential-issuer" className="block text-sm font-medium text-gray-700">
+            {t('components.tutor_credentials.issuer')}
+          </label>
+          <input
+            id="credential-issuer"
+            type="text"
+            required
+            maxLength={200}
+            value={form.issuer}
+            onChange={(e) => handleChange('issuer', e.target.value)}
+            className={inputClass}
+          />
+        </div>
+        <div>
+          <label htmlFor="credential-field" className="block text-sm font-medium text-gray-700">
+            {t('tutor.education_field')}
+          </label>
+          <input
+            id="credential-field"
+            type="text"
+            maxLength={200}
+            value={form.field_of_study}
+            onChange={(e) => handleChange('field_of_study', e.target.value)}
+            className={inputClass}
+          />
+        </div>
+        <div>
+          <label htmlFor="credential-year" className="block text-sm font-medium text-gray-700">
+            {t('components.tutor_credentials.year')}
+          </label>
+          <input
+            id="credential-year"
+            type="number"
+            min={1950}
+            max={new Date().getFullYear()}
+            value={form.year}
+            onChange={(e) => handleChange('year', e.target.value)}
+            className={inputClass}
+          />
+        </div>
+        <div>
+          <label htmlFor="credential-document" className="block text-sm font-medium text-gray-700">
+            {t('components.tutor_credentials.document')}
+          </label>
+          <input
+            id="credential-document"
+            type="file"
+            required
+            accept="application/pdf,image/jpeg,image/png"
+            onChange={(e) => setDocument(e.target.files?.[0] || null)}
+            className="mt-1 block w-full text-sm text-gray-600"
+          />
+        </div>
+        <div className="md:col-span-2 flex justify-end">
+          <button
+            type="submit"
+            disabled={isSaving || !document}
+            className="inline-flex items-center px-4 py-2 border border-transparent text-sm font-medium rounded-md shadow-sm text-white bg-orange-600 hover:bg-orange-700 disabled:opacity-50"
+          >
+            {isSaving ? t('common.saving') : t('components.tutor_credentials.submit')}
+          </button>
+        </div>
+      </form>
+    </div>
+  );
+};
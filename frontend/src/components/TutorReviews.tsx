@@ -0,0 +1,241 @@
+import React, { useCallback, useEffect, useState } from 'react';
+import { toast } from 'react-hot-toast';
+import { useTranslation } from '../contexts/I18nContext';
+import { useAuth } from '../contexts/AuthContext';
+import { getErrorMessage } from '../services/api';
+import {
+  getTutorReviews,
+  replyToReview,
+  deleteReviewReply,
+  markReviewHelpful,
+  unmarkReviewHelpful,
+  reportReview,
+} from '../services/review.service';
+import { Review, ReviewPage, ReviewSort } from '../types/lesson';
+
+const PAGE_SIZE = 10;
+const ASPECTS = ['pronunciation_help', 'preparation', 'punctuality'] as const;
+
+interface TutorReviewsProps {
+  tutorId: number;
+}
+
+const TutorReviews: React.FC<TutorReviewsProps> = ({ tutorId }) => {
+  const { t } = useTranslation();
+  const { user } = useAuth();
+  const [page, setPage] = useState<ReviewPage | null>(null);
+  const [sort, setSort] = useState<ReviewSort>('recent');
+  const [offset, setOffset] = useState(0);
+  const [voted, setVoted] = useState<Set<number>>(new Set());
+  const [replyDrafts, setReplyDrafts] = useState<Record<number, string>>({});
+
+  const isOwnProfile = user?.id === tutorId;
+
+  const loadReviews = useCallback(async () => {
+    try {
+      setPage(await getTutorReviews(tutorId, sort, PAGE_SIZE, offset));
+    } catch (error) {
+      console.error('Error loading reviews:', error);
+    }
+  }, [tutorId, sort, offset]);
+
+  useEffect(() => {
+    loadReviews();
+  }, [loadReviews]);
+
+  const updateReview = (updated: Review) => {
+    setPage(current => current && {
+      ...current,
+      reviews: current.reviews.map(review => (review.id === updated.id ? updated : review)),
+    });
+  };
+
+  const handleSortChange = (value: ReviewSort) => {
+    setSort(value);
+    setOffset(0);
+  };
+
+  const handleHelpful = async (review: Review) => {
+    const hasVoted = voted.has(review.id);
+    try {
+      const count = hasVoted ? await unmarkReviewHelpful(review.id) : await markReviewHelpful(review.id);
+      const next = new Set(voted);
+      if (hasVoted) {
+        next.delete(review.id);
+      } else {
+        next.add(review.id);
+      }
+      setVoted(next);
+      updateReview({ ...review, helpful_count: count });
+    } catch (error) {
+      toast.error(getErrorMessage(error));
+    }
+  };
+
+  const handleReport = async (review: Review) => {
+    const reason = window.prompt(t('components.tutor_reviews.report_reason'));
+    if (!reason || !reason.trim()) return;
+    try {
+      await reportReview(review.id, reason.trim());
+      toast.success(t('components.tutor_reviews.reported'));
+    } catch (error) {
+      toast.error(getErrorMessage(error));
+    }
+  };
+
+  const handleReply = async (review: Review) => {
+    const text = (replyDrafts[review.id] ?? review.reply?.text ?? '').trim();
+    if (!text) return;
+    try {
+      updateReview(await replyToReview(review.id, text));
+      setReplyDrafts(drafts => {
+        const next = { ...drafts };
+        delete next[review.id];
+        return next;
+      });
+      toast.success(t('components.tutor_reviews.reply_saved'));
+    } catch (error) {
+      toast.error(getErrorMessage(error));
+    }
+  };
+
+  const handleDeleteReply = async (review: Review) => {
+    if (!window.confirm(t('components.tutor_reviews.confirm_delete_reply'))) return;
+    try {
+      await deleteReviewReply(review.id);
+      updateReview({ ...review, reply: undefined });
+    } catch (error) {
+      toast.error(getErrorMessage(error));
+    }
+  };
+
+  if (!page) {
+    return null;
+  }
+
+  const { summary } = page;
+
+  return (
+    <div className="mt-6">
+      <div className="flex items-center justify-between mb-2">
+        <h4 className="text-lg font-semibold text-gray-800">
+          {t('components.tutor_reviews.title')} ({summary.reviews_count})
+        </h4>
+        {summary.reviews_count > 1 && (
+          <select
+            value={sort}
+            onChange={(e) => handleSortChange(e.target.value as ReviewSort)}
+            className="px-2 py-1 text-sm border border-gray-300 rounded-md focus:outline-none focus:ring-2 focus:ring-orange-500"
+          >
+            <option value="recent">{t('components.tutor_reviews.sort_recent')}</option>
+            <option value="helpful">{t('components.tutor_reviews.sort_helpful')}</option>
+          </select>
+        )}
+      </div>
+
+      {summary.reviews_count === 0 ? (
+        <p className="text-gray-500 text-sm">{t('components.tutor_reviews.no_reviews')}</p>
+      ) : (
+        <>
+          <div className="grid grid-cols-1 md:grid-cols-3 gap-2 mb-4">
+            {ASPECTS.filter(aspect => summary.aspects[aspect] !== undefined).map(aspect => (
+              <div key={aspect} className="bg-gray-50 p-2 rounded text-sm">
+                <div className="text-gray-600">{t(`components.lesson_card.aspects.${aspect}`)}</div>
+                <div className="font-semibold">{summary.aspects[aspect]!.toFixed(1)} / 5</div>
+              </div>
+            ))}
+          </div>
+
+          <ul className="space-y-4">
+            {page.reviews.map(review => (
+              <li key={review.id} className="border-b pb-4">
+                <div className="flex items-center justify-between">
+                  <span className="font-medium">
+                    {review.reviewer?.first_name} {review.reviewer?.last_name}
+                  </span>
+                  <span className="text-sm text-gray-500">{new Date(review.created_at).toLocaleDateString()}</span>
+                </div>
+                <div className="text-yellow-500 text-sm">
+                  {'★'.repeat(review.rating)}
+                  <span className="text-gray-300">{'★'.repeat(5 - review.rating)}</span>
+                </div>
+                {review.comment && <p className="mt-1 text-gray-700 whitespace-pre-line">{review.comment}</p>}
+
+                {review.reply && (
+                  <div className="mt-2 ml-4 p-2 bg-orange-50 rounded text-sm">
+                    <div className="font-medium text-gray-700">{t('components.tutor_reviews.tutor_reply')}</div>
+                    <p className="text-gray-700 whitespace-pre-line">{review.reply.text}</p>
+                  </div>
+                )}
+
+                <div className="mt-2 flex items-center gap-4 text-sm">
+                  {user && user.id !== review.reviewer_id && (
+                    <button
+                      type="button"
+                      onClick={() => handleHelpful(review)}
+                      className={voted.has(review.id) ? 'text-orange-600 font-medium' : 'text-gray-500 hover:text-orange-600'}
+                    >
+                      {t('components.tutor_reviews.helpful')} ({review.helpful_count})
+                    </button>
+                  )}
+                  {user && user.id !== review.reviewer_id && (
+                    <button type="button" onClick={() => handleReport(review)} className="text-gray-500 hover:text-red-600">
+                      {t('components.tutor_reviews.report')}
+                    </button>
+                  )}
+                  {isOwnProfile && review.reply && (
+                    <button type="button" onClick={() => handleDeleteReply(review)} className="text-gray-500 hover:text-red-600">
+                      {t('components.tutor_reviews.delete_reply')}
+                    </button>
+                  )}
+                </div>
+
+                {isOwnProfile && (
+                  <div className="mt-2 flex gap-2">
+                    <input
+                      type="text"
+                      value={replyDrafts[review.id] ?? review.reply?.text ?? ''}
+                      onChange={(e) => setReplyDrafts({ ...replyDrafts, [review.id]: e.target.value })}
+                      placeholder={t('components.tutor_reviews.reply_placeholder')}
+                      className="flex-1 px-2 py-1 text-sm border border-gray-300 rounded-md focus:outline-none focus:ring-2 focus:ring-orange-500"
+                    />
+                    <button
+                      type="button"
+                      onClick={() => handleReply(review)}
+                      className="px-3 py-1 text-sm text-white bg-orange-600 rounded-md hover:bg-orange-700"
+                    >
+                      {t('components.tutor_reviews.reply')}
+                    </button>
+                  </div>
+                )}
+              </li>
+            ))}
+          </ul>
+
+          {page.total > PAGE_SIZE && (
+            <div className="mt-4 flex justify-between">
+              <button
+                type="button"
+                disabled={offset === 0}
+                onClick={() => setOffset(Math.max(0, offset - PAGE_SIZE))}
+                className="text-sm text-orange-600 disabled:text-gray-300"
+              >
+                {t('components.tutor_reviews.previous')}
+              </button>
+              <button
+                type="button"
+                disabled={offset + PAGE_SIZE >= page.total}
+                onClick={() => setOffset(offset + PAGE_SIZE)}
+                className="text-sm text-orange-600 disabled:text-gray-300"
+              >
+                {t('components.tutor_reviews.next')}
+              </button>
+            </div>
+          )}
+        </>
+      )}
+    </div>
+  );
+};
+
+export default TutorReviews;
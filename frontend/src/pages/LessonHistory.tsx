@@ -0,0 +1,362 @@
+import React, { useCallback, useEffect, useState } from 'react';
+import { useParams } from 'react-router-dom';
+import { toast } from 'react-hot-toast';
+import { useAuth } from '../contexts/AuthContext';
+import { useTranslation } from '../contexts/I18nContext';
+import { getErrorMessage } from '../services/api';
+import {
+  addHomework,
+  deleteHomework,
+  getStudentHistory,
+  getTutorHistory,
+  saveLessonNotes,
+  saveStudentFeedback,
+  setHomeworkDone,
+} from '../services/lessonNotes.service';
+import { LessonHistory as History, LessonHistoryEntry, LessonNotesRequest } from '../types/lessonNotes';
+
+// The notes form edits lists as text: one entry per line, "term - translation" and "original -> corrected"
+interface NotesDraft {
+  summary: string;
+  topics: string;
+  vocabulary: string;
+  corrections: string;
+  private_notes: string;
+}
+
+const toDraft = (entry: LessonHistoryEntry): NotesDraft => ({
+  summary: entry.notes?.summary ?? '',
+  topics: (entry.notes?.topics ?? []).join('\n'),
+  vocabulary: (entry.notes?.vocabulary ?? [])
+    .map(item => (item.translation ? `${item.term} - ${item.translation}` : item.term))
+    .join('\n'),
+  corrections: (entry.notes?.corrections ?? []).map(c => `${c.original} -> ${c.corrected}`).join('\n'),
+  private_notes: entry.notes?.private_notes ?? '',
+});
+
+const fromDraft = (draft: NotesDraft): LessonNotesRequest => {
+  const lines = (text: string) => text.split('\n').map(line => line.trim()).filter(Boolean);
+  return {
+    summary: draft.summary,
+    topics: lines(draft.topics),
+    vocabulary: lines(draft.vocabulary).map(line => {
+      const [term, ...translation] = line.split(' - ');
+      return { term: term.trim(), translation: translation.join(' - ').trim() || undefined };
+    }),
+    corrections: lines(draft.corrections).map(line => {
+      const [original, ...corrected] = line.split('->');
+      return { original: original.trim(), corrected: corrected.join('->').trim() };
+    }),
+    private_notes: draft.private_notes,
+  };
+};
+
+const inputClass =
+  'mt-1 block w-full rounded-md border-gray-300 shadow-sm focus:border-orange-500 focus:ring-orange-500 sm:text-sm';
+
+// Lessons of the current user with another user, with notes, homework and feedback.
+// Tutors write notes, assign homework and give private feedback here; students mark homework as done.
+export const LessonHistory = () => {
+  const { t } = useTranslation();
+  const { user } = useAuth();
+  const { userId } = useParams();
+  const otherId = Number(userId);
+  const isTutor = user?.role === 'tutor';
+
+  const [history, setHistory] = useState<History | null>(null);
+  const [editing, setEditing] = useState<number | null>(null);
+  const [draft, setDraft] = useState<NotesDraft | null>(null);
+  const [homeworkDraft, setHomeworkDraft] = useState<Record<number, { description: string; due: string }>>({});
+  const [feedbackDraft, setFeedbackDraft] = useState<{ lessonId: number; rating: number; comment: string } | null>(null);
+
+  const load = useCallback(async () => {
+    try {
+      setHistory(isTutor ? await getStudentHistory(otherId) : await getTutorHistory(otherId));
+    } catch (error) {
+      toast.error(getErrorMessage(error));
+    }
+  }, [isTutor, otherId]);
+
+  useEffect(() => {
+    if (otherId) load();
+  }, [otherId, load]);
+
+  const handleSaveNotes = async (lessonId: number) => {
+    if (!draft) return;
+    try {
+      await saveLessonNotes(lessonId, fromDraft(draft));
+      setEditing(null);
+      toast.success(t('pages.lesson_history.notes_saved'));
+      load();
+    } catch (error) {
+      toast.error(getErrorMessage(error));
+    }
+  };
+
+  const handleAddHomework = async (e: React.FormEvent, lessonId: number) => {
+    e.preventDefault();
+    const homework = homeworkDraft[lessonId];
+    if (!homework?.description.trim()) return;
+    try {
+      await addHomework(lessonId, {
+        description: homework.description,
+        due_at: homework.due ? new Date(homework.due).toISOString() : undefined,
+      });
+      setHomeworkDraft(current => ({ ...current, [lessonId]: { description: '', due: '' } }));
+      load();
+    } catch (error) {
+      toast.error(getErrorMessage(error));
+    }
+  };
+
+  const handleDeleteHomework = async (homeworkId: number) => {
+    if (!window.confirm(t('pages.lesson_history.confirm_delete_homework'))) return;
+    try {
+      await deleteHomework(homeworkId);
+      load();
+    } catch (error) {
+      toast.error(getErrorMessage(error));
+    }
+  };
+
+  const handleSaveFeedback = async () => {
+    if (!feedbackDraft || !feedbackDraft.rating) return;
+    try {
+      await saveStudentFeedback(feedbackDraft.lessonId, {
+        student_id: otherId,
+        rating: feedbackDraft.rating,
+        comment: feedbackDraft.comment,
+      });
+      setFeedbackDraft(null);
+      toast.success(t('pages.lesson_history.feedback_saved'));
+      load();
+    } catch (error) {
+      toast.error(getErrorMessage(error));
+    }
+  };
+
+  const handleToggleHomework = async (homeworkId: number, done: boolean) => {
+    try {
+      await setHomeworkDone(homeworkId, done);
+      load();
+    } catch (error) {
+      toast.error(getErrorMessage(error));
+    }
+  };
+
+  if (!history) return null;
+
+  return (
+    <div className="container mx-auto px-4 py-8 max-w-4xl">
+      <h1 className="text-3xl font-bold mb-6">{t('pages.lesson_history.title')}</h1>
+
+      {history.lessons.length === 0 && <p className="text-gray-500">{t('pages.lesson_history.no_lessons')}</p>}
+
+      <div className="space-y-6">
+        {history.lessons.map(entry => (
+          <div key={entry.lesson_id} className="bg-white shadow rounded-lg p-5">
+            <div className="flex justify-between items-center mb-3">
+              <h2 className="text-lg font-semibold">
+                {entry.language} · {new Date(entry.start_time).toLocaleString()}
+              </h2>
+              {isTutor && editing !== entry.lesson_id && (
+                <button
+                  onClick={() => {
+                    setEditing(entry.lesson_id);
+                    setDraft(toDraft(entry));
+                  }}
+                  className="text-sm text-orange-600 hover:text-orange-700"
+                >
+                  {t('pages.lesson_history.edit_notes')}
+                </button>
+              )}
+            </div>
+
+            {editing === entry.lesson_id && draft ? (
+              <div className="space-y-3">
+                {(['summary', 'topics', 'vocabulary', 'corrections', 'private_notes'] as const).map(field => (
+                  <label key={field} className="block text-sm font-medium text-gray-700">
+                    {t(`pages.lesson_history.fields.${field}`)}
+                    <textarea
+                      rows={3}
+                      value={draft[field]}
+                      onChange={e => setDraft({ ...draft, [field]: e.target.value })}
+                      className={inputClass}
+                    />
+                  </label>
+                ))}
+                <div className="flex justify-end space-x-2">
+                  <button onClick={() => setEditing(null)} className="px-4 py-2 text-sm text-gray-600">
+                    {t('common.cancel')}
+                  </button>
+                  <button
+                    onClick={() => handleSaveNotes(entry.lesson_id)}
+                    className="px-4 py-2 text-sm font-medium text-white bg-orange-600 rounded-md hover:bg-orange-700"
+                  >
+                    {t('common.save')}
+                  </button>
+                </div>
+              </div>
+            ) : entry.notes ? (
+              <div className="space-y-2 text-sm text-gray-700">
+                {entry.notes.summary && <p className="whitespace-pre-wrap">{entry.notes.summary}</p>}
+                {entry.notes.topics.length > 0 && (
+                  <p>
+                    <span className="font-medium">{t('pages.lesson_history.fields.topics')}:</span>{' '}
+                    {entry.notes.topics.join(', ')}
+                  </p>
+                )}
+                {entry.notes.vocabulary.length > 0 && (
+                  <ul className="list-disc list-inside">
+                    {entry.notes.vocabulary.map((item, i) => (
+                      <li key={i}>
+                        <span className="font-medium">{item.term}</span>
+                        {item.translation && ` — ${item.translation}`}
+                      </li>
+                    ))}
+                  </ul>
+                )}
+                {entry.notes.corrections.length > 0 && (
+                  <ul className="list-disc list-inside">
+                    {entry.notes.corrections.map((correction, i) => (
+                      <li key={i}>
+                        <span className="line-through text-red-600">{correction.original}</span> →{' '}
+                        <span className="text-green-700">{correction.corrected}</span>
+                      </li>
+                    ))}
+                  </ul>
+                )}
+                {entry.notes.private_notes && (
+                  <p className="bg-gray-50 p-2 rounded whitespace-pre-wrap">
+                    <span className="font-medium">{t('pages.lesson_history.fields.private_notes')}:</span>{' '}
+                    {entry.notes.private_notes}
+                  </p>
+                )}
+              </div>
+            ) : (
+              <p className="text-sm text-gray-500">{t('pages.lesson_history.no_notes')}</p>
+            )}
+
+            <h3 className="mt-4 mb-2 text-sm font-semibold text-gray-700">{t('pages.lesson_history.homework')}</h3>
+            <ul className="space-y-1 text-sm">
+              {entry.homework.map(homework => (
+                <li key={homework.id} className="flex items-center space-x-2">
+                  <input
+                    type="checkbox"
+                    checked={!!homework.completed_at}
+                    disabled={isTutor}
+                    onChange={e => handleToggleHomework(homework.id, e.target.checked)}
+                    className="h-4 w-4 rounded border-gray-300 text-orange-600 focus:ring-orange-500"
+                  />
+                  <span className={homework.completed_at ? 'line-through text-gray-500' : ''}>{homework.description}</span>
+                  {homework.due_at && (
+                    <span className="text-xs text-gray-500">
+                      {t('pages.lesson_history.due', { date: new Date(homework.due_at).toLocaleDateString() })}
+                    </span>
+                  )}
+                  {isTutor && (
+                    <button onClick={() => handleDeleteHomework(homework.id)} className="text-xs text-red-600">
+                      {t('common.delete')}
+                    </button>
+                  )}
+                </li>
+              ))}
+            </ul>
+
+            {isTutor && (
+              <form onSubmit={e => handleAddHomework(e, entry.lesson_id)} className="mt-2 flex space-x-2">
+                <input
+                  value={homeworkDraft[entry.lesson_id]?.description ?? ''}
+                  onChange={e =>
+                    setHomeworkDraft(current => ({
+                      ...current,
+                      [entry.lesson_id]: { due: '', ...current[entry.lesson_id], description: e.target.value },
+                    }))
+                  }
+                  placeholder={t('pages.lesson_history.homework_placeholder')}
+                  className={`${inputClass} flex-1`}
+                />
+                <input
+                  type="date"
+                  value={homeworkDraft[entry.lesson_id]?.due ?? ''}
+                  onChange={e =>
+                    setHomeworkDraft(current => ({
+                      ...current,
+                      [entry.lesson_id]: { description: '', ...current[entry.lesson_id], due: e.target.value },
+                    }))
+                  }
+                  className={inputClass + ' w-40'}
+                />
+                <button
+                  type="submit"
+                  className="px-3 py-2 text-sm font-medium text-white bg-orange-600 rounded-md hover:bg-orange-700"
+                >
+                  {t('pages.lesson_history.add_homework')}
+                </button>
+              </form>
+            )}
+
+            <h3 className="mt-4 mb-2 text-sm font-semibold text-gray-700">{t('pages.lesson_history.feedback')}</h3>
+            {feedbackDraft?.lessonId === entry.lesson_id ? (
+              <div className="space-y-2">
+                <div className="flex items-center">
+                  {[1, 2, 3, 4, 5].map(star => (
+                    <button
+                      key={star}
+                      type="button"
+                      onClick={() => setFeedbackDraft({ ...feedbackDraft, rating: star })}
+                      className={`text-xl ${feedbackDraft.rating >= star ? 'text-yellow-500' : 'text-gray-300'}`}
+                    >
+                      ★
+                    </button>
+                  ))}
+                </div>
+                <textarea
+                  rows={3}
+                  value={feedbackDraft.comment}
+                  onChange={e => setFeedbackDraft({ ...feedbackDraft, comment: e.target.value })}
+                  placeholder={t('pages.lesson_history.feedback_placeholder')}
+                  className={inputClass}
+                />
+                <div className="flex justify-end space-x-2">
+                  <button onClick={() => setFeedbackDraft(null)} className="px-4 py-2 text-sm text-gray-600">
+                    {t('common.cancel')}
+                  </button>
+                  <button
+                    onClick={handleSaveFeedback}
+                    disabled={!feedbackDraft.rating}
+                    className="px-4 py-2 text-sm font-medium text-white bg-orange-600 rounded-md hover:bg-orange-700 disabled:opacity-50"
+                  >
+                    {t('common.save')}
+                  </button>
+                </div>
+              </div>
+            ) : entry.feedback ? (
+              <div className="text-sm text-gray-700">
+                <span className="text-yellow-500">{'★'.repeat(entry.feedback.rating)}</span>
+                <span className="text-gray-300">{'★'.repeat(5 - entry.feedback.rating)}</span>
+                {entry.feedback.comment && <p className="mt-1 whitespace-pre-wrap">{entry.feedback.comment}</p>}
+              </div>
+            ) : (
+              <p className="text-sm text-gray-500">{t('pages.lesson_history.no_feedback')}</p>
+            )}
+            {isTutor && feedbackDraft?.lessonId !== entry.lesson_id && new Date(entry.end_time) < new Date() && (
+              <button
+                onClick={() =>
+                  setFeedbackDraft({
+                    lessonId: entry.lesson_id,
+                    rating: entry.feedback?.rating ?? 0,
+                    comment: entry.feedback?.comment ?? '',
+                  })
+                }
+                className="mt-1 text-sm text-orange-600 hover:text-orange-700"
+              >
+                {entry.feedback ? t('pages.lesson_history.edit_feedback') : t('pages.lesson_history.give_feedback')}
+              </button>
+            )}
+          </div>
+        ))}
+      </div>
+    </div>
+  );
+};
@@ -3,7 +3,7 @@ import { useParams, useNavigate } from 'react-router-dom';
 import { useTranslation } from '../contexts/I18nContext';
 import { useAuth } from '../contexts/AuthContext';
 import { joinRoom } from '../services/videoRoom.service';
-import { getLessonById } from '../services/lesson.service';
+import { createRoomTicket, getLessonById } from '../services/lesson.service';
 import { Lesson } from '../types/lesson';
 import { toast } from 'react-hot-toast';
 import { format } from 'date-fns';
@@ -22,6 +22,7 @@ const LessonRoom: React.FC = () => {
   const [chatConnected, setChatConnected] = useState<boolean>(false);
   const [chatVisible, setChatVisible] = useState<boolean>(true);
   const [lesson, setLesson] = useState<Lesson | null>(null);
+  const [roomTicket, setRoomTicket] = useState<string | null>(null);
   
   // Refs for video and chat
   const videoRef = useRef<HTMLIFrameElement>(null);
@@ -46,8 +47,12 @@ const LessonRoom: React.FC = () => {
       try {
         setConnecting(true);
         
-        // Create/join the room
-        await joinRoom(lessonId);
+        // Create/join the room. The ticket is only valid for a couple of minutes, enough to open the room.
+        const [ticket] = await Promise.all([
+          createRoomTicket(Number(lessonId)),
+          joinRoom(lessonId),
+        ]);
+        setRoomTicket(ticket.ticket);
         
         // Set connected states
         setVideoConnected(true);
@@ -123,6 +128,8 @@ const LessonRoom: React.FC = () => {
 
   // API URL base
   const videoApiUrl = process.env.REACT_APP_FRONTEND_URL || 'https://192.168.0.100';
+  // The video service checks the ticket with the backend
+  const roomQuery = roomTicket ? `?ticket=${encodeURIComponent(roomTicket)}` : '';
   
   // Create title with tutor info, language, and end time
   const lessonTitle = lesson ? (
@@ -169,7 +176,7 @@ const LessonRoom: React.FC = () => {
           {videoConnected ? (
             <iframe
               ref={videoRef}
-              src={`${videoApiUrl}/api/room/${lessonId}/video`}
+              src={`${videoApiUrl}/api/room/${lessonId}/video${roomQuery}`}
               className="w-full h-full border-0"
               allow="camera; microphone"
               title="Video Room"
@@ -209,7 +216,7 @@ const LessonRoom: React.FC = () => {
           {chatConnected ? (
             <iframe
               ref={chatRef}
-              src={`${videoApiUrl}/api/room/${lessonId}/chat`}
+              src={`${videoApiUrl}/api/room/${lessonId}/chat${roomQuery}`}
               className="w-full h-full border-0"
               title="Chat Room"
             ></iframe>
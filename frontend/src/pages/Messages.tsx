@@ -0,0 +1,280 @@
+import React, { useCallback, useEffect, useState } from 'react';
+import { useNavigate, useParams, useSearchParams } from 'react-router-dom';
+import { toast } from 'react-hot-toast';
+import { useAuth } from '../contexts/AuthContext';
+import { useTranslation } from '../contexts/I18nContext';
+import { getErrorMessage } from '../services/api';
+import {
+  blockUser,
+  getConversation,
+  getConversations,
+  getMessages,
+  markConversationRead,
+  reportConversation,
+  sendMessage,
+  startConversation,
+} from '../services/message.service';
+import { Conversation, Message } from '../types/message';
+import { AppNotification, NOTIFICATION_EVENT } from '../types/notification';
+import { User } from '../types';
+
+const userName = (user?: User) => {
+  if (!user) return '';
+  const name = `${user.first_name || ''} ${user.last_name || ''}`.trim();
+  return name || user.username;
+};
+
+export const Messages = () => {
+  const { t } = useTranslation();
+  const { user } = useAuth();
+  const navigate = useNavigate();
+  const { conversationId } = useParams();
+  const [searchParams] = useSearchParams();
+  const recipientId = Number(searchParams.get('to')) || null;
+  const activeId = Number(conversationId) || null;
+
+  const [conversations, setConversations] = useState<Conversation[]>([]);
+  const [active, setActive] = useState<Conversation | null>(null);
+  const [messages, setMessages] = useState<Message[]>([]);
+  const [hasMore, setHasMore] = useState(false);
+  const [draft, setDraft] = useState('');
+  const [sending, setSending] = useState(false);
+
+  const otherUser = (conversation: Conversation) =>
+    conversation.student_id === user?.id ? conversation.tutor : conversation.student;
+
+  const loadConversations = useCallback(async () => {
+    try {
+      const list = await getConversations();
+      setConversations(list.conversations);
+      return list.conversations;
+    } catch (error) {
+      toast.error(getErrorMessage(error));
+      return [];
+    }
+  }, []);
+
+  // Loads the newest messages of the open conversation and marks them as read
+  const loadThread = useCallback(async (id: number) => {
+    try {
+      const [conversation, page] = await Promise.all([getConversation(id), getMessages(id)]);
+      setActive(conversation);
+      setMessages([...page.messages].reverse());
+      setHasMore(page.has_more);
+      await markConversationRead(id);
+      setConversations(current => current.map(c => (c.id === id ? { ...c, unread_count: 0 } : c)));
+    } catch (error) {
+      toast.error(getErrorMessage(error));
+    }
+  }, []);
+
+  useEffect(() => {
+    loadConversations().then(list => {
+      // Open the existing conversation with the user linked from a tutor card
+      if (!activeId && recipientId) {
+        const existing = list.find(c => c.student_id === recipientId || c.tutor_id === recipientId);
+        if (existing) navigate(`/messages/${existing.id}`, { replace: true });
+      }
+    });
+  }, [loadConversations, activeId, recipientId, navigate]);
+
+  useEffect(() => {
+    if (activeId) {
+      loadThread(activeId);
+    } else {
+      setActive(null);
+      setMessages([]);
+      setHasMore(false);
+    }
+  }, [activeId, loadThread]);
+
+  // New messages arrive as notifications
+  useEffect(() => {
+    const handleNotification = (event: Event) => {
+      const notification = (event as CustomEvent<AppNotification>).detail;
+      if (notification.type !== 'message_received') return;
+      loadConversations();
+      if (activeId && notification.data?.conversation_id === activeId) {
+        loadThread(activeId);
+      }
+    };
+
+    window.addEventListener(NOTIFICATION_EVENT, handleNotification);
+    return () => window.removeEventListener(NOTIFICATION_EVENT, handleNotification);
+  }, [activeId, loadConversations, loadThread]);
+
+  const loadOlder = async () => {
+    if (!activeId || messages.length === 0) return;
+    try {
+      const page = await getMessages(activeId, messages[0].id);
+      setMessages(current => [...[...page.messages].reverse(), ...current]);
+      setHasMore(page.has_more);
+    } catch (error) {
+      toast.error(getErrorMessage(error));
+    }
+  };
+
+  const handleSend = async (e: React.FormEvent) => {
+    e.preventDefault();
+    if (!draft.trim()) return;
+
+    try {
+      setSending(true);
+      if (activeId) {
+        const message = await sendMessage(activeId, { body: draft });
+        setMessages(current => [...current, message]);
+        loadConversations();
+      } else if (recipientId) {
+        const result = await startConversation(recipientId, { body: draft });
+        navigate(`/messages/${result.conversation.id}`, { replace: true });
+      }
+      setDraft('');
+    } catch (error) {
+      toast.error(getErrorMessage(error));
+    } finally {
+      setSending(false);
+    }
+  };
+
+  const handleBlock = async () => {
+    if (!active || !user) return;
+    const other = otherUser(active);
+    if (!window.confirm(t('pages.messages.confirm_block', { name: userName(other) }))) return;
+    try {
+      await blockUser(active.student_id === user.id ? active.tutor_id : active.student_id);
+      toast.success(t('pages.messages.blocked'));
+    } catch (error) {
+      toast.error(getErrorMessage(error));
+    }
+  };
+
+  const handleReport = async () => {
+    if (!active) return;
+    const reason = window.prompt(t('pages.messages.report_reason'));
+    if (!reason || !reason.trim()) return;
+    try {
+      await reportConversation(active.id, reason.trim());
+      toast.success(t('pages.messages.reported'));
+    } catch (error) {
+      toast.error(getErrorMessage(error));
+    }
+  };
+
+  // The last message the current user sent that the other side has read
+  const lastReadId = [...messages].reverse().find(m => m.sender_id === user?.id && m.read_at)?.id;
+
+  return (
+    <div className="container mx-auto px-4 py-8 max-w-6xl">
+      <h1 className="text-3xl font-bold mb-6">{t('pages.messages.title')}</h1>
+
+      <div className="bg-white shadow rounded-lg flex h-[70vh] overflow-hidden">
+        {/* Conversation list */}
+        <aside className="w-1/3 border-r border-gray-200 overflow-y-auto">
+          {conversations.length === 0 && (
+            <p className="p-4 text-sm text-gray-500">{t('pages.messages.no_conversations')}</p>
+          )}
+          {conversations.map(conversation => (
+            <button
+              key={conversation.id}
+              onClick={() => navigate(`/messages/${conversation.id}`)}
+              className={`w-full text-left px-4 py-3 border-b border-gray-100 hover:bg-orange-50 ${
+                conversation.id === activeId ? 'bg-orange-50' : ''
+              }`}
+            >
+              <div className="flex justify-between items-center">
+                <span className="font-medium text-gray-900">{userName(otherUser(conversation))}</span>
+                {conversation.unread_count > 0 && (
+                  <span className="ml-2 rounded-full bg-orange-500 px-2 text-xs text-white">
+                    {conversation.unread_count}
+                  </span>
+                )}
+              </div>
+              <p className="text-sm text-gray-500 truncate">{conversation.last_message?.body}</p>
+            </button>
+          ))}
+        </aside>
+
+        {/* Thread */}
+        <section className="flex-1 flex flex-col">
+          {active && (
+            <header className="flex justify-between items-center px-4 py-3 border-b border-gray-200">
+              <span className="font-semibold">{userName(otherUser(active))}</span>
+              <div className="space-x-3 text-sm">
+                <button onClick={handleReport} className="text-gray-500 hover:text-gray-700">
+                  {t('pages.messages.report')}
+                </button>
+                <button onClick={handleBlock} className="text-red-600 hover:text-red-700">
+                  {t('pages.messages.block')}
+                </button>
+              </div>
+            </header>
+          )}
+
+          <div className="flex-1 overflow-y-auto p-4 space-y-3">
+            {!activeId && !recipientId && (
+              <p className="text-sm text-gray-500">{t('pages.messages.select_conversation')}</p>
+            )}
+            {!activeId && recipientId && (
+              <p className="text-sm text-gray-500">{t('pages.messages.new_conversation')}</p>
+            )}
+            {hasMore && (
+              <div className="text-center">
+                <button onClick={loadOlder} className="text-sm text-orange-600 hover:text-orange-700">
+                  {t('pages.messages.load_older')}
+                </button>
+              </div>
+            )}
+            {messages.map(message => {
+              const mine = message.sender_id === user?.id;
+              return (
+                <div key={message.id} className={`flex ${mine ? 'justify-end' : 'justify-start'}`}>
+                  <div
+                    className={`max-w-[70%] rounded-lg px-3 py-2 text-sm ${
+                      mine ? 'bg-orange-500 text-white' : 'bg-gray-100 text-gray-900'
+                    }`}
+                  >
+                    {message.body && <p className="whitespace-pre-wrap">{message.body}</p>}
+                    {message.attachments.map(attachment => (
+                      <a
+                        key={attachment.url}
+                        href={attachment.url}
+                        target="_blank"
+                        rel="noopener noreferrer"
+                        className="block underline"
+                      >
+                        {attachment.name}
+                      </a>
+                    ))}
+                    <p className={`mt-1 text-xs ${mine ? 'text-orange-100' : 'text-gray-500'}`}>
+                      {new Date(message.created_at).toLocaleString()}
+                      {message.id === lastReadId && ` · ${t('pages.messages.read')}`}
+                    </p>
+                  </div>
+                </div>
+              );
+            })}
+          </div>
+
+          {(activeId || recipientId) && (
+            <form onSubmit={handleSend} className="border-t border-gray-200 p-3 flex space-x-2">
+              <textarea
+                value={draft}
+                onChange={e => setDraft(e.target.value)}
+                rows={2}
+                placeholder={t('pages.messages.placeholder')}
+                className="flex-1 rounded-md border-gray-300 shadow-sm focus:border-orange-500 focus:ring-orange-500 sm:text-sm"
+              />
+              <button
+                type="submit"
+                disabled={sending || !draft.trim()}
+                className="self-end py-2 px-4 rounded-md text-sm font-medium text-white bg-orange-600 hover:bg-orange-700 disabled:opacity-50"
+              >
+                {t('pages.messages.send')}
+              </button>
+            </form>
+          )}
+        </section>
+      </div>
+    </div>
+  );
+};
@@ -6,6 +6,7 @@ import LessonCard from '../components/LessonCard';
 import { toast } from 'react-hot-toast';
 import { User, UserRole } from '../types';
 import { useTranslation } from '../contexts/I18nContext';
+import { AppNotification, NOTIFICATION_EVENT } from '../types/notification';
 
 // Filter types
 type FilterType = 'all' | 'scheduled' | 'past' | 'cancelled';
@@ -34,6 +35,19 @@ const MyLessons: React.FC = () => {
     fetchLessons(activeFilter);
   }, [activeFilter]);
 
+  // Refresh the list when a lesson is booked, cancelled or rescheduled instead of polling
+  useEffect(() => {
+    const handleNotification = (event: Event) => {
+      const notification = (event as CustomEvent<AppNotification>).detail;
+      if (['lesson_booked', 'lesson_cancelled', 'lesson_rescheduled'].includes(notification.type)) {
+        fetchLessons(activeFilter);
+      }
+    };
+
+    window.addEventListener(NOTIFICATION_EVENT, handleNotification);
+    return () => window.removeEventListener(NOTIFICATION_EVENT, handleNotification);
+  }, [activeFilter]);
+
   const fetchLessons = async (filter: FilterType) => {
     setLoading(true);
     try {
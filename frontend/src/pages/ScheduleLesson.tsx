@@ -1,11 +1,12 @@
 import React, { useState, useEffect, useMemo } from 'react';
 import { useParams, Link, useNavigate } from 'react-router-dom';
 import { useTranslation } from '../contexts/I18nContext';
-import { getTutorProfile, getTutorAvailabilities, bookLesson } from '../services/tutor.service';
-import { TutorProfile, TutorAvailability, AvailableTimeSlot } from '../types/tutor';
+import { getTutorProfile, getTutorAvailabilities, getTutorBusyTimes, bookLesson } from '../services/tutor.service';
+import { TutorProfile, TutorAvailability, AvailableTimeSlot, BusyTime } from '../types/tutor';
 import { LessonBookingRequest } from '../types/lesson';
 import { formatDateToString } from '../utils/availability';
 import { envConfig } from '../config/env';
+import TutorReviews from '../components/TutorReviews';
 
 // Constants for lesson durations in minutes
 const LESSON_DURATIONS = [30, 60, 90];
@@ -26,6 +27,7 @@ export const ScheduleLesson: React.FC = () => {
   // State variables
   const [tutor, setTutor] = useState<TutorProfile | null>(null);
   const [availabilities, setAvailabilities] = useState<TutorAvailability[]>([]);
+  const [busyTimes, setBusyTimes] = useState<BusyTime[]>([]);
   const [isLoading, setIsLoading] = useState<boolean>(true);
   const [error, setError] = useState<string | null>(null);
   
@@ -46,13 +48,16 @@ export const ScheduleLesson: React.FC = () => {
       setError(null);
       
       try {
-        const [tutorData, availabilityData] = await Promise.all([
-          getTutorProfile(tutorId),
-          getTutorAvailabilities(tutorId)
+        const [tutorData, availabilityData, busyTimesData] = await Promise.all([
+          getTutorProfile(tutorId, true),
+          getTutorAvailabilities(tutorId),
+          // Busy times only narrow down the options, so the page still works without them
+          getTutorBusyTimes(tutorId).catch(() => [])
         ]);
         
         setTutor(tutorData);
         setAvailabilities(availabilityData);
+        setBusyTimes(busyTimesData);
       } catch (err) {
         setError('Failed to load tutor data');
         console.error(err);
@@ -472,8 +477,15 @@ export const ScheduleLesson: React.FC = () => {
       console.log('Cannot generate time options - missing date or duration');
       return [];
     }
-    return generateTimeOptions(availabilities, selectedDate);
-  }, [selectedDate, duration, availabilities]);
+    // Skip times that overlap the tutor's external calendar events
+    return generateTimeOptions(availabilities, selectedDate).filter(time => {
+      const start = time.getTime();
+      const end = start + duration * 60 * 1000;
+      return !busyTimes.some(busy =>
+        new Date(busy.start_time).getTime() < end && new Date(busy.end_time).getTime() > start
+      );
+    });
+  }, [selectedDate, duration, availabilities, busyTimes]);
   
   // Handle time selection
   const handleTimeSelect = (time: Date) => {
@@ -638,7 +650,9 @@ export const ScheduleLesson: React.FC = () => {
                     <div key={index} className="bg-gray-50 p-3 rounded">
                       <div className="font-medium">{edu.degree} - {edu.institution}</div>
                       <div className="text-sm text-gray-600">{edu.field_of_study}</div>
-                      <div className="text-sm text-gray-600">{edu.start_year} - {edu.end_year}</div>
+                      {edu.start_year && (
+                        <div className="text-sm text-gray-600">{edu.start_year} - {edu.end_year || t('pages.schedule_lesson.education_ongoing')}</div>
+                      )}
                     </div>
                   ))}
                 </div>
@@ -673,6 +687,9 @@ export const ScheduleLesson: React.FC = () => {
                 </div>
               </div>
             )}
+
+            {/* Reviews */}
+            <TutorReviews tutorId={Number(tutorId)} />
           </div>
         </div>
         
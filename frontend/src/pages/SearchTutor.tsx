@@ -3,18 +3,42 @@ import { useNavigate } from 'react-router-dom';
 import { useAuth } from '../contexts/AuthContext';
 import { useTranslation } from '../contexts/I18nContext';
 import { TutorCard } from '../components/TutorCard';
-import { TutorProfile, TutorSearchFilters } from '../types/tutor';
+import { RecommendedTutors } from '../components/RecommendedTutors';
+import { SavedSearches } from '../components/SavedSearches';
+import { TutorProfile, TutorSearchFilters, TutorSearchFacets, TutorSort, FacetCount } from '../types/tutor';
 import { Language, LanguageProficiency } from '../types/language';
 import { Interest, Goal } from '../types/interest-goal';
 import { UserRole } from '../types/user';
+import { FavoriteTutor } from '../types/student';
 import { 
   getErrorMessage, 
   tutorService, 
   languageService, 
   interestService, 
-  goalService 
+  goalService,
+  studentService
 } from '../services/api';
 
+const PAGE_SIZE = 20;
+
+// Days of week as the search expects them, 0 is Sunday, listed from Monday
+const WEEK_DAYS = [
+  { day: 1, key: 'monday' },
+  { day: 2, key: 'tuesday' },
+  { day: 3, key: 'wednesday' },
+  { day: 4, key: 'thursday' },
+  { day: 5, key: 'friday' },
+  { day: 6, key: 'saturday' },
+  { day: 0, key: 'sunday' },
+];
+const AVAILABLE_WITHIN_OPTIONS = [3, 7, 14, 30];
+
+// Shows how many tutors a filter option yields, once the search returned the counts
+const facetLabel = (name: string, counts: FacetCount[] | undefined, id: number) => {
+  if (!counts) return name;
+  return `${name} (${counts.find(count => count.id === id)?.count ?? 0})`;
+};
+
 export const SearchTutor = () => {
   const { user } = useAuth();
   const { t } = useTranslation();
@@ -32,11 +56,17 @@ export const SearchTutor = () => {
   
   // State for tutors
   const [tutors, setTutors] = useState<TutorProfile[]>([]);
+  const [total, setTotal] = useState(0);
+  const [offset, setOffset] = useState(0);
+  const [facets, setFacets] = useState<TutorSearchFacets | null>(null);
   const [isLoading, setIsLoading] = useState(false);
   const [error, setError] = useState<string | null>(null);
   const [filtersVisible, setFiltersVisible] = useState(true);
   const [filtersApplied, setFiltersApplied] = useState(false);
-  const [sortBy, setSortBy] = useState('relevance');
+  const [sortBy, setSortBy] = useState<TutorSort | ''>('');
+
+  // The student's shortlisted tutors
+  const [favorites, setFavorites] = useState<FavoriteTutor[]>([]);
 
   // Mobile layout handling
   const [isMobile, setIsMobile] = useState(window.innerWidth < 768);
@@ -84,6 +114,19 @@ export const SearchTutor = () => {
     loadFilters();
   }, []);
 
+  // Load favorite tutors
+  useEffect(() => {
+    const loadFavorites = async () => {
+      try {
+        setFavorites(await studentService.getFavorites());
+      } catch (error) {
+        console.error('Error loading favorite tutors:', error);
+      }
+    };
+
+    loadFavorites();
+  }, []);
+
   // Update active filters count
   useEffect(() => {
     let count = 0;
@@ -94,23 +137,37 @@ export const SearchTutor = () => {
     if (filters.years_experience) count++;
     if (filters.min_age || filters.max_age) count++;
     if (filters.sex) count++;
+    if (filters.q) count++;
+    if ((filters.days && filters.days.length > 0) || filters.from_time || filters.to_time) count++;
+    if (filters.available_within_days) count++;
+    if (filters.verified_only) count++;
     
     setActiveFilters(count);
   }, [filters]);
 
   // Search tutors
-  const searchTutors = async () => {
+  const searchTutors = async (searchFilters: TutorSearchFilters = filters, pageOffset = 0, sort = sortBy) => {
     setIsLoading(true);
     setError(null);
     setFiltersApplied(true);
     
     try {
       // Log filters for debugging
-      console.log('Submitting search with filters:', JSON.stringify(filters, null, 2));
+      console.log('Submitting search with filters:', JSON.stringify(searchFilters, null, 2));
       
-      const tutorsData = await tutorService.searchTutors(filters);
-      console.log('Received tutors:', tutorsData.length);
+      const page = await tutorService.searchTutors({
+        ...searchFilters,
+        tz: Intl.DateTimeFormat().resolvedOptions().timeZone,
+        sort: sort || undefined,
+        limit: PAGE_SIZE,
+        offset: pageOffset,
+      });
+      const tutorsData = page.tutors;
+      console.log('Received tutors:', tutorsData.length, 'of', page.total);
       setTutors(tutorsData);
+      setTotal(page.total);
+      setOffset(page.offset);
+      setFacets(page.facets);
       
       // Close filters on mobile after search
       if (isMobile) {
@@ -194,12 +251,52 @@ export const SearchTutor = () => {
     });
   };
 
+  const toggleDaySelection = (day: number) => {
+    setFilters(prev => {
+      const currentDays = prev.days || [];
+      return {
+        ...prev,
+        days: currentDays.includes(day) ? currentDays.filter(d => d !== day) : [...currentDays, day]
+      };
+    });
+  };
+
   // Handle reset filters
   const handleResetFilters = () => {
     setFilters({});
-    searchTutors();
+    searchTutors({});
+  };
+
+  // Handle sort change
+  const handleSortChange = (sort: TutorSort | '') => {
+    setSortBy(sort);
+    searchTutors(filters, 0, sort);
+  };
+
+  // Run a saved search with its filters and sort
+  const handleApplySavedSearch = (savedFilters: TutorSearchFilters, sort: TutorSort | '') => {
+    setFilters(savedFilters);
+    setSortBy(sort);
+    searchTutors(savedFilters, 0, sort);
   };
 
+  // Add a tutor to the favorites or remove them
+  const handleToggleFavorite = async (tutor: TutorProfile) => {
+    try {
+      if (favorites.some(favorite => favorite.tutor.user_id === tutor.user_id)) {
+        await studentService.removeFavorite(tutor.user_id);
+        setFavorites(favorites.filter(favorite => favorite.tutor.user_id !== tutor.user_id));
+      } else {
+        await studentService.addFavorite(tutor.user_id);
+        setFavorites([{ tutor, added_at: new Date().toISOString() }, ...favorites]);
+      }
+    } catch (error) {
+      setError(getErrorMessage(error));
+    }
+  };
+
+  const isFavorite = (tutor: TutorProfile) => favorites.some(favorite => favorite.tutor.user_id === tutor.user_id);
+
   // Toggle filters visibility (for mobile)
   const toggleFilters = () => {
     setFiltersVisible(!filtersVisible);
@@ -280,6 +377,40 @@ export const SearchTutor = () => {
             onClear={() => handleFilterChange('sex', '')} 
           />
         )}
+
+        {((filters.days && filters.days.length > 0) || filters.from_time || filters.to_time) && (
+          <FilterBadge
+            label={t('pages.search_tutor.filter_hours', {
+              days: (filters.days || []).length > 0
+                ? WEEK_DAYS.filter(d => filters.days!.includes(d.day)).map(d => t(`common.days.${d.key}`)).join(', ')
+                : t('pages.search_tutor.any_day'),
+              from: filters.from_time || '00:00',
+              to: filters.to_time || '24:00',
+            })}
+            onClear={() => setFilters(prev => ({ ...prev, days: undefined, from_time: undefined, to_time: undefined }))}
+          />
+        )}
+
+        {filters.available_within_days && (
+          <FilterBadge
+            label={t('pages.search_tutor.filter_available_within', { count: filters.available_within_days })}
+            onClear={() => handleFilterChange('available_within_days', undefined)}
+          />
+        )}
+
+        {filters.verified_only && (
+          <FilterBadge
+            label={t('pages.search_tutor.filter_verified')}
+            onClear={() => handleFilterChange('verified_only', undefined)}
+          />
+        )}
+
+        {filters.q && (
+          <FilterBadge
+            label={t('pages.search_tutor.filter_text', { text: filters.q })}
+            onClear={() => handleFilterChange('q', undefined)}
+          />
+        )}
         
         {activeFilters > 0 && (
           <button 
@@ -357,6 +488,21 @@ export const SearchTutor = () => {
             </div>
             
             <div className="p-5 space-y-6">
+              {/* Free-text Search */}
+              <div className="filter-group">
+                <label className="block text-sm font-medium text-gray-700 mb-2">
+                  {t('pages.search_tutor.search_text')}
+                </label>
+                <input
+                  type="text"
+                  className="w-full rounded-lg border border-gray-300 py-2 px-3 focus:ring-2 focus:ring-orange-500 focus:border-orange-500 transition-all shadow-sm"
+                  placeholder={t('pages.search_tutor.search_text_placeholder')}
+                  value={filters.q || ''}
+                  onChange={(e) => handleFilterChange('q', e.target.value || undefined)}
+                  onKeyDown={(e) => e.key === 'Enter' && searchTutors()}
+                />
+              </div>
+
               {/* Language Filter Group */}
               <div className="filter-group">
                 <label className="block text-sm font-medium text-gray-700 mb-2">
@@ -376,7 +522,7 @@ export const SearchTutor = () => {
                           onChange={() => toggleLanguageSelection(language.name)}
                         />
                         <label htmlFor={`language-${language.id}`} className="ml-2 block text-sm text-gray-700">
-                          {language.name}
+                          {facetLabel(language.name, facets?.languages, language.id)}
                         </label>
                       </div>
                     ))
@@ -397,12 +543,72 @@ export const SearchTutor = () => {
                   <option value="">{t('common.all')}</option>
                   {proficiencies.map(proficiency => (
                     <option key={proficiency.id} value={proficiency.id}>
-                      {proficiency.name}
+                      {facetLabel(proficiency.name, facets?.proficiencies, proficiency.id)}
                     </option>
                   ))}
                 </select>
               </div>
               
+              {/* Availability Filter */}
+              <div className="filter-group">
+                <label className="block text-sm font-medium text-gray-700 mb-2">
+                  {t('pages.search_tutor.availability')}
+                </label>
+                <div className="flex flex-wrap gap-1 mb-2">
+                  {WEEK_DAYS.map(({ day, key }) => (
+                    <button
+                      key={day}
+                      type="button"
+                      onClick={() => toggleDaySelection(day)}
+                      className={`px-2 py-1 text-xs rounded-md border ${
+                        (filters.days || []).includes(day)
+                          ? 'bg-orange-500 border-orange-500 text-white'
+                          : 'border-gray-300 text-gray-700 hover:bg-gray-50'
+                      }`}
+                    >
+                      {t(`common.days.${key}`).slice(0, 3)}
+                    </button>
+                  ))}
+                </div>
+                <div className="grid grid-cols-2 gap-2 mb-2">
+                  <input
+                    type="time"
+                    aria-label={t('pages.search_tutor.from_time')}
+                    className="w-full rounded-lg border border-gray-300 py-1.5 px-2 text-sm focus:ring-2 focus:ring-orange-500 focus:border-orange-500 transition-all shadow-sm"
+                    value={filters.from_time || ''}
+                    onChange={(e) => handleFilterChange('from_time', e.target.value || undefined)}
+                  />
+                  <input
+                    type="time"
+                    aria-label={t('pages.search_tutor.to_time')}
+                    className="w-full rounded-lg border border-gray-300 py-1.5 px-2 text-sm focus:ring-2 focus:ring-orange-500 focus:border-orange-500 transition-all shadow-sm"
+                    value={filters.to_time || ''}
+                    onChange={(e) => handleFilterChange('to_time', e.target.value || undefined)}
+                  />
+                </div>
+                <select
+                  className="w-full rounded-lg border border-gray-300 py-2 px-3 focus:ring-2 focus:ring-orange-500 focus:border-orange-500 transition-all shadow-sm"
+                  value={filters.available_within_days || ''}
+                  onChange={(e) => handleFilterChange('available_within_days', e.target.value ? parseInt(e.target.value) : undefined)}
+                >
+                  <option value="">{t('pages.search_tutor.any_open_slot')}</option>
+                  {AVAILABLE_WITHIN_OPTIONS.map(days => (
+                    <option key={days} value={days}>
+                      {t('pages.search_tutor.available_within', { count: days })}
+                    </option>
+                  ))}
+                </select>
+                <label className="flex items-center gap-2 text-sm text-gray-700">
+                  <input
+                    type="checkbox"
+                    className="h-4 w-4 text-orange-500 rounded border-gray-300 focus:ring-orange-500"
+                    checked={!!filters.verified_only}
+                    onChange={(e) => handleFilterChange('verified_only', e.target.checked || undefined)}
+                  />
+                  {t('pages.search_tutor.verified_only')}
+                </label>
+              </div>
+
               {/* Collapsible Advanced Filters */}
               <details className="group [&_summary::-webkit-details-marker]:hidden">
                 <summary className="flex cursor-pointer list-none items-center justify-between font-medium text-gray-700 text-sm border-t border-b border-gray-100 py-3 -mx-5 px-5">
@@ -458,7 +664,7 @@ export const SearchTutor = () => {
                               onChange={() => toggleInterestSelection(interest.id)}
                             />
                             <label htmlFor={`interest-${interest.id}`} className="ml-2 block text-sm text-gray-700">
-                              {interest.name}
+                              {facetLabel(interest.name, facets?.interests, interest.id)}
                             </label>
                           </div>
                         ))
@@ -541,7 +747,7 @@ export const SearchTutor = () => {
               {/* Apply Filters Button */}
               <button 
                 className="w-full bg-orange-500 hover:bg-orange-600 text-white font-medium py-2.5 px-6 rounded-lg transition-colors shadow-md hover:shadow-lg flex items-center justify-center gap-2"
-                onClick={searchTutors}
+                onClick={() => searchTutors()}
               >
                 <svg xmlns="http://www.w3.org/2000/svg" className="h-5 w-5" fill="none" viewBox="0 0 24 24" stroke="currentColor">
                   <path strokeLinecap="round" strokeLinejoin="round" strokeWidth={2} d="M21 21l-6-6m2-5a7 7 0 11-14 0 7 7 0 0114 0z" />
@@ -554,18 +760,55 @@ export const SearchTutor = () => {
         
         {/* Results Section */}
         <main className={`${isMobile ? 'w-full' : 'md:w-3/4'}`} id="results-section">
+          {activeFilters === 0 && favorites.length > 0 && (
+            <section className="mb-8">
+              <h2 className="text-xl font-bold text-gray-800 mb-4">{t('pages.search_tutor.favorites')}</h2>
+              <div className="grid grid-cols-1 md:grid-cols-2 gap-6">
+                {favorites.map(favorite => (
+                  <TutorCard
+                    key={favorite.tutor.user_id}
+                    tutor={favorite.tutor}
+                    isFavorite
+                    onToggleFavorite={handleToggleFavorite}
+                  />
+                ))}
+              </div>
+            </section>
+          )}
+
+          {activeFilters === 0 && <RecommendedTutors />}
+
+          <SavedSearches
+            filters={filters}
+            sort={sortBy}
+            canSave={activeFilters > 0}
+            onApply={handleApplySavedSearch}
+          />
+
           {/* Results Header with Sort Options */}
           <div className="flex items-center justify-between mb-6">
             <div className="flex items-center gap-2">
               <h2 className="text-xl font-bold text-gray-800">
                 {t('pages.search_tutor.results')}
               </h2>
-              {tutors.length > 0 && (
+              {total > 0 && (
                 <span className="text-sm text-gray-600 bg-gray-100 px-3 py-1 rounded-full">
-                  {t('pages.search_tutor.tutors_found', { count: tutors.length })}
+                  {t('pages.search_tutor.tutors_found', { count: total })}
                 </span>
               )}
             </div>
+            <select
+              className="rounded-lg border border-gray-300 py-1.5 px-3 text-sm focus:ring-2 focus:ring-orange-500 focus:border-orange-500 transition-all shadow-sm"
+              value={sortBy}
+              onChange={(e) => handleSortChange(e.target.value as TutorSort | '')}
+            >
+              <option value="">{t('pages.search_tutor.sort_default')}</option>
+              <option value="rating">{t('pages.search_tutor.sort_rating')}</option>
+              <option value="price">{t('pages.search_tutor.sort_price')}</option>
+              <option value="experience">{t('pages.search_tutor.sort_experience')}</option>
+              <option value="newest">{t('pages.search_tutor.sort_newest')}</option>
+              {filters.q && <option value="relevance">{t('pages.search_tutor.sort_relevance')}</option>}
+            </select>
           </div>
           
           {/* Error Display */}
@@ -590,8 +833,36 @@ export const SearchTutor = () => {
           ) : tutors.length > 0 ? (
             <div className="grid grid-cols-1 md:grid-cols-2 lg:grid-cols-2 gap-6">
               {tutors.map(tutor => (
-                <TutorCard key={tutor.user_id} tutor={tutor} />
+                <TutorCard
+                  key={tutor.user_id}
+                  tutor={tutor}
+                  isFavorite={isFavorite(tutor)}
+                  onToggleFavorite={handleToggleFavorite}
+                />
               ))}
+              {total > PAGE_SIZE && (
+                <div className="md:col-span-2 flex items-center justify-between">
+                  <button
+                    type="button"
+                    disabled={offset === 0}
+                    onClick={() => searchTutors(filters, Math.max(0, offset - PAGE_SIZE))}
+                    className="text-sm text-orange-600 disabled:text-gray-300"
+                  >
+                    {t('pages.search_tutor.previous_page')}
+                  </button>
+                  <span className="text-sm text-gray-600">
+                    {t('pages.search_tutor.page_range', { from: offset + 1, to: offset + tutors.length, total })}
+                  </span>
+                  <button
+                    type="button"
+                    disabled={offset + PAGE_SIZE >= total}
+                    onClick={() => searchTutors(filters, offset + PAGE_SIZE)}
+                    className="text-sm text-orange-600 disabled:text-gray-300"
+                  >
+                    {t('pages.search_tutor.next_page')}
+                  </button>
+                </div>
+              )}
             </div>
           ) : (
             <div className="bg-white rounded-xl shadow-md p-10 text-center">
@@ -5,6 +5,7 @@ import { useAuth } from '../contexts/AuthContext';
 import { useTranslation } from '../contexts/I18nContext';
 import { tutorService, getErrorMessage } from '../services/api';
 import { TutorProfile, Education, TutorUpdateRequest } from '../types/tutor';
+import { TutorCredentials } from '../components/TutorCredentials';
 
 // Helper function to safely get error message
 const getFieldError = (errors: any, fieldPath: string): string => {
@@ -21,8 +22,28 @@ const getFieldError = (errors: any, fieldPath: string): string => {
   return current?.toString() || '';
 };
 
+// Education entry as edited in the form, with the years as typed
+type EducationFormValues = Omit<Education, 'start_year' | 'end_year'> & {
+  start_year: string;
+  end_year: string;
+};
+
+const toFormValues = (education: Education): EducationFormValues => ({
+  ...education,
+  start_year: education.start_year?.toString() || '',
+  end_year: education.end_year?.toString() || '',
+});
+
+const fromFormValues = (values: EducationFormValues): Education => ({
+  degree: values.degree.trim(),
+  institution: values.institution.trim(),
+  field_of_study: values.field_of_study.trim(),
+  start_year: values.start_year ? parseInt(values.start_year) : undefined,
+  end_year: values.end_year ? parseInt(values.end_year) : undefined,
+});
+
 // Type guard to check if an education error is a FormikErrors object and not a string
-const isEducationError = (error: string | FormikErrors<Education>): error is FormikErrors<Education> => {
+const isEducationError = (error: string | FormikErrors<EducationFormValues>): error is FormikErrors<EducationFormValues> => {
   return typeof error !== 'string';
 };
 
@@ -54,7 +75,7 @@ export const TutorSettings = () => {
     }
   }, [user]);
 
-  const emptyEducation: Education = {
+  const emptyEducation: EducationFormValues = {
     degree: '',
     institution: '',
     field_of_study: '',
@@ -65,9 +86,14 @@ export const TutorSettings = () => {
   const formik = useFormik({
     initialValues: {
       bio: tutorProfile?.bio || '',
-      education: tutorProfile?.education || [emptyEducation],
+      education: tutorProfile?.education && tutorProfile.education.length > 0
+        ? tutorProfile.education.map(toFormValues)
+        : [emptyEducation],
       intro_video_url: tutorProfile?.intro_video_url || '',
       years_experience: tutorProfile?.years_experience || 0,
+      timezone: tutorProfile?.timezone && tutorProfile.timezone !== 'UTC'
+        ? tutorProfile.timezone
+        : Intl.DateTimeFormat().resolvedOptions().timeZone,
     },
     enableReinitialize: true,
     validationSchema: Yup.object({
@@ -77,8 +103,15 @@ export const TutorSettings = () => {
           degree: Yup.string().required(t('validation.required')),
           institution: Yup.string().required(t('validation.required')),
           field_of_study: Yup.string().required(t('validation.required')),
-          start_year: Yup.string().required(t('validation.required')),
-          end_year: Yup.string().required(t('validation.required')),
+          start_year: Yup.string()
+            .required(t('validation.required'))
+            .matches(/^\d{4}$/, t('validation.year_invalid')),
+          // Left empty while the studies are under way
+          end_year: Yup.string()
+            .matches(/^\d{4}$/, t('validation.year_invalid'))
+            .test('after-start', t('validation.end_year_before_start'), function (value) {
+              return !value || !this.parent.start_year || parseInt(value) >= parseInt(this.parent.start_year);
+            }),
         })
       ),
       intro_video_url: Yup.string().url(t('validation.url_invalid')).nullable(),
@@ -93,16 +126,12 @@ export const TutorSettings = () => {
         setError(null);
         setUpdateSuccess(false);
 
-        // Make sure we have at least one education entry for submission
-        const education = values.education && values.education.length > 0 
-          ? values.education 
-          : [emptyEducation];
-
         const updateData: TutorUpdateRequest = {
           bio: values.bio,
-          education: education,
+          education: values.education.map(fromFormValues),
           intro_video_url: values.intro_video_url || undefined,
           years_experience: values.years_experience,
+          timezone: values.timezone || undefined,
         };
 
         const updatedProfile = await tutorService.updateTutorProfile(updateData);
@@ -191,6 +220,21 @@ export const TutorSettings = () => {
               )}
             </div>
 
+            {/* Time Zone */}
+            <div className="mb-6">
+              <label htmlFor="timezone" className="block text-sm font-medium text-gray-700 mb-1">
+                {t('pages.tutor_settings.timezone')}
+              </label>
+              <input
+                id="timezone"
+                type="text"
+                {...formik.getFieldProps('timezone')}
+                className="mt-1 block w-full rounded-md border-gray-300 shadow-sm focus:border-orange-500 focus:ring-orange-500 sm:text-sm"
+                placeholder="Europe/Moscow"
+              />
+              <p className="mt-1 text-sm text-gray-500">{t('pages.tutor_settings.timezone_hint')}</p>
+            </div>
+
             {/* Intro Video URL */}
             <div className="mb-6">
               <label htmlFor="intro_video_url" className="block text-sm font-medium text-gray-700 mb-1">
@@ -263,7 +307,7 @@ export const TutorSettings = () => {
                             {formik.touched.education?.[index]?.degree && 
                              formik.errors.education?.[index] && 
                              isEducationError(formik.errors.education[index]) && 
-                             (formik.errors.education[index] as FormikErrors<Education>).degree && (
+                             (formik.errors.education[index] as FormikErrors<EducationFormValues>).degree && (
                               <p className="mt-1 text-sm text-red-600">
                                 {getFieldError(formik.errors, `education.${index}.degree`)}
                               </p>
@@ -284,7 +328,7 @@ export const TutorSettings = () => {
                             {formik.touched.education?.[index]?.institution && 
                              formik.errors.education?.[index] && 
                              isEducationError(formik.errors.education[index]) && 
-                             (formik.errors.education[index] as FormikErrors<Education>).institution && (
+                             (formik.errors.education[index] as FormikErrors<EducationFormValues>).institution && (
                               <p className="mt-1 text-sm text-red-600">
                                 {getFieldError(formik.errors, `education.${index}.institution`)}
                               </p>
@@ -305,7 +349,7 @@ export const TutorSettings = () => {
                             {formik.touched.education?.[index]?.field_of_study && 
                              formik.errors.education?.[index] && 
                              isEducationError(formik.errors.education[index]) && 
-                             (formik.errors.education[index] as FormikErrors<Education>).field_of_study && (
+                             (formik.errors.education[index] as FormikErrors<EducationFormValues>).field_of_study && (
                               <p className="mt-1 text-sm text-red-600">
                                 {getFieldError(formik.errors, `education.${index}.field_of_study`)}
                               </p>
@@ -326,7 +370,7 @@ export const TutorSettings = () => {
                             {formik.touched.education?.[index]?.start_year && 
                              formik.errors.education?.[index] && 
                              isEducationError(formik.errors.education[index]) && 
-                             (formik.errors.education[index] as FormikErrors<Education>).start_year && (
+                             (formik.errors.education[index] as FormikErrors<EducationFormValues>).start_year && (
                               <p className="mt-1 text-sm text-red-600">
                                 {getFieldError(formik.errors, `education.${index}.start_year`)}
                               </p>
@@ -344,10 +388,11 @@ export const TutorSettings = () => {
                               {...formik.getFieldProps(`education.${index}.end_year`)}
                               className="mt-1 block w-full rounded-md border-gray-300 shadow-sm focus:border-orange-500 focus:ring-orange-500 sm:text-sm"
                             />
+                            <p className="mt-1 text-sm text-gray-500">{t('pages.tutor_settings.end_year_hint')}</p>
                             {formik.touched.education?.[index]?.end_year && 
                              formik.errors.education?.[index] && 
                              isEducationError(formik.errors.education[index]) && 
-                             (formik.errors.education[index] as FormikErrors<Education>).end_year && (
+                             (formik.errors.education[index] as FormikErrors<EducationFormValues>).end_year && (
                               <p className="mt-1 text-sm text-red-600">
                                 {getFieldError(formik.errors, `education.${index}.end_year`)}
                               </p>
@@ -385,6 +430,8 @@ export const TutorSettings = () => {
           </form>
         </FormikProvider>
       </div>
+
+      <TutorCredentials />
     </div>
   );
 }; 
\ No newline at end of file
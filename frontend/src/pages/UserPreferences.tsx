@@ -5,6 +5,8 @@ import { getErrorMessage, languageService, interestService, goalService } from '
 import { Language, LanguageProficiency, UserLanguage, UserLanguageUpdate } from '../types/language';
 import { Interest, UserInterest, Goal, UserGoal } from '../types/interest-goal';
 import { toast } from 'react-hot-toast';
+import { LessonPreferencesForm } from '../components/LessonPreferencesForm';
+import { UserRole } from '../types/user';
 
 export const UserPreferences = () => {
   const { user } = useAuth();
@@ -384,6 +386,18 @@ export const UserPreferences = () => {
           >
             {t('pages.user_preferences.goals_tab')}
           </button>
+          {user?.role === UserRole.STUDENT && (
+            <button
+              onClick={() => setActiveTab('lessons')}
+              className={`${
+                activeTab === 'lessons'
+                  ? 'border-orange-500 text-orange-600'
+                  : 'border-transparent text-gray-500 hover:text-gray-700 hover:border-gray-300'
+              } py-4 px-6 font-medium text-sm border-b-2 focus:outline-none`}
+            >
+              {t('pages.user_preferences.lessons_tab')}
+            </button>
+          )}
         </nav>
       </div>
       
@@ -648,6 +662,9 @@ export const UserPreferences = () => {
           </div>
         </div>
       )}
+
+      {/* Lesson Preferences Tab */}
+      {activeTab === 'lessons' && !isLoading && <LessonPreferencesForm />}
     </div>
   );
 };
@@ -6,14 +6,20 @@ import { useTranslation } from '../contexts/I18nContext';
 import { UserUpdateRequest } from '../types';
 import { userService, getErrorMessage } from '../services/api';
 import { envConfig } from '../config/env';
+import { NotificationPreferencesForm } from '../components/NotificationPreferencesForm';
+import { setAvatar, uploadFile } from '../services/upload.service';
+import { toast } from 'react-hot-toast';
+
+const AVATAR_REFRESH_DELAY_MS = 5000;
 
 export const UserSettings = () => {
-  const { user, setUser } = useAuth();
+  const { user, setUser, refreshUser } = useAuth();
   const { t } = useTranslation();
   const [activeTab, setActiveTab] = useState('personal');
   const [isLoading, setIsLoading] = useState(false);
   const [updateSuccess, setUpdateSuccess] = useState(false);
   const [error, setError] = useState<string | null>(null);
+  const [isUploadingAvatar, setIsUploadingAvatar] = useState(false);
 
   const personalInfoFormik = useFormik({
     initialValues: {
@@ -127,6 +133,28 @@ export const UserSettings = () => {
     }
   }, [user]);
 
+  const handleAvatarChange = async (event: React.ChangeEvent<HTMLInputElement>) => {
+    const file = event.target.files?.[0];
+    event.target.value = '';
+    if (!file) {
+      return;
+    }
+
+    try {
+      setIsUploadingAvatar(true);
+      const upload = await uploadFile(file, 'avatar');
+      await setAvatar(upload.id);
+      toast.success(t('pages.user_settings.picture_processing'));
+      // The picture is resized in the background and usually ready within a few seconds
+      setTimeout(() => refreshUser(), AVATAR_REFRESH_DELAY_MS);
+    } catch (error: any) {
+      console.error('Upload avatar error:', error);
+      toast.error(`${t('notifications.profile_picture_upload_failed')}: ${getErrorMessage(error)}`);
+    } finally {
+      setIsUploadingAvatar(false);
+    }
+  };
+
   const handleImageError = (event: React.SyntheticEvent<HTMLImageElement>) => {
     const target = event.target as HTMLImageElement;
     target.src = envConfig.placeholderImage;
@@ -159,6 +187,16 @@ export const UserSettings = () => {
           >
             {t('pages.user_settings.security_tab')}
           </button>
+          <button
+            onClick={() => setActiveTab('notifications')}
+            className={`${
+              activeTab === 'notifications'
+                ? 'border-orange-500 text-orange-600'
+                : 'border-transparent text-gray-500 hover:text-gray-700 hover:border-gray-300'
+            } py-4 px-6 font-medium text-sm border-b-2 focus:outline-none`}
+          >
+            {t('pages.user_settings.notifications_tab')}
+          </button>
         </nav>
       </div>
       
@@ -218,14 +256,15 @@ export const UserSettings = () => {
                 </label>
                 <input
                   id="profile_picture_url"
-                  type="text"
-                  {...personalInfoFormik.getFieldProps('profile_picture_url')}
-                  className="mt-1 block w-full rounded-md border-gray-300 shadow-sm focus:border-orange-500 focus:ring-orange-500 sm:text-sm"
-                  placeholder={t('pages.user_settings.profile_picture_placeholder')}
+                  type="file"
+                  accept="image/jpeg,image/png,image/gif,image/webp"
+                  onChange={handleAvatarChange}
+                  disabled={isUploadingAvatar}
+                  className="mt-1 block w-full text-sm text-gray-700 file:mr-4 file:rounded-md file:border-0 file:bg-orange-50 file:px-4 file:py-2 file:text-sm file:font-medium file:text-orange-700 hover:file:bg-orange-100"
                 />
-                {personalInfoFormik.touched.profile_picture_url && personalInfoFormik.errors.profile_picture_url && (
-                  <p className="mt-1 text-sm text-red-600">{personalInfoFormik.errors.profile_picture_url}</p>
-                )}
+                <p className="mt-1 text-sm text-gray-500">
+                  {isUploadingAvatar ? t('pages.user_settings.picture_uploading') : t('pages.user_settings.picture_hint')}
+                </p>
                 
                 {personalInfoFormik.values.profile_picture_url && (
                   <div className="mt-2">
@@ -387,6 +426,14 @@ export const UserSettings = () => {
           </form>
         </div>
       )}
+      
+      {/* Notification preferences */}
+      {activeTab === 'notifications' && (
+        <div className="bg-white shadow rounded-lg p-6">
+          <h2 className="text-xl font-semibold mb-4">{t('pages.user_settings.notifications.title')}</h2>
+          <NotificationPreferencesForm />
+        </div>
+      )}
     </div>
   );
 };
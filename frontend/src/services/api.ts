@@ -3,7 +3,8 @@ import { envConfig } from '../config/env';
 import { User, LoginRequest, UserRegistrationRequest, UserUpdateRequest, AuthResponse } from '../types';
 import { Language, LanguageProficiency, UserLanguage, UserLanguageUpdate } from '../types/language';
 import { Interest, UserInterest, Goal, UserGoal } from '../types/interest-goal';
-import { TutorProfile, TutorUpdateRequest, TutorSearchFilters, TutorAvailability, TutorAvailabilityRequest } from '../types/tutor';
+import { TutorProfile, TutorUpdateRequest, Education, TutorSearchFilters, TutorSearchPage, TutorAvailability, TutorAvailabilityRequest, TutorRecommendations, TutorCredential, CredentialRequest } from '../types/tutor';
+import { FavoriteTutor, SavedSearch, SavedSearchRequest, StudentPreferences } from '../types/student';
 
 // Helper function to extract error messages from different API error formats
 export const getErrorMessage = (error: any): string => {
@@ -327,6 +328,37 @@ export const tutorService = {
         }
     },
 
+    // Education entries are identified by their position in the profile
+    addEducation: async (data: Education): Promise<Education[]> => {
+        try {
+            const response = await apiClient.post('/api/tutor/education', data);
+            return response.data;
+        } catch (error) {
+            console.error('Add education error:', error);
+            throw error;
+        }
+    },
+
+    updateEducation: async (index: number, data: Education): Promise<Education[]> => {
+        try {
+            const response = await apiClient.put(`/api/tutor/education/${index}`, data);
+            return response.data;
+        } catch (error) {
+            console.error('Update education error:', error);
+            throw error;
+        }
+    },
+
+    removeEducation: async (index: number): Promise<Education[]> => {
+        try {
+            const response = await apiClient.delete(`/api/tutor/education/${index}`);
+            return response.data;
+        } catch (error) {
+            console.error('Remove education error:', error);
+            throw error;
+        }
+    },
+
     getTutorAvailabilities: async (): Promise<TutorAvailability[]> => {
         try {
             const response = await apiClient.get('/api/tutor/availabilities');
@@ -370,7 +402,7 @@ export const tutorService = {
         }
     },
 
-    searchTutors: async (filters: TutorSearchFilters = {}): Promise<TutorProfile[]> => {
+    searchTutors: async (filters: TutorSearchFilters = {}): Promise<TutorSearchPage> => {
         try {
             // Convert filters to query parameters
             const params = new URLSearchParams();
@@ -407,17 +439,207 @@ export const tutorService = {
                 params.append('sex', filters.sex);
             }
             
+            if (filters.q && filters.q.trim()) {
+                params.append('q', filters.q.trim());
+            }
+            
+            if (filters.days && filters.days.length > 0) {
+                filters.days.forEach(day => params.append('day', day.toString()));
+            }
+            
+            if (filters.from_time) {
+                params.append('from_time', filters.from_time);
+            }
+            
+            if (filters.to_time) {
+                params.append('to_time', filters.to_time);
+            }
+            
+            if (filters.available_within_days) {
+                params.append('available_within_days', filters.available_within_days.toString());
+            }
+            
+            if (filters.verified_only) {
+                params.append('verified_only', 'true');
+            }
+            
+            if (filters.tz) {
+                params.append('tz', filters.tz);
+            }
+            
+            if (filters.sort) {
+                params.append('sort', filters.sort);
+            }
+            
+            if (filters.limit) {
+                params.append('limit', filters.limit.toString());
+            }
+            
+            if (filters.offset) {
+                params.append('offset', filters.offset.toString());
+            }
+            
             const url = `/api/tutors/search?${params.toString()}`;
             console.log('Search URL:', url);
             console.log('Applied filters:', JSON.stringify(filters, null, 2));
             
             const response = await apiClient.get(url);
-            return response.data || [];
+            return response.data;
         } catch (error) {
             console.error('Search tutors error:', error);
             console.error('Filter data that caused error:', JSON.stringify(filters, null, 2));
             throw error;
         }
+    },
+
+    getCredentials: async (): Promise<TutorCredential[]> => {
+        try {
+            const response = await apiClient.get('/api/tutor/credentials');
+            return response.data || [];
+        } catch (error) {
+            console.error('Get credentials error:', error);
+            throw error;
+        }
+    },
+
+    addCredential: async (data: CredentialRequest): Promise<TutorCredential> => {
+        try {
+            const response = await apiClient.post('/api/tutor/credentials', data);
+            return response.data;
+        } catch (error) {
+            console.error('Add credential error:', error);
+            throw error;
+        }
+    },
+
+    updateCredential: async (id: number, data: CredentialRequest): Promise<TutorCredential> => {
+        try {
+            const response = await apiClient.put(`/api/tutor/credentials/${id}`, data);
+            return response.data;
+        } catch (error) {
+            console.error('Update credential error:', error);
+            throw error;
+        }
+    },
+
+    deleteCredential: async (id: number): Promise<void> => {
+        try {
+            await apiClient.delete(`/api/tutor/credentials/${id}`);
+        } catch (error) {
+            console.error('Delete credential error:', error);
+            throw error;
+        }
+    },
+
+    // Verified credentials shown on a tutor's profile
+    getTutorCredentials: async (tutorId: number): Promise<TutorCredential[]> => {
+        try {
+            const response = await apiClient.get(`/api/tutors/${tutorId}/credentials`);
+            return response.data || [];
+        } catch (error) {
+            console.error('Get tutor credentials error:', error);
+            return [];
+        }
+    }
+};
+
+// Student Service
+export const studentService = {
+    getPreferences: async (): Promise<StudentPreferences> => {
+        try {
+            const response = await apiClient.get('/api/student/preferences');
+            return response.data;
+        } catch (error) {
+            console.error('Get student preferences error:', error);
+            throw error;
+        }
+    },
+
+    updatePreferences: async (data: StudentPreferences): Promise<StudentPreferences> => {
+        try {
+            const response = await apiClient.put('/api/student/preferences', data);
+            return response.data;
+        } catch (error) {
+            console.error('Update student preferences error:', error);
+            throw error;
+        }
+    },
+
+    getRecommendedTutors: async (limit?: number): Promise<TutorRecommendations> => {
+        try {
+            const response = await apiClient.get('/api/student/recommended-tutors', { params: { limit } });
+            return response.data;
+        } catch (error) {
+            console.error('Get recommended tutors error:', error);
+            throw error;
+        }
+    },
+
+    getFavorites: async (): Promise<FavoriteTutor[]> => {
+        try {
+            const response = await apiClient.get('/api/student/favorites');
+            return response.data;
+        } catch (error) {
+            console.error('Get favorite tutors error:', error);
+            throw error;
+        }
+    },
+
+    addFavorite: async (tutorId: number): Promise<void> => {
+        try {
+            await apiClient.put(`/api/student/favorites/${tutorId}`);
+        } catch (error) {
+            console.error('Add favorite tutor error:', error);
+            throw error;
+        }
+    },
+
+    removeFavorite: async (tutorId: number): Promise<void> => {
+        try {
+            await apiClient.delete(`/api/student/favorites/${tutorId}`);
+        } catch (error) {
+            console.error('Remove favorite tutor error:', error);
+            throw error;
+        }
+    },
+
+    getSavedSearches: async (): Promise<SavedSearch[]> => {
+        try {
+            const response = await apiClient.get('/api/student/saved-searches');
+            return response.data;
+        } catch (error) {
+            console.error('Get saved searches error:', error);
+            throw error;
+        }
+    },
+
+    createSavedSearch: async (data: SavedSearchRequest): Promise<SavedSearch> => {
+        try {
+            const response = await apiClient.post('/api/student/saved-searches', data);
+            return response.data;
+        } catch (error) {
+            console.error('Create saved search error:', error);
+            throw error;
+        }
+    },
+
+    updateSavedSearch: async (id: number, data: SavedSearchRequest): Promise<SavedSearch> => {
+        try {
+            const response = await apiClient.put(`/api/student/saved-searches/${id}`, data);
+            return response.data;
+        } catch (error) {
+            console.error('Update saved search error:', error);
+            throw error;
+        }
+    },
+
+    deleteSavedSearch: async (id: number): Promise<void> => {
+        try {
+            await apiClient.delete(`/api/student/saved-searches/${id}`);
+        } catch (error) {
+            console.error('Delete saved search error:', error);
+            throw error;
+        }
     }
 };
 
@@ -427,5 +649,6 @@ export default {
     language: languageService,
     interest: interestService,
     goal: goalService,
-    tutor: tutorService
+    tutor: tutorService,
+    student: studentService
 }; 
\ No newline at end of file
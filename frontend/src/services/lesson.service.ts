@@ -1,5 +1,5 @@
 import { apiClient } from './api';
-import { Lesson, LessonCancellationRequest, Review } from '../types/lesson';
+import { Lesson, LessonCancellationRequest, LessonRoomTicket, Review, ReviewRequest } from '../types/lesson';
 
 // Get all lessons for the current user
 export const getUserLessons = async (): Promise<Lesson[]> => {
@@ -31,6 +31,12 @@ export const getLessonById = async (lessonId: number): Promise<Lesson> => {
   return response.data;
 };
 
+// Get a ticket for the lesson's video room, passed to the video service instead of the login token
+export const createRoomTicket = async (lessonId: number): Promise<LessonRoomTicket> => {
+  const response = await apiClient.post(`/api/lessons/${lessonId}/room-ticket`);
+  return response.data;
+};
+
 // Cancel a lesson
 export const cancelLesson = async (lessonId: number, reason: string): Promise<void> => {
   const data: LessonCancellationRequest = { reason };
@@ -38,7 +44,7 @@ export const cancelLesson = async (lessonId: number, reason: string): Promise<vo
 };
 
 // Add a review for a lesson
-export const addReview = async (lessonId: number, rating: number): Promise<Review> => {
-  const response = await apiClient.post(`/api/lessons/${lessonId}/reviews`, { rating });
+export const addReview = async (lessonId: number, review: ReviewRequest): Promise<Review> => {
+  const response = await apiClient.post(`/api/lessons/${lessonId}/reviews`, review);
   return response.data;
 }; 
\ No newline at end of file
@@ -0,0 +1,72 @@
+import { apiClient } from './api';
+import {
+  Homework,
+  HomeworkRequest,
+  LessonHistory,
+  LessonNotes,
+  LessonNotesRequest,
+  StudentFeedback,
+  StudentFeedbackRequest,
+  StudentHomework,
+} from '../types/lessonNotes';
+
+export const getLessonNotes = async (lessonId: number): Promise<LessonNotes> => {
+  const response = await apiClient.get(`/api/lessons/${lessonId}/notes`);
+  return response.data;
+};
+
+export const saveLessonNotes = async (lessonId: number, notes: LessonNotesRequest): Promise<LessonNotes> => {
+  const response = await apiClient.put(`/api/lessons/${lessonId}/notes`, notes);
+  return response.data;
+};
+
+export const getLessonHomework = async (lessonId: number): Promise<Homework[]> => {
+  const response = await apiClient.get(`/api/lessons/${lessonId}/homework`);
+  return response.data;
+};
+
+export const addHomework = async (lessonId: number, homework: HomeworkRequest): Promise<Homework> => {
+  const response = await apiClient.post(`/api/lessons/${lessonId}/homework`, homework);
+  return response.data;
+};
+
+export const updateHomework = async (homeworkId: number, homework: HomeworkRequest): Promise<Homework> => {
+  const response = await apiClient.put(`/api/homework/${homeworkId}`, homework);
+  return response.data;
+};
+
+export const deleteHomework = async (homeworkId: number): Promise<void> => {
+  await apiClient.delete(`/api/homework/${homeworkId}`);
+};
+
+export const setHomeworkDone = async (homeworkId: number, done: boolean): Promise<Homework> => {
+  const response = await apiClient.put(`/api/homework/${homeworkId}/status`, { done });
+  return response.data;
+};
+
+// The tutor's history with a student, including their private notes
+export const getStudentHistory = async (studentId: number): Promise<LessonHistory> => {
+  const response = await apiClient.get(`/api/tutor/students/${studentId}/history`);
+  return response.data;
+};
+
+// The student's history with a tutor
+export const getTutorHistory = async (tutorId: number): Promise<LessonHistory> => {
+  const response = await apiClient.get(`/api/student/tutors/${tutorId}/history`);
+  return response.data;
+};
+
+export const getStudentHomework = async (openOnly = false): Promise<StudentHomework[]> => {
+  const response = await apiClient.get('/api/student/homework', { params: openOnly ? { status: 'open' } : {} });
+  return response.data;
+};
+
+export const getLessonFeedback = async (lessonId: number): Promise<StudentFeedback[]> => {
+  const response = await apiClient.get(`/api/lessons/${lessonId}/feedback`);
+  return response.data;
+};
+
+export const saveStudentFeedback = async (lessonId: number, feedback: StudentFeedbackRequest): Promise<StudentFeedback> => {
+  const response = await apiClient.put(`/api/lessons/${lessonId}/feedback`, feedback);
+  return response.data;
+};
@@ -0,0 +1,67 @@
+import { apiClient } from './api';
+import {
+  BlockedUser,
+  Conversation,
+  ConversationList,
+  Message,
+  MessagePage,
+  MessageRequest,
+} from '../types/message';
+
+export const getConversations = async (params?: { limit?: number; offset?: number }): Promise<ConversationList> => {
+  const response = await apiClient.get('/api/conversations', { params });
+  return response.data;
+};
+
+export const getConversation = async (conversationId: number): Promise<Conversation> => {
+  const response = await apiClient.get(`/api/conversations/${conversationId}`);
+  return response.data;
+};
+
+// Messages a user, starting a conversation with them unless one exists
+export const startConversation = async (
+  recipientId: number,
+  message: MessageRequest
+): Promise<{ conversation: Conversation; message: Message }> => {
+  const response = await apiClient.post('/api/conversations', { recipient_id: recipientId, ...message });
+  return response.data;
+};
+
+// Returns messages newest first; pass the ID of the oldest loaded message to get older ones
+export const getMessages = async (conversationId: number, beforeId?: number): Promise<MessagePage> => {
+  const response = await apiClient.get(`/api/conversations/${conversationId}/messages`, {
+    params: beforeId ? { before_id: beforeId } : undefined,
+  });
+  return response.data;
+};
+
+export const sendMessage = async (conversationId: number, message: MessageRequest): Promise<Message> => {
+  const response = await apiClient.post(`/api/conversations/${conversationId}/messages`, message);
+  return response.data;
+};
+
+export const markConversationRead = async (conversationId: number): Promise<void> => {
+  await apiClient.post(`/api/conversations/${conversationId}/read`);
+};
+
+export const getUnreadMessageCount = async (): Promise<number> => {
+  const response = await apiClient.get('/api/conversations/unread-count');
+  return response.data.unread_count;
+};
+
+export const reportConversation = async (conversationId: number, reason: string, messageId?: number): Promise<void> => {
+  await apiClient.post(`/api/conversations/${conversationId}/report`, { reason, message_id: messageId });
+};
+
+export const getBlockedUsers = async (): Promise<BlockedUser[]> => {
+  const response = await apiClient.get('/api/blocks');
+  return response.data;
+};
+
+export const blockUser = async (userId: number): Promise<void> => {
+  await apiClient.post('/api/blocks', { user_id: userId });
+};
+
+export const unblockUser = async (userId: number): Promise<void> => {
+  await apiClient.delete(`/api/blocks/${userId}`);
+};
@@ -0,0 +1,114 @@
+import { apiClient } from './api';
+import { envConfig } from '../config/env';
+import {
+  AppNotification,
+  NotificationList,
+  NotificationPreferences,
+  NotificationPreferencesUpdate,
+} from '../types/notification';
+
+export const getNotifications = async (params?: { unread?: boolean; limit?: number; offset?: number }): Promise<NotificationList> => {
+  const response = await apiClient.get('/api/notifications', { params });
+  return response.data;
+};
+
+export const getUnreadCount = async (): Promise<number> => {
+  const response = await apiClient.get('/api/notifications/unread-count');
+  return response.data.unread_count;
+};
+
+export const markNotificationRead = async (notificationId: number): Promise<void> => {
+  await apiClient.post(`/api/notifications/${notificationId}/read`);
+};
+
+export const markAllNotificationsRead = async (): Promise<void> => {
+  await apiClient.post('/api/notifications/read-all');
+};
+
+export const getNotificationPreferences = async (): Promise<NotificationPreferences> => {
+  const response = await apiClient.get('/api/user/preferences');
+  return response.data;
+};
+
+export const updateNotificationPreferences = async (
+  update: NotificationPreferencesUpdate
+): Promise<NotificationPreferences> => {
+  const response = await apiClient.put('/api/user/preferences', update);
+  return response.data;
+};
+
+interface NotificationStreamHandlers {
+  onUnreadCount: (count: number) => void;
+  onNotification: (notification: AppNotification) => void;
+}
+
+const RECONNECT_DELAY_MS = 5000;
+
+// Subscribes to the server-sent notification stream. EventSource cannot send the Authorization
+// header, so the stream is read with fetch. Reconnects until the returned function is called.
+export const subscribeToNotifications = (handlers: NotificationStreamHandlers): (() => void) => {
+  const controller = new AbortController();
+
+  const dispatch = (event: string, data: string) => {
+    try {
+      const payload = JSON.parse(data);
+      if (event === 'unread_count') {
+        handlers.onUnreadCount(payload.unread_count);
+      } else if (event === 'notification') {
+        handlers.onNotification(payload);
+      }
+    } catch (error) {
+      console.error('Invalid notification event:', error);
+    }
+  };
+
+  const connect = async () => {
+    while (!controller.signal.aborted) {
+      try {
+        const token = localStorage.getItem('token');
+        if (!token) return;
+
+        const response = await fetch(`${envConfig.apiUrl}/api/notifications/stream`, {
+          headers: { Authorization: `Bearer ${token}`, Accept: 'text/event-stream' },
+          credentials: 'include',
+          signal: controller.signal,
+        });
+        if (response.status === 401) return;
+        if (!response.ok || !response.body) throw new Error(`Stream failed with ${response.status}`);
+
+        const reader = response.body.getReader();
+        const decoder = new TextDecoder();
+        let buffer = '';
+
+        for (;;) {
+          const { value, done } = await reader.read();
+          if (done) break;
+          buffer += decoder.decode(value, { stream: true });
+
+          // Events are separated by a blank line
+          let boundary;
+          while ((boundary = buffer.indexOf('\n\n')) >= 0) {
+            const rawEvent = buffer.slice(0, boundary);
+            buffer = buffer.slice(boundary + 2);
+
+            let event = 'message';
+            const dataLines: string[] = [];
+            for (const line of rawEvent.split('\n')) {
+              if (line.startsWith('event:')) event = line.slice(6).trim();
+              else if (line.startsWith('data:')) dataLines.push(line.slice(5).trim());
+            }
+            if (dataLines.length > 0) dispatch(event, dataLines.join('\n'));
+          }
+        }
+      } catch (error) {
+        if (controller.signal.aborted) return;
+        console.error('Notification stream error:', error);
+      }
+
+      await new Promise(resolve => setTimeout(resolve, RECONNECT_DELAY_MS));
+    }
+  };
+
+  connect();
+  return () => controller.abort();
+};
@@ -0,0 +1,35 @@
+import { apiClient } from './api';
+import { Review, ReviewPage, ReviewSort } from '../types/lesson';
+
+export const getTutorReviews = async (
+  tutorId: number | string,
+  sort: ReviewSort = 'recent',
+  limit = 10,
+  offset = 0
+): Promise<ReviewPage> => {
+  const response = await apiClient.get(`/api/tutors/${tutorId}/reviews`, { params: { sort, limit, offset } });
+  return response.data;
+};
+
+export const replyToReview = async (reviewId: number, text: string): Promise<Review> => {
+  const response = await apiClient.put(`/api/reviews/${reviewId}/reply`, { text });
+  return response.data;
+};
+
+export const deleteReviewReply = async (reviewId: number): Promise<void> => {
+  await apiClient.delete(`/api/reviews/${reviewId}/reply`);
+};
+
+export const markReviewHelpful = async (reviewId: number): Promise<number> => {
+  const response = await apiClient.post(`/api/reviews/${reviewId}/helpful`);
+  return response.data.helpful_count;
+};
+
+export const unmarkReviewHelpful = async (reviewId: number): Promise<number> => {
+  const response = await apiClient.delete(`/api/reviews/${reviewId}/helpful`);
+  return response.data.helpful_count;
+};
+
+export const reportReview = async (reviewId: number, reason: string): Promise<void> => {
+  await apiClient.post(`/api/reviews/${reviewId}/report`, { reason });
+};
@@ -1,9 +1,12 @@
-import { TutorProfile, TutorAvailability } from '../types/tutor';
+import { TutorProfile, TutorAvailability, BusyTime } from '../types/tutor';
 import { LessonBookingRequest } from '../types/lesson';
 import { apiClient } from './api';
 
-export const getTutorProfile = async (tutorId: string): Promise<TutorProfile> => {
-  const response = await apiClient.get(`/api/tutors/${tutorId}`);
+// countView is set by the tutor's profile page, the only fetch that counts as a profile view
+export const getTutorProfile = async (tutorId: string, countView = false): Promise<TutorProfile> => {
+  const response = await apiClient.get(`/api/tutors/${tutorId}`, {
+    params: countView ? { view: 'profile' } : undefined,
+  });
   return response.data;
 };
 
@@ -12,6 +15,11 @@ export const getTutorAvailabilities = async (tutorId: string): Promise<TutorAvai
   return response.data;
 };
 
+export const getTutorBusyTimes = async (tutorId: string): Promise<BusyTime[]> => {
+  const response = await apiClient.get(`/api/tutors/${tutorId}/busy-times`);
+  return response.data;
+};
+
 export const bookLesson = async (bookingData: LessonBookingRequest): Promise<any> => {
   const response = await apiClient.post('/api/lessons', bookingData);
   return response.data;
@@ -0,0 +1,46 @@
+import { apiClient } from './api';
+import { LessonMaterial, Upload, UploadList, UploadPurpose } from '../types/upload';
+
+export const uploadFile = async (file: File, purpose: UploadPurpose): Promise<Upload> => {
+  const formData = new FormData();
+  formData.append('file', file);
+  formData.append('purpose', purpose);
+
+  const response = await apiClient.post('/api/uploads', formData, {
+    headers: { 'Content-Type': 'multipart/form-data' },
+  });
+  return response.data;
+};
+
+export const getUploads = async (): Promise<UploadList> => {
+  const response = await apiClient.get('/api/uploads');
+  return response.data;
+};
+
+export const deleteUpload = async (uploadId: number): Promise<void> => {
+  await apiClient.delete(`/api/uploads/${uploadId}`);
+};
+
+export const setAvatar = async (uploadId: number): Promise<Upload> => {
+  const response = await apiClient.put('/api/user/avatar', { upload_id: uploadId });
+  return response.data;
+};
+
+export const setIntroVideo = async (uploadId: number): Promise<Upload> => {
+  const response = await apiClient.put('/api/tutor/intro-video', { upload_id: uploadId });
+  return response.data;
+};
+
+export const getLessonMaterials = async (lessonId: number): Promise<LessonMaterial[]> => {
+  const response = await apiClient.get(`/api/lessons/${lessonId}/materials`);
+  return response.data;
+};
+
+export const addLessonMaterial = async (lessonId: number, uploadId: number): Promise<LessonMaterial> => {
+  const response = await apiClient.post(`/api/lessons/${lessonId}/materials`, { upload_id: uploadId });
+  return response.data;
+};
+
+export const removeLessonMaterial = async (lessonId: number, uploadId: number): Promise<void> => {
+  await apiClient.delete(`/api/lessons/${lessonId}/materials/${uploadId}`);
+};
@@ -14,15 +14,64 @@ export enum LessonStatus {
   CANCELLED = 'cancelled',
 }
 
+// Optional 1-5 ratings a student gives to parts of a tutor's lessons
+export interface AspectRatings {
+  pronunciation_help?: number;
+  preparation?: number;
+  punctuality?: number;
+}
+
+// A tutor's public answer to a review
+export interface ReviewReply {
+  text: string;
+  replied_at: string;
+}
+
 // Review model
 export interface Review extends BaseEntity {
   id: number;
   lesson_id: number;
   reviewer_id: number;
+  tutor_id: number;
   rating: number;
+  comment: string;
+  aspects: AspectRatings;
+  reply?: ReviewReply;
+  helpful_count: number;
+  hidden_at?: string;
+  hidden_reason?: string;
   reviewer?: User;
 }
 
+// Review request
+export interface ReviewRequest {
+  rating: number;
+  comment?: string;
+  aspects?: AspectRatings;
+}
+
+export type ReviewSort = 'recent' | 'helpful';
+
+// Average ratings of a tutor's visible reviews
+export interface ReviewSummary {
+  average_rating: number;
+  reviews_count: number;
+  aspects: {
+    pronunciation_help?: number;
+    preparation?: number;
+    punctuality?: number;
+  };
+}
+
+// A page of a tutor's reviews
+export interface ReviewPage {
+  reviews: Review[];
+  summary: ReviewSummary;
+  total: number;
+  limit: number;
+  offset: number;
+}
+
 // Lesson model
 export interface Lesson extends BaseEntity {
   id: number;
@@ -57,4 +106,9 @@ export interface LessonBookingRequest {
 // Lesson cancellation request
 export interface LessonCancellationRequest {
   reason: string;
-} 
\ No newline at end of file
+} 
+// Short-lived ticket that admits the user to the video room of a lesson
+export interface LessonRoomTicket {
+  ticket: string;
+  expires_at: string;
+}
@@ -0,0 +1,101 @@
+import { User } from './user';
+
+export interface VocabularyItem {
+  term: string;
+  translation?: string;
+  example?: string;
+}
+
+export interface Correction {
+  original: string;
+  corrected: string;
+  explanation?: string;
+}
+
+// Structured notes a tutor writes after a lesson; private_notes are only returned to the tutor
+export interface LessonNotes {
+  id: number;
+  lesson_id: number;
+  tutor_id: number;
+  summary: string;
+  topics: string[];
+  vocabulary: VocabularyItem[];
+  corrections: Correction[];
+  private_notes?: string;
+  created_at: string;
+  updated_at: string;
+}
+
+// Omit private_notes to keep them unchanged, send an empty string to remove them
+export interface LessonNotesRequest {
+  summary: string;
+  topics: string[];
+  vocabulary: VocabularyItem[];
+  corrections: Correction[];
+  private_notes?: string;
+}
+
+export interface HomeworkCompletion {
+  student_id: number;
+  completed_at: string;
+}
+
+export interface Homework {
+  id: number;
+  lesson_id: number;
+  tutor_id: number;
+  description: string;
+  due_at?: string;
+  completed_at?: string;
+  completions?: HomeworkCompletion[];
+  created_at: string;
+  updated_at: string;
+}
+
+export interface HomeworkRequest {
+  description: string;
+  due_at?: string;
+}
+
+// Private progress feedback a tutor gives a student after a lesson
+export interface StudentFeedback {
+  id: number;
+  lesson_id: number;
+  tutor_id: number;
+  student_id: number;
+  rating: number;
+  comment: string;
+  created_at: string;
+  updated_at: string;
+}
+
+// student_id can be left out for one-on-one lessons
+export interface StudentFeedbackRequest {
+  student_id?: number;
+  rating: number;
+  comment: string;
+}
+
+export interface LessonHistoryEntry {
+  lesson_id: number;
+  start_time: string;
+  end_time: string;
+  lesson_type: string;
+  language: string;
+  notes?: LessonNotes;
+  homework: Homework[];
+  feedback?: StudentFeedback;
+}
+
+export interface LessonHistory {
+  tutor_id: number;
+  student_id: number;
+  lessons: LessonHistoryEntry[];
+}
+
+export interface StudentHomework extends Homework {
+  lesson_start_time: string;
+  language: string;
+  overdue: boolean;
+  tutor?: User;
+}
@@ -0,0 +1,53 @@
+import { User } from './user';
+
+// Reference to a file uploaded elsewhere
+export interface MessageAttachment {
+  url: string;
+  name: string;
+  content_type?: string;
+  size?: number;
+}
+
+export interface Message {
+  id: number;
+  conversation_id: number;
+  sender_id: number;
+  body: string;
+  attachments: MessageAttachment[];
+  read_at?: string;
+  created_at: string;
+}
+
+// One-to-one conversation between a student and a tutor
+export interface Conversation {
+  id: number;
+  student_id: number;
+  tutor_id: number;
+  started_by: number;
+  last_message_at: string;
+  created_at: string;
+  student?: User;
+  tutor?: User;
+  last_message?: Message;
+  unread_count: number;
+}
+
+export interface ConversationList {
+  conversations: Conversation[];
+  total: number;
+}
+
+export interface MessagePage {
+  messages: Message[];
+  has_more: boolean;
+}
+
+export interface MessageRequest {
+  body: string;
+  attachments?: MessageAttachment[];
+}
+
+export interface BlockedUser {
+  user: User;
+  created_at: string;
+}
@@ -0,0 +1,54 @@
+export type NotificationType =
+  | 'lesson_booked'
+  | 'lesson_cancelled'
+  | 'lesson_rescheduled'
+  | 'lesson_reminder'
+  | 'review_received'
+  | 'review_reply'
+  | 'streak_at_risk'
+  | 'message_received'
+  | 'lesson_notes'
+  | 'student_feedback'
+  | 'weekly_summary'
+  | 'saved_search_match'
+  | 'favorite_availability'
+  | 'credential_reviewed';
+
+// In-app notification about an event concerning the current user
+export interface AppNotification {
+  id: number;
+  user_id: number;
+  type: NotificationType;
+  title: string;
+  body: string;
+  data?: Record<string, any>;
+  read_at?: string;
+  created_at: string;
+}
+
+export interface NotificationList {
+  notifications: AppNotification[];
+  total: number;
+  unread_count: number;
+}
+
+// Name of the window event dispatched for every notification pushed by the server
+export const NOTIFICATION_EVENT = 'tongly:notification';
+
+export type EmailLocale = 'en' | 'ru' | 'es';
+
+// Channels through which the user is told about an event
+export interface NotificationChannels {
+  in_app: boolean;
+  email: boolean;
+}
+
+export interface NotificationPreferences {
+  locale: EmailLocale;
+  events: Record<NotificationType, NotificationChannels>;
+}
+
+export interface NotificationPreferencesUpdate {
+  locale?: EmailLocale;
+  events?: Partial<Record<NotificationType, NotificationChannels>>;
+}
@@ -3,6 +3,7 @@ import { User } from './user';
 import { UserGoal } from './interest-goal';
 import { UserInterest } from './interest-goal';
 import { UserLanguage, UserLanguageUpdate } from './language';
+import { TutorProfile, TutorSearchFilters } from './tutor';
 
 /**
  * Student-related types
@@ -35,4 +36,39 @@ export interface StudentUpdateRequest {
   languages?: UserLanguageUpdate[];
   interests?: number[];
   goals?: number[];
-} 
\ No newline at end of file
+} 
+// Weekly time a student would like to have lessons at
+export interface PreferredTime {
+  day_of_week: number; // 0 is Sunday
+  start_time: string;  // HH:MM
+  end_time: string;    // HH:MM; before start_time for times past midnight
+}
+
+// What a student would like from their lessons, used to recommend tutors
+export interface StudentPreferences {
+  timezone: string;
+  min_price?: number;
+  max_price?: number;
+  preferred_times: PreferredTime[];
+}
+
+// Tutor on a student's shortlist
+export interface FavoriteTutor {
+  tutor: TutorProfile;
+  added_at: string;
+}
+
+// Tutor search filters a student saved; with notify set the student is alerted about new matches
+export interface SavedSearch extends BaseEntity {
+  id: number;
+  student_id: number;
+  name: string;
+  filters: TutorSearchFilters;
+  notify: boolean;
+}
+
+export interface SavedSearchRequest {
+  name: string;
+  filters: TutorSearchFilters;
+  notify?: boolean;
+}
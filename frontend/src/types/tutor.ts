@@ -1,18 +1,19 @@
 import { BaseEntity } from './common';
 import { User } from './user';
 import { UserLanguage, UserLanguageUpdate } from './language';
+import { Upload } from './upload';
 
 /**
  * Tutor-related types
  */
 
-// Education entry structure
+// Education entry structure; an entry without an end year is still under way
 export interface Education {
   degree: string;
   institution: string;
   field_of_study: string;
-  start_year: string;
-  end_year: string;
+  start_year?: number;
+  end_year?: number;
 }
 
 // Tutor profile
@@ -22,12 +23,47 @@ export interface TutorProfile extends BaseEntity {
   education: Education[];
   intro_video_url?: string;
   years_experience: number;
+  timezone?: string;
   created_at: string;
   updated_at: string;
   user?: User;
   languages?: UserLanguage[];
   rating?: number;
   reviews_count?: number;
+  rating_score?: number;
+  recent_rating_score?: number;
+  is_verified?: boolean; // Has at least one credential verified by an admin
+}
+
+export type CredentialKind = 'education' | 'certificate';
+export type CredentialStatus = 'pending' | 'verified' | 'rejected';
+
+// A degree or certificate of the tutor with its proof document, which an admin reviews.
+// document is only set for the tutor themselves.
+export interface TutorCredential {
+  id: number;
+  tutor_id: number;
+  kind: CredentialKind;
+  title: string;
+  issuer: string;
+  field_of_study?: string;
+  year?: number;
+  document_id?: number;
+  document?: Upload;
+  status: CredentialStatus;
+  rejection_reason?: string;
+  reviewed_at?: string;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface CredentialRequest {
+  kind: CredentialKind;
+  title: string;
+  issuer: string;
+  field_of_study?: string;
+  year?: number;
+  document_id: number;
 }
 
 // Tutor availability
@@ -70,6 +106,7 @@ export interface TutorUpdateRequest {
   education?: Education[];
   intro_video_url?: string;
   years_experience?: number;
+  timezone?: string;
 }
 
 // Tutor search filters
@@ -82,10 +119,90 @@ export interface TutorSearchFilters {
   min_age?: number;              // Filter by minimum age
   max_age?: number;              // Filter by maximum age
   sex?: string;                  // Filter by sex (male, female)
+  q?: string;                    // Free text matched against names, bios and education
+  days?: number[];               // Days of week the tutor works on, 0 is Sunday
+  from_time?: string;            // Hours the tutor works in, HH:MM in the student's time zone
+  to_time?: string;
+  tz?: string;                   // Student's IANA time zone
+  available_within_days?: number; // Tutor has an open slot within this many days
+  verified_only?: boolean;       // Only tutors with a verified credential
+  sort?: TutorSort;
+  limit?: number;
+  offset?: number;
+}
+
+export type TutorSort = 'rating' | 'price' | 'experience' | 'newest' | 'relevance';
+
+// Number of tutors one option of a search filter yields
+export interface FacetCount {
+  id: number;
+  name: string;
+  count: number;
+}
+
+export interface TutorSearchFacets {
+  languages: FacetCount[];
+  interests: FacetCount[];
+  proficiencies: FacetCount[];
+}
+
+export interface TutorSearchPage {
+  tutors: TutorProfile[];
+  total: number;
+  limit: number;
+  offset: number;
+  sort: TutorSort;
+  facets: TutorSearchFacets;
+}
+
+// How much each factor counts towards a recommendation score
+export interface RecommendationWeights {
+  interests: number;
+  goals: number;
+  language: number;
+  availability: number;
+  rating: number;
+  price: number;
+}
+
+export type RecommendationFactorName = keyof RecommendationWeights;
+
+// How a tutor scores on one factor of a recommendation
+export interface RecommendationFactor {
+  match: number;  // From 0 to 1
+  weight: number; // 0 when the student gave nothing to compare with
+  points: number; // What the factor adds to the score
+}
+
+export type RecommendationBreakdown = Record<RecommendationFactorName, RecommendationFactor> & {
+  shared_interests: number[];
+  shared_goals: number[];
+  taught_languages: number[];
+  overlap_minutes: number;
+  preferred_minutes: number;
+  within_price_range: boolean;
+  rated_by_reviews: boolean;
+};
+
+export interface TutorRecommendation {
+  tutor: TutorProfile;
+  score: number; // From 0 to 1
+  breakdown: RecommendationBreakdown;
+}
+
+export interface TutorRecommendations {
+  recommendations: TutorRecommendation[];
+  weights: RecommendationWeights;
 }
 
 export interface AvailableTimeSlot {
   id: number; // Related to availability ID
   start: Date;
   end: Date;
+}
+
+// Interval in which the tutor is busy according to their external calendars
+export interface BusyTime {
+  start_time: string;
+  end_time: string;
 } 
\ No newline at end of file
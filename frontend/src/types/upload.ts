@@ -0,0 +1,31 @@
+export type UploadPurpose = 'avatar' | 'intro_video' | 'lesson_material' | 'credential';
+
+// A stored file; url is a signed link valid until url_expires_at, content_url a permanent
+// link that is only set for public files such as avatars
+export interface Upload {
+  id: number;
+  user_id: number;
+  purpose: UploadPurpose;
+  filename: string;
+  content_type: string;
+  size: number;
+  created_at: string;
+  url?: string;
+  url_expires_at?: string;
+  content_url?: string;
+}
+
+export interface UploadQuota {
+  used_bytes: number;
+  limit_bytes: number;
+}
+
+export interface UploadList {
+  uploads: Upload[];
+  quota: UploadQuota;
+}
+
+export interface LessonMaterial extends Upload {
+  lesson_id: number;
+  attached_at: string;
+}
@@ -18,6 +18,8 @@ export interface User {
   first_name: string;
   last_name: string;
   profile_picture_url?: string;
+  // Square crops of an uploaded profile picture by width in pixels (64, 128 and 512)
+  avatar_urls?: Record<number, string>;
   sex?: string;
   age?: number;
   role: string;
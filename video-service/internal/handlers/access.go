@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+)
+
+// roomAccess is the backend's answer to whether a user may join the room of a lesson
+type roomAccess struct {
+	LessonID        int    `json:"lesson_id"`
+	UserID          int    `json:"user_id"`
+	Role            string `json:"role"`
+	MaxParticipants int    `json:"max_participants"`
+}
+
+var backendClient = &http.Client{
+	Timeout: 5 * time.Second,
+	Transport: &http.Transport{
+		// The backend uses the same self-signed certificate in development
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: os.Getenv("BACKEND_TLS_SKIP_VERIFY") == "true"},
+	},
+}
+
+// backendURL is the address of the backend API that room tickets are checked against
+var backendURL = strings.TrimRight(os.Getenv("BACKEND_URL"), "/")
+
+// CheckBackendURL reports an error if the backend that room access is checked against is not
+// configured, in which case no one could join a room
+func CheckBackendURL() error {
+	if backendURL == "" {
+		return errors.New("BACKEND_URL is not set: it must point at the backend API (e.g. https://backend:8080) so that room tickets can be checked")
+	}
+	return nil
+}
+
+// RequireRoomAccess only lets the tutor and the enrolled students of a lesson into its room.
+// The room ticket the backend issued to the user is passed in the ticket query parameter and
+// checked against the backend once, when the room page loads. The page then gets a room
+// session for its websockets, so that they can reconnect after the short-lived ticket expires.
+func RequireRoomAccess(c *fiber.Ctx) error {
+	ticket := c.Query("ticket")
+	if ticket == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Room ticket is required",
+		})
+	}
+
+	access, err := checkRoomAccess(c.Params("uuid"), ticket)
+	if err != nil {
+		log.Println("Room access check failed:", err)
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "You are not allowed to join this room",
+		})
+	}
+
+	session, err := createRoomSession(c.Params("uuid"), access)
+	if err != nil {
+		log.Println("Failed to create room session:", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to join the room",
+		})
+	}
+
+	c.Locals("roomSession", session)
+	return c.Next()
+}
+
+// RequireRoomSession only lets websockets opened by an admitted room page into the room. The
+// session is passed in the session query parameter; the websocket handler has to give it back
+// with releaseRoomSession when the connection ends.
+func RequireRoomSession(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return fiber.ErrUpgradeRequired
+	}
+
+	session := c.Query("session")
+	access, ok := openRoomSession(session, c.Params("uuid"))
+	if !ok {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "You are not allowed to join this room",
+		})
+	}
+
+	c.Locals("roomAccess", access)
+	c.Locals("roomSession", session)
+	if err := c.Next(); err != nil {
+		// The connection was not upgraded, so the websocket handler will not run
+		closeRoomSession(session)
+		return err
+	}
+	return nil
+}
+
+// releaseRoomSession closes the room session of a websocket let in by RequireRoomSession
+func releaseRoomSession(c *websocket.Conn) {
+	if session, ok := c.Locals("roomSession").(string); ok {
+		closeRoomSession(session)
+	}
+}
+
+// checkRoomAccess asks the backend whether the holder of ticket may join the room of a lesson
+func checkRoomAccess(lessonID, ticket string) (*roomAccess, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/lessons/%s/room-access", backendURL, url.PathEscape(lessonID)), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+ticket)
+
+	resp, err := backendClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("backend returned %d", resp.StatusCode)
+	}
+
+	var access roomAccess
+	if err := json.NewDecoder(resp.Body).Decode(&access); err != nil {
+		return nil, err
+	}
+	return &access, nil
+}
+
+// ReserveRoomSeat takes a seat in the room for the video websocket about to be upgraded, so that
+// users joining at the same time cannot overfill the room. RoomWebsocket gives the seat back.
+func ReserveRoomSeat(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return fiber.ErrUpgradeRequired
+	}
+
+	access, ok := c.Locals("roomAccess").(*roomAccess)
+	if !ok || access.MaxParticipants <= 0 {
+		return c.Next()
+	}
+
+	uuid, room := createOrGetRoom(c.Params("uuid"))
+
+	// Group lessons admit the tutor and every enrolled participant, but no more
+	if !room.ReserveSeat(access.MaxParticipants) {
+		log.Println("Room is full:", uuid)
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "The room is full",
+		})
+	}
+
+	c.Locals("seatReserved", true)
+	if err := c.Next(); err != nil {
+		// The connection was not upgraded, so RoomWebsocket will not run
+		room.ReleaseSeat()
+		return err
+	}
+	return nil
+}
+
+// roomQuery returns the query string that has to be passed on to the room's websockets
+func roomQuery(c *fiber.Ctx) string {
+	if session, ok := c.Locals("roomSession").(string); ok {
+		return "?session=" + url.QueryEscape(session)
+	}
+	return ""
+}
@@ -8,6 +8,8 @@ import (
 )
 
 func RoomChatWebsocket(c *websocket.Conn) {
+	defer releaseRoomSession(c)
+
 	uuid := c.Params("uuid")
 	if uuid == "" {
 		return
@@ -47,12 +47,20 @@ func RoomExists(c *fiber.Ctx) error {
 
 // RoomWebsocket handles WebSocket connections for the room
 func RoomWebsocket(c *websocket.Conn) {
+	defer releaseRoomSession(c)
+
 	uuid := c.Params("uuid")
 	if uuid == "" {
 		return
 	}
 
 	_, room := createOrGetRoom(uuid)
+
+	// The seat was taken by ReserveRoomSeat before the upgrade
+	if reserved, _ := c.Locals("seatReserved").(bool); reserved {
+		defer room.ReleaseSeat()
+	}
+
 	w.RoomConn(c, room.Peers)
 }
 
@@ -102,7 +110,7 @@ func RoomVideoOnly(c *fiber.Ctx) error {
 
 	// Return HTML template with video only
 	return c.Render("video", fiber.Map{
-		"RoomWebsocketAddr": fmt.Sprintf("%s://%s/api/room/%s/video/websocket", wsScheme, c.Hostname(), uuid),
+		"RoomWebsocketAddr": fmt.Sprintf("%s://%s/api/room/%s/video/websocket%s", wsScheme, c.Hostname(), uuid, roomQuery(c)),
 	}, "")
 }
 
@@ -124,6 +132,6 @@ func RoomChatOnly(c *fiber.Ctx) error {
 
 	// Return HTML template with chat only
 	return c.Render("chat", fiber.Map{
-		"ChatWebsocketAddr": fmt.Sprintf("%s://%s/api/room/%s/chat/websocket", wsScheme, c.Hostname(), uuid),
+		"ChatWebsocketAddr": fmt.Sprintf("%s://%s/api/room/%s/chat/websocket%s", wsScheme, c.Hostname(), uuid, roomQuery(c)),
 	}, "")
 }
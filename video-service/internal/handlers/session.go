@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// roomSessionIdleTTL is how long a room session stays valid without any open websocket. It
+// covers reconnects after network drops, while a page left closed cannot be reused later.
+const roomSessionIdleTTL = 10 * time.Minute
+
+// roomSession admits the websockets of one room page. It is created once the room ticket of
+// the page has been checked against the backend, so that reconnecting needs no fresh ticket.
+type roomSession struct {
+	roomID   string
+	access   *roomAccess
+	conns    int
+	lastSeen time.Time
+}
+
+var (
+	roomSessions     = make(map[string]*roomSession)
+	roomSessionsLock sync.Mutex
+)
+
+// createRoomSession starts a session for a user admitted to a room and returns its token
+func createRoomSession(roomID string, access *roomAccess) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+
+	roomSessionsLock.Lock()
+	defer roomSessionsLock.Unlock()
+
+	// Expired sessions are dropped here rather than by a background sweep
+	now := time.Now()
+	for t, s := range roomSessions {
+		if s.expired(now) {
+			delete(roomSessions, t)
+		}
+	}
+
+	roomSessions[token] = &roomSession{roomID: roomID, access: access, lastSeen: now}
+	return token, nil
+}
+
+// openRoomSession marks a websocket of the session as open and returns the admission of its
+// user, or false if the session is unknown, expired or belongs to another room
+func openRoomSession(token, roomID string) (*roomAccess, bool) {
+	roomSessionsLock.Lock()
+	defer roomSessionsLock.Unlock()
+
+	s := roomSessions[token]
+	if s == nil || s.roomID != roomID || s.expired(time.Now()) {
+		return nil, false
+	}
+
+	s.conns++
+	s.lastSeen = time.Now()
+	return s.access, true
+}
+
+// closeRoomSession marks a websocket opened with openRoomSession as closed
+func closeRoomSession(token string) {
+	roomSessionsLock.Lock()
+	defer roomSessionsLock.Unlock()
+
+	if s := roomSessions[token]; s != nil {
+		s.conns--
+		s.lastSeen = time.Now()
+	}
+}
+
+// expired checks if the session has had no open websocket for roomSessionIdleTTL
+func (s *roomSession) expired(now time.Time) bool {
+	return s.conns <= 0 && now.Sub(s.lastSeen) > roomSessionIdleTTL
+}
@@ -42,6 +42,10 @@ func Run() error {
 		*key = "../certs/key.pem"
 	}
 
+	if err := handlers.CheckBackendURL(); err != nil {
+		return err
+	}
+
 	engine := html.New("./views", ".html")
 	app := fiber.New(fiber.Config{Views: engine})
 	app.Use(logger.New())
@@ -55,12 +59,12 @@ func Run() error {
 	}))
 
 	// API endpoints used by LessonRoom
-	app.Get("/api/room/:uuid/video", handlers.RoomVideoOnly)
-	app.Get("/api/room/:uuid/video/websocket", websocket.New(handlers.RoomWebsocket, websocket.Config{
+	app.Get("/api/room/:uuid/video", handlers.RequireRoomAccess, handlers.RoomVideoOnly)
+	app.Get("/api/room/:uuid/video/websocket", handlers.RequireRoomSession, handlers.ReserveRoomSeat, websocket.New(handlers.RoomWebsocket, websocket.Config{
 		HandshakeTimeout: 10 * time.Second,
 	}))
-	app.Get("/api/room/:uuid/chat", handlers.RoomChatOnly)
-	app.Get("/api/room/:uuid/chat/websocket", websocket.New(handlers.RoomChatWebsocket))
+	app.Get("/api/room/:uuid/chat", handlers.RequireRoomAccess, handlers.RoomChatOnly)
+	app.Get("/api/room/:uuid/chat/websocket", handlers.RequireRoomSession, websocket.New(handlers.RoomChatWebsocket))
 	app.Get("/api/room/:uuid/exists", handlers.RoomExists)
 	app.Post("/api/room/create/:uuid", handlers.RoomCreateWithID)
 
@@ -41,6 +41,29 @@ type Room struct {
 	Peers     *Peers
 	Hub       *chat.Hub
 	CreatedAt time.Time
+
+	seatsLock sync.Mutex
+	seats     int // Video connections admitted to the room, including the ones being upgraded
+}
+
+// ReserveSeat takes a seat in the room if fewer than max seats are taken
+func (r *Room) ReserveSeat(max int) bool {
+	r.seatsLock.Lock()
+	defer r.seatsLock.Unlock()
+
+	if r.seats >= max {
+		return false
+	}
+	r.seats++
+	return true
+}
+
+// ReleaseSeat gives back a seat taken with ReserveSeat
+func (r *Room) ReleaseSeat() {
+	r.seatsLock.Lock()
+	defer r.seatsLock.Unlock()
+
+	r.seats--
 }
 
 type Peers struct {